@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// actionMode is set when running with --as-action
+var actionMode bool
+
+// inlineConfig holds lintrc.yaml content given through the action's config
+// input, taking precedence over any lintrc.yaml on disk
+var inlineConfig string
+
+// actionArgs translates the INPUT_* variables of a GitHub Action run into
+// command line arguments and switches to the checkout directory, so that
+// paths in results match the paths GitHub reports for the PR
+func actionArgs() ([]string, error) {
+	actionMode = true
+
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace != "" {
+		if err := os.Chdir(workspace); err != nil {
+			return nil, fmt.Errorf("failed to enter workspace %s: %v", workspace, err)
+		}
+	}
+
+	if token := os.Getenv("INPUT_TOKEN"); token != "" {
+		os.Setenv("GITHUB_TOKEN", token)
+	}
+	inlineConfig = os.Getenv("INPUT_CONFIG")
+
+	var args []string
+	flags := []struct {
+		input      string
+		flag       string
+		defaultVal bool
+	}{
+		{"INPUT_COMMENT_PR", "--comment-pr", true},
+		{"INPUT_SUGGEST", "--suggest", false},
+		{"INPUT_CHANGED_LINES_ONLY", "--changed-lines-only", false},
+		{"INPUT_FETCH_CONTENTS", "--fetch-contents", false},
+		{"INPUT_DIFF", "--diff", false},
+		{"INPUT_ANONYMOUS", "--anonymous", false},
+		{"INPUT_FREEZE", "--freeze", false},
+		{"INPUT_LIVE", "--live", false},
+		{"INPUT_SYNC", "--sync", false},
+		{"INPUT_CREATE_ISSUES", "--create-issues", false},
+		{"INPUT_AUDIT", "--audit", false},
+		{"INPUT_SANDBOX", "--sandbox", false},
+		{"INPUT_FOLLOW_SYMLINKS", "--follow-symlinks", false},
+	}
+	for _, f := range flags {
+		enabled, err := actionBool(f.input, f.defaultVal)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			args = append(args, f.flag)
+		}
+	}
+
+	if depth := strings.TrimSpace(os.Getenv("INPUT_MAX_DEPTH")); depth != "" {
+		args = append(args, "--max-depth", depth)
+	}
+	if summary := strings.TrimSpace(os.Getenv("INPUT_SUMMARY_FILE")); summary != "" {
+		args = append(args, "--summary-file", summary)
+	}
+
+	for _, dir := range strings.Fields(os.Getenv("INPUT_DIRECTORIES")) {
+		if filepath.IsAbs(dir) && workspace != "" {
+			if rel, err := filepath.Rel(workspace, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				dir = rel
+			}
+		}
+		args = append(args, dir)
+	}
+
+	return args, nil
+}
+
+// actionBool reads a boolean action input, returning defaultVal when unset
+func actionBool(name string, defaultVal bool) (bool, error) {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return defaultVal, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean for %s: %s", name, value)
+	}
+	return enabled, nil
+}
+
+// writeActionOutputs appends the run's outputs to $GITHUB_OUTPUT in action mode
+func writeActionOutputs(results []LintResult, hasErrors bool) {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if !actionMode || path == "" {
+		return
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, result := range results {
+		errorCount += len(result.Errors)
+		warningCount += len(result.Warnings)
+	}
+	status := "success"
+	if hasErrors {
+		status = "failure"
+	}
+
+	var outputs strings.Builder
+	outputs.WriteString(fmt.Sprintf("result=%s\n", status))
+	outputs.WriteString(fmt.Sprintf("errors-found=%t\n", hasErrors))
+	outputs.WriteString(fmt.Sprintf("error-count=%d\n", errorCount))
+	outputs.WriteString(fmt.Sprintf("warning-count=%d\n", warningCount))
+	outputs.WriteString(fmt.Sprintf("files-linted=%d\n", len(results)))
+	if summaryPath != "" {
+		outputs.WriteString(fmt.Sprintf("summary-file=%s\n", summaryPath))
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Warning: failed to write action outputs: %v", err)
+		return
+	}
+	defer file.Close()
+	if _, err := file.WriteString(outputs.String()); err != nil {
+		log.Printf("Warning: failed to write action outputs: %v", err)
+	}
+}
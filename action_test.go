@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestActionArgs(t *testing.T) {
+	workspace := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+		actionMode = false
+		inlineConfig = ""
+	}()
+
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("INPUT_TOKEN", "secret")
+	t.Setenv("INPUT_CONFIG", "require_host: true\n")
+	t.Setenv("INPUT_COMMENT_PR", "false")
+	t.Setenv("INPUT_SUGGEST", "")
+	t.Setenv("INPUT_CHANGED_LINES_ONLY", "")
+	t.Setenv("INPUT_FOLLOW_SYMLINKS", "true")
+	t.Setenv("INPUT_MAX_DEPTH", "3")
+	t.Setenv("INPUT_SUMMARY_FILE", "lint-summary.json")
+	t.Setenv("INPUT_DIRECTORIES", "web\n"+filepath.Join(workspace, "pwn")+" misc")
+
+	args, err := actionArgs()
+	if err != nil {
+		t.Fatalf("actionArgs failed: %v", err)
+	}
+	want := []string{"--follow-symlinks", "--max-depth", "3", "--summary-file", "lint-summary.json", "web", "pwn", "misc"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("actionArgs() = %v, want %v", args, want)
+	}
+	if os.Getenv("GITHUB_TOKEN") != "secret" {
+		t.Error("Expected INPUT_TOKEN to be exported as GITHUB_TOKEN")
+	}
+	cwd, _ := os.Getwd()
+	if resolved, _ := filepath.EvalSymlinks(workspace); cwd != resolved && cwd != workspace {
+		t.Errorf("Expected to run in the workspace, got %s", cwd)
+	}
+
+	config, err := loadLintConfig()
+	if err != nil || !config.RequireHost {
+		t.Errorf("Expected inline config to be loaded, got %+v (%v)", config, err)
+	}
+
+	t.Setenv("INPUT_SUGGEST", "maybe")
+	if _, err := actionArgs(); err == nil || !strings.Contains(err.Error(), "INPUT_SUGGEST") {
+		t.Errorf("Expected invalid boolean error, got: %v", err)
+	}
+}
+
+func TestWriteActionOutputs(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", outputPath)
+	actionMode = true
+	defer func() {
+		actionMode = false
+	}()
+
+	results := []LintResult{
+		{File: "a/challenge.yml", Errors: []Finding{{Message: "e1"}, {Message: "e2"}}, Warnings: []Finding{{Message: "w1"}}},
+		{File: "b/challenge.yml"},
+	}
+	writeActionOutputs(results, true)
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read outputs: %v", err)
+	}
+	want := "result=failure\nerrors-found=true\nerror-count=2\nwarning-count=1\nfiles-linted=2\n"
+	if string(data) != want {
+		t.Errorf("outputs =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestFindLintConfigIgnoresActionCheckout(t *testing.T) {
+	// The action's checkout, with its sample lintrc.yaml next to the binary
+	actionPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(actionPath, "lintrc.yaml"), []byte("tags: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origArg := os.Args[0]
+	origDir, _ := os.Getwd()
+	os.Args[0] = filepath.Join(actionPath, "clilint")
+	_ = os.Chdir(t.TempDir())
+	defer func() {
+		os.Args[0] = origArg
+		_ = os.Chdir(origDir)
+		actionMode = false
+	}()
+
+	if got := findLintConfig(); got != filepath.Join(actionPath, "lintrc.yaml") {
+		t.Errorf("findLintConfig() = %q, want the lintrc.yaml next to the executable", got)
+	}
+	actionMode = true
+	if got := findLintConfig(); got != "" {
+		t.Errorf("findLintConfig() in action mode = %q, want none", got)
+	}
+}
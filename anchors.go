@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes bounds the size of a challenge.yml once its aliases are
+// expanded. Real challenges have a few hundred nodes; nested aliases
+// ("billion laughs") can otherwise make a tiny file expand exponentially and
+// exhaust the CI runner.
+const maxYAMLNodes = 10000
+
+// mergeKey is the YAML merge key, which copies the entries of aliased
+// mappings into the mapping it appears in
+const mergeKey = "<<"
+
+// checkAliasExpansion parses data without expanding aliases and rejects
+// streams that would expand to more than maxYAMLNodes nodes
+func checkAliasExpansion(data []byte) error {
+	docs, err := yamlDocuments(data)
+	if err != nil {
+		// Reported by the regular parse
+		return nil
+	}
+	size := 0
+	memo := make(map[*yaml.Node]int)
+	for _, doc := range docs {
+		size += expandedSize(doc, memo, make(map[*yaml.Node]bool))
+		if size > maxYAMLNodes {
+			return fmt.Errorf("YAML aliases expand to more than %d nodes; refusing to parse the document", maxYAMLNodes)
+		}
+	}
+	return nil
+}
+
+// expandedSize counts the nodes of the tree rooted at node with every alias
+// replaced by a copy of its anchor. Sizes are memoized per node and the count
+// stops growing once it passes maxYAMLNodes, so hostile documents are rejected
+// without being expanded. Aliases to an enclosing node count as too large.
+func expandedSize(node *yaml.Node, memo map[*yaml.Node]int, visiting map[*yaml.Node]bool) int {
+	if node == nil {
+		return 0
+	}
+	if size, ok := memo[node]; ok {
+		return size
+	}
+	if visiting[node] {
+		return maxYAMLNodes + 1
+	}
+	visiting[node] = true
+	defer delete(visiting, node)
+
+	size := 1
+	if node.Kind == yaml.AliasNode {
+		size = expandedSize(node.Alias, memo, visiting)
+	}
+	for _, child := range node.Content {
+		size += expandedSize(child, memo, visiting)
+		if size > maxYAMLNodes {
+			break
+		}
+	}
+	if size > maxYAMLNodes {
+		size = maxYAMLNodes + 1
+	}
+	memo[node] = size
+	return size
+}
+
+// resolveAlias follows alias nodes to the node they refer to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// mappingKeys returns the keys of a mapping in document order, with the keys
+// brought in by merge keys ("<<: *defaults") in place of the merge key.
+// Keys defined explicitly take precedence over merged ones, as in YAML.
+func mappingKeys(mapping *yaml.Node) []string {
+	explicit := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if key := mapping.Content[i].Value; key != mergeKey {
+			explicit[key] = true
+		}
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i].Value
+		if key != mergeKey {
+			add(key)
+			continue
+		}
+		for _, source := range mergeSources(mapping.Content[i+1]) {
+			for _, merged := range mappingKeys(source) {
+				if !explicit[merged] {
+					add(merged)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// mergeSources returns the mappings named by the value of a merge key, which
+// is an alias or a sequence of aliases
+func mergeSources(value *yaml.Node) []*yaml.Node {
+	var sources []*yaml.Node
+	candidates := []*yaml.Node{value}
+	if value.Kind == yaml.SequenceNode {
+		candidates = value.Content
+	}
+	for _, candidate := range candidates {
+		if source := resolveAlias(candidate); source != nil && source.Kind == yaml.MappingNode {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const aliasBomb = `a: &a ["lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol"]
+b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a]
+c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b]
+d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c]
+e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d]
+f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e]
+g: &g [*f, *f, *f, *f, *f, *f, *f, *f, *f]
+h: &h [*g, *g, *g, *g, *g, *g, *g, *g, *g]
+i: &i [*h, *h, *h, *h, *h, *h, *h, *h, *h]
+name: "bomb"
+`
+
+func TestCheckAliasExpansion(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "no aliases",
+			data: "name: test\ntags:\n  - easy\n",
+		},
+		{
+			name: "anchors and merge keys",
+			data: "defaults: &defaults\n  initial: 500\n  decay: 100\nextra:\n  <<: *defaults\n  minimum: 100\n",
+		},
+		{
+			name:    "alias bomb",
+			data:    aliasBomb,
+			wantErr: true,
+		},
+		{
+			name: "invalid YAML is left to the parser",
+			data: "name: [unclosed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAliasExpansion([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAliasExpansion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLintChallengeFileAliasBomb(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	if err := os.WriteFile(path, []byte(aliasBomb), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := lintChallengeFile(path)
+	if len(result.Errors) != 1 || result.Errors[0].RuleID != "yaml-aliases" {
+		t.Fatalf("expected a single yaml-aliases error, got %v", result.Errors)
+	}
+	if !strings.Contains(result.Errors[0].Message, "refusing to parse") {
+		t.Errorf("unexpected message: %s", result.Errors[0].Message)
+	}
+
+	if _, err := readChallenge(path); err == nil {
+		t.Error("readChallenge() accepted an alias bomb")
+	}
+}
+
+func TestLintChallengeFileMergeKeys(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	content := `x-common: &common
+  type: dynamic
+  state: visible
+  version: "0.1"
+x-tags: &tags
+  - easy
+name: "merged"
+author: "author"
+category: "misc"
+description: "desc"
+flags:
+  - "flag{merged}"
+tags: *tags
+value: 100
+<<: *common
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err := readChallenge(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.State != "visible" || challenge.Version != "0.1" || challenge.Type != "dynamic" {
+		t.Errorf("merge key not resolved: %+v", challenge)
+	}
+	if !reflect.DeepEqual(challenge.Tags, []string{"easy"}) {
+		t.Errorf("alias not resolved: %v", challenge.Tags)
+	}
+}
+
+func TestTopLevelKeysMerge(t *testing.T) {
+	data := `base: &base
+  state: visible
+  name: ignored
+name: "explicit"
+<<: *base
+version: "0.1"
+`
+	keys, err := topLevelKeys([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"base", "name", "state", "version"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("topLevelKeys() = %v, want %v", keys, want)
+	}
+}
+
+func TestTopLevelKeysMergeSequence(t *testing.T) {
+	data := `a: &a {x: 1}
+b: &b {y: 2}
+<<: [*a, *b]
+`
+	keys, err := topLevelKeys([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a", "b", "x", "y"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("topLevelKeys() = %v, want %v", keys, want)
+	}
+}
+
+func TestEditAliasedFields(t *testing.T) {
+	data := "x-tags: &tags [easy]\nstate: &state hidden\ntags: *tags\nother: *state\n"
+
+	updated, err := appendToTopLevelSequence([]byte(data), "tags", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "x-tags: &tags [easy]\nstate: &state hidden\ntags: [\"easy\", \"web\"]\nother: *state\n"
+	if string(updated) != want {
+		t.Errorf("appendToTopLevelSequence() =\n%s\nwant\n%s", updated, want)
+	}
+
+	updated, err = setTopLevelScalar([]byte(data), "other", "visible")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "x-tags: &tags [easy]\nstate: &state hidden\ntags: *tags\nother: visible\n"
+	if string(updated) != want {
+		t.Errorf("setTopLevelScalar() =\n%s\nwant\n%s", updated, want)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// anonymousMode is set by --anonymous: reports leave out who wrote which
+// challenge and the organizers' notes, so that they can be shared with
+// external reviewers and sponsors
+var anonymousMode bool
+
+// anonymousFields are the challenge.yml keys whose lines excerpts also hide
+// in anonymous mode; notes are among the secretFields hidden in any mode
+var anonymousFields = []string{"author"}
+
+// anonymousName replaces author names in anonymous reports
+const anonymousName = "(author)"
+
+// authorTagPattern matches the author tags the default tags rule requires
+var authorTagPattern = regexp.MustCompile(`(?i)^author:\s*(.+)$`)
+
+// authorNames returns the authors of a challenge: the names of its author
+// field, separated by commas, and of its author: tags
+func authorNames(challenge Challenge) []string {
+	var names []string
+	for _, name := range strings.Split(challenge.Author, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	for _, tag := range challenge.Tags {
+		if match := authorTagPattern.FindStringSubmatch(strings.TrimSpace(tag)); match != nil {
+			names = append(names, strings.TrimSpace(match[1]))
+		}
+	}
+	return names
+}
+
+// redactNames replaces occurrences of the names in text, regardless of
+// case, and of their @-handles. Names are matched as whole words where they
+// start or end with an ASCII letter or digit, so that "bob" is replaced in
+// "by bob" but not in "bobcat", and a Japanese name within Japanese text is.
+func redactNames(text string, names []string) string {
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "@")
+		if name == "" {
+			continue
+		}
+		pattern := regexp.MustCompile(`(?i)@?` + regexp.QuoteMeta(name))
+		var out strings.Builder
+		last := 0
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+			after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+			first, _ := utf8.DecodeRuneInString(name)
+			end, _ := utf8.DecodeLastRuneInString(name)
+			if (isASCIIWord(first) && isASCIIWord(before)) || (isASCIIWord(end) && isASCIIWord(after)) {
+				continue
+			}
+			out.WriteString(text[last:loc[0]])
+			out.WriteString(anonymousName)
+			last = loc[1]
+		}
+		out.WriteString(text[last:])
+		text = out.String()
+	}
+	return text
+}
+
+func isASCIIWord(r rune) bool {
+	return r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// anonymizeResult removes the authors of a challenge from the parts of its
+// result that reports show: the description, findings, and excerpts. The
+// CODEOWNERS owners and the --diff of fixes, whose context may show any
+// line, are left out.
+func anonymizeResult(result *LintResult, challenge Challenge) {
+	names := authorNames(challenge)
+	result.Description = redactNames(result.Description, names)
+	for _, findings := range [][]Finding{result.Errors, result.Warnings, result.Deprecations} {
+		for i := range findings {
+			findings[i].Message = redactNames(findings[i].Message, names)
+			findings[i].Excerpt = redactNames(findings[i].Excerpt, names)
+		}
+	}
+	result.Owners = nil
+	result.fixDiff = ""
+	result.challenge.Author = ""
+	result.challenge.Name = redactNames(result.challenge.Name, names)
+	var tags []string
+	for _, tag := range result.challenge.Tags {
+		tags = append(tags, redactNames(tag, names))
+	}
+	result.challenge.Tags = tags
+}
+
+// redactedFields returns the challenge.yml keys whose lines excerpts hide
+func redactedFields() []string {
+	if anonymousMode {
+		return append(append([]string{}, secretFields...), anonymousFields...)
+	}
+	return secretFields
+}
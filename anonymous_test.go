@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuthorNames(t *testing.T) {
+	challenge := Challenge{Author: "Alice Smith, @bob ", Tags: []string{"easy", "author: 太郎"}}
+	want := []string{"Alice Smith", "@bob", "太郎"}
+	if got := authorNames(challenge); !reflect.DeepEqual(got, want) {
+		t.Errorf("authorNames() = %v, want %v", got, want)
+	}
+}
+
+func TestRedactNames(t *testing.T) {
+	names := []string{"bob", "Alice Smith", "太郎"}
+	tests := []struct {
+		text, want string
+	}{
+		{"Written by Bob and alice smith.", "Written by (author) and (author)."},
+		{"Ping @bob for hints", "Ping (author) for hints"},
+		{"bobcat and kebob are not bob", "bobcat and kebob are not (author)"},
+		{"作問: 太郎さん", "作問: (author)さん"},
+	}
+	for _, tt := range tests {
+		if got := redactNames(tt.text, names); got != tt.want {
+			t.Errorf("redactNames(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAnonymousLint(t *testing.T) {
+	origMode := anonymousMode
+	defer func() { anonymousMode = origMode }()
+	anonymousMode = true
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "challenge.yml")
+	data := `name: chall
+author: bob
+state: hidden
+notes: "QA: checked by carol, the solver needs 10 minutes"
+description: Made by bob for the finals.
+category: web
+version: "0.1"
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := lintChallengeFile(path)
+	if result.Description != "Made by (author) for the finals." {
+		t.Errorf("Description = %q", result.Description)
+	}
+	var excerpts []string
+	for _, finding := range result.Errors {
+		excerpts = append(excerpts, finding.Excerpt)
+	}
+	all := strings.Join(excerpts, "\n")
+	if !strings.Contains(all, "author: ··· (not shown)") || !strings.Contains(all, "state: hidden") {
+		t.Fatalf("expected the state finding's excerpt, got:\n%s", all)
+	}
+	if strings.Contains(all, "bob") || strings.Contains(all, "carol") {
+		t.Errorf("excerpts show the author or notes:\n%s", all)
+	}
+
+	body := generateCommentBody([]LintResult{result}, true)
+	if strings.Contains(body, "bob") || strings.Contains(body, "carol") {
+		t.Errorf("comment shows the author or notes:\n%s", body)
+	}
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	zipFlagEncrypted = 0x1
+	zipMethodAES     = 99
+	zipExtraAES      = 0x9901
+)
+
+// Defaults of the archive inspection limits
+const (
+	defaultArchiveMaxEntries = 10000
+	defaultArchiveMaxSize    = 1 << 30 // 1 GiB decompressed
+	defaultArchiveMaxDepth   = 3
+)
+
+// zipMagic starts every zip archive
+var zipMagic = []byte("PK\x03\x04")
+
+// ArchiveRule bounds the inspection of zip attachments, protecting the lint
+// run from zip bombs
+type ArchiveRule struct {
+	// MaxEntries limits the entries of an archive, including nested archives (default 10000)
+	MaxEntries int `yaml:"max_entries"`
+	// MaxSize limits the decompressed size of an archive in bytes, including nested archives (default 1 GiB)
+	MaxSize int64 `yaml:"max_size"`
+	// MaxDepth limits how deep archives may be nested in archives (default 3)
+	MaxDepth int `yaml:"max_depth"`
+}
+
+func (r ArchiveRule) withDefaults() ArchiveRule {
+	if r.MaxEntries <= 0 {
+		r.MaxEntries = defaultArchiveMaxEntries
+	}
+	if r.MaxSize <= 0 {
+		r.MaxSize = defaultArchiveMaxSize
+	}
+	if r.MaxDepth <= 0 {
+		r.MaxDepth = defaultArchiveMaxDepth
+	}
+	return r
+}
+
+// archiveBudget tracks what scanning an archive has used of the limits
+type archiveBudget struct {
+	rule    ArchiveRule
+	entries int
+	size    int64
+}
+
+// checkArchiveLimits decompresses each zip attachment, and the zip archives
+// inside it, until a limit is exceeded
+func checkArchiveLimits(challengePath string, challenge Challenge, rule ArchiveRule) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+	rule = rule.withDefaults()
+
+	for _, file := range challenge.Files {
+		if isRemoteFile(file) {
+			continue
+		}
+		reader, err := zip.OpenReader(filepath.Join(baseDir, normalizeFilePath(file)))
+		if err != nil {
+			// Not a zip archive (or missing, which checkFiles reports)
+			continue
+		}
+		budget := &archiveBudget{rule: rule}
+		if err := budget.scan(&reader.Reader, 0); err != nil {
+			errors = append(errors, fmt.Sprintf("Archive '%s' %v", file, err))
+		}
+		reader.Close()
+	}
+
+	return errors
+}
+
+// scan decompresses the entries of an archive at the given nesting depth.
+// Encrypted entries and unsupported compression methods count with their
+// declared size, as they cannot be decompressed.
+func (b *archiveBudget) scan(r *zip.Reader, depth int) error {
+	for _, f := range r.File {
+		b.entries++
+		if b.entries > b.rule.MaxEntries {
+			return fmt.Errorf("has more than %d entries (archives.max_entries)", b.rule.MaxEntries)
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		content, err := f.Open()
+		if err != nil || f.Flags&zipFlagEncrypted != 0 {
+			if content != nil {
+				content.Close()
+			}
+			b.size += int64(f.UncompressedSize64)
+			if b.size > b.rule.MaxSize {
+				return b.sizeError()
+			}
+			continue
+		}
+
+		// Nested archives are scanned too; other entries are only counted
+		reader := bufio.NewReader(io.LimitReader(content, b.rule.MaxSize-b.size+1))
+		magic, _ := reader.Peek(len(zipMagic))
+		if bytes.Equal(magic, zipMagic) {
+			err = b.scanNested(f.Name, reader, depth+1)
+			content.Close()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		n, _ := io.Copy(io.Discard, reader)
+		content.Close()
+		b.size += n
+		if b.size > b.rule.MaxSize {
+			return b.sizeError()
+		}
+	}
+	return nil
+}
+
+// scanNested spools a nested archive to a temporary file and scans it, so
+// that memory use does not grow with archives.max_size
+func (b *archiveBudget) scanNested(name string, r io.Reader, depth int) error {
+	spool, err := os.CreateTemp("", "clilint-archive-*")
+	if err != nil {
+		return fmt.Errorf("could not spool the nested archive '%s': %v", name, err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	n, err := io.Copy(spool, r)
+	b.size += n
+	if b.size > b.rule.MaxSize {
+		return b.sizeError()
+	}
+	if err != nil {
+		// Corrupt entries are left to the tools that extract them
+		return nil
+	}
+	inner, err := zip.NewReader(spool, n)
+	if err != nil {
+		return nil
+	}
+	if depth > b.rule.MaxDepth {
+		return fmt.Errorf("nests archives more than %d levels deep at '%s' (archives.max_depth)", b.rule.MaxDepth, name)
+	}
+	return b.scan(inner, depth)
+}
+
+func (b *archiveBudget) sizeError() error {
+	return fmt.Errorf("decompresses to more than %.2f MB (archives.max_size)", float64(b.rule.MaxSize)/(1024*1024))
+}
+
+// passwordPattern extracts a password stated in a challenge description,
+// e.g. "password: infected", "Password is `s3cr3t`" or "パスワード：abc"
+var passwordPattern = regexp.MustCompile("(?i)(?:password|パスワード)\\s*(?:is\\s+|[:：=]\\s*)[`\"'「]?([^\\s`\"'」]+)")
+
+// statedPassword returns the archive password stated in the description, if any
+func statedPassword(description string) (string, bool) {
+	match := passwordPattern.FindStringSubmatch(description)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+func checkArchivePasswords(challengePath string, challenge Challenge, rule ArchiveRule) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+	password, hasPassword := statedPassword(challenge.Description)
+	rule = rule.withDefaults()
+
+	for _, file := range challenge.Files {
+		if isRemoteFile(file) {
+			continue
+		}
+		reader, err := zip.OpenReader(filepath.Join(baseDir, normalizeFilePath(file)))
+		if err != nil {
+			// Not a zip archive (or missing, which checkFiles reports)
+			continue
+		}
+
+		var encrypted *zip.File
+		for _, f := range reader.File {
+			if f.Flags&zipFlagEncrypted != 0 && !f.FileInfo().IsDir() {
+				encrypted = f
+				break
+			}
+		}
+
+		switch {
+		case encrypted == nil && hasPassword:
+			errors = append(errors, fmt.Sprintf("Archive '%s' is not password-protected but the description states a password", file))
+		case encrypted != nil && !hasPassword:
+			errors = append(errors, fmt.Sprintf("Archive '%s' is password-protected but the description does not state the password", file))
+		case encrypted != nil:
+			ok, err := verifyZipPassword(encrypted, password, rule.MaxSize)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Archive '%s' could not be verified: %v", file, err))
+			} else if !ok {
+				errors = append(errors, fmt.Sprintf("Archive '%s' cannot be opened with the password stated in the description", file))
+			}
+		}
+		reader.Close()
+	}
+
+	return errors
+}
+
+// verifyZipPassword checks the password against an encrypted entry using
+// either traditional PKWARE (ZipCrypto) or WinZip AES encryption. ZipCrypto
+// entries are decompressed to check their CRC, up to maxSize bytes.
+func verifyZipPassword(f *zip.File, password string, maxSize int64) (bool, error) {
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return false, err
+	}
+
+	if f.Method == zipMethodAES {
+		data, err := io.ReadAll(raw)
+		if err != nil {
+			return false, err
+		}
+		return verifyAESPassword(f, data, password)
+	}
+	return verifyZipCryptoPassword(f, raw, password, maxSize)
+}
+
+// zipCryptoKeys implements the traditional PKWARE stream cipher
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	keys := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+	for i := 0; i < len(password); i++ {
+		keys.update(password[i])
+	}
+	return keys
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ (crc >> 8)
+}
+
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = crc32Update(k[0], b)
+	k[1] = (k[1]+(k[0]&0xff))*134775813 + 1
+	k[2] = crc32Update(k[2], byte(k[1]>>24))
+}
+
+// zipCryptoReader decrypts a ZipCrypto stream as it is read
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+func (z *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	for i, c := range p[:n] {
+		temp := uint16(z.keys[2]) | 2
+		p[i] = c ^ byte((uint32(temp)*uint32(temp^1))>>8)
+		z.keys.update(p[i])
+	}
+	return n, err
+}
+
+// verifyZipCryptoPassword decrypts and decompresses an entry as a stream
+// into its CRC, so that an encrypted zip bomb is never held in memory
+func verifyZipCryptoPassword(f *zip.File, raw io.Reader, password string, maxSize int64) (bool, error) {
+	plain := &zipCryptoReader{r: raw, keys: newZipCryptoKeys(password)}
+	if _, err := io.ReadFull(plain, make([]byte, 12)); err != nil {
+		return false, fmt.Errorf("encryption header is truncated")
+	}
+
+	content, err := decompressZipEntry(f.Method, plain)
+	if err != nil {
+		return false, nil
+	}
+	crc := crc32.NewIEEE()
+	n, err := io.Copy(crc, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		// Wrong passwords produce garbage that fails to inflate
+		return false, nil
+	}
+	if n > maxSize {
+		return false, fmt.Errorf("decompresses to more than %.2f MB (archives.max_size)", float64(maxSize)/(1024*1024))
+	}
+	return crc.Sum32() == f.CRC32, nil
+}
+
+func decompressZipEntry(method uint16, r io.Reader) (io.Reader, error) {
+	switch method {
+	case zip.Store:
+		return r, nil
+	case zip.Deflate:
+		return flate.NewReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression method %d", method)
+	}
+}
+
+func verifyAESPassword(f *zip.File, data []byte, password string) (bool, error) {
+	strength, err := zipAESStrength(f.Extra)
+	if err != nil {
+		return false, err
+	}
+	keyLen := 8 + 8*strength // 16, 24 or 32 bytes
+	saltLen := keyLen / 2
+	if len(data) < saltLen+2+10 {
+		return false, fmt.Errorf("AES encrypted data is truncated")
+	}
+
+	salt := data[:saltLen]
+	verifier := data[saltLen : saltLen+2]
+	encrypted := data[saltLen+2 : len(data)-10]
+	authCode := data[len(data)-10:]
+
+	key := pbkdf2.Key([]byte(password), salt, 1000, 2*keyLen+2, sha1.New)
+	if !bytes.Equal(key[2*keyLen:], verifier) {
+		return false, nil
+	}
+
+	mac := hmac.New(sha1.New, key[keyLen:2*keyLen])
+	mac.Write(encrypted)
+	return hmac.Equal(mac.Sum(nil)[:10], authCode), nil
+}
+
+// zipAESStrength returns the WinZip AES strength (1: 128, 2: 192, 3: 256 bit)
+func zipAESStrength(extra []byte) (int, error) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra[0:2])
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if len(extra) < 4+size {
+			break
+		}
+		if id == zipExtraAES && size >= 7 {
+			strength := int(extra[4+4])
+			if strength < 1 || strength > 3 {
+				return 0, fmt.Errorf("unknown AES strength %d", strength)
+			}
+			return strength, nil
+		}
+		extra = extra[4+size:]
+	}
+	return 0, fmt.Errorf("missing AES extra field")
+}
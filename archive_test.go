@@ -0,0 +1,252 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// writeTestZip writes a zip with one stored entry, optionally encrypted with
+// ZipCrypto ("zipcrypto") or WinZip AES-256 ("aes")
+func writeTestZip(t *testing.T, path, encryption, password string) {
+	t.Helper()
+
+	content := []byte("flag{zip_password}")
+	header := &zip.FileHeader{
+		Name:               "secret.txt",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(content),
+		UncompressedSize64: uint64(len(content)),
+	}
+
+	var data []byte
+	switch encryption {
+	case "zipcrypto":
+		header.Flags |= zipFlagEncrypted
+		plain := append([]byte("0123456789A"), byte(header.CRC32>>24))
+		plain = append(plain, content...)
+		keys := newZipCryptoKeys(password)
+		for _, p := range plain {
+			temp := uint16(keys[2]) | 2
+			data = append(data, p^byte((uint32(temp)*uint32(temp^1))>>8))
+			keys.update(p)
+		}
+	case "aes":
+		header.Flags |= zipFlagEncrypted
+		header.Method = zipMethodAES
+		header.CRC32 = 0
+		extra := make([]byte, 11)
+		binary.LittleEndian.PutUint16(extra[0:], zipExtraAES)
+		binary.LittleEndian.PutUint16(extra[2:], 7)
+		binary.LittleEndian.PutUint16(extra[4:], 2) // AE-2
+		copy(extra[6:], "AE")
+		extra[8] = 3 // AES-256
+		binary.LittleEndian.PutUint16(extra[9:], zip.Store)
+		header.Extra = extra
+
+		salt := []byte("0123456789abcdef")
+		key := pbkdf2.Key([]byte(password), salt, 1000, 66, sha1.New)
+		ciphertext := []byte("not really encrypted")
+		mac := hmac.New(sha1.New, key[32:64])
+		mac.Write(ciphertext)
+		data = append(append(append(append([]byte{}, salt...), key[64:]...), ciphertext...), mac.Sum(nil)[:10]...)
+	default:
+		data = content
+	}
+	header.CompressedSize64 = uint64(len(data))
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	entry, err := w.CreateRaw(header)
+	if err != nil {
+		t.Fatalf("Failed to create zip entry: %v", err)
+	}
+	if _, err := entry.Write(data); err != nil {
+		t.Fatalf("Failed to write zip entry: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+}
+
+func TestCheckArchivePasswords(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	writeTestZip(t, filepath.Join(tempDir, "plain.zip"), "", "")
+	writeTestZip(t, filepath.Join(tempDir, "zipcrypto.zip"), "zipcrypto", "infected")
+	writeTestZip(t, filepath.Join(tempDir, "aes.zip"), "aes", "s3cr3t")
+
+	tests := []struct {
+		name        string
+		file        string
+		description string
+		wantError   string
+	}{
+		{name: "plain archive without password", file: "plain.zip", description: "Find the flag."},
+		{name: "plain archive described as protected", file: "plain.zip", description: "password: infected", wantError: "is not password-protected but the description states a password"},
+		{name: "zipcrypto with correct password", file: "zipcrypto.zip", description: "The password is `infected`."},
+		{name: "zipcrypto with wrong password", file: "zipcrypto.zip", description: "Password: wrong", wantError: "cannot be opened with the password stated in the description"},
+		{name: "zipcrypto without stated password", file: "zipcrypto.zip", description: "Good luck!", wantError: "does not state the password"},
+		{name: "aes with correct password", file: "aes.zip", description: "パスワード：s3cr3t"},
+		{name: "aes with wrong password", file: "aes.zip", description: "password=s3cr3", wantError: "cannot be opened with the password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := Challenge{Description: tt.description, Files: []string{tt.file}}
+			errs := checkArchivePasswords(challengePath, challenge, ArchiveRule{})
+			if tt.wantError == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no errors, got: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantError) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantError, errs)
+			}
+		})
+	}
+}
+
+func TestCheckArchivePasswordsBoundsDecompression(t *testing.T) {
+	// A small ZipCrypto entry inflating to 4 MiB of zeros
+	content := make([]byte, 4<<20)
+	var compressed bytes.Buffer
+	deflater, _ := flate.NewWriter(&compressed, flate.BestCompression)
+	deflater.Write(content)
+	deflater.Close()
+
+	header := &zip.FileHeader{Name: "bomb.bin", Method: zip.Deflate, Flags: zipFlagEncrypted, CRC32: crc32.ChecksumIEEE(content), UncompressedSize64: uint64(len(content))}
+	plain := append(append([]byte("0123456789A"), byte(header.CRC32>>24)), compressed.Bytes()...)
+	var data []byte
+	keys := newZipCryptoKeys("infected")
+	for _, p := range plain {
+		temp := uint16(keys[2]) | 2
+		data = append(data, p^byte((uint32(temp)*uint32(temp^1))>>8))
+		keys.update(p)
+	}
+	header.CompressedSize64 = uint64(len(data))
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	entry, err := w.CreateRaw(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Write(data)
+	w.Close()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bomb.zip"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := Challenge{Description: "password: infected", Files: []string{"bomb.zip"}}
+	if errs := checkArchivePasswords(filepath.Join(dir, "challenge.yml"), challenge, ArchiveRule{}); len(errs) != 0 {
+		t.Errorf("Expected the password to verify within the default limit, got: %v", errs)
+	}
+	errs := checkArchivePasswords(filepath.Join(dir, "challenge.yml"), challenge, ArchiveRule{MaxSize: 1 << 20})
+	if len(errs) != 1 || !strings.Contains(errs[0], "archives.max_size") {
+		t.Errorf("Expected archives.max_size to bound the password check, got: %v", errs)
+	}
+}
+
+// zipOf builds a zip archive in memory from name/content pairs
+func zipOf(t *testing.T, entries ...[2][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		f, err := w.Create(string(entry[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(entry[1])
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCheckArchiveLimits(t *testing.T) {
+	entry := func(name string, content []byte) [2][]byte { return [2][]byte{[]byte(name), content} }
+	nested := zipOf(t, entry("flag.txt", []byte("flag{nested}")))
+	for i := 0; i < 3; i++ {
+		nested = zipOf(t, entry(fmt.Sprintf("level%d.zip", i), nested))
+	}
+
+	tests := []struct {
+		name    string
+		archive []byte
+		rule    ArchiveRule
+		want    string
+	}{
+		{name: "within limits", archive: zipOf(t, entry("a.txt", []byte("a")), entry("b.txt", []byte("b")))},
+		{name: "too many entries", archive: zipOf(t, entry("a.txt", nil), entry("b.txt", nil), entry("c.txt", nil)), rule: ArchiveRule{MaxEntries: 2},
+			want: "Archive 'dist.zip' has more than 2 entries (archives.max_entries)"},
+		{name: "too large", archive: zipOf(t, entry("zeros", make([]byte, 2*1024*1024))), rule: ArchiveRule{MaxSize: 1024 * 1024},
+			want: "Archive 'dist.zip' decompresses to more than 1.00 MB (archives.max_size)"},
+		{name: "nested within depth", archive: nested},
+		{name: "nested too deep", archive: nested, rule: ArchiveRule{MaxDepth: 2},
+			want: "Archive 'dist.zip' nests archives more than 2 levels deep at 'level0.zip' (archives.max_depth)"},
+		{name: "nested entries count", archive: nested, rule: ArchiveRule{MaxEntries: 3},
+			want: "Archive 'dist.zip' has more than 3 entries (archives.max_entries)"},
+		{name: "nested too large", archive: zipOf(t, entry("inner.zip", zipOf(t, entry("zeros", make([]byte, 2*1024*1024))))), rule: ArchiveRule{MaxSize: 1024 * 1024},
+			want: "Archive 'dist.zip' decompresses to more than 1.00 MB (archives.max_size)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nested archives are spooled to temporary files, removed after the scan
+			spoolDir := t.TempDir()
+			t.Setenv("TMPDIR", spoolDir)
+			defer func() {
+				if spooled, _ := os.ReadDir(spoolDir); len(spooled) != 0 {
+					t.Errorf("spooled archives left behind: %v", spooled)
+				}
+			}()
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "dist.zip"), tt.archive, 0644); err != nil {
+				t.Fatal(err)
+			}
+			challenge := Challenge{Files: []string{"dist.zip", "https://example.com/remote.zip"}}
+			errors := checkArchiveLimits(filepath.Join(dir, "challenge.yml"), challenge, tt.rule)
+			if tt.want == "" {
+				if len(errors) > 0 {
+					t.Errorf("unexpected errors %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0] != tt.want {
+				t.Errorf("errors = %v, want [%s]", errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveLimitsSecurityFinding(t *testing.T) {
+	result := LintResult{ruleTimeout: defaultRuleTimeout}
+	result.checkSecurity("archive-limits", func() []string { return []string{"Archive 'dist.zip' has more than 2 entries (archives.max_entries)"} })
+	if len(result.Errors) != 1 || result.Errors[0].Severity != SeveritySecurity || result.Errors[0].Field != "files" {
+		t.Fatalf("unexpected findings %+v", result.Errors)
+	}
+
+	var out bytes.Buffer
+	writeResults(&out, []LintResult{{File: "dist/challenge.yml", Errors: result.Errors}})
+	if !strings.Contains(out.String(), "  - 🛡️  Archive 'dist.zip' has more than 2 entries") {
+		t.Errorf("output:\n%s", out.String())
+	}
+}
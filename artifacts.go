@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultArtifactPatterns are denied in challenge directories unless
+// artifacts.deny is set
+var defaultArtifactPatterns = []string{
+	".env",
+	".env.local",
+	"node_modules/",
+	".idea/",
+	".vscode/",
+	"__pycache__/",
+	"*.pyc",
+	".DS_Store",
+	"*.swp",
+}
+
+// ArtifactsRule configures the files that must not be committed in
+// challenge directories, independently of .gitignore
+type ArtifactsRule struct {
+	// Deny lists gitignore-style patterns relative to the challenge
+	// directory; patterns ending in '/' only match directories. An empty
+	// list disables the rule.
+	Deny []string `yaml:"deny"`
+}
+
+func (r ArtifactsRule) patterns() []string {
+	if r.Deny == nil {
+		return defaultArtifactPatterns
+	}
+	return r.Deny
+}
+
+// checkArtifacts reports files of the challenge directory matching the deny
+// patterns, and compiled solve scripts, which should never be committed or
+// deployed. Challenges nested in the directory are left to their own lint.
+func checkArtifacts(challengePath string, rule ArtifactsRule) []string {
+	var warnings []string
+
+	patterns := rule.patterns()
+	if len(patterns) == 0 {
+		return warnings
+	}
+	baseDir := filepath.Dir(challengePath)
+
+	filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == baseDir {
+			return nil
+		}
+		rel, _ := filepath.Rel(baseDir, path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "challenge.yml")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		if pattern := matchArtifact(rel, d.IsDir(), patterns); pattern != "" {
+			if d.IsDir() {
+				warnings = append(warnings, fmt.Sprintf("Challenge directory contains '%s/', which should not be committed (artifacts.deny '%s')", rel, pattern))
+				return filepath.SkipDir
+			}
+			warnings = append(warnings, fmt.Sprintf("Challenge directory contains '%s', which should not be committed (artifacts.deny '%s')", rel, pattern))
+			return nil
+		}
+
+		if d.Type().IsRegular() && solveScriptNames[strings.ToLower(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))] {
+			if info := parseBinary(path); info != nil {
+				warnings = append(warnings, fmt.Sprintf("Challenge directory contains compiled solver '%s' (%s); commit its source instead", rel, info.Format))
+			}
+		}
+		return nil
+	})
+
+	return warnings
+}
+
+// matchArtifact returns the first deny pattern matching relPath, or ""
+func matchArtifact(relPath string, isDir bool, patterns []string) string {
+	for _, pattern := range patterns {
+		if isIgnored(relPath, isDir, []string{pattern}) {
+			return pattern
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckArtifacts(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, file := range []string{
+		"challenge.yml",
+		".env",
+		".env.example",
+		"src/app.py",
+		"src/__pycache__/app.cpython-311.pyc",
+		"web/node_modules/express/index.js",
+		".idea/workspace.xml",
+		"solve.py",
+		"nested/challenge.yml",
+		"nested/.env",
+		".git/config",
+	} {
+		path := filepath.Join(tempDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Failed to find the test binary: %v", err)
+	}
+	data, err := os.ReadFile(executable)
+	if err != nil {
+		t.Fatalf("Failed to read the test binary: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "solver"), data, 0755); err != nil {
+		t.Fatalf("Failed to create solver: %v", err)
+	}
+	format := parseBinary(executable)
+	if format == nil {
+		t.Skip("the test binary is neither ELF nor PE")
+	}
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+
+	tests := []struct {
+		name string
+		rule ArtifactsRule
+		want []string
+	}{
+		{
+			name: "default patterns",
+			want: []string{
+				"Challenge directory contains '.env', which should not be committed (artifacts.deny '.env')",
+				"Challenge directory contains '.idea/', which should not be committed (artifacts.deny '.idea/')",
+				"Challenge directory contains compiled solver 'solver' (" + format.Format + "); commit its source instead",
+				"Challenge directory contains 'src/__pycache__/', which should not be committed (artifacts.deny '__pycache__/')",
+				"Challenge directory contains 'web/node_modules/', which should not be committed (artifacts.deny 'node_modules/')",
+			},
+		},
+		{
+			name: "configured patterns",
+			rule: ArtifactsRule{Deny: []string{".env*", "src/*.py"}},
+			want: []string{
+				"Challenge directory contains '.env', which should not be committed (artifacts.deny '.env*')",
+				"Challenge directory contains '.env.example', which should not be committed (artifacts.deny '.env*')",
+				"Challenge directory contains compiled solver 'solver' (" + format.Format + "); commit its source instead",
+				"Challenge directory contains 'src/app.py', which should not be committed (artifacts.deny 'src/*.py')",
+			},
+		},
+		{
+			name: "disabled",
+			rule: ArtifactsRule{Deny: []string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkArtifacts(challengePath, tt.rule)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkArtifacts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
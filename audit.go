@@ -0,0 +1,545 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// auditMode is set by --audit: challenges are also checked for live hosts
+// and images in their registry, and a summary with the changes since the
+// previous audit is posted
+var auditMode bool
+
+// defaultAuditState is the file the findings of the last audit are kept in
+const defaultAuditState = ".clilint-audit.json"
+
+// defaultAuditLabel marks audit summary issues
+const defaultAuditLabel = "clilint-audit"
+
+// auditListLimit is the number of findings listed per summary section
+const auditListLimit = 50
+
+// discordMessageLimit is the length limit of a Discord message
+const discordMessageLimit = 2000
+
+// dialTimeout bounds connecting to a challenge host
+const dialTimeout = 10 * time.Second
+
+// registryScheme is the scheme of registry API requests, replaced in tests
+var registryScheme = "https"
+
+// bearerParamPattern matches the parameters of a WWW-Authenticate Bearer challenge
+var bearerParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// maxManifestSize bounds the manifests read from registries
+const maxManifestSize = 4 << 20
+
+// manifestMediaTypes are the manifest types accepted from registries
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// AuditRule configures --audit
+type AuditRule struct {
+	// State is the file the findings of the last audit are kept in, to
+	// report changes (default .clilint-audit.json); cache or commit it
+	State string `yaml:"state"`
+	// Issue posts the summary as a GitHub issue and closes the previous one
+	Issue bool `yaml:"issue"`
+	// Label marks summary issues (default clilint-audit)
+	Label string `yaml:"label"`
+}
+
+// AuditState is the result of one audit
+type AuditState struct {
+	Timestamp time.Time `json:"timestamp"`
+	Ref       string    `json:"ref,omitempty"`
+	// Findings lists the findings of each challenge file, e.g. "❌ [host-live] ..."
+	Findings map[string][]string `json:"findings"`
+}
+
+// AuditDelta compares an audit with the previous one
+type AuditDelta struct {
+	Previous *AuditState
+	Current  AuditState
+	Errors   int
+	Warnings int
+	// Failing lists the challenges with errors and their error count
+	Failing  map[string]int
+	New      []string // "`file`: finding"
+	Resolved []string
+}
+
+// checkHostLive connects to the challenge host: URLs of http(s) hosts must
+// respond without a server error, other hosts must accept a TCP connection
+func checkHostLive(host interface{}) []string {
+	var errors []string
+
+	target := ""
+	switch v := host.(type) {
+	case string:
+		target = v
+	case map[string]interface{}:
+		name, _ := v["host"].(string)
+		port, hasPort := v["port"]
+		protocol, _ := v["protocol"].(string)
+		if name == "" {
+			return errors
+		}
+		switch {
+		case protocol == "http" || protocol == "https":
+			target = protocol + "://" + name
+			if hasPort {
+				target = fmt.Sprintf("%s:%v", target, port)
+			}
+		case protocol == "udp" || !hasPort:
+			// Nothing to connect to without a port or over UDP
+			return errors
+		default:
+			target = fmt.Sprintf("tcp://%s", net.JoinHostPort(name, fmt.Sprint(port)))
+		}
+	default:
+		return errors
+	}
+	if target == "" || strings.Contains(target, "${") || strings.Contains(target, "{{") {
+		return errors
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		// Reported by checkHost
+		return errors
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		resp, err := httpClient.Get(target)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Host '%s' is unreachable: %v", target, err))
+			return errors
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			errors = append(errors, fmt.Sprintf("Host '%s' returned HTTP %d", target, resp.StatusCode))
+		}
+		return errors
+	}
+	if u.Port() == "" {
+		return errors
+	}
+	conn, err := net.DialTimeout("tcp", u.Host, dialTimeout)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Host '%s' is unreachable: %v", u.Host, err))
+		return errors
+	}
+	conn.Close()
+	return errors
+}
+
+// checkImageRegistry looks up the manifest of a registry image reference
+func checkImageRegistry(image interface{}) []string {
+	var errors []string
+
+	reference, manifestURL, ok := imageManifestURL(image)
+	if !ok {
+		return errors
+	}
+	status, _, err := requestManifest(http.MethodHead, manifestURL)
+	switch {
+	case err != nil:
+		errors = append(errors, fmt.Sprintf("Registry lookup of image '%s' failed: %v", reference, err))
+	case status == http.StatusNotFound:
+		errors = append(errors, fmt.Sprintf("Image '%s' does not exist in its registry", reference))
+	case status < 200 || status >= 300:
+		errors = append(errors, fmt.Sprintf("Registry lookup of image '%s' failed: HTTP %d", reference, status))
+	}
+	return errors
+}
+
+// imageManifestURL returns the registry reference of an image field and the
+// URL of its manifest; ok is false for build contexts, templated references,
+// and references checkImage reports as invalid
+func imageManifestURL(image interface{}) (reference, manifestURL string, ok bool) {
+	switch v := image.(type) {
+	case string:
+		if v != "." && !strings.HasPrefix(v, "./") {
+			reference = v
+		}
+	case map[string]interface{}:
+		reference, _, _ = parseImageMap(v)
+	}
+	if reference == "" || strings.Contains(reference, "${") || strings.Contains(reference, "{{") {
+		return reference, "", false
+	}
+	ref, ok := parseImageReference(reference)
+	if !ok {
+		return reference, "", false
+	}
+
+	domain, repository := "registry-1.docker.io", ref.repository
+	components := strings.SplitN(repository, "/", 2)
+	if len(components) == 2 && (strings.ContainsAny(components[0], ".:") || components[0] == "localhost") {
+		domain, repository = components[0], components[1]
+		if domain == "docker.io" {
+			domain = "registry-1.docker.io"
+		}
+	}
+	if domain == "registry-1.docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	tag := ref.digest
+	if tag == "" {
+		tag = ref.tag
+	}
+	if tag == "" {
+		tag = "latest"
+	}
+	return reference, fmt.Sprintf("%s://%s/v2/%s/manifests/%s", registryScheme, domain, repository, tag), true
+}
+
+// requestManifest requests a manifest, fetching an anonymous token when the
+// registry asks for one, and returns the status and, for GET, the body
+func requestManifest(method, manifestURL string) (int, []byte, error) {
+	return requestRegistry(method, manifestURL, manifestMediaTypes, maxManifestSize)
+}
+
+// requestRegistry requests a registry API URL with the anonymous token flow,
+// reading at most limit bytes of the body
+func requestRegistry(method, registryURL string, accept []string, limit int64) (int, []byte, error) {
+	request := func(token string) (*http.Response, []byte, error) {
+		req, err := http.NewRequest(method, registryURL, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp, body, nil
+	}
+
+	resp, body, err := request("")
+	if err != nil {
+		return 0, nil, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if resp.StatusCode != http.StatusUnauthorized || !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return resp.StatusCode, body, nil
+	}
+
+	params := make(map[string]string)
+	for _, match := range bearerParamPattern.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil || tokenURL.Host == "" {
+		return resp.StatusCode, body, nil
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	tokenResp, err := httpClient.Get(tokenURL.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tokenResp.Body.Close()
+	if tokenResp.StatusCode != http.StatusOK {
+		return tokenResp.StatusCode, nil, nil
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(tokenResp.Body).Decode(&token); err != nil {
+		return 0, nil, fmt.Errorf("invalid token response: %v", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	resp, body, err = request(token.Token)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// auditStateOf records the findings of each challenge
+func auditStateOf(results []LintResult, ref string) AuditState {
+	state := AuditState{Timestamp: now().UTC(), Ref: ref, Findings: make(map[string][]string)}
+	for _, result := range results {
+		var findings []string
+		for _, finding := range result.Errors {
+			findings = append(findings, fmt.Sprintf("❌ [%s] %s", historyRule(finding), finding.Message))
+		}
+		for _, finding := range result.Warnings {
+			findings = append(findings, fmt.Sprintf("⚠️ [%s] %s", historyRule(finding), finding.Message))
+		}
+		state.Findings[result.File] = findings
+	}
+	return state
+}
+
+// loadAuditState reads the previous audit, or returns nil when there is none
+func loadAuditState(path string) (*AuditState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state AuditState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return &state, nil
+}
+
+func saveAuditState(path string, state AuditState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// compareAudits lists the findings that appeared or disappeared since the
+// previous audit; challenges not linted this time are left out
+func compareAudits(previous *AuditState, current AuditState) AuditDelta {
+	delta := AuditDelta{Previous: previous, Current: current, Failing: make(map[string]int)}
+
+	files := make([]string, 0, len(current.Findings))
+	for file := range current.Findings {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		findings := current.Findings[file]
+		for _, finding := range findings {
+			if strings.HasPrefix(finding, "❌") {
+				delta.Errors++
+				delta.Failing[file]++
+			} else {
+				delta.Warnings++
+			}
+		}
+		if previous == nil {
+			continue
+		}
+		before := make(map[string]bool)
+		for _, finding := range previous.Findings[file] {
+			before[finding] = true
+		}
+		after := make(map[string]bool)
+		for _, finding := range findings {
+			after[finding] = true
+			if !before[finding] {
+				delta.New = append(delta.New, "`"+file+"`: "+finding)
+			}
+		}
+		for _, finding := range previous.Findings[file] {
+			if !after[finding] {
+				delta.Resolved = append(delta.Resolved, "`"+file+"`: "+finding)
+			}
+		}
+	}
+	return delta
+}
+
+// auditSummary renders an audit and its changes as Markdown
+func auditSummary(delta AuditDelta) string {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "## 🔍 clilint audit %s\n\n", delta.Current.Timestamp.Format("2006-01-02"))
+	fmt.Fprintf(&summary, "%d challenge(s): %d error(s), %d warning(s)", len(delta.Current.Findings), delta.Errors, delta.Warnings)
+
+	if delta.Previous == nil {
+		summary.WriteString("\n\nFirst audit: there is no previous audit to compare with.\n")
+	} else {
+		previous := compareAudits(nil, *delta.Previous)
+		fmt.Fprintf(&summary, " (%s errors, %s warnings since %s)\n",
+			signed(delta.Errors-previous.Errors), signed(delta.Warnings-previous.Warnings), delta.Previous.Timestamp.Format("2006-01-02"))
+		writeAuditList(&summary, fmt.Sprintf("🆕 New findings (%d)", len(delta.New)), delta.New)
+		writeAuditList(&summary, fmt.Sprintf("✅ Resolved findings (%d)", len(delta.Resolved)), delta.Resolved)
+	}
+
+	failing := make([]string, 0, len(delta.Failing))
+	for file, count := range delta.Failing {
+		failing = append(failing, fmt.Sprintf("`%s`: %d error(s)", file, count))
+	}
+	sort.Strings(failing)
+	writeAuditList(&summary, fmt.Sprintf("❌ Challenges with errors (%d)", len(failing)), failing)
+	return summary.String()
+}
+
+func writeAuditList(summary *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(summary, "\n### %s\n\n", title)
+	for i, item := range items {
+		if i == auditListLimit {
+			fmt.Fprintf(summary, "- …and %d more\n", len(items)-auditListLimit)
+			break
+		}
+		fmt.Fprintf(summary, "- %s\n", item)
+	}
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return "+" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// postAudit compares the results with the previous audit, prints the summary,
+// posts it to the configured GitHub issue and Discord webhook, and saves the
+// results for the next audit. Failures to post are reported as warnings.
+func postAudit(results []LintResult) {
+	if !auditMode {
+		return
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Printf("Warning: failed to post audit: %v", err)
+		return
+	}
+	statePath := config.Audit.State
+	if statePath == "" {
+		statePath = defaultAuditState
+	}
+
+	previous, err := loadAuditState(statePath)
+	if err != nil {
+		log.Printf("Warning: ignoring previous audit: %v", err)
+	}
+	current := auditStateOf(results, currentRef())
+	summary := auditSummary(compareAudits(previous, current))
+	fmt.Println(summary)
+
+	if config.Audit.Issue {
+		if err := postAuditIssue(config.Audit, current.Timestamp, summary); err != nil {
+			log.Printf("Warning: failed to post audit issue: %v", err)
+		}
+	}
+	if webhook := os.Getenv("DISCORD_WEBHOOK_URL"); webhook != "" {
+		if err := postDiscord(webhook, summary); err != nil {
+			log.Printf("Warning: failed to post audit to Discord: %v", err)
+		}
+	}
+	if err := saveAuditState(statePath, current); err != nil {
+		log.Printf("Warning: failed to save audit state: %v", err)
+	}
+}
+
+// postAuditIssue opens an issue with the summary and closes the previous
+// summary issues, so that one issue describes the latest audit
+func postAuditIssue(rule AuditRule, timestamp time.Time, summary string) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	repository := os.Getenv("INPUT_REPOSITORY")
+	if repository == "" {
+		repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	owner, repo, ok := strings.Cut(repository, "/")
+	if token == "" || !ok {
+		return fmt.Errorf("GITHUB_TOKEN and GITHUB_REPOSITORY (owner/repo) are required")
+	}
+	label := rule.Label
+	if label == "" {
+		label = defaultAuditLabel
+	}
+	client, ctx := getGitHubClient(token)
+
+	var previous []*github.Issue
+	opt := &github.IssueListByRepoOptions{State: "open", Labels: []string{label}, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
+		if err != nil {
+			return fmt.Errorf("error listing issues: %v", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				previous = append(previous, issue)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	title := "clilint audit " + timestamp.Format("2006-01-02")
+	labels := []string{label}
+	created, _, err := client.Issues.Create(ctx, owner, repo, &github.IssueRequest{Title: &title, Body: &summary, Labels: &labels})
+	if err != nil {
+		return fmt.Errorf("error creating issue: %v", err)
+	}
+	fmt.Printf("🔍 Posted audit to issue #%d\n", created.GetNumber())
+
+	for _, issue := range previous {
+		comment := fmt.Sprintf("Superseded by #%d.", created.GetNumber())
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, issue.GetNumber(), &github.IssueComment{Body: &comment}); err != nil {
+			return fmt.Errorf("error commenting on issue #%d: %v", issue.GetNumber(), err)
+		}
+		if _, _, err := client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{State: github.String("closed")}); err != nil {
+			return fmt.Errorf("error closing issue #%d: %v", issue.GetNumber(), err)
+		}
+	}
+	return nil
+}
+
+// postDiscord sends the summary to a Discord webhook, truncated to fit a message
+func postDiscord(webhook, summary string) error {
+	content := []rune(summary)
+	if len(content) > discordMessageLimit {
+		content = append(content[:discordMessageLimit-1], '…')
+	}
+	body, err := json.Marshal(map[string]string{"content": string(content)})
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The webhook URL is a secret; keep it out of the log
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckHostLive(t *testing.T) {
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer web.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, openPort, _ := net.SplitHostPort(listener.Addr().String())
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+	_, closedPort, _ := net.SplitHostPort(closedAddr)
+
+	tests := []struct {
+		name string
+		host interface{}
+		want string
+	}{
+		{name: "null", host: nil},
+		{name: "live URL", host: web.URL},
+		{name: "server error", host: web.URL + "/broken", want: "returned HTTP 502"},
+		{name: "tcp URL", host: "tcp://127.0.0.1:" + openPort},
+		{name: "tcp map", host: map[string]interface{}{"host": "127.0.0.1", "port": openPort, "protocol": "tcp"}},
+		{name: "closed port", host: map[string]interface{}{"host": "127.0.0.1", "port": closedPort}, want: "Host '" + closedAddr + "' is unreachable"},
+		{name: "udp", host: map[string]interface{}{"host": "127.0.0.1", "port": closedPort, "protocol": "udp"}},
+		{name: "no port", host: map[string]interface{}{"host": "127.0.0.1"}},
+		{name: "placeholder", host: "https://${WEB_HOST}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := checkHostLive(tt.host)
+			if tt.want == "" {
+				if len(errors) > 0 {
+					t.Errorf("unexpected errors %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.want) {
+				t.Errorf("errors = %v, want one containing %q", errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckImageRegistry(t *testing.T) {
+	var registry *httptest.Server
+	registry = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:ctf/web:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"token": "pull-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:ctf/web:pull"`, registry.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/ctf/web/manifests/v1" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	origClient := httpClient
+	defer func() { httpClient = origClient }()
+	httpClient = registry.Client()
+	domain := strings.TrimPrefix(registry.URL, "https://")
+
+	if errors := checkImageRegistry(domain + "/ctf/web:v1"); len(errors) > 0 {
+		t.Errorf("unexpected errors %v", errors)
+	}
+	if errors := checkImageRegistry(map[string]interface{}{"name": "ctf/web:v1", "registry": domain}); len(errors) > 0 {
+		t.Errorf("unexpected errors for map image %v", errors)
+	}
+	want := []string{fmt.Sprintf("Image '%s/ctf/web:v2' does not exist in its registry", domain)}
+	if errors := checkImageRegistry(domain + "/ctf/web:v2"); !reflect.DeepEqual(errors, want) {
+		t.Errorf("errors = %v, want %v", errors, want)
+	}
+	if errors := checkImageRegistry("."); len(errors) > 0 {
+		t.Errorf("build context was looked up: %v", errors)
+	}
+}
+
+func TestAuditSummary(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC) }
+
+	previous := &AuditState{
+		Timestamp: time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC),
+		Findings: map[string][]string{
+			"web/a/challenge.yml": {"❌ [host-live] Host 'https://a.example.com' returned HTTP 502"},
+			"web/b/challenge.yml": {"⚠️ [tags] Tag 'Web' should be lowercase"},
+		},
+	}
+	results := []LintResult{
+		{File: "web/a/challenge.yml"},
+		{File: "web/b/challenge.yml", Errors: []Finding{{RuleID: "image-registry", Message: "Image 'ctf/b:v1' does not exist in its registry"}},
+			Warnings: []Finding{{RuleID: "tags", Message: "Tag 'Web' should be lowercase"}}},
+	}
+	delta := compareAudits(previous, auditStateOf(results, ""))
+	if delta.Errors != 1 || delta.Warnings != 1 {
+		t.Errorf("counts = %d errors, %d warnings", delta.Errors, delta.Warnings)
+	}
+	wantNew := []string{"`web/b/challenge.yml`: ❌ [image-registry] Image 'ctf/b:v1' does not exist in its registry"}
+	wantResolved := []string{"`web/a/challenge.yml`: ❌ [host-live] Host 'https://a.example.com' returned HTTP 502"}
+	if !reflect.DeepEqual(delta.New, wantNew) || !reflect.DeepEqual(delta.Resolved, wantResolved) {
+		t.Errorf("new = %v, resolved = %v", delta.New, delta.Resolved)
+	}
+
+	summary := auditSummary(delta)
+	for _, line := range []string{
+		"## 🔍 clilint audit 2026-10-16\n",
+		"2 challenge(s): 1 error(s), 1 warning(s) (0 errors, 0 warnings since 2026-10-15)\n",
+		"### 🆕 New findings (1)\n",
+		"### ✅ Resolved findings (1)\n",
+		"- `web/b/challenge.yml`: 1 error(s)\n",
+	} {
+		if !strings.Contains(summary, line) {
+			t.Errorf("summary missing %q:\n%s", line, summary)
+		}
+	}
+
+	if first := auditSummary(compareAudits(nil, auditStateOf(results, ""))); !strings.Contains(first, "First audit") {
+		t.Errorf("first audit summary:\n%s", first)
+	}
+}
+
+func TestAuditStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.json")
+	if state, err := loadAuditState(path); err != nil || state != nil {
+		t.Fatalf("loadAuditState() of a missing file = %v, %v", state, err)
+	}
+	state := AuditState{Timestamp: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Findings: map[string][]string{"a/challenge.yml": {"❌ [flags] bad"}}}
+	if err := saveAuditState(path, state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadAuditState(path)
+	if err != nil || !reflect.DeepEqual(*loaded, state) {
+		t.Errorf("loadAuditState() = %+v, %v, want %+v", loaded, err, state)
+	}
+}
+
+func TestPostDiscord(t *testing.T) {
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		content = body["content"]
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := postDiscord(server.URL+"/api/webhooks/1/secret", strings.Repeat("x", discordMessageLimit+5)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(content)); n != discordMessageLimit {
+		t.Errorf("message is %d characters, want %d", n, discordMessageLimit)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// AuthorsRule routes the PR comment to the authors of the touched challenges
+// when a PR spans challenges by more than one author
+type AuthorsRule struct {
+	// Mention lists each author's challenges with an @-mention in the comment
+	Mention bool `yaml:"mention"`
+	// RequestReview requests a review from each author on the PR
+	RequestReview bool `yaml:"request_review"`
+	// Handles maps author field values to GitHub logins; authors already
+	// written as @login need no entry
+	Handles map[string]string `yaml:"handles"`
+}
+
+// authorHandle returns the GitHub login for an author field value, or "" if
+// it is unknown
+func (r AuthorsRule) authorHandle(author string) string {
+	if handle, ok := r.Handles[author]; ok {
+		return strings.TrimPrefix(handle, "@")
+	}
+	if strings.HasPrefix(author, "@") {
+		return strings.TrimPrefix(author, "@")
+	}
+	return ""
+}
+
+// challengeAuthor is one author and the challenges of theirs a PR touches
+type challengeAuthor struct {
+	author     string
+	handle     string
+	challenges []LintResult
+}
+
+// groupByAuthor groups results by author; an author field may list several
+// authors separated by commas. Authors are sorted by name.
+func groupByAuthor(results []LintResult, rule AuthorsRule) []challengeAuthor {
+	byAuthor := make(map[string]*challengeAuthor)
+	for _, result := range results {
+		for _, author := range strings.Split(result.author, ",") {
+			author = strings.TrimSpace(author)
+			if author == "" {
+				continue
+			}
+			entry, ok := byAuthor[author]
+			if !ok {
+				entry = &challengeAuthor{author: author, handle: rule.authorHandle(author)}
+				byAuthor[author] = entry
+			}
+			entry.challenges = append(entry.challenges, result)
+		}
+	}
+
+	authors := make([]challengeAuthor, 0, len(byAuthor))
+	for _, entry := range byAuthor {
+		authors = append(authors, *entry)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		return authors[i].author < authors[j].author
+	})
+	return authors
+}
+
+// authorsSection renders the per-author mentions appended to the PR comment,
+// or "" when disabled or all touched challenges share one author. Authors
+// without a known handle are listed by name.
+func authorsSection(results []LintResult, rule AuthorsRule) string {
+	if !rule.Mention {
+		return ""
+	}
+	authors := groupByAuthor(results, rule)
+	if len(authors) < 2 {
+		return ""
+	}
+
+	var body strings.Builder
+	body.WriteString("\n\n### 👥 Challenge Authors\n\n")
+	for _, entry := range authors {
+		name := entry.author
+		if entry.handle != "" {
+			name = "@" + entry.handle
+		}
+		var challenges []string
+		for _, result := range entry.challenges {
+			status := "✅"
+			if len(result.Errors) > 0 {
+				status = "❌"
+			} else if len(result.Warnings) > 0 {
+				status = "⚠️"
+			}
+			challenges = append(challenges, fmt.Sprintf("%s **%s**", status, result.Name))
+		}
+		body.WriteString(fmt.Sprintf("- %s: %s\n", name, strings.Join(challenges, ", ")))
+	}
+	return strings.TrimRight(body.String(), "\n")
+}
+
+// requestAuthorReviews requests a review from the known authors of the
+// touched challenges, other than the PR author, when they span more than one
+// author
+func requestAuthorReviews(env Env, results []LintResult, rule AuthorsRule) error {
+	if !rule.RequestReview {
+		return nil
+	}
+	authors := groupByAuthor(results, rule)
+	if len(authors) < 2 {
+		return nil
+	}
+
+	client, ctx := getGitHubClient(env.token)
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return fmt.Errorf("error getting PR: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var reviewers []string
+	for _, entry := range authors {
+		login := strings.ToLower(entry.handle)
+		// GitHub rejects review requests for the PR author
+		if entry.handle == "" || seen[login] || strings.EqualFold(entry.handle, pr.GetUser().GetLogin()) {
+			continue
+		}
+		seen[login] = true
+		reviewers = append(reviewers, entry.handle)
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	_, _, err = client.PullRequests.RequestReviewers(ctx, env.owner, env.repo, env.prNumber, github.ReviewersRequest{Reviewers: reviewers})
+	if err != nil {
+		return fmt.Errorf("error requesting reviewers: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAuthorsSection(t *testing.T) {
+	rule := AuthorsRule{Mention: true, Handles: map[string]string{"Alice": "alice-gh", "Bob": "@bob"}}
+	results := []LintResult{
+		{Name: "web1", author: "Alice", Errors: []Finding{{Message: "broken"}}},
+		{Name: "web2", author: "Bob, Carol"},
+		{Name: "pwn1", author: "Alice", Warnings: []Finding{{Message: "careful"}}},
+		{Name: "misc1", author: "@dave"},
+	}
+
+	want := "\n\n### 👥 Challenge Authors\n\n" +
+		"- @dave: ✅ **misc1**\n" +
+		"- @alice-gh: ❌ **web1**, ⚠️ **pwn1**\n" +
+		"- @bob: ✅ **web2**\n" +
+		"- Carol: ✅ **web2**"
+	if got := authorsSection(results, rule); got != want {
+		t.Errorf("authorsSection() =\n%q\nwant\n%q", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		rule    AuthorsRule
+		results []LintResult
+	}{
+		{name: "disabled", rule: AuthorsRule{}, results: results},
+		{name: "single author", rule: rule, results: []LintResult{{Name: "web1", author: "Alice"}, {Name: "pwn1", author: "Alice"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorsSection(tt.results, tt.rule); got != "" {
+				t.Errorf("expected no section, got %q", got)
+			}
+		})
+	}
+}
+
+func TestRequestAuthorReviews(t *testing.T) {
+	var requested []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 7, "user": {"login": "Alice"}}`)
+	})
+	mux.HandleFunc("POST /repos/owner/repo/pulls/7/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		requested = body.Reviewers
+		fmt.Fprint(w, `{"number": 7}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	rule := AuthorsRule{RequestReview: true, Handles: map[string]string{"Bob": "bob", "Bobby": "bob"}}
+	results := []LintResult{
+		{Name: "web1", author: "@alice"},
+		{Name: "web2", author: "Bob"},
+		{Name: "web3", author: "Bobby"},
+		{Name: "pwn1", author: "Unknown"},
+	}
+	if err := requestAuthorReviews(env, results, rule); err != nil {
+		t.Fatalf("requestAuthorReviews() error = %v", err)
+	}
+	if want := []string{"bob"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested reviewers = %v, want %v", requested, want)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultWriteupPattern matches writeup files and directories next to
+// challenge.yml, case-insensitively
+const defaultWriteupPattern = "writeup*"
+
+// noAuthor groups challenges whose author field is empty
+const noAuthor = "(no author)"
+
+// AuthorStats summarizes the challenges of one author
+type AuthorStats struct {
+	Author     string
+	Challenges int
+	Categories []string
+	Points     int
+	Errors     int
+	Warnings   int
+	// MissingWriteups lists the author's challenges without a writeup
+	MissingWriteups []string
+}
+
+func runAuthors(args []string) {
+	format := "markdown"
+	writeupPattern := defaultWriteupPattern
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--format" && i+1 < len(args) {
+			i++
+			format = args[i]
+		} else if args[i] == "--writeup" && i+1 < len(args) {
+			i++
+			writeupPattern = args[i]
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if format != "markdown" && format != "csv" {
+		log.Fatalf("Unknown --format %s (want markdown or csv)", format)
+	}
+	if _, err := filepath.Match(writeupPattern, ""); err != nil {
+		log.Fatalf("Invalid --writeup pattern %s: %v", writeupPattern, err)
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	var results []LintResult
+	for _, dir := range targetDirs {
+		dirResults, err := lintChallenges(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		results = append(results, dirResults...)
+	}
+	checkFlagCollisions(results, nil)
+
+	stats := authorStats(results, config.Authors, writeupPattern)
+	if format == "csv" {
+		if err := writeAuthorsCSV(os.Stdout, stats); err != nil {
+			log.Fatalf("Error writing CSV: %v", err)
+		}
+		return
+	}
+	writeAuthorsMarkdown(os.Stdout, stats)
+}
+
+// authorStats aggregates lint results per author. A challenge with several
+// authors counts fully for each of them.
+func authorStats(results []LintResult, rule AuthorsRule, writeupPattern string) []AuthorStats {
+	var unattributed []LintResult
+	for _, result := range results {
+		if strings.TrimSpace(strings.ReplaceAll(result.author, ",", "")) == "" {
+			unattributed = append(unattributed, result)
+		}
+	}
+	authors := groupByAuthor(results, rule)
+	if len(unattributed) > 0 {
+		authors = append(authors, challengeAuthor{author: noAuthor, challenges: unattributed})
+	}
+
+	stats := make([]AuthorStats, 0, len(authors))
+	for _, author := range authors {
+		entry := AuthorStats{Author: author.author, Challenges: len(author.challenges)}
+		categories := make(map[string]bool)
+		for _, result := range author.challenges {
+			entry.Errors += len(result.Errors)
+			entry.Warnings += len(result.Warnings)
+			if challenge, err := readChallenge(result.File); err == nil {
+				if challenge.Category != "" {
+					categories[challenge.Category] = true
+				}
+				entry.Points += challengeValue(challenge)
+			}
+			if !hasWriteup(result.File, writeupPattern) {
+				name := result.Name
+				if name == "" {
+					name = result.File
+				}
+				entry.MissingWriteups = append(entry.MissingWriteups, name)
+			}
+		}
+		for category := range categories {
+			entry.Categories = append(entry.Categories, category)
+		}
+		sort.Strings(entry.Categories)
+		sort.Strings(entry.MissingWriteups)
+		stats = append(stats, entry)
+	}
+	return stats
+}
+
+// hasWriteup reports whether the challenge directory has an entry matching
+// the writeup pattern, compared case-insensitively
+func hasWriteup(challengePath, pattern string) bool {
+	entries, err := os.ReadDir(filepath.Dir(challengePath))
+	if err != nil {
+		return false
+	}
+	pattern = strings.ToLower(pattern)
+	for _, entry := range entries {
+		if matched, _ := filepath.Match(pattern, strings.ToLower(entry.Name())); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAuthorsMarkdown renders the author statistics as a Markdown table
+func writeAuthorsMarkdown(w io.Writer, stats []AuthorStats) {
+	fmt.Fprintln(w, "| Author | Challenges | Categories | Points | Errors | Warnings | Missing writeups |")
+	fmt.Fprintln(w, "| ------ | ---------: | ---------- | -----: | -----: | -------: | ---------------- |")
+	for _, entry := range stats {
+		missing := "-"
+		if len(entry.MissingWriteups) > 0 {
+			missing = strings.Join(entry.MissingWriteups, ", ")
+		}
+		fmt.Fprintf(w, "| %s | %d | %s | %d | %d | %d | %s |\n",
+			markdownCell(entry.Author), entry.Challenges, markdownCell(strings.Join(entry.Categories, ", ")),
+			entry.Points, entry.Errors, entry.Warnings, markdownCell(missing))
+	}
+}
+
+// markdownCell escapes pipes so a value stays in its table cell
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// writeAuthorsCSV renders the author statistics as CSV with a header row
+func writeAuthorsCSV(w io.Writer, stats []AuthorStats) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"author", "challenges", "categories", "points", "errors", "warnings", "missing_writeups"}); err != nil {
+		return err
+	}
+	for _, entry := range stats {
+		record := []string{
+			entry.Author,
+			strconv.Itoa(entry.Challenges),
+			strings.Join(entry.Categories, ";"),
+			strconv.Itoa(entry.Points),
+			strconv.Itoa(entry.Errors),
+			strconv.Itoa(entry.Warnings),
+			strings.Join(entry.MissingWriteups, ";"),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
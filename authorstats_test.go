@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAuthorStats(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"web/login/challenge.yml":  "name: login\nauthor: alice\ncategory: web\nvalue: 300\n",
+		"web/login/WRITEUP.md":     "# Solution\n",
+		"pwn/bof/challenge.yml":    "name: bof\nauthor: alice, bob\ncategory: pwn\ntype: dynamic\nextra: {initial: 500}\n",
+		"pwn/bof/writeup/solve.py": "print('flag')\n",
+		"misc/quiz/challenge.yml":  "name: quiz\ncategory: misc\nvalue: 100\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := []LintResult{
+		{File: "web/login/challenge.yml", Name: "login", author: "alice", Errors: []Finding{{}, {}}},
+		{File: "pwn/bof/challenge.yml", Name: "bof", author: "alice, bob", Warnings: []Finding{{}}},
+		{File: "misc/quiz/challenge.yml", Name: "quiz", Errors: []Finding{{}}},
+	}
+	want := []AuthorStats{
+		{Author: "alice", Challenges: 2, Categories: []string{"pwn", "web"}, Points: 800, Errors: 2, Warnings: 1},
+		{Author: "bob", Challenges: 1, Categories: []string{"pwn"}, Points: 500, Warnings: 1},
+		{Author: noAuthor, Challenges: 1, Categories: []string{"misc"}, Points: 100, Errors: 1, MissingWriteups: []string{"quiz"}},
+	}
+	got := authorStats(results, AuthorsRule{}, defaultWriteupPattern)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("authorStats() = %+v, want %+v", got, want)
+	}
+
+	// A stricter pattern no longer accepts the writeup directory
+	got = authorStats(results, AuthorsRule{}, "writeup.md")
+	if want := []string{"bof"}; !reflect.DeepEqual(got[0].MissingWriteups, want) {
+		t.Errorf("missing writeups = %v, want %v", got[0].MissingWriteups, want)
+	}
+}
+
+func TestWriteAuthors(t *testing.T) {
+	stats := []AuthorStats{
+		{Author: "alice", Challenges: 2, Categories: []string{"pwn", "web"}, Points: 800, Errors: 2, Warnings: 1},
+		{Author: "bob|b", Challenges: 1, Categories: []string{"pwn"}, Points: 500, MissingWriteups: []string{"bof", "heap"}},
+	}
+
+	var md bytes.Buffer
+	writeAuthorsMarkdown(&md, stats)
+	wantMarkdown := `| Author | Challenges | Categories | Points | Errors | Warnings | Missing writeups |
+| ------ | ---------: | ---------- | -----: | -----: | -------: | ---------------- |
+| alice | 2 | pwn, web | 800 | 2 | 1 | - |
+| bob\|b | 1 | pwn | 500 | 0 | 0 | bof, heap |
+`
+	if md.String() != wantMarkdown {
+		t.Errorf("markdown =\n%s\nwant\n%s", md.String(), wantMarkdown)
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeAuthorsCSV(&csvOut, stats); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `author,challenges,categories,points,errors,warnings,missing_writeups
+alice,2,pwn;web,800,2,1,
+bob|b,1,pwn,500,0,0,bof;heap
+`
+	if csvOut.String() != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", csvOut.String(), wantCSV)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// benchChallengeCount is the size of the synthetic tree the benchmarks lint
+const benchChallengeCount = 1000
+
+// writeBenchTree creates a repository of n challenges spread over a few
+// categories, each with an attachment, and a lintrc.yaml at its root
+func writeBenchTree(b *testing.B, n int) string {
+	b.Helper()
+	root := b.TempDir()
+	config := `tags:
+  condition: and
+  patterns:
+    - type: static
+      values:
+        - easy
+        - medium
+        - hard
+requirements:
+  condition: none
+`
+	if err := os.WriteFile(filepath.Join(root, "lintrc.yaml"), []byte(config), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	categories := []string{"web", "pwn", "crypto", "rev", "osint"}
+	difficulties := []string{"easy", "medium", "hard"}
+	for i := 0; i < n; i++ {
+		category := categories[i%len(categories)]
+		dir := filepath.Join(root, category, fmt.Sprintf("chall_%04d", i))
+		if err := os.MkdirAll(filepath.Join(dir, "public"), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "public", "attachment.txt"), []byte("attachment"), 0644); err != nil {
+			b.Fatal(err)
+		}
+		challenge := fmt.Sprintf(`name: "chall_%04d"
+author: "author"
+category: "%s"
+description: |
+  Synthetic challenge %d
+flags:
+  - "flag{chall_%04d}"
+tags:
+  - %s
+files:
+  - public/attachment.txt
+value: 100
+type: dynamic
+image: null
+host: null
+state: visible
+version: "0.1"
+`, i, category, i, i, difficulties[i%len(difficulties)])
+		if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte(challenge), 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+// chdirBench changes into dir for the duration of the benchmark
+func chdirBench(b *testing.B, dir string) {
+	b.Helper()
+	origDir, _ := os.Getwd()
+	b.Cleanup(func() {
+		_ = os.Chdir(origDir)
+	})
+	if err := os.Chdir(dir); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkLintChallenges(b *testing.B) {
+	chdirBench(b, writeBenchTree(b, benchChallengeCount))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		results, err := lintChallenges(".")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(results) != benchChallengeCount {
+			b.Fatalf("linted %d challenges, want %d", len(results), benchChallengeCount)
+		}
+	}
+}
+
+func BenchmarkFindChallengeFiles(b *testing.B) {
+	chdirBench(b, writeBenchTree(b, benchChallengeCount))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := findChallengeFiles("."); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLintChallengeFile(b *testing.B) {
+	chdirBench(b, writeBenchTree(b, 1))
+	path := filepath.Join("web", "chall_0000", "challenge.yml")
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lintChallengeFile(path)
+	}
+}
+
+func BenchmarkReporters(b *testing.B) {
+	chdirBench(b, writeBenchTree(b, benchChallengeCount))
+	results, err := lintChallenges(".")
+	if err != nil {
+		b.Fatal(err)
+	}
+	sortResults(results)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := marshalResults(results, false); err != nil {
+			b.Fatal(err)
+		}
+		generateCommentBody(results, false)
+	}
+}
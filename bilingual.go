@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Defaults of the bilingual length check: Japanese text usually takes
+// between a fifth of and about as many characters as its English original
+const (
+	defaultBilingualSeparator = "---"
+	defaultBilingualMinRatio  = 0.2
+	defaultBilingualMaxRatio  = 1.2
+	// bilingualMinLength is the English length below which the ratio is not checked
+	bilingualMinLength = 40
+)
+
+// BilingualRule configures the check that every description is published in
+// English and Japanese, either as two blocks of description separated by a
+// line of Separator, or with the translation in description_ja
+type BilingualRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Separator is the line between the English and Japanese blocks (default "---")
+	Separator string `yaml:"separator"`
+	// MinRatio and MaxRatio bound the Japanese length divided by the English
+	// length, in characters (default 0.2 and 1.2)
+	MinRatio float64 `yaml:"min_ratio"`
+	MaxRatio float64 `yaml:"max_ratio"`
+}
+
+// splitBilingual returns the English and Japanese descriptions of a
+// challenge; ok is false when no translation is given
+func splitBilingual(challenge Challenge, rule BilingualRule) (english, japanese string, ok bool) {
+	if strings.TrimSpace(challenge.DescriptionJA) != "" {
+		return strings.TrimSpace(challenge.Description), strings.TrimSpace(challenge.DescriptionJA), true
+	}
+	separator := rule.Separator
+	if separator == "" {
+		separator = defaultBilingualSeparator
+	}
+	lines := strings.Split(challenge.Description, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != separator {
+			continue
+		}
+		first := strings.TrimSpace(strings.Join(lines[:i], "\n"))
+		second := strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		// Either language may come first
+		if japaneseRatio(first) > japaneseRatio(second) {
+			first, second = second, first
+		}
+		return first, second, true
+	}
+	return strings.TrimSpace(challenge.Description), "", false
+}
+
+// japaneseRatio returns the share of letters of text that are Japanese
+func japaneseRatio(text string) float64 {
+	letters, japanese := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			japanese++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(japanese) / float64(letters)
+}
+
+// checkBilingual checks that both the English and the Japanese description are present
+func checkBilingual(challenge Challenge, rule BilingualRule) []string {
+	var errors []string
+
+	if !rule.Enabled || strings.TrimSpace(challenge.Description) == "" && strings.TrimSpace(challenge.DescriptionJA) == "" {
+		// A missing description is reported by the description rules
+		return errors
+	}
+	english, japanese, ok := splitBilingual(challenge, rule)
+	if !ok {
+		separator := rule.Separator
+		if separator == "" {
+			separator = defaultBilingualSeparator
+		}
+		return append(errors, fmt.Sprintf("Field 'description' has no Japanese translation: add it after a '%s' line or in 'description_ja'", separator))
+	}
+	if english == "" {
+		errors = append(errors, "Field 'description' has no English text")
+	} else if japaneseRatio(english) > 0.5 {
+		errors = append(errors, "Field 'description' has no English text: both blocks are in Japanese")
+	}
+	if japanese == "" {
+		errors = append(errors, "Field 'description' has an empty Japanese translation")
+	} else if japaneseRatio(japanese) == 0 {
+		errors = append(errors, "Field 'description' has no Japanese text in its translation")
+	}
+	return errors
+}
+
+// checkBilingualLength warns when one language is much shorter than the
+// other expects, which usually means a paragraph was not translated
+func checkBilingualLength(challenge Challenge, rule BilingualRule) []string {
+	var warnings []string
+
+	if !rule.Enabled {
+		return warnings
+	}
+	english, japanese, ok := splitBilingual(challenge, rule)
+	if !ok || english == "" || japanese == "" {
+		return warnings
+	}
+	englishLength := utf8.RuneCountInString(english)
+	japaneseLength := utf8.RuneCountInString(japanese)
+	if englishLength < bilingualMinLength {
+		return warnings
+	}
+	minRatio, maxRatio := rule.MinRatio, rule.MaxRatio
+	if minRatio <= 0 {
+		minRatio = defaultBilingualMinRatio
+	}
+	if maxRatio <= 0 {
+		maxRatio = defaultBilingualMaxRatio
+	}
+	ratio := float64(japaneseLength) / float64(englishLength)
+	if ratio < minRatio || ratio > maxRatio {
+		warnings = append(warnings, fmt.Sprintf("Japanese description has %d characters for %d English ones (ratio %.2f, expected %.2f-%.2f); one of them may be incomplete",
+			japaneseLength, englishLength, ratio, minRatio, maxRatio))
+	}
+	return warnings
+}
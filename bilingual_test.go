@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckBilingual(t *testing.T) {
+	rule := BilingualRule{Enabled: true}
+	english := "Find the hidden message in the image attached to this challenge."
+	japanese := "添付された画像に隠されたメッセージを見つけてください。"
+
+	tests := []struct {
+		name      string
+		challenge Challenge
+		rule      BilingualRule
+		want      []string
+	}{
+		{name: "separated blocks", challenge: Challenge{Description: english + "\n---\n" + japanese}, rule: rule},
+		{name: "japanese first", challenge: Challenge{Description: japanese + "\n---\n" + english}, rule: rule},
+		{name: "description_ja", challenge: Challenge{Description: english, DescriptionJA: japanese}, rule: rule},
+		{name: "custom separator", challenge: Challenge{Description: english + "\n===\n" + japanese}, rule: BilingualRule{Enabled: true, Separator: "==="}},
+		{
+			name:      "no translation",
+			challenge: Challenge{Description: english},
+			rule:      rule,
+			want:      []string{"Field 'description' has no Japanese translation: add it after a '---' line or in 'description_ja'"},
+		},
+		{
+			name:      "empty translation",
+			challenge: Challenge{Description: english + "\n---\n"},
+			rule:      rule,
+			want:      []string{"Field 'description' has an empty Japanese translation"},
+		},
+		{
+			name:      "untranslated copy",
+			challenge: Challenge{Description: english + "\n---\n" + english},
+			rule:      rule,
+			want:      []string{"Field 'description' has no Japanese text in its translation"},
+		},
+		{
+			name:      "both japanese",
+			challenge: Challenge{Description: japanese + "\n---\n" + japanese},
+			rule:      rule,
+			want:      []string{"Field 'description' has no English text: both blocks are in Japanese"},
+		},
+		{name: "no description", challenge: Challenge{}, rule: rule},
+		{name: "disabled", challenge: Challenge{Description: english}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkBilingual(tt.challenge, tt.rule)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkBilingual() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBilingualLength(t *testing.T) {
+	rule := BilingualRule{Enabled: true}
+	english := strings.Repeat("Connect to the server and exploit the service. ", 4)
+	japanese := "サーバーに接続してサービスを攻略してください。"
+
+	if warnings := checkBilingualLength(Challenge{Description: english + "\n---\n" + strings.Repeat(japanese, 3)}, rule); len(warnings) != 0 {
+		t.Errorf("expected no warnings for a proportional translation, got %v", warnings)
+	}
+
+	warnings := checkBilingualLength(Challenge{Description: english + "\n---\n" + "接続して"}, rule)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Japanese description has 4 characters for 187 English ones") {
+		t.Errorf("expected a warning for a short translation, got %v", warnings)
+	}
+
+	warnings = checkBilingualLength(Challenge{Description: "Short.\n---\n" + strings.Repeat(japanese, 5)}, rule)
+	if len(warnings) != 0 {
+		t.Errorf("expected no ratio check for a short description, got %v", warnings)
+	}
+
+	warnings = checkBilingualLength(Challenge{Description: english, DescriptionJA: strings.Repeat(japanese, 20)}, BilingualRule{Enabled: true, MaxRatio: 2})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "expected 0.20-2.00") {
+		t.Errorf("expected a warning for a long translation, got %v", warnings)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/pe"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BinaryRule configures the opt-in checks for ELF/PE attachments
+type BinaryRule struct {
+	Stripped     *bool `yaml:"stripped"`     // true: symbols must be stripped, false: must be kept
+	DebugPaths   bool  `yaml:"debug_paths"`  // forbid home directory paths leaking the author's username
+	Architecture bool  `yaml:"architecture"` // require the architecture to match an architecture tag
+}
+
+// binaryInfo is the subset of ELF/PE metadata the binary checks look at
+type binaryInfo struct {
+	Format   string
+	Arch     string
+	Stripped bool
+}
+
+// archAliases maps architecture tags to their canonical name
+var archAliases = map[string]string{
+	"amd64":   "amd64",
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"386":     "386",
+	"i386":    "386",
+	"x86":     "386",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"arm":     "arm",
+	"armhf":   "arm",
+	"mips":    "mips",
+	"riscv64": "riscv64",
+	"ppc64":   "ppc64",
+}
+
+var homePathPattern = regexp.MustCompile(`(/home/[A-Za-z0-9._-]+/|/Users/[A-Za-z0-9._-]+/|[A-Za-z]:\\\\?Users\\\\?[A-Za-z0-9._-]+)`)
+
+// parseBinary returns ELF/PE metadata, or nil if the file is neither
+func parseBinary(path string) *binaryInfo {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		info := &binaryInfo{Format: "ELF", Stripped: f.Section(".symtab") == nil}
+		switch f.Machine {
+		case elf.EM_X86_64:
+			info.Arch = "amd64"
+		case elf.EM_386:
+			info.Arch = "386"
+		case elf.EM_AARCH64:
+			info.Arch = "arm64"
+		case elf.EM_ARM:
+			info.Arch = "arm"
+		case elf.EM_MIPS:
+			info.Arch = "mips"
+		case elf.EM_RISCV:
+			info.Arch = "riscv64"
+		case elf.EM_PPC64:
+			info.Arch = "ppc64"
+		default:
+			info.Arch = strings.ToLower(strings.TrimPrefix(f.Machine.String(), "EM_"))
+		}
+		return info
+	}
+
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		info := &binaryInfo{Format: "PE", Stripped: f.NumberOfSymbols == 0 && f.Section(".debug_info") == nil}
+		switch f.Machine {
+		case pe.IMAGE_FILE_MACHINE_AMD64:
+			info.Arch = "amd64"
+		case pe.IMAGE_FILE_MACHINE_I386:
+			info.Arch = "386"
+		case pe.IMAGE_FILE_MACHINE_ARM64:
+			info.Arch = "arm64"
+		case pe.IMAGE_FILE_MACHINE_ARMNT:
+			info.Arch = "arm"
+		default:
+			info.Arch = fmt.Sprintf("0x%x", f.Machine)
+		}
+		return info
+	}
+
+	return nil
+}
+
+func checkBinaries(challengePath string, challenge Challenge, rule BinaryRule) []string {
+	var errors []string
+
+	if rule.Stripped == nil && !rule.DebugPaths && !rule.Architecture {
+		return errors
+	}
+
+	var declaredArchs []string
+	for _, tag := range challenge.Tags {
+		if arch, ok := archAliases[strings.ToLower(tag)]; ok {
+			declaredArchs = append(declaredArchs, arch)
+		}
+	}
+
+	baseDir := filepath.Dir(challengePath)
+	for _, file := range challenge.Files {
+		fullPath := filepath.Join(baseDir, normalizeFilePath(file))
+		info := parseBinary(fullPath)
+		if info == nil {
+			continue
+		}
+
+		if rule.Stripped != nil {
+			if *rule.Stripped && !info.Stripped {
+				errors = append(errors, fmt.Sprintf("%s binary '%s' is not stripped", info.Format, file))
+			} else if !*rule.Stripped && info.Stripped {
+				errors = append(errors, fmt.Sprintf("%s binary '%s' is stripped but symbols are required", info.Format, file))
+			}
+		}
+
+		if rule.DebugPaths {
+			data, err := os.ReadFile(fullPath)
+			if err == nil {
+				if match := homePathPattern.Find(data); match != nil {
+					errors = append(errors, fmt.Sprintf("%s binary '%s' leaks a home directory path: %s", info.Format, file, bytes.TrimRight(match, `/\`)))
+				}
+			}
+		}
+
+		if rule.Architecture && len(declaredArchs) > 0 {
+			matched := false
+			for _, arch := range declaredArchs {
+				if arch == info.Arch {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				errors = append(errors, fmt.Sprintf("%s binary '%s' is %s but tags declare: %s", info.Format, file, info.Arch, strings.Join(declaredArchs, ", ")))
+			}
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestELF writes a minimal ELF64 file with an optional .symtab section
+func writeTestELF(t *testing.T, path string, machine elf.Machine, withSymtab bool, trailer string) {
+	t.Helper()
+
+	shstrtab := []byte("\x00.shstrtab\x00.symtab\x00")
+	sections := []elf.Section64{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_STRTAB), Off: 64, Size: uint64(len(shstrtab)), Addralign: 1},
+	}
+	if withSymtab {
+		sections = append(sections, elf.Section64{Name: 11, Type: uint32(elf.SHT_SYMTAB), Off: 64, Entsize: 24, Addralign: 8})
+	}
+
+	header := elf.Header64{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     uint64(64 + len(shstrtab)),
+		Ehsize:    64,
+		Shentsize: 64,
+		Shnum:     uint16(len(sections)),
+		Shstrndx:  1,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, header)
+	buf.Write(shstrtab)
+	_ = binary.Write(&buf, binary.LittleEndian, sections)
+	buf.WriteString(trailer)
+
+	if err := os.WriteFile(path, buf.Bytes(), 0755); err != nil {
+		t.Fatalf("Failed to write ELF file: %v", err)
+	}
+}
+
+func TestCheckBinaries(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	writeTestELF(t, filepath.Join(tempDir, "chall"), elf.EM_X86_64, true, "")
+	writeTestELF(t, filepath.Join(tempDir, "leaky"), elf.EM_X86_64, false, "/home/alice/src/chall.c")
+	writeTestELF(t, filepath.Join(tempDir, "arm"), elf.EM_AARCH64, false, "")
+	if err := os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("/home/bob/"), 0644); err != nil {
+		t.Fatalf("Failed to write text file: %v", err)
+	}
+
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		files      []string
+		tags       []string
+		rule       BinaryRule
+		wantErrors []string
+	}{
+		{name: "checks disabled", files: []string{"leaky"}, rule: BinaryRule{}},
+		{name: "require stripped", files: []string{"chall"}, rule: BinaryRule{Stripped: &yes}, wantErrors: []string{"ELF binary 'chall' is not stripped"}},
+		{name: "require symbols", files: []string{"chall"}, rule: BinaryRule{Stripped: &no}},
+		{name: "require symbols on stripped binary", files: []string{"arm"}, rule: BinaryRule{Stripped: &no}, wantErrors: []string{"ELF binary 'arm' is stripped but symbols are required"}},
+		{name: "debug path leak", files: []string{"leaky"}, rule: BinaryRule{DebugPaths: true}, wantErrors: []string{"leaks a home directory path: /home/alice"}},
+		{name: "non binary files are ignored", files: []string{"notes.txt"}, rule: BinaryRule{Stripped: &yes, DebugPaths: true}},
+		{name: "matching architecture tag", files: []string{"chall"}, tags: []string{"easy", "x86_64"}, rule: BinaryRule{Architecture: true}},
+		{name: "mismatching architecture tag", files: []string{"arm"}, tags: []string{"amd64"}, rule: BinaryRule{Architecture: true}, wantErrors: []string{"ELF binary 'arm' is arm64 but tags declare: amd64"}},
+		{name: "no architecture tag", files: []string{"chall"}, tags: []string{"easy"}, rule: BinaryRule{Architecture: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge := Challenge{Files: tt.files, Tags: tt.tags}
+			errs := checkBinaries(challengePath, challenge, tt.rule)
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got: %v", len(tt.wantErrors), errs)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("Expected error containing '%s', got: %s", want, errs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// BuildSpec describes how a challenge's distributed files are produced from source
+type BuildSpec struct {
+	Make   string `yaml:"make"`   // make target to run
+	Script string `yaml:"script"` // script to run, relative to the challenge directory
+}
+
+func runBuild(args []string) {
+	verify := false
+	var targetDirs []string
+	for _, arg := range args {
+		if arg == "--verify" {
+			verify = true
+		} else {
+			targetDirs = append(targetDirs, arg)
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	failed := false
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			fmt.Printf("❌ %s: %v\n", dir, err)
+			os.Exit(1)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				fmt.Printf("❌ %s: %v\n", path, err)
+				failed = true
+				continue
+			}
+			if challenge.Build == nil {
+				continue
+			}
+
+			if verify {
+				errs := verifyBuild(path, challenge)
+				if len(errs) > 0 {
+					fmt.Printf("❌ %s:\n", path)
+					for _, err := range errs {
+						fmt.Printf("  - %s\n", err)
+					}
+					failed = true
+				} else {
+					fmt.Printf("✅ %s: build is reproducible\n", path)
+				}
+				continue
+			}
+
+			output, err := runBuildCommand(*challenge.Build, filepath.Dir(path))
+			if err != nil {
+				fmt.Printf("❌ %s: %v\n%s", path, err, output)
+				failed = true
+				continue
+			}
+			fmt.Printf("🔨 %s: built\n", path)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runBuildCommand runs the make target or script of a build spec in dir
+func runBuildCommand(spec BuildSpec, dir string) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch {
+	case spec.Make != "":
+		cmd = exec.Command("make", spec.Make)
+	case spec.Script != "":
+		script, err := filepath.Abs(filepath.Join(dir, spec.Script))
+		if err != nil {
+			return nil, err
+		}
+		cmd = exec.Command(script)
+	default:
+		return nil, fmt.Errorf("build must specify 'make' or 'script'")
+	}
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("build failed: %v", err)
+	}
+	return output, nil
+}
+
+// verifyBuild builds the challenge in a clean copy of its directory, without
+// the committed files entries, and compares the produced artifacts byte by byte
+func verifyBuild(challengePath string, challenge Challenge) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+
+	tempDir, err := os.MkdirTemp("", "clilint-build-")
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to create build directory: %v", err))
+		return errors
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := copyDir(baseDir, tempDir); err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to copy challenge sources: %v", err))
+		return errors
+	}
+	for _, file := range challenge.Files {
+		if !isRemoteFile(file) {
+			_ = os.Remove(filepath.Join(tempDir, normalizeFilePath(file)))
+		}
+	}
+
+	if output, err := runBuildCommand(*challenge.Build, tempDir); err != nil {
+		errors = append(errors, fmt.Sprintf("%v: %s", err, bytes.TrimSpace(output)))
+		return errors
+	}
+
+	for _, file := range challenge.Files {
+		if isRemoteFile(file) {
+			continue
+		}
+		built, err := os.ReadFile(filepath.Join(tempDir, normalizeFilePath(file)))
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Build did not produce '%s'", file))
+			continue
+		}
+		committed, err := os.ReadFile(filepath.Join(baseDir, normalizeFilePath(file)))
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Committed file '%s' is not readable: %v", file, err))
+			continue
+		}
+		if !bytes.Equal(built, committed) {
+			errors = append(errors, fmt.Sprintf("Built '%s' differs from the committed file", file))
+		}
+	}
+
+	return errors
+}
+
+// copyDir recursively copies the regular files and directories of src into dst
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
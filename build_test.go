@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyBuild(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	tests := []struct {
+		name       string
+		script     string
+		committed  string
+		wantErrors []string
+	}{
+		{
+			name:       "reproducible build",
+			script:     "#!/bin/sh\nmkdir -p dist && printf 'v1' > dist/chall.bin\n",
+			committed:  "v1",
+			wantErrors: []string{},
+		},
+		{
+			name:       "stale committed artifact",
+			script:     "#!/bin/sh\nmkdir -p dist && printf 'v2' > dist/chall.bin\n",
+			committed:  "v1",
+			wantErrors: []string{"Built 'dist/chall.bin' differs from the committed file"},
+		},
+		{
+			name:       "artifact not produced",
+			script:     "#!/bin/sh\ntrue\n",
+			committed:  "v1",
+			wantErrors: []string{"Build did not produce 'dist/chall.bin'"},
+		},
+		{
+			name:       "failing build",
+			script:     "#!/bin/sh\necho boom\nexit 1\n",
+			committed:  "v1",
+			wantErrors: []string{"build failed", "boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "dist"), 0755); err != nil {
+				t.Fatalf("Failed to create dist: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "build.sh"), []byte(tt.script), 0755); err != nil {
+				t.Fatalf("Failed to write build script: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "dist", "chall.bin"), []byte(tt.committed), 0644); err != nil {
+				t.Fatalf("Failed to write artifact: %v", err)
+			}
+
+			challenge := Challenge{
+				Files: []string{"dist/chall.bin"},
+				Build: &BuildSpec{Script: "build.sh"},
+			}
+			errs := verifyBuild(filepath.Join(dir, "challenge.yml"), challenge)
+
+			if len(tt.wantErrors) == 0 && len(errs) != 0 {
+				t.Errorf("Expected no errors, got: %v", errs)
+			}
+			joined := strings.Join(errs, "\n")
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(joined, want) {
+					t.Errorf("Expected error containing '%s' not found in: %v", want, errs)
+				}
+			}
+
+			// The committed artifact must never be touched by verification
+			data, _ := os.ReadFile(filepath.Join(dir, "dist", "chall.bin"))
+			if string(data) != tt.committed {
+				t.Errorf("Committed artifact was modified: %q", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// celRule is a custom rule of a policy bundle: a CEL expression evaluated
+// against every challenge. It passes when it returns true, an empty string,
+// or an empty list, and otherwise fails with the returned message(s).
+type celRule struct {
+	// name is the rule's path in the bundle, e.g. rules/flags.cel
+	name string
+	// message is the rule's first // comment, reported when it returns false
+	message string
+	program cel.Program
+}
+
+// celEnv declares what CEL rules can read: the challenge.yml document as a
+// map (includes merged, placeholders unexpanded) and its path relative to
+// the working directory
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("challenge", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("path", cel.StringType),
+	)
+})
+
+// compileCELRules compiles the .cel files of an extracted bundle, sorted by path
+func compileCELRules(dir string) ([]celRule, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(d.Name(), ".cel") {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	var rules []celRule
+	for _, path := range paths {
+		rel, _ := filepath.Rel(dir, path)
+		name := filepath.ToSlash(rel)
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		ast, issues := env.Compile(string(source))
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("CEL rule %s: %v", name, issues.Err())
+		}
+		switch output := ast.OutputType(); {
+		case output.IsExactType(types.BoolType), output.IsExactType(types.StringType),
+			output.IsExactType(types.NewListType(types.StringType)), output.IsExactType(types.DynType):
+		default:
+			return nil, fmt.Errorf("CEL rule %s returns %s, not a bool, string, or list of strings", name, output)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("CEL rule %s: %v", name, err)
+		}
+		rules = append(rules, celRule{name: name, message: celRuleMessage(string(source)), program: program})
+	}
+	return rules, nil
+}
+
+// celRuleMessage returns the first // comment of a CEL rule
+func celRuleMessage(source string) string {
+	for _, line := range strings.Split(source, "\n") {
+		if comment, ok := strings.CutPrefix(strings.TrimSpace(line), "//"); ok {
+			if comment = strings.TrimSpace(comment); comment != "" {
+				return comment
+			}
+		}
+	}
+	return ""
+}
+
+// checkCELRules evaluates the policy bundle's CEL rules against a challenge
+func checkCELRules(challengePath string, challenge map[string]interface{}, rules []celRule) []string {
+	if challenge == nil {
+		challenge = map[string]interface{}{}
+	}
+	vars := map[string]interface{}{
+		"challenge": challenge,
+		"path":      filepath.ToSlash(challengePath),
+	}
+
+	var errors []string
+	for _, rule := range rules {
+		out, _, err := rule.program.Eval(vars)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Policy rule %s failed to evaluate: %v", rule.name, err))
+			continue
+		}
+		switch value := out.Value().(type) {
+		case bool:
+			if value {
+				continue
+			}
+			message := rule.message
+			if message == "" {
+				message = "returned false"
+			}
+			errors = append(errors, fmt.Sprintf("Policy rule %s: %s", rule.name, message))
+		case string:
+			if value != "" {
+				errors = append(errors, fmt.Sprintf("Policy rule %s: %s", rule.name, value))
+			}
+		default:
+			native, err := out.ConvertToNative(reflect.TypeOf([]string{}))
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("Policy rule %s returned %s, not a bool, string, or list of strings", rule.name, out.Type()))
+				continue
+			}
+			for _, message := range native.([]string) {
+				errors = append(errors, fmt.Sprintf("Policy rule %s: %s", rule.name, message))
+			}
+		}
+	}
+	return errors
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+)
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// changedLines returns the line numbers on the new side of a unified diff
+// patch that were added or modified
+func changedLines(patch string) map[int]bool {
+	lines := make(map[int]bool)
+	current := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if match := hunkHeaderPattern.FindStringSubmatch(line); match != nil {
+			current, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if current == 0 {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			lines[current] = true
+			current++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+			// Removed lines do not exist on the new side
+		default:
+			current++
+		}
+	}
+	return lines
+}
+
+// filterToChangedLines drops findings attributed to fields the PR did not
+// touch. Findings that cannot be attributed to a single field (such as
+// manifest or cross-challenge problems) are always kept.
+func filterToChangedLines(results []LintResult, prFiles []*github.CommitFile) []LintResult {
+	byName := make(map[string]*github.CommitFile)
+	for _, prFile := range prFiles {
+		byName[prFile.GetFilename()] = prFile
+	}
+
+	filtered := make([]LintResult, 0, len(results))
+	for _, result := range results {
+		path := filepath.ToSlash(filepath.Clean(result.File))
+		prFile, ok := byName[path]
+		if ok && prFile.GetStatus() == "added" {
+			filtered = append(filtered, result)
+			continue
+		}
+
+		var changed map[int]bool
+		if ok {
+			changed = changedLines(prFile.GetPatch())
+		}
+		data := result.data
+
+		// Attachments changed next to challenge.yml make file findings relevant
+		attachmentsChanged := false
+		dir := filepath.ToSlash(filepath.Dir(path)) + "/"
+		for name := range byName {
+			if name != path && strings.HasPrefix(name, dir) {
+				attachmentsChanged = true
+				break
+			}
+		}
+
+		keep := func(finding Finding) bool {
+			field := finding.Field
+			if field == "" {
+				return true
+			}
+			if field == "files" && attachmentsChanged {
+				return true
+			}
+			location, err := findTopLevelField(data, field)
+			if err != nil || location == nil {
+				// Missing fields cannot be located, keep the finding
+				return true
+			}
+			for line := location.Key.Line; line <= location.EndLine; line++ {
+				if changed[line] {
+					return true
+				}
+			}
+			return false
+		}
+
+		kept := result
+		kept.Errors = filterFindings(result.Errors, keep)
+		kept.Warnings = filterFindings(result.Warnings, keep)
+		filtered = append(filtered, kept)
+	}
+	return filtered
+}
+
+func filterFindings(findings []Finding, keep func(Finding) bool) []Finding {
+	kept := []Finding{}
+	for _, finding := range findings {
+		if keep(finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestChangedLines(t *testing.T) {
+	patch := `@@ -1,4 +1,4 @@
+ name: "chall"
+-state: hidden
++state: visible
+ version: "0.1"
+@@ -10,2 +10,3 @@ tags:
+ host: null
++image: null
+\ No newline at end of file`
+
+	got := changedLines(patch)
+	want := map[int]bool{2: true, 11: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedLines() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterToChangedLines(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	if err := os.MkdirAll(filepath.Join("web", "chall"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	content := "name: chall\nfiles:\n  - a.txt\ntags:\n  - easy\nstate: hidden\nversion: \"0.2\"\ntype: standard\n"
+	path := filepath.Join("web", "chall", "challenge.yml")
+	// The checkout has the base of the PR; findings are located in the
+	// linted content, as --fetch-contents lints the head
+	base := "name: chall\nversion: \"0.3\"\nfiles:\n  - a.txt\ntags:\n  - easy\nstate: hidden\ntype: standard\n"
+	if err := os.WriteFile(path, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+
+	result := LintResult{File: path, data: []byte(content)}
+	result.addErrors("state", []string{"Field 'state' should be 'visible'"})
+	result.addErrors("version", []string{"Field 'version' should be '0.1'"})
+	result.addErrors("files", []string{"File specified in 'files' does not exist: a.txt"})
+	result.addErrors("", []string{"Kubernetes manifest problem"})
+	result.addWarnings("type", []string{"Field 'type' is 'standard'"})
+
+	t.Run("only findings on changed fields", func(t *testing.T) {
+		prFiles := []*github.CommitFile{{
+			Filename: github.String("web/chall/challenge.yml"),
+			Status:   github.String("modified"),
+			Patch:    github.String("@@ -7,1 +7,1 @@\n-version: \"0.3\"\n+version: \"0.2\""),
+		}}
+		filtered := filterToChangedLines([]LintResult{result}, prFiles)
+		wantErrors := []string{"Field 'version' should be '0.1'", "Kubernetes manifest problem"}
+		if got := findingMessages(filtered[0].Errors); !reflect.DeepEqual(got, wantErrors) {
+			t.Errorf("Errors = %v, want %v", got, wantErrors)
+		}
+		if len(filtered[0].Warnings) != 0 {
+			t.Errorf("Expected warnings to be filtered, got %v", filtered[0].Warnings)
+		}
+	})
+
+	t.Run("changed attachments keep file findings", func(t *testing.T) {
+		prFiles := []*github.CommitFile{{
+			Filename: github.String("web/chall/public/a.txt"),
+			Status:   github.String("removed"),
+		}}
+		filtered := filterToChangedLines([]LintResult{result}, prFiles)
+		wantErrors := []string{"File specified in 'files' does not exist: a.txt", "Kubernetes manifest problem"}
+		if got := findingMessages(filtered[0].Errors); !reflect.DeepEqual(got, wantErrors) {
+			t.Errorf("Errors = %v, want %v", got, wantErrors)
+		}
+	})
+
+	t.Run("added challenge keeps everything", func(t *testing.T) {
+		prFiles := []*github.CommitFile{{
+			Filename: github.String("web/chall/challenge.yml"),
+			Status:   github.String("added"),
+		}}
+		filtered := filterToChangedLines([]LintResult{result}, prFiles)
+		if len(filtered[0].Errors) != 4 || len(filtered[0].Warnings) != 1 {
+			t.Errorf("Expected all findings to be kept, got %v / %v", filtered[0].Errors, filtered[0].Warnings)
+		}
+	})
+}
+
+func findingMessages(findings []Finding) []string {
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Message)
+	}
+	return messages
+}
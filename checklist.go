@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ChecklistItem is a reviewer checklist entry from lintrc.yaml. Items with
+// conditions are checked automatically when all of them hold; items without
+// are left for the reviewer.
+type ChecklistItem struct {
+	Item string `yaml:"item"`
+	// Passes lists rule IDs that must have no findings, e.g. [host, inventory]
+	Passes []string `yaml:"passes"`
+	// Exists is a glob, relative to the challenge directory, that must match a file
+	Exists string `yaml:"exists"`
+}
+
+// ChecklistEntry is a checklist item as evaluated for one challenge
+type ChecklistEntry struct {
+	Item    string `json:"item"`
+	Checked bool   `json:"checked"`
+}
+
+// evaluateChecklist decides which checklist items are already satisfied by a
+// challenge's findings and files
+func evaluateChecklist(items []ChecklistItem, challengePath string, result LintResult) []ChecklistEntry {
+	if len(items) == 0 {
+		return nil
+	}
+
+	failing := make(map[string]bool)
+	for _, findings := range [][]Finding{result.Errors, result.Warnings} {
+		for _, finding := range findings {
+			failing[finding.RuleID] = true
+		}
+	}
+
+	entries := make([]ChecklistEntry, 0, len(items))
+	for _, item := range items {
+		checked := len(item.Passes) > 0 || item.Exists != ""
+		for _, ruleID := range item.Passes {
+			if failing[ruleID] {
+				checked = false
+			}
+		}
+		if item.Exists != "" {
+			pattern := filepath.Join(filepath.Dir(challengePath), normalizeFilePath(item.Exists))
+			matches, err := filepath.Glob(pattern)
+			if err != nil || len(matches) == 0 {
+				checked = false
+			}
+		}
+		entries = append(entries, ChecklistEntry{Item: item.Item, Checked: checked})
+	}
+	return entries
+}
+
+// writeChecklist renders a checklist as a markdown task list
+func writeChecklist(body *strings.Builder, checklist []ChecklistEntry) {
+	if len(checklist) == 0 {
+		return
+	}
+	body.WriteString("**Review checklist:**\n")
+	for _, entry := range checklist {
+		mark := " "
+		if entry.Checked {
+			mark = "x"
+		}
+		body.WriteString(fmt.Sprintf("- [%s] %s\n", mark, entry.Item))
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateChecklist(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	if err := os.MkdirAll(filepath.Join(tempDir, "writeup"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "writeup", "README.md"), []byte("# writeup"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []ChecklistItem{
+		{Item: "Flag verified"},
+		{Item: "Infra deployed", Passes: []string{"host", "inventory"}},
+		{Item: "Tags reviewed", Passes: []string{"tags"}},
+		{Item: "Writeup present", Exists: "writeup/*.md"},
+		{Item: "Solver present", Exists: "solver/*"},
+		{Item: "Writeup and infra", Passes: []string{"host"}, Exists: "writeup/*.md"},
+	}
+	result := LintResult{
+		Errors:   []Finding{{RuleID: "tags"}},
+		Warnings: []Finding{{RuleID: "type"}},
+	}
+
+	got := evaluateChecklist(items, challengePath, result)
+	want := []ChecklistEntry{
+		{Item: "Flag verified", Checked: false},
+		{Item: "Infra deployed", Checked: true},
+		{Item: "Tags reviewed", Checked: false},
+		{Item: "Writeup present", Checked: true},
+		{Item: "Solver present", Checked: false},
+		{Item: "Writeup and infra", Checked: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("evaluateChecklist() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := evaluateChecklist(nil, challengePath, result); got != nil {
+		t.Errorf("expected no checklist without items, got %+v", got)
+	}
+}
+
+func TestCommentBodyChecklist(t *testing.T) {
+	checklist := []ChecklistEntry{{Item: "Flag verified"}, {Item: "Writeup present", Checked: true}}
+	results := []LintResult{
+		{File: "web/ok/challenge.yml", Name: "ok", Checklist: checklist},
+		{File: "web/bad/challenge.yml", Name: "bad", Errors: []Finding{{Message: "broken"}}, Checklist: checklist},
+	}
+
+	body := generateCommentBody(results, true)
+	if strings.Count(body, "**Review checklist:**\n- [ ] Flag verified\n- [x] Writeup present\n") != 2 {
+		t.Errorf("expected a checklist for each challenge:\n%s", body)
+	}
+	if !strings.Contains(body, "#### 🚩 **ok** (`web/ok/challenge.yml`)\n\n**Review checklist:**") {
+		t.Errorf("expected the checklist under a passing challenge without description:\n%s", body)
+	}
+}
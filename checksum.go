@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checksumManifest is the name of the per-challenge checksum manifest
+const checksumManifest = "SHA256SUMS"
+
+// ChecksumRule configures the SHA256SUMS manifest verification
+type ChecksumRule struct {
+	Required bool `yaml:"required"` // error when a challenge with files has no manifest
+}
+
+func runChecksum(args []string) {
+	targetDirs := args
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	failed := false
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				log.Fatalf("Error reading %s: %v", path, err)
+			}
+			if len(challenge.Files) == 0 {
+				continue
+			}
+			manifestPath, err := writeChecksumManifest(path, challenge.Files)
+			if err != nil {
+				fmt.Printf("❌ %s: %v\n", path, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("📝 %s\n", manifestPath)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// writeChecksumManifest writes SHA256SUMS next to challenge.yml in sha256sum format
+func writeChecksumManifest(challengePath string, files []string) (string, error) {
+	baseDir := filepath.Dir(challengePath)
+
+	var manifest strings.Builder
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		sum, err := sha256File(filepath.Join(baseDir, normalizeFilePath(file)))
+		if err != nil {
+			return "", err
+		}
+		manifest.WriteString(fmt.Sprintf("%s  %s\n", sum, slashPath(file)))
+	}
+
+	manifestPath := filepath.Join(baseDir, checksumManifest)
+	return manifestPath, os.WriteFile(manifestPath, []byte(manifest.String()), 0644)
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readChecksumManifest parses a sha256sum formatted manifest into path -> checksum
+func readChecksumManifest(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("malformed line: %s", line)
+		}
+		// sha256sum marks binary mode with a leading '*'
+		name = strings.TrimPrefix(strings.TrimLeft(name, " "), "*")
+		sums[slashPath(name)] = strings.ToLower(sum)
+	}
+	return sums, scanner.Err()
+}
+
+func checkChecksums(challengePath string, files []string, rule ChecksumRule) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+	manifestPath := filepath.Join(baseDir, checksumManifest)
+
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		if rule.Required && len(files) > 0 {
+			errors = append(errors, fmt.Sprintf("%s manifest is missing, run 'clilint checksum'", checksumManifest))
+		}
+		return errors
+	}
+
+	sums, err := readChecksumManifest(manifestPath)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to read %s: %v", checksumManifest, err))
+		return errors
+	}
+
+	listed := make(map[string]bool)
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		listed[slashPath(file)] = true
+		want, ok := sums[slashPath(file)]
+		if !ok {
+			errors = append(errors, fmt.Sprintf("%s is out of date: '%s' is not listed", checksumManifest, file))
+			continue
+		}
+		got, err := sha256File(filepath.Join(baseDir, normalizeFilePath(file)))
+		if err != nil {
+			// Missing files are reported by checkFiles
+			continue
+		}
+		if got != want {
+			errors = append(errors, fmt.Sprintf("%s is out of date: checksum of '%s' changed", checksumManifest, file))
+		}
+	}
+	var stale []string
+	for name := range sums {
+		if !listed[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		errors = append(errors, fmt.Sprintf("%s is out of date: '%s' is not in 'files'", checksumManifest, name))
+	}
+
+	return errors
+}
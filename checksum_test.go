@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChecksumManifest(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	files := []string{"public/a.txt", "b.txt"}
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "public"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, file), []byte(file), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+
+	t.Run("missing manifest is fine unless required", func(t *testing.T) {
+		if errs := checkChecksums(challengePath, files, ChecksumRule{}); len(errs) != 0 {
+			t.Errorf("Expected no errors, got: %v", errs)
+		}
+		errs := checkChecksums(challengePath, files, ChecksumRule{Required: true})
+		if len(errs) != 1 || !strings.Contains(errs[0], "SHA256SUMS manifest is missing") {
+			t.Errorf("Expected missing manifest error, got: %v", errs)
+		}
+	})
+
+	manifestPath, err := writeChecksumManifest(challengePath, files)
+	if err != nil {
+		t.Fatalf("writeChecksumManifest failed: %v", err)
+	}
+	data, _ := os.ReadFile(manifestPath)
+	// sha256 of the content "b.txt"
+	wantLine := "ffa0da5d885fba09d903c782713b6b098c8cf21f56a3a35d9aa920613220d2e1  b.txt\n"
+	if !strings.HasSuffix(string(data), wantLine) || !strings.Contains(string(data), "  public/a.txt\n") {
+		t.Errorf("Unexpected manifest content: %q", data)
+	}
+
+	t.Run("up to date manifest", func(t *testing.T) {
+		if errs := checkChecksums(challengePath, files, ChecksumRule{Required: true}); len(errs) != 0 {
+			t.Errorf("Expected no errors, got: %v", errs)
+		}
+	})
+
+	t.Run("changed attachment content", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(tempDir, "b.txt"), []byte("changed"), 0644); err != nil {
+			t.Fatalf("Failed to update file: %v", err)
+		}
+		errs := checkChecksums(challengePath, files, ChecksumRule{})
+		if len(errs) != 1 || !strings.Contains(errs[0], "checksum of 'b.txt' changed") {
+			t.Errorf("Expected checksum mismatch, got: %v", errs)
+		}
+	})
+
+	t.Run("files entries added and removed", func(t *testing.T) {
+		errs := checkChecksums(challengePath, []string{"b.txt", "c.txt"}, ChecksumRule{})
+		want := []string{"'c.txt' is not listed", "'public/a.txt' is not in 'files'"}
+		for _, w := range want {
+			found := false
+			for _, e := range errs {
+				if strings.Contains(e, w) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected error containing '%s' not found in: %v", w, errs)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// codeownersPaths are where GitHub looks for CODEOWNERS, in order
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnersRule lists the CODEOWNERS owners of failing challenges in the
+// report and PR comment, so findings in shared directories reach their
+// maintainers rather than only the PR author
+type CodeOwnersRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Mention @-mentions the owners in the PR comment; otherwise they are
+	// shown without notifying them
+	Mention bool `yaml:"mention"`
+	// File is the CODEOWNERS file, relative to lintrc.yaml (default: where
+	// GitHub looks for it)
+	File string `yaml:"file"`
+}
+
+// codeownersEntry is one line of a CODEOWNERS file
+type codeownersEntry struct {
+	pattern string
+	owners  []string
+}
+
+// codeownersCache holds the parsed CODEOWNERS files by path
+var codeownersCache sync.Map
+
+// loadCodeowners parses a CODEOWNERS file; a missing file has no entries
+func loadCodeowners(path string) []codeownersEntry {
+	if cached, ok := codeownersCache.Load(path); ok {
+		return cached.([]codeownersEntry)
+	}
+	var entries []codeownersEntry
+	if file, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if i := strings.Index(line, " #"); i >= 0 {
+				line = line[:i]
+			}
+			fields := strings.Fields(line)
+			// A pattern without owners clears the owners of earlier patterns
+			entries = append(entries, codeownersEntry{pattern: fields[0], owners: fields[1:]})
+		}
+		file.Close()
+	}
+	codeownersCache.Store(path, entries)
+	return entries
+}
+
+// codeownersFile returns the CODEOWNERS file of the repository of lintrc.yaml
+func codeownersFile(config *LintConfig) string {
+	if config.CodeOwners.File != "" {
+		return config.resolvePath(config.CodeOwners.File)
+	}
+	for _, path := range codeownersPaths {
+		path = config.resolvePath(filepath.FromSlash(path))
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// challengeOwners returns the owners of a challenge.yml: those of the last
+// CODEOWNERS pattern matching it, as on GitHub
+func challengeOwners(challengePath string, config *LintConfig) []string {
+	if !config.CodeOwners.Enabled {
+		return nil
+	}
+	path := codeownersFile(config)
+	if path == "" {
+		return nil
+	}
+	base, err := filepath.Abs(config.baseDir)
+	if err != nil {
+		return nil
+	}
+	absPath, err := filepath.Abs(challengePath)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(base, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+
+	var owners []string
+	for _, entry := range loadCodeowners(path) {
+		if matchCodeowners(entry.pattern, rel) {
+			owners = entry.owners
+		}
+	}
+	return owners
+}
+
+// matchCodeowners reports whether a CODEOWNERS pattern matches the file at
+// relPath: patterns match the file or any directory above it, except that
+// "dir/*" only matches files directly in dir
+func matchCodeowners(pattern, relPath string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		return matchGitPattern(pattern, relPath)
+	}
+	parts := strings.Split(relPath, "/")
+	for i := 1; i <= len(parts); i++ {
+		if dirOnly && i == len(parts) {
+			break
+		}
+		if matchGitPattern(pattern, strings.Join(parts[:i], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownersText renders the owners of a challenge, as @-mentions when mention
+// is set and as code otherwise
+func ownersText(owners []string, mention bool) string {
+	if mention {
+		return strings.Join(owners, ", ")
+	}
+	quoted := make([]string, len(owners))
+	for i, owner := range owners {
+		quoted[i] = "`" + owner + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
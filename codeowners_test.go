@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchCodeowners(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "web/app/challenge.yml", true},
+		{"*.yml", "web/app/challenge.yml", true},
+		{"/web/", "web/app/challenge.yml", true},
+		{"/web/", "pwn/web/challenge.yml", false},
+		{"web/", "pwn/web/challenge.yml", true},
+		{"templates", "misc/templates/base/challenge.yml", true},
+		{"/web/app", "web/app/challenge.yml", true},
+		{"/web/app", "web/application/challenge.yml", false},
+		{"web/*", "web/challenge.yml", true},
+		{"web/*", "web/app/challenge.yml", false},
+		{"/pwn/**/challenge.yml", "pwn/heap/tcache/challenge.yml", true},
+		{"challenge.yml/", "web/app/challenge.yml", false},
+	}
+	for _, tt := range tests {
+		if got := matchCodeowners(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchCodeowners(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestChallengeOwners(t *testing.T) {
+	tempDir := t.TempDir()
+	codeowners := `# Default owners
+*                 @org/ctf-admins
+/web/             @org/web-team @alice
+/web/legacy/                     # no owners
+templates/        @org/infra     # shared templates
+`
+	if err := os.MkdirAll(filepath.Join(tempDir, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, ".github", "CODEOWNERS"), []byte(codeowners), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := &LintConfig{CodeOwners: CodeOwnersRule{Enabled: true}, baseDir: tempDir}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"pwn/bof/challenge.yml", []string{"@org/ctf-admins"}},
+		{"web/xss/challenge.yml", []string{"@org/web-team", "@alice"}},
+		{"web/legacy/old/challenge.yml", []string{}},
+		{"web/templates/sqli/challenge.yml", []string{"@org/infra"}},
+	}
+	for _, tt := range tests {
+		got := challengeOwners(filepath.Join(tempDir, filepath.FromSlash(tt.path)), config)
+		if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("challengeOwners(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if got := challengeOwners(filepath.Join(tempDir, "pwn", "bof", "challenge.yml"), &LintConfig{baseDir: tempDir}); got != nil {
+		t.Errorf("challengeOwners() = %v with codeowners disabled", got)
+	}
+	outside := filepath.Join(t.TempDir(), "challenge.yml")
+	if got := challengeOwners(outside, config); got != nil {
+		t.Errorf("challengeOwners() = %v for a challenge outside the repository", got)
+	}
+}
+
+func TestOwnersOutput(t *testing.T) {
+	results := []LintResult{{
+		File:   "web/xss/challenge.yml",
+		Name:   "xss",
+		Errors: []Finding{{Message: "Field 'state' should be 'visible'"}},
+		Owners: []string{"@org/web-team", "@alice"},
+	}}
+
+	body := generateCommentBody(results, true)
+	if !strings.Contains(body, "**Owners:** `@org/web-team`, `@alice`") {
+		t.Errorf("comment should quote owners without mentioning them:\n%s", body)
+	}
+	results[0].mentionOwners = true
+	body = generateCommentBody(results, true)
+	if !strings.Contains(body, "**Owners:** @org/web-team, @alice") {
+		t.Errorf("comment should mention owners:\n%s", body)
+	}
+
+	var buf bytes.Buffer
+	writeResults(&buf, results)
+	if !strings.Contains(buf.String(), "❌ web/xss/challenge.yml:\n  👤 Owners: @org/web-team, @alice\n") {
+		t.Errorf("CLI output should list owners:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// maxCompareFiles is the most files the Compare API lists for a comparison
+const maxCompareFiles = 300
+
+// comparePRFiles lists the files that differ between the merge base of the
+// PR and its head commit, with the Compare API. Unlike the PR's file list,
+// which GitHub computes when the PR is updated, it always reflects the
+// current base and head, such as after a force-pushed rebase.
+func comparePRFiles(env Env) ([]*github.CommitFile, error) {
+	client, ctx := getGitHubClient(env.token)
+
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error getting PR: %v", err)
+	}
+	base, head := pr.GetBase().GetSHA(), pr.GetHead().GetSHA()
+	if base == "" || head == "" {
+		return nil, fmt.Errorf("PR #%d has no base or head commit", env.prNumber)
+	}
+	// Commits of forks are compared as <owner>:<ref>
+	if owner := pr.GetHead().GetRepo().GetOwner().GetLogin(); owner != "" && owner != env.owner {
+		head = owner + ":" + head
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, env.owner, env.repo, base, head, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing %s...%s: %v", base, head, err)
+	}
+	if len(comparison.Files) >= maxCompareFiles {
+		return nil, fmt.Errorf("comparison of %s...%s lists %d files or more", base, head, maxCompareFiles)
+	}
+	return comparison.Files, nil
+}
+
+// mergePRFiles returns the files of both lists, once per path and previous path
+func mergePRFiles(files, more []*github.CommitFile) []*github.CommitFile {
+	seen := make(map[[2]string]bool)
+	var merged []*github.CommitFile
+	for _, list := range [][]*github.CommitFile{files, more} {
+		for _, file := range list {
+			key := [2]string{file.GetFilename(), file.GetPreviousFilename()}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, file)
+		}
+	}
+	return merged
+}
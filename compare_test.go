@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func newCompareAPI(t *testing.T, headOwner string, files int) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"number": 7, "base": {"sha": "base1"}, "head": {"sha": "head2", "repo": {"name": "repo", "owner": {"login": %q}}}}`, headOwner)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/compare/", func(w http.ResponseWriter, r *http.Request) {
+		want := "base1...head2"
+		if headOwner != "owner" {
+			want = "base1..." + headOwner + ":head2"
+		}
+		if got := strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/compare/"); got != want {
+			t.Errorf("compared %s, want %s", got, want)
+		}
+		entries := []string{`{"filename": "web/new/challenge.yml", "previous_filename": "web/old/challenge.yml", "status": "renamed"}`}
+		for i := 1; i < files; i++ {
+			entries = append(entries, fmt.Sprintf(`{"filename": "misc/file%d", "status": "added"}`, i))
+		}
+		fmt.Fprintf(w, `{"files": [%s]}`, strings.Join(entries, ","))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("GITHUB_API_URL", server.URL)
+}
+
+func TestComparePRFiles(t *testing.T) {
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	for _, headOwner := range []string{"owner", "contributor"} {
+		t.Run(headOwner, func(t *testing.T) {
+			newCompareAPI(t, headOwner, 1)
+			files, err := comparePRFiles(env)
+			if err != nil {
+				t.Fatalf("comparePRFiles() error: %v", err)
+			}
+			if len(files) != 1 || files[0].GetFilename() != "web/new/challenge.yml" || files[0].GetPreviousFilename() != "web/old/challenge.yml" {
+				t.Errorf("comparePRFiles() = %v", files)
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		newCompareAPI(t, "owner", maxCompareFiles)
+		if _, err := comparePRFiles(env); err == nil {
+			t.Error("expected an error for a comparison at the file limit")
+		}
+	})
+}
+
+func TestMergePRFiles(t *testing.T) {
+	file := func(name, previous string) *github.CommitFile {
+		f := &github.CommitFile{Filename: github.String(name)}
+		if previous != "" {
+			f.PreviousFilename = github.String(previous)
+		}
+		return f
+	}
+	merged := mergePRFiles(
+		[]*github.CommitFile{file("a", ""), file("b", "")},
+		[]*github.CommitFile{file("b", ""), file("c", "b-old"), file("a", "")},
+	)
+	var names []string
+	for _, f := range merged {
+		names = append(names, f.GetFilename()+"<"+f.GetPreviousFilename())
+	}
+	want := []string{"a<", "b<", "c<b-old"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("mergePRFiles() = %v, want %v", names, want)
+	}
+}
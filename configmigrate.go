@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// currentConfigVersion is the lintrc.yaml schema this release reads. An
+// incompatible schema change bumps it and adds the migration from the
+// previous version to configMigrations.
+const currentConfigVersion = 2
+
+// configMigration rewrites a lintrc.yaml of version From as version From+1
+type configMigration struct {
+	From int
+	// Note explains the change; it is written as a comment above config_version
+	Note  string
+	Apply func(data []byte) ([]byte, error)
+}
+
+// configMigrations are applied in order to configs older than
+// currentConfigVersion. Unversioned configs are version 0.
+var configMigrations = []configMigration{
+	{
+		From: 0,
+		Note: "config_version 1 records the schema version; no settings changed",
+		Apply: func(data []byte) ([]byte, error) {
+			return data, nil
+		},
+	},
+	{
+		From: 1,
+		Note: "the per-category overrides 'categories' are now 'profiles' (layout.categories and reviewers.categories are unchanged)",
+		Apply: func(data []byte) ([]byte, error) {
+			return renameTopLevelKey(data, "categories", "profiles")
+		},
+	},
+}
+
+// configVersion returns the config_version of a lintrc.yaml, 0 if unset
+func configVersion(data []byte) (int, error) {
+	field, err := findTopLevelField(data, "config_version")
+	if err != nil || field == nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(resolveAlias(field.Value).Value)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("invalid config_version: %s", resolveAlias(field.Value).Value)
+	}
+	return version, nil
+}
+
+// migrateConfig upgrades a lintrc.yaml to currentConfigVersion, keeping its
+// comments and layout, and returns the notes of the applied migrations
+func migrateConfig(data []byte) ([]byte, []string, error) {
+	version, err := configVersion(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version > currentConfigVersion {
+		return nil, nil, fmt.Errorf("config_version %d is newer than this clilint supports (%d)", version, currentConfigVersion)
+	}
+	if version == currentConfigVersion {
+		return data, nil, nil
+	}
+
+	var notes []string
+	for _, migration := range configMigrations {
+		if migration.From < version {
+			continue
+		}
+		if data, err = migration.Apply(data); err != nil {
+			return nil, nil, fmt.Errorf("migrating from config_version %d: %v", migration.From, err)
+		}
+		notes = append(notes, migration.Note)
+	}
+
+	data, err = setTopLevelScalar(data, "config_version", strconv.Itoa(currentConfigVersion))
+	if err != nil {
+		return nil, nil, err
+	}
+	return commentAbove(data, "config_version", notes), notes, nil
+}
+
+// upgradeConfig migrates a lintrc.yaml to currentConfigVersion in memory, so
+// older configs are read as clilint config migrate would write them
+func upgradeConfig(data []byte) ([]byte, error) {
+	version, err := configVersion(data)
+	if err != nil {
+		// Malformed YAML is reported by the caller's parser
+		return data, nil
+	}
+	if version > currentConfigVersion {
+		return nil, fmt.Errorf("lintrc.yaml has config_version %d, newer than this clilint supports (%d)", version, currentConfigVersion)
+	}
+	if version == currentConfigVersion {
+		return data, nil
+	}
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("lintrc.yaml: %v", err)
+	}
+	return migrated, nil
+}
+
+// renameTopLevelKey renames a top-level key in place, keeping its value,
+// comments, and layout
+func renameTopLevelKey(data []byte, from, to string) ([]byte, error) {
+	field, err := findTopLevelField(data, from)
+	if err != nil || field == nil {
+		return data, err
+	}
+	if other, err := findTopLevelField(data, to); err != nil || other != nil {
+		return nil, fmt.Errorf("both '%s' and '%s' are set; merge them into '%s'", from, to, to)
+	}
+	lines := strings.Split(string(data), "\n")
+	line := lines[field.Key.Line-1]
+	start := field.Key.Column - 1
+	if start >= 0 && start <= len(line) {
+		for _, quote := range []string{"", "\"", "'"} {
+			if key := quote + from + quote; strings.HasPrefix(line[start:], key) {
+				lines[field.Key.Line-1] = line[:start] + quote + to + quote + line[start+len(key):]
+				return []byte(strings.Join(lines, "\n")), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("cannot rename '%s' in place; rename it to '%s' by hand", from, to)
+}
+
+// commentAbove inserts comment lines above a top-level key
+func commentAbove(data []byte, key string, comments []string) []byte {
+	field, err := findTopLevelField(data, key)
+	if err != nil || field == nil || len(comments) == 0 {
+		return data
+	}
+	_, cr := cutCR(strings.SplitN(string(data), "\n", 2)[0])
+	lines := strings.Split(string(data), "\n")
+	var inserted []string
+	for _, comment := range comments {
+		inserted = append(inserted, "# clilint config migrate: "+comment+cr)
+	}
+	updated := append([]string{}, lines[:field.Key.Line-1]...)
+	updated = append(updated, inserted...)
+	updated = append(updated, lines[field.Key.Line-1:]...)
+	return []byte(strings.Join(updated, "\n"))
+}
+
+// runConfig runs the config subcommands
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "migrate" {
+		log.Fatalf("Usage: clilint config migrate [--write] [lintrc.yaml]")
+	}
+	runConfigMigrate(args[1:])
+}
+
+// runConfigMigrate prints a lintrc.yaml migrated to the current schema, or
+// with --write updates it in place
+func runConfigMigrate(args []string) {
+	write := false
+	path := "lintrc.yaml"
+	for _, arg := range args {
+		if arg == "--write" {
+			write = true
+		} else if strings.HasPrefix(arg, "-") {
+			log.Fatalf("Usage: clilint config migrate [--write] [lintrc.yaml]")
+		} else {
+			path = arg
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", path, err)
+	}
+	migrated, notes, err := migrateConfig(data)
+	if err != nil {
+		log.Fatalf("Error migrating %s: %v", path, err)
+	}
+
+	if !write {
+		if _, err := os.Stdout.Write(migrated); err != nil {
+			log.Fatalf("Error writing output: %v", err)
+		}
+		return
+	}
+	if len(notes) == 0 {
+		fmt.Printf("%s is already at config_version %d\n", path, currentConfigVersion)
+		return
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		log.Fatalf("Error writing %s: %v", path, err)
+	}
+	fmt.Printf("%s: migrated to config_version %d\n", path, currentConfigVersion)
+	for _, note := range notes {
+		fmt.Printf("  - %s\n", note)
+	}
+}
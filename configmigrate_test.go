@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	old := "# Tags of the 2025 event\ntags:\n  condition: and # every pattern\nrequirements:\n  condition: none\n"
+
+	migrated, notes, err := migrateConfig([]byte(old))
+	if err != nil {
+		t.Fatalf("migrateConfig() failed: %v", err)
+	}
+	want := old + "# clilint config migrate: " + configMigrations[0].Note +
+		"\n# clilint config migrate: " + configMigrations[1].Note + "\nconfig_version: 2\n"
+	if string(migrated) != want {
+		t.Errorf("migrateConfig() =\n%s\nwant\n%s", migrated, want)
+	}
+	if len(notes) != 2 {
+		t.Errorf("notes = %v", notes)
+	}
+	config, err := parseLintConfig(migrated, ".")
+	if err != nil || config.ConfigVersion != currentConfigVersion || config.Requirements.Condition != "none" {
+		t.Errorf("migrated config does not load: %+v, %v", config, err)
+	}
+
+	// Current configs are left alone
+	again, notes, err := migrateConfig(migrated)
+	if err != nil || len(notes) != 0 || string(again) != string(migrated) {
+		t.Errorf("migrating a current config changed it: %q, %v, %v", again, notes, err)
+	}
+
+	// CRLF files keep their line endings
+	crlf, _, err := migrateConfig([]byte("tags:\r\n  condition: and\r\n"))
+	if err != nil || strings.Count(string(crlf), "\r\n") != strings.Count(string(crlf), "\n") {
+		t.Errorf("line endings changed: %q, %v", crlf, err)
+	}
+}
+
+func TestMigrateConfigProfiles(t *testing.T) {
+	old := "config_version: 1\n# Per-category overrides\ncategories: # web needs a host\n  web:\n    require_host: true\nlayout:\n  categories: [web]\n"
+
+	migrated, notes, err := migrateConfig([]byte(old))
+	if err != nil {
+		t.Fatalf("migrateConfig() failed: %v", err)
+	}
+	want := "# clilint config migrate: " + configMigrations[1].Note +
+		"\nconfig_version: 2\n# Per-category overrides\nprofiles: # web needs a host\n  web:\n    require_host: true\nlayout:\n  categories: [web]\n"
+	if string(migrated) != want {
+		t.Errorf("migrateConfig() =\n%s\nwant\n%s", migrated, want)
+	}
+	if len(notes) != 1 {
+		t.Errorf("notes = %v", notes)
+	}
+
+	// Unmigrated configs keep working
+	config, err := parseLintConfig([]byte(old), ".")
+	if err != nil {
+		t.Fatalf("parseLintConfig() failed: %v", err)
+	}
+	if !config.forCategory("web").RequireHost || config.ConfigVersion != currentConfigVersion {
+		t.Errorf("categories was not read as profiles: %+v", config.Profiles)
+	}
+
+	quoted, _, err := migrateConfig([]byte("\"categories\":\n  web:\n    require_host: true\n"))
+	if err != nil || !strings.HasPrefix(string(quoted), "\"profiles\":\n") {
+		t.Errorf("quoted key not renamed: %q, %v", quoted, err)
+	}
+	if _, _, err := migrateConfig([]byte("categories: {}\nprofiles: {}\n")); err == nil {
+		t.Error("expected categories and profiles together to be an error")
+	}
+}
+
+func TestMigrateConfigRejects(t *testing.T) {
+	for _, data := range []string{"config_version: 99\n", "config_version: latest\n"} {
+		if _, _, err := migrateConfig([]byte(data)); err == nil {
+			t.Errorf("migrateConfig(%q) succeeded", data)
+		}
+	}
+	if _, err := parseLintConfig([]byte("config_version: 99\n"), "."); err == nil {
+		t.Error("a config newer than the binary was loaded")
+	}
+}
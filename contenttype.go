@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// contentSniffLength is how much of an attachment is read to detect its
+// type; the tar magic is the furthest in, at offset 257
+const contentSniffLength = 512
+
+// contentMagic identifies a file format by the bytes at an offset
+type contentMagic struct {
+	format string
+	offset int
+	magic  []byte
+}
+
+// contentMagics are the formats attachments are commonly in. Weak two-byte
+// magics come last: a tar starts with the name of its first entry, which
+// may well start with "BM" or "MZ".
+var contentMagics = []contentMagic{
+	{"tar", 257, []byte("ustar")},
+	{"zip", 0, []byte("PK\x03\x04")},
+	{"zip", 0, []byte("PK\x05\x06")},
+	{"gzip", 0, []byte("\x1f\x8b")},
+	{"bzip2", 0, []byte("BZh")},
+	{"xz", 0, []byte("\xfd7zXZ\x00")},
+	{"zstd", 0, []byte("\x28\xb5\x2f\xfd")},
+	{"7z", 0, []byte("7z\xbc\xaf\x27\x1c")},
+	{"rar", 0, []byte("Rar!\x1a\x07")},
+	{"png", 0, []byte("\x89PNG\r\n\x1a\n")},
+	{"jpeg", 0, []byte("\xff\xd8\xff")},
+	{"gif", 0, []byte("GIF87a")},
+	{"gif", 0, []byte("GIF89a")},
+	{"webp", 8, []byte("WEBP")},
+	{"wav", 8, []byte("WAVE")},
+	{"pdf", 0, []byte("%PDF-")},
+	{"elf", 0, []byte("\x7fELF")},
+	{"pcap", 0, []byte("\xd4\xc3\xb2\xa1")},
+	{"pcap", 0, []byte("\xa1\xb2\xc3\xd4")},
+	{"pcapng", 0, []byte("\x0a\x0d\x0d\x0a")},
+	{"sqlite", 0, []byte("SQLite format 3\x00")},
+	{"mp4", 4, []byte("ftyp")},
+	{"mp3", 0, []byte("ID3")},
+	{"ogg", 0, []byte("OggS")},
+	{"flac", 0, []byte("fLaC")},
+	{"bmp", 0, []byte("BM")},
+	{"pe", 0, []byte("MZ")},
+}
+
+// extensionFormats are the formats a file with each extension may be in.
+// Extensions not listed, such as .txt or .bin, are not checked.
+var extensionFormats = map[string][]string{
+	".zip":     {"zip"},
+	".docx":    {"zip"},
+	".xlsx":    {"zip"},
+	".pptx":    {"zip"},
+	".jar":     {"zip"},
+	".apk":     {"zip"},
+	".gz":      {"gzip"},
+	".tgz":     {"gzip"},
+	".bz2":     {"bzip2"},
+	".xz":      {"xz"},
+	".zst":     {"zstd"},
+	".7z":      {"7z"},
+	".rar":     {"rar"},
+	".tar":     {"tar"},
+	".png":     {"png"},
+	".jpg":     {"jpeg"},
+	".jpeg":    {"jpeg"},
+	".gif":     {"gif"},
+	".bmp":     {"bmp"},
+	".webp":    {"webp"},
+	".wav":     {"wav"},
+	".pdf":     {"pdf"},
+	".exe":     {"pe"},
+	".dll":     {"pe"},
+	".pcap":    {"pcap", "pcapng"},
+	".pcapng":  {"pcapng"},
+	".sqlite":  {"sqlite"},
+	".sqlite3": {"sqlite"},
+	".db":      {"sqlite"},
+	".mp4":     {"mp4"},
+	".m4a":     {"mp4"},
+	".mov":     {"mp4"},
+	".mp3":     {"mp3"},
+	".ogg":     {"ogg"},
+	".flac":    {"flac"},
+}
+
+// detectContentFormat returns the format of a file's first bytes, or "" if
+// it is none of contentMagics
+func detectContentFormat(head []byte) string {
+	for _, magic := range contentMagics {
+		end := magic.offset + len(magic.magic)
+		if len(head) >= end && bytes.Equal(head[magic.offset:end], magic.magic) {
+			return magic.format
+		}
+	}
+	return ""
+}
+
+// checkContentTypes warns about attachments whose contents are in another
+// format than their extension says, such as a .zip that is a tar or a .png
+// that is a JPEG: players' tools and some browsers go by the extension
+func checkContentTypes(challengePath string, files []string) []string {
+	var warnings []string
+	baseDir := filepath.Dir(challengePath)
+
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		expected, ok := extensionFormats[strings.ToLower(filepath.Ext(file))]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(baseDir, normalizeFilePath(file))
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			// Missing files are reported by checkFiles
+			continue
+		}
+		head, err := readHead(path, contentSniffLength)
+		if err != nil {
+			continue
+		}
+		// Unrecognized contents, including Git LFS pointers, are not reported
+		detected := detectContentFormat(head)
+		if detected == "" || slices.Contains(expected, detected) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Attachment '%s' contains %s data, not %s as its extension says", file, formatName(detected), formatName(expected[0])))
+	}
+	return warnings
+}
+
+// readHead reads up to n bytes from the start of a file
+func readHead(path string, n int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	head := make([]byte, n)
+	read, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:read], nil
+}
+
+// formatName is how a detected format is named in findings
+func formatName(format string) string {
+	if format == "pe" {
+		return "PE (Windows executable)"
+	}
+	return strings.ToUpper(format)
+}
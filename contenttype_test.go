@@ -0,0 +1,65 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectContentFormat(t *testing.T) {
+	var tarball bytes.Buffer
+	archive := tar.NewWriter(&tarball)
+	archive.WriteHeader(&tar.Header{Name: "BMP/notes.txt", Mode: 0644, Typeflag: tar.TypeReg})
+	archive.Close()
+
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"zip", []byte("PK\x03\x04\x14\x00"), "zip"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00"), "png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "jpeg"},
+		{"tar with an entry named like a BMP", tarball.Bytes(), "tar"},
+		{"webp", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "webp"},
+		{"text", []byte("hello world\n"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := detectContentFormat(tt.head); got != tt.want {
+			t.Errorf("detectContentFormat(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckContentTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"flag.png":     "\xff\xd8\xff\xe0\x00\x10JFIF",
+		"photo.JPG":    "\xff\xd8\xff\xe1\x00\x10Exif",
+		"dist.zip":     "\x1f\x8b\x08\x00",
+		"capture.pcap": "\x0a\x0d\x0d\x0a",
+		"notes.txt":    "\x89PNG\r\n\x1a\n",
+		"big.zip":      "version https://git-lfs.github.com/spec/v1\n",
+		"short.png":    "\x89P",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := checkContentTypes(filepath.Join(tempDir, "challenge.yml"), []string{
+		"flag.png", "photo.JPG", "dist.zip", "capture.pcap", "notes.txt", "big.zip", "short.png", "missing.png", "https://example.com/a.png",
+	})
+	want := []string{
+		"Attachment 'flag.png' contains JPEG data, not PNG as its extension says",
+		"Attachment 'dist.zip' contains GZIP data, not ZIP as its extension says",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkContentTypes() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Deprecation marks a field (or one of its values) as scheduled for removal.
+// Until Sunset it is reported as deprecated, afterwards as an error.
+type Deprecation struct {
+	Field   string `yaml:"field"`
+	Value   string `yaml:"value"`   // optional; empty matches any value
+	Sunset  string `yaml:"sunset"`  // YYYY-MM-DD
+	Message string `yaml:"message"` // migration hint shown to authors
+}
+
+// now is replaced in tests
+var now = time.Now
+
+func checkDeprecations(raw map[string]interface{}, deprecations []Deprecation) (deprecated []string, errors []string) {
+	for _, dep := range deprecations {
+		value, present := raw[dep.Field]
+		if !present || value == nil {
+			continue
+		}
+		if dep.Value != "" && fmt.Sprint(value) != dep.Value {
+			continue
+		}
+
+		subject := fmt.Sprintf("Field '%s'", dep.Field)
+		if dep.Value != "" {
+			subject = fmt.Sprintf("Field '%s' value '%s'", dep.Field, dep.Value)
+		}
+		hint := ""
+		if dep.Message != "" {
+			hint = ": " + dep.Message
+		}
+
+		if dep.Sunset == "" {
+			deprecated = append(deprecated, fmt.Sprintf("%s is deprecated%s", subject, hint))
+			continue
+		}
+		sunset, err := time.Parse("2006-01-02", dep.Sunset)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid sunset date '%s' for deprecated field '%s'", dep.Sunset, dep.Field))
+			continue
+		}
+		if !now().Before(sunset) {
+			errors = append(errors, fmt.Sprintf("%s is no longer allowed since %s%s", subject, dep.Sunset, hint))
+		} else {
+			deprecated = append(deprecated, fmt.Sprintf("%s is deprecated and becomes an error on %s%s", subject, dep.Sunset, hint))
+		}
+	}
+	return deprecated, errors
+}
+
+// writeDeprecationSummary appends a summary of deprecation findings across
+// all results to the PR comment, grouped by message
+func writeDeprecationSummary(body *strings.Builder, results []LintResult) {
+	counts := make(map[string]int)
+	for _, result := range results {
+		for _, dep := range result.Deprecations {
+			counts[dep.Message]++
+		}
+	}
+	if len(counts) == 0 {
+		return
+	}
+
+	messages := make([]string, 0, len(counts))
+	for message := range counts {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+
+	body.WriteString("### ⏳ Deprecations Summary\n\n")
+	for _, message := range messages {
+		body.WriteString(fmt.Sprintf("- %s (%d challenge(s))\n", message, counts[message]))
+	}
+	body.WriteString("\n")
+}
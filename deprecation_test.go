@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckDeprecations(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	raw := map[string]interface{}{
+		"type":    "standard",
+		"author":  "alice",
+		"version": "0.1",
+		"host":    nil,
+	}
+
+	tests := []struct {
+		name           string
+		deprecations   []Deprecation
+		wantDeprecated []string
+		wantErrors     []string
+	}{
+		{
+			name:           "value deprecated before sunset",
+			deprecations:   []Deprecation{{Field: "type", Value: "standard", Sunset: "2026-12-01", Message: "use 'dynamic'"}},
+			wantDeprecated: []string{"Field 'type' value 'standard' is deprecated and becomes an error on 2026-12-01: use 'dynamic'"},
+		},
+		{
+			name:         "value deprecated after sunset",
+			deprecations: []Deprecation{{Field: "type", Value: "standard", Sunset: "2026-01-01"}},
+			wantErrors:   []string{"Field 'type' value 'standard' is no longer allowed since 2026-01-01"},
+		},
+		{
+			name:           "field deprecated without sunset",
+			deprecations:   []Deprecation{{Field: "author", Message: "use the author tag"}},
+			wantDeprecated: []string{"Field 'author' is deprecated: use the author tag"},
+		},
+		{
+			name:         "other value is not deprecated",
+			deprecations: []Deprecation{{Field: "type", Value: "dynamic", Sunset: "2026-01-01"}},
+		},
+		{
+			name:         "missing and null fields are ignored",
+			deprecations: []Deprecation{{Field: "hints"}, {Field: "host"}},
+		},
+		{
+			name:         "invalid sunset date",
+			deprecations: []Deprecation{{Field: "version", Sunset: "next year"}},
+			wantErrors:   []string{"Invalid sunset date 'next year'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deprecated, errs := checkDeprecations(raw, tt.deprecations)
+			if len(deprecated) != len(tt.wantDeprecated) || len(errs) != len(tt.wantErrors) {
+				t.Fatalf("Expected deprecations %v and errors %v, got %v and %v", tt.wantDeprecated, tt.wantErrors, deprecated, errs)
+			}
+			for i, want := range tt.wantDeprecated {
+				if deprecated[i] != want {
+					t.Errorf("Expected deprecation '%s', got '%s'", want, deprecated[i])
+				}
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("Expected error containing '%s', got '%s'", want, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDeprecationSummaryInComment(t *testing.T) {
+	results := []LintResult{
+		{File: "a/challenge.yml", Name: "a", Deprecations: []Finding{{Message: "Field 'type' is deprecated"}}},
+		{File: "b/challenge.yml", Name: "b", Deprecations: []Finding{{Message: "Field 'type' is deprecated"}}},
+		{File: "c/challenge.yml", Name: "c"},
+	}
+	body := generateCommentBody(results, false)
+
+	if !strings.Contains(body, "#### ⚠️ **a** (`a/challenge.yml`)") {
+		t.Errorf("Expected deprecated challenge to be highlighted, got:\n%s", body)
+	}
+	if !strings.Contains(body, "### ⏳ Deprecations Summary\n\n- Field 'type' is deprecated (2 challenge(s))") {
+		t.Errorf("Expected deprecation summary, got:\n%s", body)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// attachmentExtensions are the extensions of names in a description that
+// are taken as references to attachments
+var attachmentExtensions = []string{
+	"7z", "apk", "bin", "bmp", "bz2", "c", "cpp", "csv", "db", "dmp", "docx",
+	"e01", "elf", "exe", "gif", "gz", "img", "iso", "jar", "jpeg", "jpg",
+	"json", "log", "mem", "mp3", "mp4", "ova", "pcap", "pcapng", "pdf", "png",
+	"py", "rar", "raw", "sage", "sqlite", "svg", "tar", "tgz", "txt", "vmdk",
+	"wav", "xlsx", "xz", "zip",
+}
+
+var (
+	attachmentNamePattern = regexp.MustCompile(`(?i)\b\w[\w.-]*\.(?:` + strings.Join(attachmentExtensions, "|") + `)\b`)
+	// ctfdFileURLPattern matches links to files uploaded to a CTFd instance,
+	// whose upload hash changes when the challenge is imported again
+	ctfdFileURLPattern = regexp.MustCompile(`(?:https?://[^\s/]+)?/files/[0-9a-f]{32}/[^\s)"'<>]+`)
+	codeBlockPattern   = regexp.MustCompile("(?s)```.*?(```|$)|`[^`\n]*`")
+	urlPattern         = regexp.MustCompile(`[a-z][a-z0-9+.-]*://\S+`)
+)
+
+// checkDescriptionFileURLs reports hard-coded CTFd /files/ URLs, which break
+// when the challenge is imported into another instance
+func checkDescriptionFileURLs(description string) []string {
+	var errors []string
+
+	for _, url := range ctfdFileURLPattern.FindAllString(description, -1) {
+		url = strings.TrimRight(url, ".,;")
+		errors = append(errors, fmt.Sprintf("Field 'description' links the uploaded file '%s', which breaks on re-import; list the file in 'files' instead", url))
+	}
+	return errors
+}
+
+// checkDescriptionFiles warns about attachment names mentioned in the
+// description that no files or external_files entry provides. Names in code
+// spans and blocks, such as a flag.txt on the server, and in URLs are skipped.
+func checkDescriptionFiles(challenge Challenge) []string {
+	var warnings []string
+
+	provided := make(map[string]bool)
+	for _, file := range challenge.Files {
+		provided[strings.ToLower(path.Base(slashPath(file)))] = true
+	}
+	for _, file := range challenge.ExternalFiles {
+		provided[strings.ToLower(path.Base(file.URL))] = true
+	}
+
+	text := codeBlockPattern.ReplaceAllString(challenge.Description, " ")
+	text = urlPattern.ReplaceAllString(text, " ")
+	seen := make(map[string]bool)
+	for _, name := range attachmentNamePattern.FindAllString(text, -1) {
+		key := strings.ToLower(name)
+		if seen[key] || provided[key] {
+			continue
+		}
+		seen[key] = true
+		warnings = append(warnings, fmt.Sprintf("Field 'description' mentions '%s', which is not in 'files'", name))
+	}
+	return warnings
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckDescriptionFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge Challenge
+		want      []string
+	}{
+		{
+			name:      "mentioned attachments are provided",
+			challenge: Challenge{Description: "Analyze capture.pcap (and notes.TXT).", Files: []string{"dist/capture.pcap", "dist/notes.txt"}},
+		},
+		{
+			name: "external files and remote entries",
+			challenge: Challenge{
+				Description:   "Download disk.img and memory.raw.",
+				Files:         []string{"https://cdn.example.com/ctf/disk.img"},
+				ExternalFiles: []ExternalFile{{URL: "https://cdn.example.com/ctf/memory.raw"}},
+			},
+		},
+		{
+			name:      "missing attachments are reported once",
+			challenge: Challenge{Description: "See capture.pcap, then capture.pcap again and solver.py.", Files: []string{"dist/other.pcap"}},
+			want: []string{
+				"Field 'description' mentions 'capture.pcap', which is not in 'files'",
+				"Field 'description' mentions 'solver.py', which is not in 'files'",
+			},
+		},
+		{
+			name:      "code and URLs are skipped",
+			challenge: Challenge{Description: "Read `flag.txt` on the server:\n```\ncat /app/secret.txt\n```\nDocs: https://example.com/guide.pdf. Built with Node.js."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkDescriptionFiles(tt.challenge)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkDescriptionFiles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDescriptionFileURLs(t *testing.T) {
+	description := "Get it [here](https://ctf.example.com/files/0123456789abcdef0123456789abcdef/chall.zip?token=x).\nOr from /files/ in the repo."
+	want := []string{"Field 'description' links the uploaded file 'https://ctf.example.com/files/0123456789abcdef0123456789abcdef/chall.zip?token=x', which breaks on re-import; list the file in 'files' instead"}
+	if got := checkDescriptionFileURLs(description); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkDescriptionFileURLs() = %q, want %q", got, want)
+	}
+}
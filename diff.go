@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChallengeDiff is the change of one challenge between a git ref and the
+// working tree
+type ChallengeDiff struct {
+	File   string `json:"file"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // added, removed, modified, or moved
+	// From is the previous file of a moved challenge
+	From    string        `json:"from,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange is a semantic change of one challenge.yml field
+type FieldChange struct {
+	Field   string   `json:"field"`
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Note summarizes changes whose values are not shown, e.g. of flags
+	Note string `json:"note,omitempty"`
+}
+
+func runDiff(args []string) {
+	jsonOutput := false
+	var ref string
+	var targetDirs []string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		} else if ref == "" {
+			ref = arg
+		} else {
+			targetDirs = append(targetDirs, arg)
+		}
+	}
+	if ref == "" {
+		log.Fatalf("Usage: clilint diff <ref> [--json] [directory...]")
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	before, err := challengesAtRef(ref, targetDirs, config)
+	if err != nil {
+		log.Fatalf("Error reading challenges at %s: %v", ref, err)
+	}
+	after, err := challengesInTree(targetDirs, config)
+	if err != nil {
+		log.Fatalf("Error reading challenges: %v", err)
+	}
+
+	diffs := diffChallenges(before, after)
+	if jsonOutput {
+		jsonData, err := json.Marshal(map[string]interface{}{"ref": ref, "challenges": diffs})
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+	writeDiff(os.Stdout, ref, diffs)
+}
+
+// challengesAtRef parses the challenge.yml files under dirs as of a git ref.
+// Includes are resolved against the snippets of the working tree.
+func challengesAtRef(ref string, dirs []string, config *LintConfig) (map[string]Challenge, error) {
+	challenges := make(map[string]Challenge)
+	for _, dir := range dirs {
+		output, err := exec.Command("git", "ls-tree", "-r", "--name-only", "-z", ref, "--", dir).Output()
+		if err != nil {
+			return nil, gitError(err)
+		}
+		for _, path := range strings.Split(string(output), "\x00") {
+			if filepath.Base(path) != "challenge.yml" {
+				continue
+			}
+			path = filepath.FromSlash(path)
+			rel, err := filepath.Rel(dir, path)
+			if err != nil || isIgnored(filepath.ToSlash(rel), false, config.Ignore) || isTemplateFile(path, config) {
+				continue
+			}
+			data, err := exec.Command("git", "show", ref+":./"+filepath.ToSlash(path)).Output()
+			if err != nil {
+				return nil, gitError(err)
+			}
+			challenge, err := parseChallengeData(data, config)
+			if err != nil {
+				log.Printf("Warning: skipping %s at %s: %v", path, ref, err)
+				continue
+			}
+			challenges[path] = challenge
+		}
+	}
+	return challenges, nil
+}
+
+// challengesInTree parses the challenge.yml files under dirs in the working tree
+func challengesInTree(dirs []string, config *LintConfig) (map[string]Challenge, error) {
+	challenges := make(map[string]Challenge)
+	for _, dir := range dirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := readYAMLFile(path, config.YAMLLimits)
+			if err != nil {
+				return nil, err
+			}
+			challenge, err := parseChallengeData(data, config)
+			if err != nil {
+				log.Printf("Warning: skipping %s: %v", path, err)
+				continue
+			}
+			challenges[filepath.Clean(path)] = challenge
+		}
+	}
+	return challenges, nil
+}
+
+func parseChallengeData(data []byte, config *LintConfig) (Challenge, error) {
+	var challenge Challenge
+	data, err := resolveChallengeData(data, config)
+	if err != nil {
+		return challenge, err
+	}
+	if err := yaml.Unmarshal(data, &challenge); err != nil {
+		return challenge, fmt.Errorf("invalid YAML format: %v", err)
+	}
+	return challenge, nil
+}
+
+// gitError includes git's stderr in the error, if any
+func gitError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
+
+// diffChallenges pairs challenges by file, then removed and added
+// challenges by name as moves, and returns the changed ones sorted by file
+func diffChallenges(before, after map[string]Challenge) []ChallengeDiff {
+	var diffs []ChallengeDiff
+	removed := make(map[string]string) // name -> file
+	for file, old := range before {
+		if _, ok := after[file]; !ok {
+			removed[old.Name] = file
+		}
+	}
+
+	for file, challenge := range after {
+		old, ok := before[file]
+		if !ok {
+			if from, moved := removed[challenge.Name]; moved && challenge.Name != "" {
+				delete(removed, challenge.Name)
+				diffs = append(diffs, ChallengeDiff{File: file, Name: challenge.Name, Status: "moved", From: from, Changes: diffChallenge(before[from], challenge)})
+				continue
+			}
+			diffs = append(diffs, ChallengeDiff{File: file, Name: challenge.Name, Status: "added"})
+			continue
+		}
+		if changes := diffChallenge(old, challenge); len(changes) > 0 {
+			diffs = append(diffs, ChallengeDiff{File: file, Name: challenge.Name, Status: "modified", Changes: changes})
+		}
+	}
+	for name, file := range removed {
+		diffs = append(diffs, ChallengeDiff{File: file, Name: name, Status: "removed"})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].File < diffs[j].File })
+	return diffs
+}
+
+// diffChallenge returns the field changes between two versions of a
+// challenge. Flags and descriptions are summarized rather than shown.
+func diffChallenge(old, current Challenge) []FieldChange {
+	var changes []FieldChange
+
+	scalar := func(field string, oldValue, newValue interface{}) {
+		if o, n := formatDiffValue(oldValue), formatDiffValue(newValue); o != n {
+			changes = append(changes, FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+	list := func(field string, oldValues, newValues []string) {
+		added, removed := diffLists(oldValues, newValues)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	scalar("name", old.Name, current.Name)
+	scalar("author", old.Author, current.Author)
+	scalar("category", old.Category, current.Category)
+	if old.Description != current.Description {
+		changes = append(changes, FieldChange{Field: "description", Note: "changed"})
+	}
+	if old.DescriptionJA != current.DescriptionJA {
+		changes = append(changes, FieldChange{Field: "description_ja", Note: "changed"})
+	}
+	if note := diffFlags(old.Flags, current.Flags); note != "" {
+		changes = append(changes, FieldChange{Field: "flags", Note: note})
+	}
+	list("tags", old.Tags, current.Tags)
+	list("files", old.Files, current.Files)
+	list("requirements", old.Requirements, current.Requirements)
+	scalar("value", old.Value, current.Value)
+	scalar("type", old.Type, current.Type)
+
+	keys := make(map[string]bool)
+	for key := range old.Extra {
+		keys[key] = true
+	}
+	for key := range current.Extra {
+		keys[key] = true
+	}
+	var extraKeys []string
+	for key := range keys {
+		extraKeys = append(extraKeys, key)
+	}
+	sort.Strings(extraKeys)
+	for _, key := range extraKeys {
+		scalar("extra."+key, old.Extra[key], current.Extra[key])
+	}
+
+	scalar("image", old.Image, current.Image)
+	scalar("host", old.Host, current.Host)
+	scalar("connection_info", old.ConnectionInfo, current.ConnectionInfo)
+	scalar("state", old.State, current.State)
+	scalar("version", old.Version, current.Version)
+	scalar("wave", old.Wave, current.Wave)
+	scalar("hints", hintSummary(old.Hints), hintSummary(current.Hints))
+
+	return changes
+}
+
+// formatDiffValue renders a field value on one line; unset values are ""
+func formatDiffValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		if v == 0 {
+			return ""
+		}
+		return fmt.Sprint(v)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// diffLists returns the items added to and removed from a list
+func diffLists(old, current []string) (added, removed []string) {
+	counts := make(map[string]int)
+	for _, item := range old {
+		counts[item]++
+	}
+	for _, item := range current {
+		if counts[item] > 0 {
+			counts[item]--
+		} else {
+			added = append(added, item)
+		}
+	}
+	for _, item := range old {
+		if counts[item] > 0 {
+			counts[item]--
+			removed = append(removed, item)
+		}
+	}
+	return added, removed
+}
+
+// diffFlags counts added, removed, and changed flags without revealing them
+func diffFlags(old, current []FlagItem) string {
+	key := func(flags []FlagItem) []string {
+		var keys []string
+		for _, flag := range flags {
+			if flag.FlagValue != nil {
+				data := ""
+				if flag.FlagValue.Data != nil {
+					data = *flag.FlagValue.Data
+				}
+				keys = append(keys, strings.Join([]string{flag.FlagValue.Type, flag.FlagValue.Content, data}, "\x00"))
+			} else {
+				keys = append(keys, strings.Join([]string{"static", flag.Content(), ""}, "\x00"))
+			}
+		}
+		return keys
+	}
+	added, removed := diffLists(key(old), key(current))
+	changed := min(len(added), len(removed))
+
+	var parts []string
+	if changed > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", changed))
+	}
+	if n := len(added) - changed; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", n))
+	}
+	if n := len(removed) - changed; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// hintSummary describes hints by count and total cost
+func hintSummary(hints []HintItem) string {
+	if len(hints) == 0 {
+		return ""
+	}
+	total := 0
+	for _, hint := range hints {
+		total += hint.Cost()
+	}
+	return fmt.Sprintf("%d hint(s), cost %d", len(hints), total)
+}
+
+// writeDiff writes the challenge changes in a human readable format
+func writeDiff(w io.Writer, ref string, diffs []ChallengeDiff) {
+	if len(diffs) == 0 {
+		fmt.Fprintf(w, "No challenge changes since %s\n", ref)
+		return
+	}
+	for _, diff := range diffs {
+		switch diff.Status {
+		case "added":
+			fmt.Fprintf(w, "➕ %s (%s)\n", diff.File, diff.Name)
+		case "removed":
+			fmt.Fprintf(w, "➖ %s (%s)\n", diff.File, diff.Name)
+		case "moved":
+			fmt.Fprintf(w, "🚚 %s (%s), moved from %s\n", diff.File, diff.Name, diff.From)
+		default:
+			fmt.Fprintf(w, "📝 %s (%s)\n", diff.File, diff.Name)
+		}
+		for _, change := range diff.Changes {
+			fmt.Fprintf(w, "  - %s: %s\n", change.Field, formatFieldChange(change))
+		}
+	}
+}
+
+func formatFieldChange(change FieldChange) string {
+	if change.Note != "" {
+		return change.Note
+	}
+	if change.Added != nil || change.Removed != nil {
+		var parts []string
+		for _, item := range change.Added {
+			parts = append(parts, "+"+item)
+		}
+		for _, item := range change.Removed {
+			parts = append(parts, "-"+item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	old, current := change.Old, change.New
+	if old == "" {
+		old = "(unset)"
+	}
+	if current == "" {
+		current = "(unset)"
+	}
+	return fmt.Sprintf("%s → %s", old, current)
+}
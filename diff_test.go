@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiffChallenge(t *testing.T) {
+	parse := func(data string) Challenge {
+		var challenge Challenge
+		if err := yaml.Unmarshal([]byte(data), &challenge); err != nil {
+			t.Fatal(err)
+		}
+		return challenge
+	}
+	old := parse(`name: login
+description: old text
+flags: ["flag{old}", "flag{kept}"]
+tags: [easy]
+files: [dist/app.zip]
+value: 300
+type: dynamic
+extra: {initial: 300, decay: 10}
+state: hidden
+`)
+	current := parse(`name: login
+description: new text
+flags: ["flag{new}", "flag{kept}", "flag{extra}"]
+tags: [medium]
+files: [dist/app.zip, dist/README.txt]
+value: 500
+type: dynamic
+extra: {initial: 500, decay: 10, minimum: 100}
+state: visible
+hints: [{content: look closer, cost: 50}]
+`)
+
+	want := []FieldChange{
+		{Field: "description", Note: "changed"},
+		{Field: "flags", Note: "1 changed, 1 added"},
+		{Field: "tags", Added: []string{"medium"}, Removed: []string{"easy"}},
+		{Field: "files", Added: []string{"dist/README.txt"}},
+		{Field: "value", Old: "300", New: "500"},
+		{Field: "extra.initial", Old: "300", New: "500"},
+		{Field: "extra.minimum", New: "100"},
+		{Field: "state", Old: "hidden", New: "visible"},
+		{Field: "hints", New: "1 hint(s), cost 50"},
+	}
+	if got := diffChallenge(old, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffChallenge() = %+v, want %+v", got, want)
+	}
+	if got := diffChallenge(current, current); len(got) != 0 {
+		t.Errorf("expected no changes, got %+v", got)
+	}
+}
+
+func TestDiffChallenges(t *testing.T) {
+	before := map[string]Challenge{
+		"web/a/challenge.yml":    {Name: "a", Value: 100},
+		"web/old/challenge.yml":  {Name: "moved", Value: 100},
+		"web/gone/challenge.yml": {Name: "gone"},
+		"web/same/challenge.yml": {Name: "same"},
+	}
+	after := map[string]Challenge{
+		"web/a/challenge.yml":      {Name: "a", Value: 200},
+		"misc/moved/challenge.yml": {Name: "moved", Value: 100},
+		"web/new/challenge.yml":    {Name: "new"},
+		"web/same/challenge.yml":   {Name: "same"},
+	}
+
+	var got []string
+	for _, diff := range diffChallenges(before, after) {
+		got = append(got, diff.Status+" "+diff.File+" "+diff.From)
+	}
+	want := []string{
+		"moved misc/moved/challenge.yml web/old/challenge.yml",
+		"modified web/a/challenge.yml ",
+		"removed web/gone/challenge.yml ",
+		"added web/new/challenge.yml ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffChallenges() = %v, want %v", got, want)
+	}
+}
+
+func TestChallengesAtRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "--quiet")
+	write("web/login/challenge.yml", "name: login\nvalue: 300\nflags: [\"flag{a}\"]\n")
+	write("web/admin/challenge.yml", "name: admin\nvalue: 100\n")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "initial")
+
+	write("web/login/challenge.yml", "name: login\nvalue: 500\nflags: [\"flag{b}\"]\n")
+	if err := os.RemoveAll("web/admin"); err != nil {
+		t.Fatal(err)
+	}
+	write("pwn/bof/challenge.yml", "name: bof\n")
+
+	config := &LintConfig{}
+	before, err := challengesAtRef("HEAD", []string{"."}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := challengesInTree([]string{"."}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	writeDiff(&out, "HEAD", diffChallenges(before, after))
+	want := `➕ pwn/bof/challenge.yml (bof)
+➖ web/admin/challenge.yml (admin)
+📝 web/login/challenge.yml (login)
+  - flags: 1 changed
+  - value: 300 → 500
+`
+	if got := filepath.ToSlash(out.String()); got != want {
+		t.Errorf("writeDiff() =\n%s\nwant\n%s", got, want)
+	}
+	if strings.Contains(out.String(), "flag{") {
+		t.Error("diff must not reveal flags")
+	}
+
+	if _, err := challengesAtRef("no-such-ref", []string{"."}, config); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
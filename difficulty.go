@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DifficultyRule configures the advisory warnings for challenges whose
+// value, hints, or binaries seem inconsistent with their difficulty tag
+type DifficultyRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Levels sets the expectations per difficulty tag, replacing the defaults
+	Levels map[string]DifficultyLevel `yaml:"levels"`
+}
+
+// DifficultyLevel is what a challenge of one difficulty usually looks like;
+// zero values are not checked
+type DifficultyLevel struct {
+	MinValue int `yaml:"min_value"`
+	MaxValue int `yaml:"max_value"`
+	MaxHints int `yaml:"max_hints"`
+	// MaxBinarySize is the size in bytes above which an ELF/PE attachment
+	// seems too complex
+	MaxBinarySize int64 `yaml:"max_binary_size"`
+	// Stripped set to false flags stripped ELF/PE attachments
+	Stripped *bool `yaml:"stripped"`
+}
+
+var notStripped = false
+
+// defaultDifficultyLevels apply when difficulty.levels is not set
+var defaultDifficultyLevels = map[string]DifficultyLevel{
+	"beginner": {MaxValue: 200, MaxHints: 3, MaxBinarySize: 256 * 1024, Stripped: &notStripped},
+	"easy":     {MaxValue: 300, MaxHints: 3, MaxBinarySize: 512 * 1024, Stripped: &notStripped},
+	"medium":   {MinValue: 100, MaxValue: 500},
+	"hard":     {MinValue: 300},
+}
+
+// checkDifficulty warns when a challenge looks easier or harder than its
+// difficulty tag says. The thresholds are heuristics to prompt a second look
+// during review, so every finding is a warning.
+func checkDifficulty(challengePath string, challenge Challenge, rule DifficultyRule) []string {
+	var warnings []string
+
+	if !rule.Enabled {
+		return warnings
+	}
+	levels := rule.Levels
+	if len(levels) == 0 {
+		levels = defaultDifficultyLevels
+	}
+	var difficulty string
+	var level DifficultyLevel
+	for _, tag := range challenge.Tags {
+		if candidate, ok := levels[tag]; ok {
+			difficulty, level = tag, candidate
+			break
+		}
+	}
+	if difficulty == "" {
+		return warnings
+	}
+
+	value := challenge.Value
+	if initial, ok := challenge.Extra["initial"].(int); ok && initial > 0 {
+		value = initial
+	}
+	if value > 0 && level.MaxValue > 0 && value > level.MaxValue {
+		warnings = append(warnings, fmt.Sprintf("Value %d seems high for '%s' challenges (difficulty max_value: %d)", value, difficulty, level.MaxValue))
+	}
+	if value > 0 && level.MinValue > 0 && value < level.MinValue {
+		warnings = append(warnings, fmt.Sprintf("Value %d seems low for '%s' challenges (difficulty min_value: %d)", value, difficulty, level.MinValue))
+	}
+	if level.MaxHints > 0 && len(challenge.Hints) > level.MaxHints {
+		warnings = append(warnings, fmt.Sprintf("%d hints are many for '%s' challenges (difficulty max_hints: %d)", len(challenge.Hints), difficulty, level.MaxHints))
+	}
+
+	if level.MaxBinarySize == 0 && level.Stripped == nil {
+		return warnings
+	}
+	baseDir := filepath.Dir(challengePath)
+	for _, file := range challenge.Files {
+		if isRemoteFile(file) {
+			continue
+		}
+		path := filepath.Join(baseDir, normalizeFilePath(file))
+		info := parseBinary(path)
+		if info == nil {
+			continue
+		}
+		if stat, err := os.Stat(path); err == nil && level.MaxBinarySize > 0 && stat.Size() > level.MaxBinarySize {
+			warnings = append(warnings, fmt.Sprintf("%s binary '%s' (%.2f MB) seems large for '%s' challenges (difficulty max_binary_size: %.2f MB)",
+				info.Format, file, float64(stat.Size())/(1024*1024), difficulty, float64(level.MaxBinarySize)/(1024*1024)))
+		}
+		if level.Stripped != nil && !*level.Stripped && info.Stripped {
+			warnings = append(warnings, fmt.Sprintf("%s binary '%s' is stripped, which is unusual for '%s' challenges", info.Format, file, difficulty))
+		}
+	}
+
+	return warnings
+}
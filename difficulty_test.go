@@ -0,0 +1,79 @@
+package main
+
+import (
+	"debug/elf"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckDifficulty(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTestELF(t, filepath.Join(tempDir, "small"), elf.EM_X86_64, true, "")
+	writeTestELF(t, filepath.Join(tempDir, "stripped"), elf.EM_X86_64, false, strings.Repeat("A", 900*1024))
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	hints := make([]HintItem, 5)
+	rule := DifficultyRule{Enabled: true}
+
+	tests := []struct {
+		name      string
+		challenge Challenge
+		rule      DifficultyRule
+		want      []string
+	}{
+		{
+			name:      "consistent easy challenge",
+			challenge: Challenge{Tags: []string{"easy"}, Value: 100, Files: []string{"small"}, Hints: hints[:2]},
+			rule:      rule,
+		},
+		{
+			name:      "easy challenge with a large stripped binary and many hints",
+			challenge: Challenge{Tags: []string{"easy"}, Value: 100, Files: []string{"stripped"}, Hints: hints},
+			rule:      rule,
+			want: []string{
+				"5 hints are many for 'easy' challenges (difficulty max_hints: 3)",
+				"ELF binary 'stripped' (0.88 MB) seems large for 'easy' challenges (difficulty max_binary_size: 0.50 MB)",
+				"ELF binary 'stripped' is stripped, which is unusual for 'easy' challenges",
+			},
+		},
+		{
+			name:      "hard challenge with a low value",
+			challenge: Challenge{Tags: []string{"web", "hard"}, Value: 100},
+			rule:      rule,
+			want:      []string{"Value 100 seems low for 'hard' challenges (difficulty min_value: 300)"},
+		},
+		{
+			name:      "dynamic initial value",
+			challenge: Challenge{Tags: []string{"easy"}, Extra: map[string]interface{}{"initial": 500}},
+			rule:      rule,
+			want:      []string{"Value 500 seems high for 'easy' challenges (difficulty max_value: 300)"},
+		},
+		{
+			name:      "configured levels replace the defaults",
+			challenge: Challenge{Tags: []string{"hard"}, Value: 100, Files: []string{"stripped"}},
+			rule:      DifficultyRule{Enabled: true, Levels: map[string]DifficultyLevel{"easy": {MaxValue: 50}}},
+		},
+		{
+			name:      "no difficulty tag",
+			challenge: Challenge{Tags: []string{"web"}, Value: 1000},
+			rule:      rule,
+		},
+		{
+			name:      "disabled",
+			challenge: Challenge{Tags: []string{"hard"}, Value: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkDifficulty(challengePath, tt.challenge, tt.rule)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkDifficulty() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
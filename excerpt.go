@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// excerptContext is how many lines around a finding's line its excerpt shows
+const excerptContext = 2
+
+// secretFields are the challenge.yml keys whose lines excerpts never show
+var secretFields = []string{"flags", "flag_tests", "notes"}
+
+// redactedLine replaces the lines excerpts do not show
+const redactedLine = "··· (not shown)"
+
+// excerptLines returns the lines of a challenge.yml as excerpts show them,
+// with the values of secretFields (and anonymousFields) redacted, and any
+// other line containing one of the flags, e.g. a hint giving the flag away
+func excerptLines(data []byte, flags []string) []string {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	for i, text := range lines {
+		if containsFlag(text, flags) {
+			lines[i] = text[:len(text)-len(strings.TrimLeft(text, " \t"))] + redactedLine
+		}
+	}
+	for _, key := range redactedFields() {
+		field, err := findTopLevelField(data, key)
+		if err != nil || field == nil {
+			continue
+		}
+		for line := field.Key.Line; line <= field.EndLine && line <= len(lines); line++ {
+			text := lines[line-1]
+			indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+			if line == field.Key.Line {
+				indent += key + ": "
+			}
+			lines[line-1] = indent + redactedLine
+		}
+	}
+	return lines
+}
+
+// flagValues returns the values excerpts redact for a challenge: its flags
+// and the submissions its flag_tests accept
+func flagValues(challenge Challenge) []string {
+	var values []string
+	for _, flag := range challenge.Flags {
+		if content := flag.Content(); content != "" {
+			values = append(values, content)
+		}
+	}
+	if challenge.FlagTests != nil {
+		values = append(values, challenge.FlagTests.Accept...)
+	}
+	return values
+}
+
+// containsFlag reports whether text contains one of the flags, regardless
+// of case since flags may be case insensitive
+func containsFlag(text string, flags []string) bool {
+	lower := strings.ToLower(text)
+	for _, flag := range flags {
+		if flag != "" && strings.Contains(lower, strings.ToLower(flag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// excerpt renders the lines around line, numbered, with the line itself
+// marked, e.g.
+//
+//	  3 | category: web
+//	> 4 | tags: []
+//	  5 | value: 100
+func excerpt(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	first, last := max(1, line-excerptContext), min(len(lines), line+excerptContext)
+	width := len(fmt.Sprint(last))
+
+	var out strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&out, "%s %*d | %s\n", marker, width, n, lines[n-1])
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// indentLines prefixes each line of text
+func indentLines(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
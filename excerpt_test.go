@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestExcerpt(t *testing.T) {
+	data := []byte("name: chall\ncategory: web\nflags:\n  - flag{secret}\n  - type: static\n    content: flag{other}\ntags: []\nvalue: 100\nflag_tests: {accept: [\"flag{secret}\"]}\n")
+	lines := excerptLines(data, nil)
+
+	tests := []struct {
+		name string
+		line int
+		want string
+	}{
+		{
+			name: "flags redacted",
+			line: 7,
+			want: "  5 |   ··· (not shown)\n  6 |     ··· (not shown)\n> 7 | tags: []\n  8 | value: 100\n  9 | flag_tests: ··· (not shown)",
+		},
+		{
+			name: "first line",
+			line: 1,
+			want: "> 1 | name: chall\n  2 | category: web\n  3 | flags: ··· (not shown)",
+		},
+		{
+			name: "last line",
+			line: 9,
+			want: "  7 | tags: []\n  8 | value: 100\n> 9 | flag_tests: ··· (not shown)",
+		},
+		{
+			name: "out of range",
+			line: 0,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := excerpt(lines, tt.line); got != tt.want {
+				t.Errorf("excerpt() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	// Lines giving a flag away and notes are redacted wherever they are
+	leaky := []byte("name: chall\ndescription: \"the flag is FLAG{Secret}\"\nhints:\n  - {content: \"the flag is flag{secret}\", cost: 200}\nnotes: checked by carol\nvalue: 100\n")
+	want := "  2 | " + redactedLine + "\n  3 | hints:\n> 4 |   " + redactedLine + "\n  5 | notes: " + redactedLine + "\n  6 | value: 100"
+	if got := excerpt(excerptLines(leaky, []string{"flag{secret}"}), 4); got != want {
+		t.Errorf("excerpt() =\n%s\nwant\n%s", got, want)
+	}
+
+	wide := excerpt(make([]string, 12), 10)
+	if want := "   8 | \n   9 | \n> 10 | \n  11 | \n  12 | "; wide != want {
+		t.Errorf("excerpt() =\n%q\nwant\n%q", wide, want)
+	}
+}
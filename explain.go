@@ -0,0 +1,536 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ruleDoc is what clilint explain prints about a rule
+type ruleDoc struct {
+	// Title is the rule's name in the README rule table
+	Title string
+	// Summary says what the rule checks
+	Summary string
+	// Rationale says why the rule exists
+	Rationale string
+	// Options are the lintrc.yaml settings of the rule
+	Options []string
+	// Failing and Passing are challenge.yml excerpts the rule rejects and
+	// accepts, "" when the rule is not about challenge.yml contents
+	Failing string
+	Passing string
+	// Fix says how --fix handles the rule's findings, "" when it does not
+	Fix string
+}
+
+// ruleDocs documents every rule of lintRules, keyed by rule ID
+var ruleDocs = map[string]ruleDoc{
+	"config": {
+		Title:     "Lint Config",
+		Summary:   "lintrc.yaml, and the policy bundle it names, must load and parse.",
+		Rationale: "Every other rule depends on the config; linting with a half-read config would silently skip checks.",
+		Options:   []string{"policy: path, URL, or OCI reference of a policy bundle applied under lintrc.yaml"},
+	},
+	"read": {
+		Title:     "Readable File",
+		Summary:   "challenge.yml must be readable.",
+		Rationale: "A file that cannot be read cannot be checked or deployed.",
+	},
+	"yaml": {
+		Title:     "YAML Format",
+		Summary:   "challenge.yml must be valid YAML syntax.",
+		Rationale: "ctfcli refuses to install challenges it cannot parse.",
+		Failing:   "name: web\ntags: [easy\n",
+		Passing:   "name: web\ntags: [easy]\n",
+	},
+	"yaml-aliases": {
+		Title:     "YAML Aliases",
+		Summary:   "Anchors, aliases, and merge keys are resolved, but documents that would expand to more than 10,000 nodes are rejected without being parsed.",
+		Rationale: "Alias bombs (\"billion laughs\") exhaust the memory of the runner and of every tool parsing the file after it.",
+		Failing:   "a: &a [x, x, x, x, x, x, x, x, x, x]\nb: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]\nc: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b, *b]\nd: [*c, *c, *c, *c, *c, *c, *c, *c, *c, *c]\n",
+		Passing:   "defaults: &defaults {type: dynamic}\n<<: *defaults\n",
+	},
+	"yaml-limits": {
+		Title:     "YAML Limits",
+		Summary:   "challenge.yml and included files larger than 1.00 MB or nested more than 64 levels are rejected with a single finding.",
+		Rationale: "Oversized files are never read whole, so a log dump committed as challenge.yml cannot exhaust the runner's memory.",
+		Options:   []string{"yaml_limits.max_size: largest file in bytes (default 1 MB)", "yaml_limits.max_depth: deepest nesting (default 64)"},
+	},
+	"include": {
+		Title:     "Includes",
+		Summary:   "Files named by include must exist and be a single YAML mapping without includes of their own; every document of a multi-document file must be a mapping.",
+		Rationale: "Includes are resolved relative to the repository root; a missing or nested snippet would deploy a different challenge than the one reviewed.",
+		Failing:   "include: snippets/missing.yml\n",
+		Passing:   "include: snippets/dynamic-scoring.yml\n",
+	},
+	"flags": {
+		Title:     "Flags",
+		Summary:   "Each flag is a non-empty string or a map with type (static or regex), content, and optional data: case_insensitive. Regex flags must compile, and no static flag may equal, start with, or contain another flag of the same challenge.",
+		Rationale: "A malformed flag makes the challenge unsolvable; a duplicate is usually a placeholder or truncated flag left behind.",
+		Failing:   "flags:\n  - flag{real_flag}\n  - flag{real\n  - {type: regex, content: 'flag{[a-z+}'}\n",
+		Passing:   "flags:\n  - flag{real_flag}\n  - {type: regex, content: 'flag\\{[a-z]+\\}', data: case_insensitive}\n",
+	},
+	"flag-tests": {
+		Title:     "Flag Tests",
+		Summary:   "An optional flag_tests block lists submissions the flags must accept and reject, checked as CTFd would.",
+		Rationale: "Complicated regex flags are easy to get wrong; testing them in CI catches flags that reject the intended answer or accept a wrong one. Failing entries are named by index, never echoed.",
+		Failing:   "flags:\n  - {type: regex, content: 'flag\\{[a-z]+\\}'}\nflag_tests:\n  accept: [\"flag{abc123}\"]\n",
+		Passing:   "flags:\n  - {type: regex, content: 'flag\\{[a-z0-9]+\\}'}\nflag_tests:\n  accept: [\"flag{abc123}\"]\n  reject: [\"flag{ABC}\"]\n",
+	},
+	"files": {
+		Title:     "File Existence",
+		Summary:   "All files in files[] must exist and be 1.00 MB (or max_file_size bytes) or smaller; symlinks must point to an existing file.",
+		Rationale: "ctfcli uploads files[] on install; a missing or huge attachment breaks the deployment or the players' downloads.",
+		Options:   []string{"max_file_size: largest attachment in bytes (default 1 MB)", "profiles.<category>.max_file_size: per-category override"},
+		Failing:   "files:\n  - dist/missing.zip\n",
+		Passing:   "files:\n  - dist/challenge.zip\n",
+	},
+	"files-entries": {
+		Title:     "Files Entries",
+		Summary:   "files[] must not list a path twice, the challenge.yml itself, or a solution or writeup.",
+		Rationale: "Duplicates upload the same file twice, and a solve script or writeup in files[] hands the answer to every player.",
+		Options:   []string{"publish.writeup: glob of writeup files (default writeup*)"},
+		Failing:   "files:\n  - dist/a.zip\n  - ./dist/a.zip\n  - solver/solve.py\n",
+		Passing:   "files:\n  - dist/a.zip\n",
+	},
+	"content-type": {
+		Title:     "Content Type",
+		Summary:   "Warns when an attachment's magic bytes show another format than its extension, e.g. a .zip that is a tar.",
+		Rationale: "Players rely on the extension to open a file; a mislabeled attachment is usually a packaging mistake.",
+		Failing:   "files:\n  - dist/capture.zip # actually a gzip\n",
+		Passing:   "files:\n  - dist/capture.tar.gz\n",
+	},
+	"filenames": {
+		Title:     "Filenames",
+		Summary:   "The names attachments are downloaded under must be ASCII, without spaces, lowercase, and start with a prefix, as configured.",
+		Rationale: "Consistent names keep downloads of different challenges apart in the players' download folders.",
+		Options:   []string{"filenames.ascii", "filenames.no_spaces", "filenames.lowercase", "filenames.prefix: e.g. <category>-<slug>-"},
+		Failing:   "name: Phantom Capture\ncategory: OSINT\nfiles:\n  - dist/My Capture.zip\n",
+		Passing:   "name: Phantom Capture\ncategory: OSINT\nfiles:\n  - dist/osint-phantom-capture.zip\n",
+	},
+	"value": {
+		Title:     "Value",
+		Summary:   "value (and the extra.initial of dynamic challenges) must be between min and max and a multiple of step; extra.minimum must be a multiple of step too.",
+		Rationale: "Values off the event's scale make the scoreboard inconsistent across categories.",
+		Options:   []string{"value.min", "value.max", "value.step"},
+		Failing:   "value: 333\n",
+		Passing:   "value: 300\n",
+	},
+	"notes": {
+		Title:     "Notes",
+		Summary:   "notes is a freeform, organizer-only field; with notes.required every challenge must have notes matching notes.pattern.",
+		Rationale: "Notes record organizer decisions such as a QA signoff; they are stripped from clilint export --format yaml so players never see them.",
+		Options:   []string{"notes.required", "notes.pattern: regular expression the notes must match"},
+		Failing:   "notes: \"\"\n",
+		Passing:   "notes: \"QA: verified by alice on 2026-10-01\"\n",
+	},
+	"prizes": {
+		Title:     "Prizes",
+		Summary:   "An optional prizes.first_blood block must have a positive amount and an ISO 4217 currency, and may name a sponsor.",
+		Rationale: "Prize metadata is exported for the sponsors and the announcement; a typo there is a promise the organizers must keep.",
+		Options:   []string{"prizes.currencies", "prizes.sponsors", "prizes.require_sponsor", "prizes.max_amount"},
+		Failing:   "prizes:\n  first_blood: {amount: -100, currency: dollars}\n",
+		Passing:   "prizes:\n  first_blood: {amount: 100, currency: USD, sponsor: Acme}\n",
+	},
+	"requirements": {
+		Title:     "Welcome Dependency",
+		Summary:   "Non-welcome challenges must include \"welcome\" in requirements[].",
+		Rationale: "The welcome challenge introduces the rules and flag format; other challenges unlock after it.",
+		Options:   []string{"requirements.condition: none disables the rule", "requirements.ignore: challenge names exempt from it (default: welcome)"},
+		Failing:   "name: web\nrequirements: []\n",
+		Passing:   "name: web\nrequirements:\n  - welcome\n",
+	},
+	"image": {
+		Title:     "Image Field",
+		Summary:   "image must be null unless allow_image is set; then a pinned registry reference, a build context containing a Dockerfile, or a {name, build, registry} map.",
+		Rationale: "ctfcli builds and pushes images on install; an unpinned :latest image changes under a running event.",
+		Options:   []string{"allow_image", "profiles.<category>.allow_image: per-category override"},
+		Failing:   "image: ghcr.io/example/web:latest\n",
+		Passing:   "image: null\n",
+		Fix:       "--fix sets image to null when allow_image is not set.",
+	},
+	"host": {
+		Title:     "Host Field",
+		Summary:   "host must be null, a URL with a scheme, or a {host, port, protocol} map, and match the host policy.",
+		Rationale: "CTFd shows host to players as-is; a bare hostname does not tell them how to connect.",
+		Options:   []string{"require_host", "host_policy.schemes", "host_policy.require_port", "profiles.<category>.host_policy: per-category override"},
+		Failing:   "host: pwn.example.com\n",
+		Passing:   "host: tcp://pwn.example.com:1337\n",
+	},
+	"placeholders": {
+		Title:     "Placeholders",
+		Summary:   "${VAR} and {{ .VAR }} in host and connection_info must resolve from the vars file or the environment.",
+		Rationale: "An unresolved placeholder is shown to players literally; other rules check the expanded values.",
+		Options:   []string{"vars_file: values per environment (--vars FILE overrides it)"},
+		Failing:   "host: https://${UNDEFINED_HOST}\n",
+		Passing:   "host: https://${WEB_HOST}\n",
+	},
+	"state": {
+		Title:     "State Field",
+		Summary:   "state must be \"visible\", except for challenges of a wave the schedule has not released yet, which must be \"hidden\".",
+		Rationale: "A hidden challenge is silently missing from the event; an unreleased wave must not leak early.",
+		Options:   []string{"schedule: release waves (see the schedule rule)"},
+		Failing:   "state: hidden\n",
+		Passing:   "state: visible\n",
+		Fix:       "--fix sets state to visible, except for challenges of waves not released yet.",
+	},
+	"schedule": {
+		Title:     "Release Waves",
+		Summary:   "With schedule set, a challenge's wave (the wave field or a wave-N tag) must be listed in the schedule file.",
+		Rationale: "A challenge of an unknown wave would never be released; clilint release --wave N flips a wave to visible.",
+		Options:   []string{"schedule: path of the schedule file"},
+		Failing:   "wave: 9\nstate: hidden\n",
+		Passing:   "wave: 2\nstate: hidden\n",
+	},
+	"version": {
+		Title:     "Version Field",
+		Summary:   "version must be \"0.1\".",
+		Rationale: "\"0.1\" is the challenge.yml spec version ctfcli understands.",
+		Failing:   "version: 1\n",
+		Passing:   "version: \"0.1\"\n",
+		Fix:       "--fix sets version to \"0.1\".",
+	},
+	"tags": {
+		Title:     "Tags Field",
+		Summary:   "tags must contain exactly one of beginner, easy, medium, hard, plus the configured patterns.",
+		Rationale: "Players filter by difficulty, and scoring and the difficulty heuristics depend on it.",
+		Options:   []string{"tags.condition: and, or, or none", "tags.patterns: static or regex values the tags must match"},
+		Failing:   "tags: [easy, hard]\n",
+		Passing:   "tags: [easy]\n",
+		Fix:       "--interactive prompts for a missing difficulty tag and writes it back.",
+	},
+	"type": {
+		Title:     "Type Field",
+		Summary:   "Warns when type is standard.",
+		Rationale: "The event uses dynamic scoring; standard is usually left over from a template.",
+		Failing:   "type: standard\n",
+		Passing:   "type: dynamic\n",
+	},
+	"extra": {
+		Title:     "Extra Field",
+		Summary:   "Keys of extra must match the schema of the challenge type; unknown keys, wrong value types, and missing required keys are errors.",
+		Rationale: "CTFd ignores misspelled keys such as dacay, so the challenge silently scores with defaults.",
+		Options:   []string{"extra_schemas: schemas per challenge type (replaces the built-in dynamic schema)"},
+		Failing:   "type: dynamic\nextra:\n  initial: 500\n  dacay: 100\n  minimum: 100\n",
+		Passing:   "type: dynamic\nextra:\n  initial: 500\n  decay: 100\n  minimum: 100\n",
+	},
+	"hints": {
+		Title:     "Hints",
+		Summary:   "Each hint is a non-empty string or a {content, cost} map with a non-negative cost; policies bound the total cost, order, and contents.",
+		Rationale: "Hints that cost more than the challenge or give the flag away break the scoring.",
+		Options:   []string{"hints.max_cost_percent", "hints.ordered", "hints.no_flags"},
+		Failing:   "value: 100\nhints:\n  - {content: Look at the cookies, cost: 150}\n",
+		Passing:   "value: 100\nhints:\n  - {content: Look at the cookies, cost: 10}\n",
+	},
+	"kubernetes": {
+		Title:     "Kubernetes Manifests",
+		Summary:   "Containers of the YAML manifests in the challenge directory must set resources.limits, namespace must match the challenge slug, and secrets must not be inlined.",
+		Rationale: "A container without limits can starve the other challenges of the cluster, and inlined secrets leak with the repository.",
+	},
+	"inventory": {
+		Title:     "Host Inventory",
+		Summary:   "When inventory is set, host must exist in that inventory.",
+		Rationale: "A host missing from the provisioned infrastructure is unreachable during the event.",
+		Options:   []string{"inventory: JSON/YAML list of provisioned hosts"},
+		Failing:   "host: https://typo.example.com\n",
+		Passing:   "host: https://web.example.com\n",
+	},
+	"host-live": {
+		Title:     "Audit Liveness",
+		Summary:   "With --audit, http(s) hosts must respond without a server error, and other hosts with a port must accept a TCP connection.",
+		Rationale: "Audits catch challenges whose infrastructure went down after they were merged.",
+	},
+	"healthcheck": {
+		Title:     "Healthcheck",
+		Summary:   "A healthcheck script must exist in the challenge directory and be executable; with healthcheck.required, hosted challenges must declare one.",
+		Rationale: "clilint healthcheck --run executes them as ctfcli does, so broken infrastructure is noticed before players report it.",
+		Options:   []string{"healthcheck.required", "healthcheck.timeout"},
+		Failing:   "host: https://web.example.com\nhealthcheck: missing.sh\n",
+		Passing:   "host: https://web.example.com\nhealthcheck: healthcheck.sh\n",
+	},
+	"image-size": {
+		Title:     "Image Size",
+		Summary:   "Warns when the registry image is compressed larger than image_size.max_size or has more than image_size.max_layers layers.",
+		Rationale: "Large images slow down deployment and instancers starting a container per team.",
+		Options:   []string{"image_size.max_size: compressed bytes", "image_size.max_layers", "image_size.platform (default linux/amd64)"},
+	},
+	"image-registry": {
+		Title:     "Audit Registry Images",
+		Summary:   "With --audit, registry images must exist in their registry.",
+		Rationale: "An image deleted from the registry breaks redeployments during the event.",
+	},
+	"binaries": {
+		Title:     "Binary Attachments",
+		Summary:   "ELF/PE attachments must be (un)stripped, must not leak home directory paths, and must match an architecture tag.",
+		Rationale: "Symbols or paths left in a binary can give away the solution or the author's identity.",
+		Options:   []string{"binaries.stripped", "binaries.debug_paths", "binaries.architecture"},
+	},
+	"checksums": {
+		Title:     "Checksum Manifest",
+		Summary:   "SHA256SUMS, written by clilint checksum, must match the files[] entries.",
+		Rationale: "The manifest lets players and organizers verify the attachments they downloaded.",
+		Options:   []string{"checksums.required"},
+		Fix:       "clilint checksum rewrites SHA256SUMS.",
+	},
+	"lfs": {
+		Title:     "Git LFS",
+		Summary:   "files[] must not list LFS pointer files; with lfs.threshold, larger files must be tracked by LFS in .gitattributes.",
+		Rationale: "A checkout without LFS would upload the pointer text instead of the attachment.",
+		Options:   []string{"lfs.threshold: bytes"},
+	},
+	"remote-files": {
+		Title:     "Remote Files",
+		Summary:   "URL entries in files[] must respond with 2xx; s3:// and gs:// entries must be downloadable anonymously.",
+		Rationale: "Players download these directly; a private or missing object is a broken attachment.",
+		Failing:   "files:\n  - s3://private-bucket/capture.pcap\n",
+		Passing:   "files:\n  - s3://public-bucket/capture.pcap\n",
+	},
+	"external-files": {
+		Title:     "External Files",
+		Summary:   "external_files[] entries must respond to HEAD with 2xx and match the declared size, sha256, and content_type.",
+		Rationale: "A changed or moved external file means players get something other than what was tested.",
+		Failing:   "external_files:\n  - {url: https://example.com/a.zip, size: 1}\n",
+		Passing:   "external_files:\n  - {url: https://example.com/a.zip, size: 104857600}\n",
+	},
+	"release-assets": {
+		Title:     "Release Assets",
+		Summary:   "release://tag/asset entries in files[] must name an existing asset of that release, no larger than releases.max_size.",
+		Rationale: "Large attachments live in GitHub releases; a typo in the tag or asset name breaks the download.",
+		Options:   []string{"releases.repository (default: GITHUB_REPOSITORY)", "releases.max_size (default: 2 GB)"},
+		Failing:   "files:\n  - release://v0.0/missing.zip\n",
+		Passing:   "files:\n  - release://v1.0/disk.img\n",
+	},
+	"archive-password": {
+		Title:     "Zip Passwords",
+		Summary:   "Password-protected zips must open with the password stated in description, and unprotected zips must not state one.",
+		Rationale: "A wrong password makes the challenge unsolvable.",
+		Options:   []string{"archives.max_size: bounds the decompression of the entry checked"},
+		Failing:   "description: \"password: infected\"\nfiles:\n  - dist/unprotected.zip\n",
+		Passing:   "description: \"password: infected\"\nfiles:\n  - dist/protected.zip\n",
+	},
+	"archive-limits": {
+		Title:     "Zip Bombs",
+		Summary:   "Zip attachments, including nested zips, must stay within the entry count, decompressed size, and nesting limits; violations are security findings.",
+		Rationale: "A zip bomb harms every player who opens the attachment.",
+		Options:   []string{"archives.max_entries (default 10000)", "archives.max_size (default 1 GiB)", "archives.max_depth (default 3)"},
+	},
+	"malware": {
+		Title:     "Malware Scan",
+		Summary:   "When malware is configured, local attachments are scanned by clamd and/or looked up on VirusTotal by hash; detections are warnings.",
+		Rationale: "Malware samples in forensics challenges must be intentional and packaged safely.",
+		Options:   []string{"malware.clamav", "malware.virustotal (key in VT_API_KEY)", "malware.min_detections"},
+	},
+	"artifacts": {
+		Title:     "Stray Artifacts",
+		Summary:   "Challenge directories must not contain files that should never be committed or deployed, such as .env, node_modules/, or compiled solve binaries.",
+		Rationale: "Stray files leak credentials or solutions once the repository or the deployment is public.",
+		Options:   []string{"artifacts.deny: gitignore-style patterns replacing the defaults (deny: [] disables the rule)"},
+	},
+	"difficulty": {
+		Title:     "Difficulty Heuristics",
+		Summary:   "With difficulty.enabled, advisory warnings when the value, hint count, or binaries look inconsistent with the difficulty tag.",
+		Rationale: "A beginner challenge worth 500 points or a hard one with five hints is usually mislabeled.",
+		Options:   []string{"difficulty.enabled", "difficulty.levels: thresholds per difficulty (replaces the built-in ones)"},
+		Failing:   "tags: [beginner]\nvalue: 500\n",
+		Passing:   "tags: [beginner]\nvalue: 100\n",
+	},
+	"bilingual": {
+		Title:     "Bilingual Descriptions",
+		Summary:   "With bilingual.enabled, description must hold an English and a Japanese block, or the translation must be in description_ja.",
+		Rationale: "The event is bilingual; a missing translation excludes part of the players.",
+		Options:   []string{"bilingual.enabled", "bilingual.separator (default ---)", "bilingual.min_ratio", "bilingual.max_ratio"},
+		Failing:   "description: Find the flag.\n",
+		Passing:   "description: |\n  Find the flag.\n  ---\n  フラグを見つけてください。\n",
+	},
+	"description-files": {
+		Title:     "Description Attachments",
+		Summary:   "Attachment names mentioned in description must be provided by files or external_files; links to files uploaded to a CTFd instance are errors.",
+		Rationale: "A description naming a file players cannot download is confusing, and CTFd upload links break on re-import.",
+		Failing:   "description: Analyze capture.pcap.\nfiles: []\n",
+		Passing:   "description: Analyze capture.pcap.\nfiles:\n  - dist/capture.pcap\n",
+	},
+	"signatures": {
+		Title:     "Attachment Signatures",
+		Summary:   "With signatures.enabled, every local files[] entry needs a detached minisign or ssh-keygen signature that verifies against one of the public keys.",
+		Rationale: "Signatures let players verify attachments were published by the organizers.",
+		Options:   []string{"signatures.enabled", "signatures.public_keys", "signatures.namespace"},
+	},
+	"deprecations": {
+		Title:     "Deprecations",
+		Summary:   "Fields and values listed in deprecations are reported as deprecated until their sunset date, then as errors.",
+		Rationale: "Policy migrations give authors time to update their challenges before the new rule fails CI.",
+		Options:   []string{"deprecations: list of {field, value, sunset, message}"},
+		Failing:   "type: standard # deprecated with field: type, value: standard\n",
+		Passing:   "type: dynamic\n",
+	},
+	"template": {
+		Title:     "Template Conformance",
+		Summary:   "When template is set, every challenge contains all top-level keys of the template challenge.yml, in the template's order.",
+		Rationale: "A common layout makes challenges easy to review and diff.",
+		Options:   []string{"template: path of the template challenge.yml"},
+	},
+	"category-directory": {
+		Title:     "Category Directory",
+		Summary:   "With category_directory.enabled, category must match the parent directory of the challenge directory.",
+		Rationale: "A challenge filed under the wrong directory is missed by reviewers of that category.",
+		Options:   []string{"category_directory.enabled", "category_directory.mapping: directory names per category"},
+		Failing:   "# web/login/challenge.yml\ncategory: pwn\n",
+		Passing:   "# web/login/challenge.yml\ncategory: web\n",
+	},
+	"name-slug": {
+		Title:     "Name Slug",
+		Summary:   "With name_slug.enabled, the challenge directory must be the lowercase, hyphenated slug of name.",
+		Rationale: "Predictable directory names make challenges easy to find from the scoreboard.",
+		Options:   []string{"name_slug.enabled"},
+		Failing:   "# web/login/challenge.yml\nname: SQL Injection 101\n",
+		Passing:   "# web/sql-injection-101/challenge.yml\nname: SQL Injection 101\n",
+		Fix:       "--fix prints the git mv that renames the directory; it does not move it.",
+	},
+	"missing-challenge": {
+		Title:     "Missing challenge.yml",
+		Summary:   "With missing_challenge.enabled, directories that look like challenges but have no challenge.yml are reported as warnings.",
+		Rationale: "A challenge without challenge.yml is never linted or deployed.",
+		Options:   []string{"missing_challenge.enabled", "missing_challenge.markers"},
+	},
+	"layout": {
+		Title:     "Layout",
+		Summary:   "With layout.pattern, every challenge.yml must be exactly as deep as the pattern, in a known category directory.",
+		Rationale: "Deployment scripts and reviewers rely on the repository layout.",
+		Options:   []string{"layout.pattern: e.g. <category>/<challenge>/challenge.yml", "layout.categories"},
+	},
+	"policy-rules": {
+		Title:     "Policy Rules",
+		Summary:   "The CEL rules (.cel files) of the policy bundle must pass for every challenge: a rule fails when it returns false, a non-empty string, or a non-empty list of strings.",
+		Rationale: "Organizations encode conventions the built-in rules do not cover once, in the policy bundle shared by their repositories.",
+		Options:   []string{"policy: the bundle holding the .cel files"},
+	},
+	"flag-collision": {
+		Title:     "Flag Collisions",
+		Summary:   "No static flag may be accepted for two challenges; a regex flag accepting another challenge's static flag is a warning.",
+		Rationale: "A flag accepted by two challenges gives away points, and usually means one was copied from the other.",
+		Failing:   "# web/a/challenge.yml and web/b/challenge.yml\nflags:\n  - flag{same}\n",
+		Passing:   "# web/a/challenge.yml\nflags:\n  - flag{a}\n",
+	},
+	"freeze": {
+		Title:     "Freeze",
+		Summary:   "In PR mode with --freeze, or within the freeze window, changing the flags, value, or files of a visible challenge is an error unless the PR is labeled.",
+		Rationale: "Late changes to released challenges are unfair to teams who already worked on them.",
+		Options:   []string{"freeze.start", "freeze.end", "freeze.override_label (default freeze-override)"},
+	},
+	"live": {
+		Title:     "Live",
+		Summary:   "With --live in PR mode, changing the flags or state of a challenge solved on the CTFd instance is an error.",
+		Rationale: "Changing a solved challenge during the event invalidates the solves already recorded.",
+		Options:   []string{"live.ctfd_url (admin token in CTFD_TOKEN)", "live.errors: more rule IDs that stay errors under --live"},
+	},
+	"cross-repo-name": {
+		Title:     "Cross-Repository Names",
+		Summary:   "clilint multi reports challenge names used in more than one repository.",
+		Rationale: "CTFd identifies challenges by name, so the second one would overwrite the first.",
+	},
+	"cross-repo-flag": {
+		Title:     "Cross-Repository Flags",
+		Summary:   "clilint multi reports flags accepted by challenges of more than one repository.",
+		Rationale: "A shared flag scores points on both challenges.",
+	},
+}
+
+// runExplain implements clilint explain
+func runExplain(args []string) {
+	switch len(args) {
+	case 0:
+		writeRuleList(os.Stdout)
+	case 1:
+		if err := writeExplanation(os.Stdout, args[0], explainDocsBaseURL()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exit(1)
+		}
+	default:
+		log.Fatalf("Usage: clilint explain [rule-id]")
+	}
+}
+
+// explainDocsBaseURL returns the docs_base_url of lintrc.yaml, "" when it
+// cannot be loaded; explain works without a config
+func explainDocsBaseURL() string {
+	config, err := loadLintConfig()
+	if err != nil {
+		return ""
+	}
+	return config.DocsBaseURL
+}
+
+// writeRuleList writes every rule ID with its title
+func writeRuleList(w io.Writer) {
+	for _, id := range ruleIDs() {
+		fmt.Fprintf(w, "%-20s %s\n", id, ruleDocs[id].Title)
+	}
+}
+
+// ruleIDs returns the IDs of lintRules, sorted
+func ruleIDs() []string {
+	var ids []string
+	for id := range lintRules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// writeExplanation writes the documentation of a rule
+func writeExplanation(w io.Writer, id, docsBaseURL string) error {
+	rule, ok := lintRules[id]
+	if !ok {
+		return unknownRuleError(id)
+	}
+	doc := ruleDocs[id]
+
+	fmt.Fprintf(w, "%s: %s\n\n", id, doc.Title)
+	if rule.Field != "" {
+		fmt.Fprintf(w, "Field: %s\n", rule.Field)
+	}
+	if url := docsURL(docsBaseURL, id); url != "" {
+		fmt.Fprintf(w, "Docs: %s\n", url)
+	}
+	if rule.Field != "" || docsBaseURL != "" {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%s\n\nWhy: %s\n", doc.Summary, doc.Rationale)
+
+	if len(doc.Options) > 0 {
+		fmt.Fprintln(w, "\nConfiguration (lintrc.yaml):")
+		for _, option := range doc.Options {
+			fmt.Fprintf(w, "  - %s\n", option)
+		}
+	}
+	if doc.Failing != "" {
+		fmt.Fprintf(w, "\nFailing:\n%s\n", indentLines(strings.TrimSuffix(doc.Failing, "\n"), "  "))
+	}
+	if doc.Passing != "" {
+		fmt.Fprintf(w, "\nPassing:\n%s\n", indentLines(strings.TrimSuffix(doc.Passing, "\n"), "  "))
+	}
+	fix := doc.Fix
+	if fix == "" {
+		fix = "None; findings must be fixed by hand."
+	}
+	fmt.Fprintf(w, "\nAuto-fix: %s\n", fix)
+	return nil
+}
+
+// unknownRuleError names the rule IDs the caller may have meant
+func unknownRuleError(id string) error {
+	var similar []string
+	for _, known := range ruleIDs() {
+		if strings.Contains(known, strings.ToLower(id)) || strings.Contains(strings.ToLower(id), known) {
+			similar = append(similar, known)
+		}
+	}
+	if len(similar) == 0 {
+		return fmt.Errorf("unknown rule ID '%s'; run clilint explain for the list of rules", id)
+	}
+	return fmt.Errorf("unknown rule ID '%s'; did you mean %s?", id, strings.Join(similar, ", "))
+}
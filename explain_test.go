@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleDocsCoverEveryRule(t *testing.T) {
+	for id := range lintRules {
+		doc, ok := ruleDocs[id]
+		if !ok {
+			t.Errorf("rule %s has no ruleDocs entry", id)
+			continue
+		}
+		if doc.Title == "" || doc.Summary == "" || doc.Rationale == "" {
+			t.Errorf("rule %s lacks a title, summary, or rationale", id)
+		}
+		if (doc.Failing == "") != (doc.Passing == "") {
+			t.Errorf("rule %s has only one of its failing and passing examples", id)
+		}
+	}
+	for id := range ruleDocs {
+		if _, ok := lintRules[id]; !ok {
+			t.Errorf("ruleDocs documents unknown rule %s", id)
+		}
+	}
+}
+
+func TestWriteExplanation(t *testing.T) {
+	var out strings.Builder
+	if err := writeExplanation(&out, "version", "https://docs.example.com/rules"); err != nil {
+		t.Fatal(err)
+	}
+	want := `version: Version Field
+
+Field: version
+Docs: https://docs.example.com/rules#version
+
+version must be "0.1".
+
+Why: "0.1" is the challenge.yml spec version ctfcli understands.
+
+Failing:
+  version: 1
+
+Passing:
+  version: "0.1"
+
+Auto-fix: --fix sets version to "0.1".
+`
+	if out.String() != want {
+		t.Errorf("writeExplanation() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestUnknownRuleError(t *testing.T) {
+	if err := writeExplanation(&strings.Builder{}, "flag", ""); err == nil || !strings.Contains(err.Error(), "did you mean cross-repo-flag, flag-collision, flag-tests, flags?") {
+		t.Errorf("got %v", err)
+	}
+	if err := writeExplanation(&strings.Builder{}, "CLI012", ""); err == nil || !strings.Contains(err.Error(), "run clilint explain") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+// exportColumns are the columns of the challenge inventory
+var exportColumns = []string{"name", "category", "author", "value", "tags", "state", "files", "host", "file", "first_blood"}
+
+// sheetsAPIURL is the Google Sheets API endpoint, replaced in tests
+var sheetsAPIURL = "https://sheets.googleapis.com"
+
+// sheetsScope grants read and write access to spreadsheets
+const sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+
+func runExport(args []string) {
+	format := "csv"
+	output := ""
+	sheet := ""
+	sheetRange := "Sheet1"
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--format" && i+1 < len(args) {
+			i++
+			format = args[i]
+		} else if args[i] == "--output" && i+1 < len(args) {
+			i++
+			output = args[i]
+		} else if args[i] == "--sheet" && i+1 < len(args) {
+			i++
+			sheet = args[i]
+		} else if args[i] == "--sheet-range" && i+1 < len(args) {
+			i++
+			sheetRange = args[i]
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if format != "csv" && format != "json" && format != "yaml" {
+		log.Fatalf("Unknown --format %s (want csv, json, or yaml)", format)
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+
+	var rows [][]string
+	var paths []string
+	var docs [][]byte
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				log.Printf("Warning: skipping %s: %v", path, err)
+				continue
+			}
+			rows = append(rows, exportRow(path, challenge))
+			if format == "yaml" {
+				doc, err := platformData(path, config)
+				if err != nil {
+					log.Fatalf("Error exporting %s: %v", path, err)
+				}
+				paths = append(paths, path)
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	if sheet != "" {
+		client, err := sheetsClient(context.Background())
+		if err != nil {
+			log.Fatalf("Error authenticating to Google Sheets: %v", err)
+		}
+		if err := pushToSheet(client, sheet, sheetRange, rows); err != nil {
+			log.Fatalf("Error updating spreadsheet %s: %v", sheet, err)
+		}
+		fmt.Printf("📊 Wrote %d challenge(s) to spreadsheet %s (%s)\n", len(rows), sheet, sheetRange)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			log.Fatalf("Error creating %s: %v", output, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	switch format {
+	case "json":
+		err = writeExportJSON(out, rows)
+	case "yaml":
+		err = writeExportYAML(out, paths, docs)
+	default:
+		err = writeExportCSV(out, rows)
+	}
+	if err != nil {
+		log.Fatalf("Error writing export: %v", err)
+	}
+}
+
+// exportRow lists a challenge's inventory columns; lists are joined with ";"
+func exportRow(path string, challenge Challenge) []string {
+	return []string{
+		challenge.Name,
+		challenge.Category,
+		challenge.Author,
+		strconv.Itoa(challengeValue(challenge)),
+		strings.Join(challenge.Tags, ";"),
+		challenge.State,
+		strings.Join(challenge.Files, ";"),
+		formatHost(challenge.Host),
+		path,
+		formatPrize(challenge),
+	}
+}
+
+// formatHost renders the host field on one line
+func formatHost(host interface{}) string {
+	switch v := host.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}:
+		name := fmt.Sprint(v["host"])
+		if protocol, ok := v["protocol"]; ok {
+			name = fmt.Sprintf("%v://%s", protocol, name)
+		}
+		if port, ok := v["port"]; ok {
+			name = fmt.Sprintf("%s:%v", name, port)
+		}
+		return name
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func writeExportCSV(w io.Writer, rows [][]string) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(exportColumns); err != nil {
+		return err
+	}
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+func writeExportJSON(w io.Writer, rows [][]string) error {
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(exportColumns))
+		for i, column := range exportColumns {
+			record[column] = row[i]
+		}
+		records = append(records, record)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// sheetsClient authenticates with the service account key file named by
+// GOOGLE_APPLICATION_CREDENTIALS, or else with GOOGLE_OAUTH_ACCESS_TOKEN
+func sheetsClient(ctx context.Context) (*http.Client, error) {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var key struct {
+			ClientEmail  string `json:"client_email"`
+			PrivateKey   string `json:"private_key"`
+			PrivateKeyID string `json:"private_key_id"`
+			TokenURI     string `json:"token_uri"`
+		}
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		if key.ClientEmail == "" || key.PrivateKey == "" {
+			return nil, fmt.Errorf("%s is not a service account key", path)
+		}
+		tokenURL := key.TokenURI
+		if tokenURL == "" {
+			tokenURL = "https://oauth2.googleapis.com/token"
+		}
+		config := &jwt.Config{
+			Email:        key.ClientEmail,
+			PrivateKey:   []byte(key.PrivateKey),
+			PrivateKeyID: key.PrivateKeyID,
+			Scopes:       []string{sheetsScope},
+			TokenURL:     tokenURL,
+		}
+		return config.Client(ctx), nil
+	}
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})), nil
+	}
+	return nil, fmt.Errorf("set GOOGLE_APPLICATION_CREDENTIALS or GOOGLE_OAUTH_ACCESS_TOKEN")
+}
+
+// pushToSheet clears the range and writes the header and rows to it
+func pushToSheet(client *http.Client, spreadsheetID, sheetRange string, rows [][]string) error {
+	base := fmt.Sprintf("%s/v4/spreadsheets/%s/values/%s", sheetsAPIURL, url.PathEscape(spreadsheetID), url.PathEscape(sheetRange))
+
+	if err := sheetsRequest(client, http.MethodPost, base+":clear", []byte("{}")); err != nil {
+		return err
+	}
+
+	values := [][]string{exportColumns}
+	values = append(values, rows...)
+	body, err := json.Marshal(map[string]interface{}{
+		"range":          sheetRange,
+		"majorDimension": "ROWS",
+		"values":         values,
+	})
+	if err != nil {
+		return err
+	}
+	return sheetsRequest(client, http.MethodPut, base+"?valueInputOption=RAW", body)
+}
+
+func sheetsRequest(client *http.Client, method, endpoint string, body []byte) error {
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportRow(t *testing.T) {
+	var challenge Challenge
+	data := `name: bof
+category: pwn
+author: alice
+value: 300
+tags: [easy, wave-2]
+state: hidden
+files: [dist/bof, dist/libc.so.6]
+host: {host: pwn.example.com, port: 1337, protocol: tcp}
+`
+	if err := yaml.Unmarshal([]byte(data), &challenge); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"bof", "pwn", "alice", "300", "easy;wave-2", "hidden", "dist/bof;dist/libc.so.6", "tcp://pwn.example.com:1337", "pwn/bof/challenge.yml", ""}
+	if got := exportRow("pwn/bof/challenge.yml", challenge); !reflect.DeepEqual(got, want) {
+		t.Errorf("exportRow() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	rows := [][]string{{"web, 1", "web", "bob", "100", "easy", "visible", "", "https://web.example.com", "web/one/challenge.yml", "100 USD (Acme)"}}
+
+	var csvOut bytes.Buffer
+	if err := writeExportCSV(&csvOut, rows); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "name,category,author,value,tags,state,files,host,file,first_blood\n\"web, 1\",web,bob,100,easy,visible,,https://web.example.com,web/one/challenge.yml,100 USD (Acme)\n"
+	if csvOut.String() != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", csvOut.String(), wantCSV)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeExportJSON(&jsonOut, rows); err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]string
+	if err := json.Unmarshal(jsonOut.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0]["name"] != "web, 1" || records[0]["host"] != "https://web.example.com" {
+		t.Errorf("unexpected JSON records %v", records)
+	}
+}
+
+func TestPushToSheet(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests []string
+	var written struct {
+		Range  string     `json:"range"`
+		Values [][]string `json:"values"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"sheets-token","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/v4/spreadsheets/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer sheets-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+"?"+r.URL.RawQuery)
+		if r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&written)
+		}
+		io.WriteString(w, "{}")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	origURL := sheetsAPIURL
+	defer func() { sheetsAPIURL = origURL }()
+	sheetsAPIURL = server.URL
+
+	credentials := filepath.Join(t.TempDir(), "service-account.json")
+	keyFile, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "clilint@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL + "/token",
+	})
+	if err := os.WriteFile(credentials, keyFile, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", credentials)
+
+	client, err := sheetsClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]string{{"bof", "pwn", "alice", "300", "easy", "visible", "", "", "pwn/bof/challenge.yml"}}
+	if err := pushToSheet(client, "sheet-id", "Inventory!A1", rows); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{
+		"POST /v4/spreadsheets/sheet-id/values/Inventory%21A1:clear?",
+		"PUT /v4/spreadsheets/sheet-id/values/Inventory%21A1?valueInputOption=RAW",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+	if written.Range != "Inventory!A1" || len(written.Values) != 2 || !reflect.DeepEqual(written.Values[0], exportColumns) || written.Values[1][0] != "bof" {
+		t.Errorf("unexpected values written: %+v", written)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExternalFile represents an attachment hosted outside the repository
+type ExternalFile struct {
+	URL    string `yaml:"url"`
+	Size   int64  `yaml:"size"`   // optional expected size in bytes
+	SHA256 string `yaml:"sha256"` // optional expected hex SHA256 checksum
+	// ContentType is the optional expected media type, e.g. application/zip
+	ContentType string `yaml:"content_type"`
+}
+
+// httpClient is used for all outbound liveness checks
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// isRemoteFile reports whether a files entry is a URL or release asset rather
+// than a repository path
+func isRemoteFile(file string) bool {
+	return isHTTPFile(file) || isObjectStorageFile(file) || isReleaseFile(file)
+}
+
+// isHTTPFile reports whether a files entry is an http(s) URL
+func isHTTPFile(file string) bool {
+	return strings.HasPrefix(file, "https://") || strings.HasPrefix(file, "http://")
+}
+
+func checkExternalFiles(files []string, externalFiles []ExternalFile) []string {
+	var errors []string
+
+	var targets []ExternalFile
+	for _, file := range files {
+		if isHTTPFile(file) || isObjectStorageFile(file) {
+			targets = append(targets, ExternalFile{URL: file})
+		}
+	}
+	targets = append(targets, externalFiles...)
+
+	for _, ext := range targets {
+		if isObjectStorageFile(ext.URL) {
+			errors = append(errors, checkObjectStorageFile(ext)...)
+			continue
+		}
+		if !isHTTPFile(ext.URL) {
+			errors = append(errors, fmt.Sprintf("External file '%s' must be an http(s) URL, s3://bucket/key, or gs://bucket/key", ext.URL))
+			continue
+		}
+		errors = append(errors, checkExternalFile(ext)...)
+	}
+
+	return errors
+}
+
+func checkExternalFile(ext ExternalFile) []string {
+	var errors []string
+
+	resp, err := httpClient.Head(ext.URL)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("External file '%s' is unreachable: %v", ext.URL, err))
+		return errors
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errors = append(errors, fmt.Sprintf("External file '%s' returned HTTP %d", ext.URL, resp.StatusCode))
+		return errors
+	}
+
+	return checkExternalMetadata(ext.URL, ext, ext.URL, resp.Header)
+}
+
+// checkExternalMetadata compares the size, checksum, and content type declared
+// for an external file with the response headers of its download URL; name is
+// the entry as written in challenge.yml
+func checkExternalMetadata(name string, ext ExternalFile, url string, header http.Header) []string {
+	var errors []string
+
+	if ext.Size > 0 {
+		size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("External file '%s' does not report its size", name))
+		} else if size != ext.Size {
+			errors = append(errors, fmt.Sprintf("External file '%s' size mismatch: expected %d bytes, got %d", name, ext.Size, size))
+		}
+	}
+
+	if ext.ContentType != "" {
+		contentType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+		if !strings.EqualFold(contentType, ext.ContentType) {
+			errors = append(errors, fmt.Sprintf("External file '%s' content type mismatch: expected %s, got %s", name, ext.ContentType, header.Get("Content-Type")))
+		}
+	}
+
+	if ext.SHA256 != "" {
+		sum, err := remoteSHA256(url, header)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("External file '%s' checksum could not be verified: %v", name, err))
+		} else if !strings.EqualFold(sum, ext.SHA256) {
+			errors = append(errors, fmt.Sprintf("External file '%s' checksum mismatch: expected %s, got %s", name, strings.ToLower(ext.SHA256), sum))
+		}
+	}
+
+	return errors
+}
+
+// remoteSHA256 returns the object's SHA256 from well-known object storage
+// headers, falling back to downloading and hashing the content
+func remoteSHA256(url string, header http.Header) (string, error) {
+	for _, key := range []string{"X-Amz-Meta-Sha256", "X-Goog-Meta-Sha256"} {
+		if value := header.Get(key); value != "" {
+			return strings.ToLower(value), nil
+		}
+	}
+	if value := header.Get("X-Amz-Checksum-Sha256"); value != "" {
+		if raw, err := base64.StdEncoding.DecodeString(value); err == nil {
+			return hex.EncodeToString(raw), nil
+		}
+	}
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckExternalFiles(t *testing.T) {
+	content := "attachment content"
+	// sha256 of content
+	contentSum := "275448a1a959fc53524b38f1366f57a3ed7afaa59c9e099c72454e5fd7f8a6fa"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dump.zip":
+			_, _ = w.Write([]byte(content))
+		case "/meta.zip":
+			w.Header().Set("X-Amz-Meta-Sha256", strings.ToUpper(contentSum))
+			_, _ = w.Write([]byte(content))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		files      []string
+		external   []ExternalFile
+		wantErrors []string
+	}{
+		{name: "local files are ignored", files: []string{"public/a.txt"}},
+		{name: "url entry in files", files: []string{server.URL + "/dump.zip"}},
+		{name: "url entry in files not found", files: []string{server.URL + "/missing.zip"}, wantErrors: []string{"returned HTTP 404"}},
+		{name: "matching size and checksum", external: []ExternalFile{{URL: server.URL + "/dump.zip", Size: int64(len(content)), SHA256: contentSum}}},
+		{name: "checksum from object metadata", external: []ExternalFile{{URL: server.URL + "/meta.zip", SHA256: contentSum}}},
+		{name: "size mismatch", external: []ExternalFile{{URL: server.URL + "/dump.zip", Size: 1}}, wantErrors: []string{"size mismatch: expected 1 bytes"}},
+		{name: "checksum mismatch", external: []ExternalFile{{URL: server.URL + "/dump.zip", SHA256: strings.Repeat("0", 64)}}, wantErrors: []string{"checksum mismatch"}},
+		{name: "non url external file", external: []ExternalFile{{URL: "dump.zip"}}, wantErrors: []string{"must be an http(s) URL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkExternalFiles(tt.files, tt.external)
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got: %v", len(tt.wantErrors), errs)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("Expected error containing '%s', got: %s", want, errs[i])
+				}
+			}
+		})
+	}
+}
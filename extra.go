@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExtraField describes a key of the extra map of a challenge type
+type ExtraField struct {
+	// Type is the YAML type of the value: int, number, string, or bool
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+	// Values lists the allowed values, if restricted
+	Values []string `yaml:"values"`
+}
+
+// defaultExtraSchemas describe the extra keys of CTFd's dynamic challenges;
+// extra_schemas in lintrc.yaml replaces them per type and adds plugin types
+// such as king-of-the-hill
+var defaultExtraSchemas = map[string]map[string]ExtraField{
+	"dynamic": {
+		"initial":  {Type: "int"},
+		"decay":    {Type: "int"},
+		"minimum":  {Type: "int"},
+		"function": {Type: "string", Values: []string{"linear", "logarithmic"}},
+	},
+}
+
+// extraSchema returns the schema of a challenge type, and whether it has one
+func (c *LintConfig) extraSchema(challengeType string) (map[string]ExtraField, bool) {
+	if schema, ok := c.ExtraSchemas[challengeType]; ok {
+		return schema, true
+	}
+	schema, ok := defaultExtraSchemas[challengeType]
+	return schema, ok
+}
+
+// checkExtra validates the extra map against the schema of the challenge
+// type. Types without a schema are not checked.
+func checkExtra(challengeType string, extra map[string]interface{}, config *LintConfig) []string {
+	var errors []string
+
+	schema, ok := config.extraSchema(challengeType)
+	if !ok {
+		return errors
+	}
+
+	var known []string
+	for key := range schema {
+		known = append(known, key)
+	}
+	sort.Strings(known)
+
+	var keys []string
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := schema[key]
+		if !ok {
+			message := fmt.Sprintf("Field 'extra' has unknown key '%s' for type '%s'", key, challengeType)
+			if suggestion := closestKey(key, known); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			} else if len(known) > 0 {
+				message += fmt.Sprintf(" (expected %s)", strings.Join(known, ", "))
+			}
+			errors = append(errors, message)
+			continue
+		}
+		errors = append(errors, checkExtraValue(key, extra[key], field)...)
+	}
+
+	for _, key := range known {
+		if _, ok := extra[key]; !ok && schema[key].Required {
+			errors = append(errors, fmt.Sprintf("Field 'extra' is missing '%s' for type '%s'", key, challengeType))
+		}
+	}
+
+	return errors
+}
+
+// checkExtraValue checks a value of the extra map against its field
+func checkExtraValue(key string, value interface{}, field ExtraField) []string {
+	var errors []string
+
+	valid := true
+	switch field.Type {
+	case "int":
+		_, valid = value.(int)
+	case "number":
+		switch value.(type) {
+		case int, float64:
+		default:
+			valid = false
+		}
+	case "string":
+		_, valid = value.(string)
+	case "bool":
+		_, valid = value.(bool)
+	}
+	if !valid {
+		errors = append(errors, fmt.Sprintf("Field 'extra.%s' must be of type %s (got '%v')", key, field.Type, value))
+		return errors
+	}
+
+	if len(field.Values) > 0 && !containsFold(field.Values, fmt.Sprint(value)) {
+		errors = append(errors, fmt.Sprintf("Field 'extra.%s' must be one of %s (got '%v')", key, strings.Join(field.Values, ", "), value))
+	}
+
+	return errors
+}
+
+// closestKey returns the candidate within two edits of key, or "" if none
+func closestKey(key string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckExtra(t *testing.T) {
+	config, err := parseLintConfig([]byte(`extra_schemas:
+  koth:
+    tick:
+      type: int
+      required: true
+    scoring:
+      type: string
+      values: [linear, decay]
+    ratio:
+      type: number
+`), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		challenge  string
+		wantErrors []string
+	}{
+		{name: "dynamic", challenge: "type: dynamic\nextra: {initial: 500, decay: 100, minimum: 100, function: logarithmic}\n"},
+		{
+			name:       "dynamic typo",
+			challenge:  "type: dynamic\nextra: {initial: 500, dacay: 100, minimum: 100}\n",
+			wantErrors: []string{"Field 'extra' has unknown key 'dacay' for type 'dynamic' (did you mean 'decay'?)"},
+		},
+		{
+			name:      "dynamic wrong types",
+			challenge: "type: dynamic\nextra: {initial: '500', function: exponential}\n",
+			wantErrors: []string{
+				"Field 'extra.function' must be one of linear, logarithmic (got 'exponential')",
+				"Field 'extra.initial' must be of type int (got '500')",
+			},
+		},
+		{name: "type without schema", challenge: "type: standard\nextra: {anything: 1}\n"},
+		{name: "configured type", challenge: "type: koth\nextra: {tick: 60, scoring: LINEAR, ratio: 0.5}\n"},
+		{
+			name:      "configured type missing and unknown keys",
+			challenge: "type: koth\nextra: {hill: 1}\n",
+			wantErrors: []string{
+				"Field 'extra' has unknown key 'hill' for type 'koth' (expected ratio, scoring, tick)",
+				"Field 'extra' is missing 'tick' for type 'koth'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte(tt.challenge), &challenge); err != nil {
+				t.Fatal(err)
+			}
+			if got := checkExtra(challenge.Type, challenge.Extra, config); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkExtra() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestExtraSchemaOverride(t *testing.T) {
+	config, err := parseLintConfig([]byte("extra_schemas:\n  dynamic:\n    initial: {type: int, required: true}\n"), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Field 'extra' has unknown key 'decay' for type 'dynamic' (expected initial)"}
+	if got := checkExtra("dynamic", map[string]interface{}{"initial": 500, "decay": 100}, config); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkExtra() = %v, want %v", got, want)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"decay", "decay", 0},
+		{"dacay", "decay", 1},
+		{"minimun", "minimum", 1},
+		{"", "tick", 4},
+		{"initial", "inital", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// FilenameRule configures the names attachments are downloaded under, so
+// that files players grab from many challenges stay unambiguous
+type FilenameRule struct {
+	// ASCII requires filenames of printable ASCII characters only
+	ASCII bool `yaml:"ascii"`
+	// NoSpaces forbids whitespace in filenames
+	NoSpaces bool `yaml:"no_spaces"`
+	// Lowercase forbids uppercase letters in filenames
+	Lowercase bool `yaml:"lowercase"`
+	// Prefix every filename must start with. <slug> and <category> stand for
+	// the slugs of the challenge name and category, e.g. "<category>-<slug>"
+	// requires osint-phantom-capture.zip for "Phantom Capture" in OSINT.
+	Prefix string `yaml:"prefix"`
+}
+
+// attachmentName returns the name a files entry is downloaded under
+func attachmentName(file string) string {
+	if isRemoteFile(file) {
+		if parsed, err := url.Parse(file); err == nil {
+			return path.Base(parsed.Path)
+		}
+	}
+	return path.Base(slashPath(file))
+}
+
+// filenamePrefix expands the placeholders of a prefix for a challenge
+func filenamePrefix(prefix string, challenge Challenge) string {
+	return strings.NewReplacer("<slug>", slugify(challenge.Name), "<category>", slugify(challenge.Category)).Replace(prefix)
+}
+
+func checkFilenames(challenge Challenge, rule FilenameRule) []string {
+	var errors []string
+	prefix := filenamePrefix(rule.Prefix, challenge)
+
+	// Duplicated entries are reported by checkFileEntries, not once per copy
+	seen := make(map[string]bool)
+	for _, file := range challenge.Files {
+		cleaned := path.Clean(slashPath(file))
+		if seen[cleaned] {
+			continue
+		}
+		seen[cleaned] = true
+		name := attachmentName(file)
+		if name == "" || name == "." || name == "/" {
+			continue
+		}
+		if rule.ASCII && strings.IndexFunc(name, func(r rune) bool { return r > unicode.MaxASCII || !unicode.IsPrint(r) }) >= 0 {
+			errors = append(errors, fmt.Sprintf("Filename '%s' must only contain ASCII characters", name))
+		}
+		if rule.NoSpaces && strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+			errors = append(errors, fmt.Sprintf("Filename '%s' must not contain spaces", name))
+		}
+		if rule.Lowercase && strings.ToLower(name) != name {
+			errors = append(errors, fmt.Sprintf("Filename '%s' must be lowercase", name))
+		}
+		if prefix != "" && !strings.HasPrefix(name, prefix) {
+			errors = append(errors, fmt.Sprintf("Filename '%s' must start with '%s'", name, prefix))
+		}
+	}
+	return errors
+}
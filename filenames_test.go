@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckFilenames(t *testing.T) {
+	challenge := Challenge{
+		Name:     "Phantom Capture",
+		Category: "OSINT",
+		Files: []string{
+			"dist/osint-phantom-capture.zip",
+			"dist/Phantom Capture.png",
+			"写真.jpg",
+			`dist\Phantom Capture.png`,
+			"./dist/Phantom Capture.png",
+			"https://cdn.example.com/files/osint-phantom-capture-large.pcap?sig=abc",
+		},
+	}
+
+	tests := []struct {
+		name string
+		rule FilenameRule
+		want []string
+	}{
+		{name: "disabled"},
+		{
+			name: "ascii, spaces, lowercase",
+			rule: FilenameRule{ASCII: true, NoSpaces: true, Lowercase: true},
+			want: []string{
+				"Filename 'Phantom Capture.png' must not contain spaces",
+				"Filename 'Phantom Capture.png' must be lowercase",
+				"Filename '写真.jpg' must only contain ASCII characters",
+			},
+		},
+		{
+			name: "prefix",
+			rule: FilenameRule{Prefix: "<category>-<slug>"},
+			want: []string{
+				"Filename 'Phantom Capture.png' must start with 'osint-phantom-capture'",
+				"Filename '写真.jpg' must start with 'osint-phantom-capture'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkFilenames(challenge, tt.rule)
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkFilenames() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
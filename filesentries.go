@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// checkFileEntries checks the files list itself: each path must be listed
+// once, and neither challenge.yml nor a solution or writeup may be handed
+// out to players. Such entries deploy without error and only show up once
+// players download them.
+func checkFileEntries(challengePath string, files []string, config *LintConfig) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+	challengeInfo, _ := os.Stat(challengePath)
+	writeup := config.Publish.Writeup
+	if writeup == "" {
+		writeup = defaultWriteupPattern
+	}
+
+	seen := make(map[string]string)
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		cleaned := path.Clean(slashPath(file))
+		if first, ok := seen[cleaned]; ok {
+			if first == file {
+				errors = append(errors, fmt.Sprintf("File '%s' is listed more than once in 'files'", file))
+			} else {
+				errors = append(errors, fmt.Sprintf("File '%s' is listed more than once in 'files' (also as '%s')", file, first))
+			}
+			continue
+		}
+		seen[cleaned] = file
+
+		fullPath := filepath.Join(baseDir, filepath.FromSlash(cleaned))
+		if filepath.Clean(fullPath) == filepath.Clean(challengePath) || sameFile(challengeInfo, fullPath) {
+			errors = append(errors, fmt.Sprintf("File '%s' is the challenge.yml itself, which holds the flags", file))
+		} else if isSolutionEntry(cleaned, writeup) {
+			errors = append(errors, fmt.Sprintf("File '%s' looks like a solution or writeup and must not be given to players", file))
+		}
+	}
+	return errors
+}
+
+// sameFile reports whether path is the file described by info, such as a
+// symlink to it
+func sameFile(info os.FileInfo, path string) bool {
+	if info == nil {
+		return false
+	}
+	other, err := os.Stat(path)
+	return err == nil && os.SameFile(info, other)
+}
+
+// isSolutionEntry reports whether a files entry is in or named like a
+// solution: a path segment matches the writeup pattern or starts with
+// solution, solve, writeup, or exploit (solver/, solve.py, exploit.c)
+func isSolutionEntry(file, writeupPattern string) bool {
+	writeupPattern = strings.ToLower(writeupPattern)
+	for _, segment := range strings.Split(strings.ToLower(file), "/") {
+		if matched, _ := filepath.Match(writeupPattern, segment); matched {
+			return true
+		}
+		for _, word := range solutionPathWords {
+			if strings.HasPrefix(segment, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckFileEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	for _, name := range []string{"challenge.yml", "dist/chall.zip", "dist/readme.txt"} {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("../challenge.yml", filepath.Join(tempDir, "dist", "config.yml")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		files  []string
+		config LintConfig
+		want   []string
+	}{
+		{
+			name:  "distinct files",
+			files: []string{"dist/chall.zip", "dist/readme.txt", "https://example.com/big.zip", "https://example.com/big.zip", "resolve.txt"},
+		},
+		{
+			name:  "duplicates",
+			files: []string{"dist/chall.zip", "dist/chall.zip", "./dist/chall.zip", "dist\\chall.zip"},
+			want: []string{
+				"File 'dist/chall.zip' is listed more than once in 'files'",
+				"File './dist/chall.zip' is listed more than once in 'files' (also as 'dist/chall.zip')",
+				"File 'dist\\chall.zip' is listed more than once in 'files' (also as 'dist/chall.zip')",
+			},
+		},
+		{
+			name:  "challenge.yml",
+			files: []string{"challenge.yml", "dist/../challenge.yml", "dist/config.yml"},
+			want: []string{
+				"File 'challenge.yml' is the challenge.yml itself, which holds the flags",
+				"File 'dist/../challenge.yml' is listed more than once in 'files' (also as 'challenge.yml')",
+				"File 'dist/config.yml' is the challenge.yml itself, which holds the flags",
+			},
+		},
+		{
+			name:  "solutions and writeups",
+			files: []string{"solver/solve.py", "Writeup.md", "dist/exploit.c", "docs/notes.md"},
+			want: []string{
+				"File 'solver/solve.py' looks like a solution or writeup and must not be given to players",
+				"File 'Writeup.md' looks like a solution or writeup and must not be given to players",
+				"File 'dist/exploit.c' looks like a solution or writeup and must not be given to players",
+			},
+		},
+		{
+			name:   "configured writeup pattern",
+			files:  []string{"docs/notes.md"},
+			config: LintConfig{Publish: PublishRule{Writeup: "notes*"}},
+			want:   []string{"File 'docs/notes.md' looks like a solution or writeup and must not be given to players"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkFileEntries(challengePath, tt.files, &tt.config)
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkFileEntries() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
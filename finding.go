@@ -0,0 +1,131 @@
+package main
+
+import "sort"
+
+// Severities of a finding
+const (
+	SeverityError      = "error"
+	SeverityWarning    = "warning"
+	SeverityDeprecated = "deprecated"
+	// SeveritySecurity marks errors that protect the lint run or players,
+	// such as archive bombs
+	SeveritySecurity = "security"
+)
+
+// Finding is a single problem reported for a challenge.yml
+type Finding struct {
+	RuleID   string `json:"rule_id"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// Field is the top-level key the finding concerns, "" when it cannot be
+	// attributed to a single field
+	Field string `json:"field,omitempty"`
+	// Line and Column (1-based) locate Field in challenge.yml, 0 when unknown
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Excerpt is the numbered challenge.yml lines around Line, flags redacted
+	Excerpt string `json:"excerpt,omitempty"`
+	// Fixable reports whether --fix can correct the finding
+	Fixable bool `json:"fixable,omitempty"`
+	// Docs links the rule's guideline section when docs_base_url is configured
+	Docs string `json:"docs,omitempty"`
+}
+
+func (f Finding) String() string {
+	return f.Message
+}
+
+func (r *LintResult) newFindings(ruleID, severity string, messages []string) []Finding {
+	findings := make([]Finding, 0, len(messages))
+	for _, message := range messages {
+		findings = append(findings, Finding{
+			RuleID:   ruleID,
+			Severity: severity,
+			Message:  message,
+			Field:    ruleField(ruleID),
+			Docs:     docsURL(r.docsBaseURL, ruleID),
+		})
+	}
+	return findings
+}
+
+func (r *LintResult) addErrors(ruleID string, errs []string) {
+	r.Errors = append(r.Errors, r.newFindings(ruleID, SeverityError, errs)...)
+}
+
+// addSecurity records security findings, which fail the run like errors
+func (r *LintResult) addSecurity(ruleID string, errs []string) {
+	r.Errors = append(r.Errors, r.newFindings(ruleID, SeveritySecurity, errs)...)
+}
+
+func (r *LintResult) addWarnings(ruleID string, warnings []string) {
+	r.Warnings = append(r.Warnings, r.newFindings(ruleID, SeverityWarning, warnings)...)
+}
+
+func (r *LintResult) addDeprecations(ruleID string, deprecations []string) {
+	r.Deprecations = append(r.Deprecations, r.newFindings(ruleID, SeverityDeprecated, deprecations)...)
+}
+
+// locateFindings fills in the position and excerpt of each finding's field
+// in data and marks findings on fields that have an automatic fix. Excerpts
+// leave out the lines containing one of the flags.
+func (r *LintResult) locateFindings(data []byte, flags []string) {
+	fixable := make(map[string]bool)
+	for _, fix := range r.Fixes {
+		fixable[fix.Field] = true
+	}
+	positions := make(map[string][2]int)
+	var lines []string
+
+	for _, findings := range [][]Finding{r.Errors, r.Warnings, r.Deprecations} {
+		for i := range findings {
+			finding := &findings[i]
+			if finding.Field == "" {
+				continue
+			}
+			finding.Fixable = fixable[finding.Field]
+			position, ok := positions[finding.Field]
+			if !ok {
+				if location, err := findTopLevelField(data, finding.Field); err == nil && location != nil {
+					position = [2]int{location.Key.Line, location.Key.Column}
+				}
+				positions[finding.Field] = position
+			}
+			finding.Line, finding.Column = position[0], position[1]
+			if finding.Line > 0 {
+				if lines == nil {
+					lines = excerptLines(data, flags)
+				}
+				finding.Excerpt = excerpt(lines, finding.Line)
+			}
+		}
+	}
+}
+
+// sortFindings orders findings by line, then rule ID. Findings without a
+// position come last; ties keep the order the checks reported them in.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.RuleID < b.RuleID
+	})
+}
+
+// sortResults orders results by file path and the findings of each result,
+// so that every reporter produces the same output for the same tree
+func sortResults(results []LintResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].File < results[j].File
+	})
+	for i := range results {
+		sortFindings(results[i].Errors)
+		sortFindings(results[i].Warnings)
+		sortFindings(results[i].Deprecations)
+	}
+}
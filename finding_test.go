@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindingLocations(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	content := "name: chall\nfiles:\n  - missing.txt\nstate: hidden\nversion: \"0.1\"\ntype: standard\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+
+	result := lintChallengeFile(path)
+	byRule := make(map[string]Finding)
+	for _, finding := range append(result.Errors, result.Warnings...) {
+		byRule[finding.RuleID] = finding
+	}
+
+	state := byRule["state"]
+	if state.Severity != SeverityError || state.Field != "state" || state.Line != 4 || state.Column != 1 || !state.Fixable {
+		t.Errorf("Unexpected state finding: %+v", state)
+	}
+	files := byRule["files"]
+	if files.Line != 2 || files.Fixable {
+		t.Errorf("Unexpected files finding: %+v", files)
+	}
+	typ := byRule["type"]
+	if typ.Severity != SeverityWarning || typ.Line != 6 {
+		t.Errorf("Unexpected type finding: %+v", typ)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `{"rule_id":"state","severity":"error","message":"Field 'state' should be 'visible'","field":"state","line":4,"column":1,"excerpt":"  2 | files:\n  3 |   - missing.txt\n\u003e 4 | state: hidden\n  5 | version: \"0.1\"\n  6 | type: standard","fixable":true}`) {
+		t.Errorf("Unexpected JSON encoding: %s", data)
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	results := []LintResult{
+		{File: "web/b/challenge.yml", Errors: []Finding{
+			{RuleID: "kubernetes", Message: "k8s"},
+			{RuleID: "tags", Line: 9, Message: "tags"},
+			{RuleID: "state", Line: 3, Message: "state"},
+			{RuleID: "files", Line: 3, Message: "files 1"},
+			{RuleID: "files", Line: 3, Message: "files 2"},
+		}},
+		{File: "pwn/a/challenge.yml"},
+		{File: "web/a/challenge.yml"},
+	}
+	sortResults(results)
+
+	var files []string
+	for _, result := range results {
+		files = append(files, result.File)
+	}
+	if strings.Join(files, ",") != "pwn/a/challenge.yml,web/a/challenge.yml,web/b/challenge.yml" {
+		t.Errorf("Unexpected result order: %v", files)
+	}
+	got := strings.Join(findingMessages(results[2].Errors), ",")
+	if got != "files 1,files 2,state,tags,k8s" {
+		t.Errorf("Unexpected finding order: %s", got)
+	}
+}
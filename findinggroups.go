@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// findingGroupThreshold is how many challenges must have an identical error
+// or warning for the PR comment to report it once, as a group, rather than
+// under each challenge; such findings usually come from a config change
+const findingGroupThreshold = 5
+
+// findingGroup is an error or warning shared by many challenges
+type findingGroup struct {
+	finding Finding
+	files   []string
+}
+
+// findingKey identifies identical findings across challenges
+func findingKey(finding Finding) string {
+	return finding.RuleID + "\x00" + finding.Severity + "\x00" + finding.Message
+}
+
+// groupFindings returns the errors and warnings that at least threshold
+// challenges share, the most common first
+func groupFindings(results []LintResult, threshold int) []findingGroup {
+	byKey := make(map[string]*findingGroup)
+	for _, result := range results {
+		seen := make(map[string]bool)
+		for _, findings := range [][]Finding{result.Errors, result.Warnings} {
+			for _, finding := range findings {
+				key := findingKey(finding)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				if byKey[key] == nil {
+					byKey[key] = &findingGroup{finding: finding}
+				}
+				byKey[key].files = append(byKey[key].files, result.File)
+			}
+		}
+	}
+
+	var groups []findingGroup
+	for _, group := range byKey {
+		if len(group.files) >= threshold {
+			groups = append(groups, *group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if len(a.files) != len(b.files) {
+			return len(a.files) > len(b.files)
+		}
+		if a.finding.Severity != b.finding.Severity {
+			// Errors and security findings before warnings
+			return a.finding.Severity != SeverityWarning
+		}
+		return findingKey(a.finding) < findingKey(b.finding)
+	})
+	return groups
+}
+
+// withoutGroupedFindings returns the result without the findings reported
+// in groups, and whether any were removed
+func withoutGroupedFindings(result LintResult, groups []findingGroup) (LintResult, bool) {
+	if len(groups) == 0 {
+		return result, false
+	}
+	grouped := make(map[string]bool)
+	for _, group := range groups {
+		grouped[findingKey(group.finding)] = true
+	}
+	removed := false
+	keep := func(finding Finding) bool {
+		if grouped[findingKey(finding)] {
+			removed = true
+			return false
+		}
+		return true
+	}
+	result.Errors = filterFindings(result.Errors, keep)
+	result.Warnings = filterFindings(result.Warnings, keep)
+	return result, removed
+}
+
+// writeFindingGroups writes the findings shared by many challenges once
+// each, with the affected challenges folded away
+func writeFindingGroups(body *strings.Builder, groups []findingGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	body.WriteString("### 🧩 Findings Shared by Many Challenges:\n\n")
+	for _, group := range groups {
+		icon, verb := "❌", "fail"
+		if group.finding.Severity == SeverityWarning {
+			icon, verb = "⚠️", "warn on"
+		}
+		body.WriteString(fmt.Sprintf("- %s **%d challenges** %s rule `%s`: %s%s\n", icon, len(group.files), verb, group.finding.RuleID, group.finding.Message, markdownDocsLink(group.finding.Docs)))
+		body.WriteString("  <details><summary>Challenges</summary>\n\n")
+		for _, file := range group.files {
+			body.WriteString(fmt.Sprintf("  - `%s`\n", file))
+		}
+		body.WriteString("\n  </details>\n")
+	}
+	body.WriteString("\n---\n\n")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGroupFindings(t *testing.T) {
+	tags := Finding{RuleID: "tags", Severity: SeverityError, Message: "Field 'tags' is required"}
+	typeWarning := Finding{RuleID: "type", Severity: SeverityWarning, Message: "Field 'type' is 'standard'"}
+	var results []LintResult
+	for i := 0; i < 6; i++ {
+		result := LintResult{File: fmt.Sprintf("web/c%d/challenge.yml", i), Name: fmt.Sprintf("c%d", i), Errors: []Finding{tags}}
+		if i < 5 {
+			result.Warnings = []Finding{typeWarning}
+		}
+		results = append(results, result)
+	}
+	unique := Finding{RuleID: "value", Severity: SeverityError, Message: "Field 'value' is required"}
+	results[0].Errors = append(results[0].Errors, unique, unique)
+
+	groups := groupFindings(results, findingGroupThreshold)
+	if len(groups) != 2 || groups[0].finding != tags || len(groups[0].files) != 6 || groups[1].finding != typeWarning || len(groups[1].files) != 5 {
+		t.Fatalf("groupFindings() = %+v", groups)
+	}
+
+	first, grouped := withoutGroupedFindings(results[0], groups)
+	if !grouped || len(first.Errors) != 2 || len(first.Warnings) != 0 {
+		t.Errorf("withoutGroupedFindings() = %+v, %v", first, grouped)
+	}
+	if len(results[0].Errors) != 3 {
+		t.Error("withoutGroupedFindings() must not modify the result")
+	}
+
+	body := generateCommentBody(results, true)
+	for _, want := range []string{
+		"- ❌ **6 challenges** fail rule `tags`: Field 'tags' is required",
+		"- ⚠️ **5 challenges** warn on rule `type`: Field 'type' is 'standard'",
+		"  - `web/c5/challenge.yml`",
+		"#### ❌ **c0** (`web/c0/challenge.yml`)",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("comment is missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "**c1**") || strings.Count(body, "Field 'tags' is required") != 1 {
+		t.Errorf("grouped findings repeated per challenge:\n%s", body)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// diffMode is set by --diff: fixes are shown as unified diffs instead of
+// being written, and attached to the PR comment
+var diffMode bool
+
+// Fix is an automatic correction of a top-level challenge.yml field
+type Fix struct {
+	Field   string // top-level key
+	Value   string // replacement value as YAML text
+	Message string // finding resolved by the fix
+}
+
+// fixesFor returns the fixes for the auto-fixable findings of a challenge
+func fixesFor(challenge Challenge, config *LintConfig) []Fix {
+	var fixes []Fix
+
+	// Challenges of unreleased waves stay hidden, see checkSchedule
+	if challenge.State != "visible" && gatedWave(challenge, config) == 0 {
+		fixes = append(fixes, Fix{Field: "state", Value: "visible", Message: "Field 'state' should be 'visible'"})
+	}
+	if challenge.Version != "0.1" {
+		fixes = append(fixes, Fix{Field: "version", Value: `"0.1"`, Message: "Field 'version' should be '0.1'"})
+	}
+	if challenge.Image != nil && !config.AllowImage {
+		fixes = append(fixes, Fix{Field: "image", Value: "null", Message: "Field 'image' should be null"})
+	}
+
+	return fixes
+}
+
+// applyFixes rewrites challenge.yml with the given fixes, preserving everything else
+func applyFixes(filePath string, fixes []Fix) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	data, err = fixedData(data, fixes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// fixedData returns the contents of a challenge.yml with the fixes applied
+func fixedData(data []byte, fixes []Fix) ([]byte, error) {
+	var err error
+	for _, fix := range fixes {
+		data, err = setTopLevelScalar(data, fix.Field, fix.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix '%s': %v", fix.Field, err)
+		}
+	}
+	return data, nil
+}
+
+// fixDiff returns the fixes of a challenge.yml as a unified diff. Like
+// excerpts, its context never shows flags or the secretFields.
+func fixDiff(filePath string, data []byte, fixes []Fix, flags []string) (string, error) {
+	fixed, err := fixedData(data, fixes)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(filePath, redactedData(data, flags), redactedData(fixed, flags)), nil
+}
+
+// redactedData returns a challenge.yml with its lines as excerpts show them
+func redactedData(data []byte, flags []string) []byte {
+	redacted := strings.Join(excerptLines(data, flags), "\n")
+	if bytes.HasSuffix(data, []byte("\n")) {
+		redacted += "\n"
+	}
+	return []byte(redacted)
+}
+
+// writeFixDiff writes the changes --fix would make to a challenge, folded
+// away, to the PR comment
+func writeFixDiff(body *strings.Builder, diff string) {
+	if diff == "" {
+		return
+	}
+	body.WriteString("\n<details><summary>🔧 Changes <code>clilint --fix</code> would make</summary>\n\n```diff\n")
+	body.WriteString(diff)
+	body.WriteString("```\n\n</details>\n")
+}
+
+// suggestionComments builds inline review comments with GitHub suggestion
+// blocks for every fix that can be expressed as a single-line change
+func suggestionComments(results []LintResult) []*github.DraftReviewComment {
+	var comments []*github.DraftReviewComment
+
+	for _, result := range results {
+		if len(result.Fixes) == 0 {
+			continue
+		}
+		for _, fix := range result.Fixes {
+			line, replacement, ok := fieldLineAfter(result.data, fix.Field, fix.Value)
+			if !ok {
+				continue
+			}
+			body := fmt.Sprintf("%s\n\n```suggestion\n%s\n```", fix.Message, replacement)
+			comments = append(comments, &github.DraftReviewComment{
+				Path: github.String(result.File),
+				Line: github.Int(line),
+				Side: github.String("RIGHT"),
+				Body: github.String(body),
+			})
+		}
+	}
+
+	return comments
+}
+
+// postReviewSuggestions posts a PR review containing suggestion comments
+func postReviewSuggestions(results []LintResult, env Env) error {
+	comments := suggestionComments(results)
+	if len(comments) == 0 {
+		return nil
+	}
+
+	client, ctx := getGitHubClient(env.token)
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return fmt.Errorf("error getting PR: %v", err)
+	}
+
+	review := &github.PullRequestReviewRequest{
+		CommitID: github.String(pr.GetHead().GetSHA()),
+		Event:    github.String("COMMENT"),
+		Body:     github.String("🔧 clilint can fix some findings automatically. Accept the suggestions below to apply them."),
+		Comments: comments,
+	}
+	_, _, err = client.PullRequests.CreateReview(ctx, env.owner, env.repo, env.prNumber, review)
+	if err != nil {
+		return fmt.Errorf("failed to post review suggestions: %v", err)
+	}
+
+	fmt.Printf("Posted %d suggestion(s) to PR #%d\n", len(comments), env.prNumber)
+	return nil
+}
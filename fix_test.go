@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyFixes(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	input := `# flag provenance: generated by solver.py
+name: "chall"
+image:
+  name: chall
+host: null
+state: hidden # flipped at release
+version: "0.2"
+`
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+
+	challenge, err := readChallenge(path)
+	if err != nil {
+		t.Fatalf("readChallenge failed: %v", err)
+	}
+	fixes := fixesFor(challenge, &LintConfig{})
+	if len(fixes) != 3 {
+		t.Fatalf("Expected 3 fixes, got: %v", fixes)
+	}
+
+	comments := suggestionComments([]LintResult{{File: path, Fixes: fixes, data: []byte(input)}})
+	if len(comments) != 2 {
+		t.Fatalf("Expected suggestions for state and version only, got %d", len(comments))
+	}
+	if comments[0].GetLine() != 6 || !strings.Contains(comments[0].GetBody(), "```suggestion\nstate: visible # flipped at release\n```") {
+		t.Errorf("Unexpected state suggestion at line %d: %s", comments[0].GetLine(), comments[0].GetBody())
+	}
+
+	if err := applyFixes(path, fixes); err != nil {
+		t.Fatalf("applyFixes failed: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	want := `# flag provenance: generated by solver.py
+name: "chall"
+image: null
+host: null
+state: visible # flipped at release
+version: "0.1"
+`
+	if string(got) != want {
+		t.Errorf("applyFixes() wrote\n%s\nwant\n%s", got, want)
+	}
+
+	challenge, _ = readChallenge(path)
+	if fixes := fixesFor(challenge, &LintConfig{}); len(fixes) != 0 {
+		t.Errorf("Expected no fixes after applying, got: %v", fixes)
+	}
+}
+
+func TestFixesForAllowedImage(t *testing.T) {
+	challenge := Challenge{State: "visible", Version: "0.1", Image: "registry.example.com/chall:1.0"}
+	if fixes := fixesFor(challenge, &LintConfig{AllowImage: true}); len(fixes) != 0 {
+		t.Errorf("Expected no fixes when image is allowed, got: %v", fixes)
+	}
+}
+
+func TestFixDiff(t *testing.T) {
+	input := "name: chall\nimage:\n  name: chall\nstate: hidden\n"
+	fixes := []Fix{{Field: "state", Value: "visible"}, {Field: "image", Value: "null"}}
+	got, err := fixDiff("web/chall/challenge.yml", []byte(input), fixes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- a/web/chall/challenge.yml
++++ b/web/chall/challenge.yml
+@@ -1,4 +1,3 @@
+ name: chall
+-image:
+-  name: chall
+-state: hidden
++image: null
++state: visible
+`
+	if got != want {
+		t.Errorf("fixDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	results := []LintResult{{
+		File:    "web/chall/challenge.yml",
+		Name:    "chall",
+		Errors:  []Finding{{RuleID: "state", Severity: SeverityError, Message: "Field 'state' should be 'visible'"}},
+		Fixes:   fixes,
+		fixDiff: got,
+	}}
+	body := generateCommentBody(results, true)
+	if !strings.Contains(body, "<details><summary>🔧 Changes <code>clilint --fix</code> would make</summary>\n\n```diff\n--- a/web/chall/challenge.yml\n") {
+		t.Errorf("comment lacks the fix diff:\n%s", body)
+	}
+}
+
+func TestFixDiffRedactsFlags(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "challenge.yml")
+	input := "name: chall\nhints:\n  - the answer is flag{secret_value}\nflags:\n  - flag{secret_value}\nstate: hidden\nversion: \"0.1\"\n"
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origDiffMode := diffMode
+	diffMode = true
+	defer func() { diffMode = origDiffMode }()
+
+	result := lintChallengeFile(path)
+	if !strings.Contains(result.fixDiff, "+state: visible") {
+		t.Fatalf("fix diff lacks the state fix:\n%s", result.fixDiff)
+	}
+	body := generateCommentBody([]LintResult{result}, true)
+	if strings.Contains(body, "secret_value") {
+		t.Errorf("comment shows the flag:\n%s", body)
+	}
+	if !strings.Contains(body, " flags: "+redactedLine) {
+		t.Errorf("diff context does not redact flags:\n%s", body)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Conflicts between two static flags, from the point of view of the first.
+// Flags are never echoed into reports.
+const (
+	flagDuplicate             = "Flag is also used by %s"
+	flagCaseOnly              = "Flag differs only by case from a flag of %s"
+	flagAcceptsOther          = "Case-insensitive flag also accepts a flag of %s"
+	flagAcceptedByInsensitive = "Flag is also accepted by a case-insensitive flag of %s"
+)
+
+// Regex flags accepting another challenge's static flag, reported as
+// warnings since a broad regex is often deliberate
+const (
+	flagRegexAcceptsOther = "Regex flag also accepts a flag of %s"
+	flagAcceptedByRegex   = "Flag is also accepted by a regex flag of %s"
+)
+
+// flagConflictOrder is the order conflicts of one flag are reported in
+var flagConflictOrder = []string{flagDuplicate, flagCaseOnly, flagAcceptsOther, flagAcceptedByInsensitive}
+
+// parsedFlag is a static flag as CTFd compares submissions against it
+type parsedFlag struct {
+	content         string
+	caseInsensitive bool
+}
+
+// flagOwner is a challenge file and its static and regex flags
+type flagOwner struct {
+	file    string
+	flags   []parsedFlag
+	regexes []*regexp.Regexp
+}
+
+// staticFlags returns the static flags of a challenge; plain string flags
+// are static and case-sensitive, and regex flags are not compared
+func staticFlags(flags []FlagItem) []parsedFlag {
+	var parsed []parsedFlag
+	for _, flag := range flags {
+		content := flag.Content()
+		if content == "" {
+			continue
+		}
+		caseInsensitive := false
+		if flag.FlagValue != nil {
+			if flag.FlagValue.Type != "" && flag.FlagValue.Type != "static" {
+				continue
+			}
+			caseInsensitive = flag.FlagValue.Data != nil && *flag.FlagValue.Data == "case_insensitive"
+		}
+		parsed = append(parsed, parsedFlag{content: content, caseInsensitive: caseInsensitive})
+	}
+	return parsed
+}
+
+// regexFlags returns the regex flags of a challenge as CTFd matches them:
+// against the whole submission. Regexes that do not compile are reported by
+// checkFlags and skipped here.
+func regexFlags(flags []FlagItem) []*regexp.Regexp {
+	var regexes []*regexp.Regexp
+	for _, flag := range flags {
+		if flag.FlagValue == nil || flag.FlagValue.Type != "regex" || flag.FlagValue.Content == "" {
+			continue
+		}
+		pattern := "^(?:" + flag.FlagValue.Content + ")$"
+		if flag.FlagValue.Data != nil && *flag.FlagValue.Data == "case_insensitive" {
+			pattern = "(?i)" + pattern
+		}
+		if regex, err := regexp.Compile(pattern); err == nil {
+			regexes = append(regexes, regex)
+		}
+	}
+	return regexes
+}
+
+// flagConflict returns the conflict format for two flags of different
+// challenges, or "" when no submission is accepted by both
+func flagConflict(flag, other parsedFlag) string {
+	switch {
+	case flag.content == other.content:
+		return flagDuplicate
+	case !strings.EqualFold(flag.content, other.content):
+		return ""
+	case flag.caseInsensitive:
+		return flagAcceptsOther
+	case other.caseInsensitive:
+		return flagAcceptedByInsensitive
+	default:
+		return flagCaseOnly
+	}
+}
+
+// flagCollisions reports the conflicts of owner's flags with the flags of
+// others, once per kind of conflict, naming each conflicting file once even
+// when several flags of owner (or the same flag listed twice) conflict with it
+func flagCollisions(owner flagOwner, others []flagOwner) []string {
+	var errors []string
+	files := make(map[string][]string)
+	for _, flag := range owner.flags {
+		for _, other := range others {
+			if other.file == owner.file {
+				continue
+			}
+			for _, otherFlag := range other.flags {
+				if format := flagConflict(flag, otherFlag); format != "" {
+					files[format] = append(files[format], other.file)
+				}
+			}
+		}
+	}
+	for _, format := range flagConflictOrder {
+		if len(files[format]) > 0 {
+			errors = append(errors, fmt.Sprintf(format, strings.Join(uniqueSorted(files[format]), ", ")))
+		}
+	}
+	return errors
+}
+
+// regexCollisions reports the files with a static flag that a regex flag of
+// owner accepts, and those with a regex flag accepting a static flag of
+// owner, each file once
+func regexCollisions(owner flagOwner, others []flagOwner) []string {
+	var warnings []string
+	accepts := func(regexes []*regexp.Regexp, flags []parsedFlag) bool {
+		for _, regex := range regexes {
+			for _, flag := range flags {
+				if regex.MatchString(flag.content) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var accepting, accepted []string
+	for _, other := range others {
+		if other.file == owner.file {
+			continue
+		}
+		if accepts(owner.regexes, other.flags) {
+			accepting = append(accepting, other.file)
+		}
+		if accepts(other.regexes, owner.flags) {
+			accepted = append(accepted, other.file)
+		}
+	}
+	if len(accepting) > 0 {
+		warnings = append(warnings, fmt.Sprintf(flagRegexAcceptsOther, strings.Join(uniqueSorted(accepting), ", ")))
+	}
+	if len(accepted) > 0 {
+		warnings = append(warnings, fmt.Sprintf(flagAcceptedByRegex, strings.Join(uniqueSorted(accepted), ", ")))
+	}
+	return warnings
+}
+
+// checkFlagCollisions reports flags of the linted challenges that CTFd would
+// also accept for another challenge, among the results and the challenge
+// files in others (e.g. the rest of the repository in PR mode)
+func checkFlagCollisions(results []LintResult, others []string) {
+	var owners []flagOwner
+	linted := make(map[string]bool)
+	for _, result := range results {
+		file := filepath.Clean(result.File)
+		linted[file] = true
+		owners = append(owners, flagOwner{file: file, flags: result.flags, regexes: result.regexes})
+	}
+	for _, file := range others {
+		file = filepath.Clean(file)
+		if linted[file] {
+			continue
+		}
+		challenge, err := readChallenge(file)
+		if err != nil {
+			continue
+		}
+		owners = append(owners, flagOwner{file: file, flags: staticFlags(challenge.Flags), regexes: regexFlags(challenge.Flags)})
+	}
+
+	for i := range results {
+		result := &results[i]
+		if errs := flagCollisions(owners[i], owners); len(errs) > 0 {
+			start := len(result.Errors)
+			result.addErrors("flag-collision", errs)
+			for j := start; j < len(result.Errors); j++ {
+				result.Errors[j].Line, result.Errors[j].Column = result.flagsAt[0], result.flagsAt[1]
+			}
+			sortFindings(result.Errors)
+		}
+		if warnings := regexCollisions(owners[i], owners); len(warnings) > 0 {
+			start := len(result.Warnings)
+			result.addWarnings("flag-collision", warnings)
+			for j := start; j < len(result.Warnings); j++ {
+				result.Warnings[j].Line, result.Warnings[j].Column = result.flagsAt[0], result.flagsAt[1]
+			}
+			sortFindings(result.Warnings)
+		}
+	}
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	var unique []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStaticFlags(t *testing.T) {
+	var challenge Challenge
+	data := `flags:
+  - flag{plain}
+  - type: static
+    content: flag{Mixed}
+    data: case_insensitive
+  - type: static
+    content: flag{exact}
+  - type: regex
+    content: flag{.*}
+`
+	if err := yaml.Unmarshal([]byte(data), &challenge); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []parsedFlag{
+		{content: "flag{plain}"},
+		{content: "flag{Mixed}", caseInsensitive: true},
+		{content: "flag{exact}"},
+	}
+	if got := staticFlags(challenge.Flags); !reflect.DeepEqual(got, want) {
+		t.Errorf("staticFlags() = %+v, want %+v", got, want)
+	}
+	regexes := regexFlags(challenge.Flags)
+	if len(regexes) != 1 || regexes[0].String() != "^(?:flag{.*})$" {
+		t.Errorf("regexFlags() = %v, want the anchored regex flag", regexes)
+	}
+}
+
+func TestRegexCollisions(t *testing.T) {
+	var challenge Challenge
+	data := `flags:
+  - type: regex
+    content: flag\{[a-z]+\}
+    data: case_insensitive
+  - type: regex
+    content: flag\{\d+\}
+`
+	if err := yaml.Unmarshal([]byte(data), &challenge); err != nil {
+		t.Fatal(err)
+	}
+	owner := flagOwner{file: "web/regex/challenge.yml", flags: []parsedFlag{{content: "flag{static}"}}, regexes: regexFlags(challenge.Flags)}
+	others := []flagOwner{
+		owner,
+		{file: "web/b/challenge.yml", flags: []parsedFlag{{content: "FLAG{ABC}"}}},
+		{file: "web/c/challenge.yml", flags: []parsedFlag{{content: "flag{abc}x"}, {content: "flag{123}"}}},
+		{file: "web/d/challenge.yml", regexes: regexFlags([]FlagItem{{FlagValue: &Flag{Type: "regex", Content: "flag\\{s.*"}}})},
+	}
+
+	want := []string{
+		"Regex flag also accepts a flag of web/b/challenge.yml, web/c/challenge.yml",
+		"Flag is also accepted by a regex flag of web/d/challenge.yml",
+	}
+	if got := regexCollisions(owner, others); !reflect.DeepEqual(got, want) {
+		t.Errorf("regexCollisions() = %q, want %q", got, want)
+	}
+}
+
+func TestFlagConflict(t *testing.T) {
+	tests := []struct {
+		name  string
+		flag  parsedFlag
+		other parsedFlag
+		want  string
+	}{
+		{name: "different flags", flag: parsedFlag{content: "flag{a}"}, other: parsedFlag{content: "flag{b}"}, want: ""},
+		{name: "equal flags", flag: parsedFlag{content: "flag{a}"}, other: parsedFlag{content: "flag{a}"}, want: flagDuplicate},
+		{name: "differ only by case", flag: parsedFlag{content: "flag{a}"}, other: parsedFlag{content: "FLAG{A}"}, want: flagCaseOnly},
+		{name: "case-insensitive flag", flag: parsedFlag{content: "flag{a}", caseInsensitive: true}, other: parsedFlag{content: "FLAG{A}"}, want: flagAcceptsOther},
+		{name: "other case-insensitive", flag: parsedFlag{content: "flag{a}"}, other: parsedFlag{content: "FLAG{A}", caseInsensitive: true}, want: flagAcceptedByInsensitive},
+		{name: "case-insensitive but different", flag: parsedFlag{content: "flag{a}", caseInsensitive: true}, other: parsedFlag{content: "flag{b}"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flagConflict(tt.flag, tt.other); got != tt.want {
+				t.Errorf("flagConflict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFlagCollisions(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A challenge the run did not lint, e.g. one untouched by the PR
+	untouched := filepath.Join("misc", "old", "challenge.yml")
+	if err := os.MkdirAll(filepath.Dir(untouched), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(untouched, []byte("name: old\nflags:\n  - flag{Shared}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []LintResult{
+		{File: filepath.Join("web", "a", "challenge.yml"), flags: []parsedFlag{{content: "flag{shared}", caseInsensitive: true}}, flagsAt: [2]int{3, 1}},
+		// The same flag listed twice is reported once
+		{File: filepath.Join("web", "b", "challenge.yml"), flags: []parsedFlag{{content: "flag{SHARED}"}, {content: "flag{unique}"}, {content: "flag{SHARED}"}}, flagsAt: [2]int{5, 1}},
+		{File: filepath.Join("web", "c", "challenge.yml"), flags: []parsedFlag{{content: "flag{other}"}}},
+	}
+	checkFlagCollisions(results, []string{untouched, results[0].File})
+
+	want := [][]string{
+		{"Case-insensitive flag also accepts a flag of misc/old/challenge.yml, web/b/challenge.yml"},
+		{
+			"Flag differs only by case from a flag of misc/old/challenge.yml",
+			"Flag is also accepted by a case-insensitive flag of web/a/challenge.yml",
+		},
+		nil,
+	}
+	for i, result := range results {
+		var got []string
+		for _, finding := range result.Errors {
+			got = append(got, finding.Message)
+			if finding.RuleID != "flag-collision" || finding.Line != result.flagsAt[0] {
+				t.Errorf("unexpected finding %+v", finding)
+			}
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s: got %v, want %v", result.File, got, want[i])
+		}
+	}
+}
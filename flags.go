@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// flagFields are the keys of a map-style flag
+var flagFields = map[string]bool{"type": true, "content": true, "data": true}
+
+// flagTypes are the CTFd flag types
+var flagTypes = map[string]bool{"static": true, "regex": true}
+
+// checkFlags validates each flag: strings must not be empty, map-style
+// flags need a known type, content, and a known data value if set, and
+// regex flags must compile. Static flags of one challenge must not contain
+// each other.
+func checkFlags(flags []FlagItem) []string {
+	var errors []string
+
+	for i, flag := range flags {
+		n := i + 1
+		switch {
+		case flag.invalid != "":
+			errors = append(errors, fmt.Sprintf("Flag %d %s", n, flag.invalid))
+		case flag.StringValue != nil:
+			if *flag.StringValue == "" {
+				errors = append(errors, fmt.Sprintf("Flag %d is empty", n))
+			}
+		case flag.FlagValue != nil:
+			if flag.FlagValue.Type == "" {
+				errors = append(errors, fmt.Sprintf("Flag %d is missing 'type' (static or regex)", n))
+			} else if !flagTypes[flag.FlagValue.Type] {
+				errors = append(errors, fmt.Sprintf("Flag %d has unknown type '%s' (expected static or regex)", n, flag.FlagValue.Type))
+			}
+			if flag.FlagValue.Content == "" {
+				errors = append(errors, fmt.Sprintf("Flag %d is missing 'content'", n))
+			}
+			if data := flag.FlagValue.Data; data != nil && *data != "" && *data != "case_insensitive" {
+				errors = append(errors, fmt.Sprintf("Flag %d has unknown data '%s' (expected case_insensitive)", n, *data))
+			}
+			if flag.FlagValue.Type == "regex" && flag.FlagValue.Content != "" {
+				if _, err := regexp.Compile(flag.FlagValue.Content); err != nil {
+					errors = append(errors, fmt.Sprintf("Flag %d is not a valid regex (%s)", n, regexErrorCode(err)))
+				}
+			}
+		}
+	}
+
+	return append(errors, flagOverlaps(flags)...)
+}
+
+// regexErrorCode describes why a regex does not compile without quoting the
+// regex, which is the flag
+func regexErrorCode(err error) string {
+	if syntaxErr, ok := err.(*syntax.Error); ok {
+		return string(syntaxErr.Code)
+	}
+	return "invalid syntax"
+}
+
+// flagOverlaps reports static flags of one challenge that equal, start with,
+// or contain another: usually a placeholder or truncated flag left next to
+// the real one
+func flagOverlaps(flags []FlagItem) []string {
+	var errors []string
+
+	type static struct {
+		n               int
+		content         string
+		caseInsensitive bool
+	}
+	var statics []static
+	for i, flag := range flags {
+		parsed := staticFlags([]FlagItem{flag})
+		if flag.invalid != "" || len(parsed) == 0 {
+			continue
+		}
+		statics = append(statics, static{n: i + 1, content: parsed[0].content, caseInsensitive: parsed[0].caseInsensitive})
+	}
+
+	for i, flag := range statics {
+		for _, other := range statics[i+1:] {
+			a, b := flag.content, other.content
+			if flag.caseInsensitive || other.caseInsensitive {
+				a, b = strings.ToLower(a), strings.ToLower(b)
+			}
+			switch {
+			case a == b:
+				errors = append(errors, fmt.Sprintf("Flag %d duplicates flag %d", other.n, flag.n))
+			case strings.HasPrefix(a, b):
+				errors = append(errors, fmt.Sprintf("Flag %d starts with flag %d", flag.n, other.n))
+			case strings.HasPrefix(b, a):
+				errors = append(errors, fmt.Sprintf("Flag %d starts with flag %d", other.n, flag.n))
+			case strings.Contains(a, b):
+				errors = append(errors, fmt.Sprintf("Flag %d contains flag %d", flag.n, other.n))
+			case strings.Contains(b, a):
+				errors = append(errors, fmt.Sprintf("Flag %d contains flag %d", other.n, flag.n))
+			}
+		}
+	}
+	return errors
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		flags      string
+		wantErrors []string
+	}{
+		{
+			name:  "string and map flags",
+			flags: "- flag{plain}\n- type: static\n  content: flag{map}\n  data: case_insensitive\n- type: regex\n  content: flag\\{\\d+\\}\n- {type: static, content: \"flag{null_data}\", data: null}\n",
+		},
+		{
+			name:       "empty string",
+			flags:      "- \"\"\n",
+			wantErrors: []string{"Flag 1 is empty"},
+		},
+		{
+			name:       "missing type and content",
+			flags:      "- data: case_insensitive\n",
+			wantErrors: []string{"Flag 1 is missing 'type' (static or regex)", "Flag 1 is missing 'content'"},
+		},
+		{
+			name:       "unknown type and data",
+			flags:      "- type: fuzzy\n  content: flag{x}\n  data: ignore_case\n",
+			wantErrors: []string{"Flag 1 has unknown type 'fuzzy' (expected static or regex)", "Flag 1 has unknown data 'ignore_case' (expected case_insensitive)"},
+		},
+		{
+			name:       "unknown field",
+			flags:      "- type: static\n  value: flag{x}\n",
+			wantErrors: []string{"Flag 1 has unknown field 'value' (expected type, content, and data)"},
+		},
+		{
+			name:       "non-string field",
+			flags:      "- type: static\n  content: [\"flag{x}\"]\n",
+			wantErrors: []string{"Flag 1 field 'content' must be a string"},
+		},
+		{
+			name:       "invalid regex",
+			flags:      "- type: regex\n  content: flag\\{[a-z+\\}\n",
+			wantErrors: []string{"Flag 1 is not a valid regex (missing closing ])"},
+		},
+		{
+			name:  "overlapping flags",
+			flags: "- flag{real_flag}\n- flag{\n- type: static\n  content: FLAG{REAL_FLAG}\n  data: case_insensitive\n- real\n- type: regex\n  content: flag\\{.*\\}\n",
+			wantErrors: []string{
+				"Flag 1 starts with flag 2",
+				"Flag 3 duplicates flag 1",
+				"Flag 1 contains flag 4",
+				"Flag 3 starts with flag 2",
+				"Flag 3 contains flag 4",
+			},
+		},
+		{
+			name:       "number flag",
+			flags:      "- flag{ok}\n- 1234\n",
+			wantErrors: []string{"Flag 2 must be either a string or a map with type, content, and optional data fields"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte("flags:\n"+indent(tt.flags)), &challenge); err != nil {
+				t.Fatalf("flags should decode without error, got %v", err)
+			}
+			if got := checkFlags(challenge.Flags); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkFlags() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+// indent nests YAML lines under a parent key
+func indent(lines string) string {
+	var out []byte
+	atLineStart := true
+	for _, c := range []byte(lines) {
+		if atLineStart {
+			out = append(out, "  "...)
+		}
+		out = append(out, c)
+		atLineStart = c == '\n'
+	}
+	return string(out)
+}
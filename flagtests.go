@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagTests are submissions a challenge's flags must accept or reject,
+// verified by clilint so that complicated regex flags are tested in CI
+// rather than by players during the event
+type FlagTests struct {
+	Accept []string `yaml:"accept"`
+	Reject []string `yaml:"reject"`
+}
+
+// acceptsSubmission reports whether CTFd would accept a submission for any
+// of the flags
+func acceptsSubmission(flags []FlagItem, submission string) bool {
+	for _, flag := range staticFlags(flags) {
+		if flag.content == submission || (flag.caseInsensitive && strings.EqualFold(flag.content, submission)) {
+			return true
+		}
+	}
+	for _, regex := range regexFlags(flags) {
+		if regex.MatchString(submission) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFlagTests runs the flag_tests of a challenge against its flags.
+// Submissions are named by their index, as they are flags.
+func checkFlagTests(challenge Challenge) []string {
+	var errors []string
+
+	if challenge.FlagTests == nil {
+		return errors
+	}
+	for i, submission := range challenge.FlagTests.Accept {
+		if !acceptsSubmission(challenge.Flags, submission) {
+			errors = append(errors, fmt.Sprintf("flag_tests.accept[%d] is not accepted by any flag", i))
+		}
+	}
+	for i, submission := range challenge.FlagTests.Reject {
+		if acceptsSubmission(challenge.Flags, submission) {
+			errors = append(errors, fmt.Sprintf("flag_tests.reject[%d] is accepted by a flag", i))
+		}
+	}
+	return errors
+}
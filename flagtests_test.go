@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckFlagTests(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "no flag tests",
+			data: "flags: ['flag{x}']\n",
+		},
+		{
+			name: "passing",
+			data: `flags:
+  - type: regex
+    content: flag\{[0-9a-f]{8}\}
+    data: case_insensitive
+  - flag{Static}
+flag_tests:
+  accept: ["flag{deadbeef}", "FLAG{DEADBEEF}", "flag{Static}"]
+  reject: ["flag{deadbeef}x", "flag{xyz}", "flag{static}"]
+`,
+		},
+		{
+			name: "failing",
+			data: `flags:
+  - type: regex
+    content: flag\{\d+\}
+flag_tests:
+  accept: ["flag{123}", "flag{12a}"]
+  reject: ["flag{42}", "flag{}"]
+`,
+			want: []string{"flag_tests.accept[1] is not accepted by any flag", "flag_tests.reject[0] is accepted by a flag"},
+		},
+		{
+			name: "static flags only",
+			data: "flags: ['flag{x}']\nflag_tests:\n  accept: ['flag{x}']\n  reject: ['FLAG{X}']\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte(tt.data), &challenge); err != nil {
+				t.Fatal(err)
+			}
+			if got := checkFlagTests(challenge); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkFlagTests() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
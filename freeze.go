@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// defaultFreezeLabel is the PR label that allows frozen changes
+const defaultFreezeLabel = "freeze-override"
+
+// freezeMode is set by --freeze: in PR mode, changes to the scoring of
+// visible challenges are errors regardless of freeze.start and freeze.end
+var freezeMode bool
+
+// FreezeRule configures the pre-event freeze, during which PRs must not
+// change the flags, value, or files of a challenge that is already visible
+type FreezeRule struct {
+	// Start and End bound the freeze, as RFC 3339 times or dates (YYYY-MM-DD,
+	// UTC; an end date includes that day). Either may be left open.
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// OverrideLabel is the PR label that allows frozen changes (default:
+	// freeze-override)
+	OverrideLabel string `yaml:"override_label"`
+}
+
+func (r FreezeRule) label() string {
+	if r.OverrideLabel == "" {
+		return defaultFreezeLabel
+	}
+	return r.OverrideLabel
+}
+
+// active reports whether t is within the freeze window. A rule without
+// start and end is never active; --freeze enables it regardless.
+func (r FreezeRule) active(t time.Time) (bool, error) {
+	if r.Start == "" && r.End == "" {
+		return false, nil
+	}
+	if r.Start != "" {
+		start, err := parseFreezeTime(r.Start, false)
+		if err != nil {
+			return false, fmt.Errorf("invalid freeze.start: %v", err)
+		}
+		if t.Before(start) {
+			return false, nil
+		}
+	}
+	if r.End != "" {
+		end, err := parseFreezeTime(r.End, true)
+		if err != nil {
+			return false, fmt.Errorf("invalid freeze.end: %v", err)
+		}
+		if !t.Before(end) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseFreezeTime parses an RFC 3339 time or a date; the end of a window
+// given as a date is the start of the next day
+func parseFreezeTime(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC 3339 time nor a YYYY-MM-DD date", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// changeError is an error about a change of a challenge, and the field it
+// is at
+type changeError struct {
+	field   string
+	message string
+}
+
+// frozenChanges describes the changes of a visible challenge that the
+// freeze forbids. changedFiles are the attachments whose contents changed.
+func frozenChanges(old, current Challenge, changedFiles []string, label string) []changeError {
+	var changes []changeError
+	if old.State != "visible" {
+		return changes
+	}
+	add := func(field, format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		changes = append(changes, changeError{field, message + fmt.Sprintf(" during the freeze; add the '%s' label to allow it", label)})
+	}
+
+	if note := diffFlags(old.Flags, current.Flags); note != "" {
+		add("flags", "Flags of this visible challenge changed (%s)", note)
+	}
+	if old.Value != current.Value {
+		add("value", "Field 'value' of this visible challenge changed (%d → %d)", old.Value, current.Value)
+	}
+	if added, removed := diffLists(old.Files, current.Files); len(added) > 0 || len(removed) > 0 {
+		add("files", "Field 'files' of this visible challenge changed (%d added, %d removed)", len(added), len(removed))
+	}
+	for _, file := range changedFiles {
+		add("files", "Attachment '%s' of this visible challenge changed", file)
+	}
+	return changes
+}
+
+// changedAttachments returns the entries of files, relative to the
+// challenge.yml at challengePath, whose contents the PR changes
+func changedAttachments(challengePath string, files []string, prFiles []*github.CommitFile) []string {
+	changed := make(map[string]bool)
+	for _, file := range prFiles {
+		changed[file.GetFilename()] = true
+		if previous := file.GetPreviousFilename(); previous != "" {
+			changed[previous] = true
+		}
+	}
+	dir := path.Dir(filepath.ToSlash(challengePath))
+	var attachments []string
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		if changed[path.Join(dir, slashPath(normalizeFilePath(file)))] {
+			attachments = append(attachments, file)
+		}
+	}
+	return attachments
+}
+
+// checkFreeze reports, with --freeze or during the freeze window of the
+// config, the frozen changes the PR makes to each linted challenge, unless
+// the PR has the override label. Both versions of each challenge.yml are
+// fetched via the API, so the check does not depend on the checkout.
+func checkFreeze(env Env, results []LintResult, prFiles []*github.CommitFile) error {
+	config, err := loadLintConfig()
+	if err != nil {
+		return err
+	}
+	active, err := config.Freeze.active(now())
+	if err != nil {
+		return err
+	}
+	if !freezeMode && !active {
+		return nil
+	}
+
+	client, ctx := getGitHubClient(env.token)
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return fmt.Errorf("error getting PR: %v", err)
+	}
+	label := config.Freeze.label()
+	for _, prLabel := range pr.Labels {
+		if prLabel.GetName() == label {
+			return nil
+		}
+	}
+	return comparePRChallenges(env, pr, results, prFiles, config, func(result *LintResult, file string, old, current Challenge, data []byte) {
+		for _, change := range frozenChanges(old, current, changedAttachments(file, old.Files, prFiles), label) {
+			result.addErrorAt("freeze", change, data)
+		}
+	})
+}
+
+// comparePRChallenges fetches the base and head version of each linted
+// challenge.yml via the API, so that the comparison does not depend on the
+// checkout, and calls visit for those that are valid in both. Challenges
+// moved by the PR are compared with their previous path.
+func comparePRChallenges(env Env, pr *github.PullRequest, results []LintResult, prFiles []*github.CommitFile, config *LintConfig, visit func(result *LintResult, file string, old, current Challenge, data []byte)) error {
+	client, ctx := getGitHubClient(env.token)
+	headOwner, headRepo := pr.GetHead().GetRepo().GetOwner().GetLogin(), pr.GetHead().GetRepo().GetName()
+	if headOwner == "" || headRepo == "" {
+		headOwner, headRepo = env.owner, env.repo
+	}
+
+	previous := make(map[string]string)
+	for _, file := range prFiles {
+		if file.GetPreviousFilename() != "" {
+			previous[file.GetFilename()] = file.GetPreviousFilename()
+		}
+	}
+
+	fetch := func(owner, repo, file, ref string) ([]byte, error) {
+		content, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, file, &github.RepositoryContentGetOptions{Ref: ref})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s at %s: %v", file, ref, err)
+		}
+		text, err := content.GetContent()
+		return []byte(text), err
+	}
+
+	for i := range results {
+		result := &results[i]
+		file := filepath.ToSlash(filepath.Clean(result.File))
+		baseFile := file
+		if moved, ok := previous[file]; ok {
+			baseFile = moved
+		}
+		oldData, err := fetch(env.owner, env.repo, baseFile, pr.GetBase().GetSHA())
+		if err != nil {
+			return err
+		}
+		if oldData == nil {
+			// A challenge added by the PR
+			continue
+		}
+		data, err := fetch(headOwner, headRepo, file, pr.GetHead().GetSHA())
+		if err != nil {
+			return err
+		}
+		old, oldErr := parseChallengeData(oldData, config)
+		current, err := parseChallengeData(data, config)
+		if data == nil || oldErr != nil || err != nil {
+			// Invalid challenge.yml files are reported by the lint itself
+			continue
+		}
+		start := len(result.Errors)
+		visit(result, file, old, current, data)
+		if len(result.Errors) > start {
+			sortFindings(result.Errors)
+		}
+	}
+	return nil
+}
+
+// addErrorAt adds an error located at the field of a change in data, the
+// challenge.yml it was found in
+func (r *LintResult) addErrorAt(ruleID string, change changeError, data []byte) {
+	r.addErrors(ruleID, []string{change.message})
+	if location, err := findTopLevelField(data, change.field); err == nil && location != nil {
+		finding := &r.Errors[len(r.Errors)-1]
+		finding.Line, finding.Column = location.Key.Line, location.Key.Column
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestFreezeActive(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tests := []struct {
+		name string
+		rule FreezeRule
+		now  string
+		want bool
+	}{
+		{name: "no window", rule: FreezeRule{}, now: "2026-10-16T00:00:00Z", want: false},
+		{name: "before", rule: FreezeRule{Start: "2026-10-17", End: "2026-10-18"}, now: "2026-10-16T23:59:59Z", want: false},
+		{name: "within", rule: FreezeRule{Start: "2026-10-17", End: "2026-10-18"}, now: "2026-10-17T00:00:00Z", want: true},
+		{name: "end date includes the day", rule: FreezeRule{Start: "2026-10-17", End: "2026-10-18"}, now: "2026-10-18T23:00:00Z", want: true},
+		{name: "after", rule: FreezeRule{Start: "2026-10-17", End: "2026-10-18"}, now: "2026-10-19T00:00:00Z", want: false},
+		{name: "open end", rule: FreezeRule{Start: "2026-10-17T12:00:00+09:00"}, now: "2026-10-17T03:00:00Z", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.active(at(tt.now))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (FreezeRule{Start: "next week"}).active(time.Now()); err == nil {
+		t.Error("expected an error for an invalid freeze.start")
+	}
+}
+
+func TestFrozenChanges(t *testing.T) {
+	flag := func(content string) FlagItem { return FlagItem{StringValue: &content} }
+	old := Challenge{State: "visible", Value: 300, Flags: []FlagItem{flag("flag{a}")}, Files: []string{"dist/a.zip"}}
+
+	if got := frozenChanges(old, old, nil, "freeze-override"); len(got) != 0 {
+		t.Errorf("unchanged challenge: got %v", got)
+	}
+	hidden := old
+	hidden.State = "hidden"
+	if got := frozenChanges(hidden, Challenge{}, nil, "freeze-override"); len(got) != 0 {
+		t.Errorf("hidden challenge: got %v", got)
+	}
+
+	current := old
+	current.Value = 500
+	current.Flags = []FlagItem{flag("flag{b}")}
+	current.Files = []string{"dist/a.zip", "dist/b.zip"}
+	current.Tags = []string{"easy"}
+	want := []changeError{
+		{"flags", "Flags of this visible challenge changed (1 changed) during the freeze; add the 'ok' label to allow it"},
+		{"value", "Field 'value' of this visible challenge changed (300 → 500) during the freeze; add the 'ok' label to allow it"},
+		{"files", "Field 'files' of this visible challenge changed (1 added, 0 removed) during the freeze; add the 'ok' label to allow it"},
+		{"files", "Attachment 'dist/a.zip' of this visible challenge changed during the freeze; add the 'ok' label to allow it"},
+	}
+	if got := frozenChanges(old, current, []string{"dist/a.zip"}, "ok"); !reflect.DeepEqual(got, want) {
+		t.Errorf("frozenChanges() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestChangedAttachments(t *testing.T) {
+	prFiles := []*github.CommitFile{
+		{Filename: github.String("web/a/dist/a.zip")},
+		{Filename: github.String("web/a/src/main.go")},
+		{Filename: github.String("web/a/dist/c.zip"), PreviousFilename: github.String("web/a/dist/b.zip")},
+	}
+	got := changedAttachments("web/a/challenge.yml", []string{"./dist/a.zip", "dist/b.zip", "dist/d.zip", "https://example.com/a.zip"}, prFiles)
+	want := []string{"./dist/a.zip", "dist/b.zip"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedAttachments() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckFreeze(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("lintrc.yaml", []byte("freeze:\n  start: 2026-10-01\n  end: 2026-10-20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC) }
+
+	contents := map[string]string{
+		"base1:web/a/challenge.yml":   "name: a\nstate: visible\nvalue: 300\nflags: ['flag{a}']\n",
+		"head2:web/a/challenge.yml":   "name: a\nstate: visible\nvalue: 500\nflags: ['flag{a}']\n",
+		"base1:web/b/challenge.yml":   "name: b\nstate: hidden\nvalue: 300\n",
+		"head2:web/b/challenge.yml":   "name: b\nstate: visible\nvalue: 500\n",
+		"head2:web/new/challenge.yml": "name: new\nstate: visible\nvalue: 100\n",
+	}
+	labels := `[]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"number": 7, "labels": %s, "base": {"sha": "base1"}, "head": {"sha": "head2", "repo": {"name": "repo", "owner": {"login": "owner"}}}}`, labels)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/contents/", func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("ref") + ":" + r.URL.Path[len("/repos/owner/repo/contents/"):]
+		content, ok := contents[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(content)))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	lint := func() []LintResult {
+		results := []LintResult{{File: "web/a/challenge.yml"}, {File: "web/b/challenge.yml"}, {File: "web/new/challenge.yml"}}
+		if err := checkFreeze(env, results, nil); err != nil {
+			t.Fatalf("checkFreeze() error: %v", err)
+		}
+		return results
+	}
+
+	results := lint()
+	if len(results[0].Errors) != 1 || results[0].Errors[0].RuleID != "freeze" || results[0].Errors[0].Line != 3 {
+		t.Errorf("visible challenge: got %+v", results[0].Errors)
+	}
+	if len(results[1].Errors) != 0 || len(results[2].Errors) != 0 {
+		t.Errorf("hidden and new challenges: got %+v and %+v", results[1].Errors, results[2].Errors)
+	}
+
+	labels = `[{"name": "freeze-override"}]`
+	if results := lint(); len(results[0].Errors) != 0 {
+		t.Errorf("overridden freeze: got %+v", results[0].Errors)
+	}
+}
@@ -0,0 +1,49 @@
+// Package golden compares test output against expected files stored under
+// testdata, for clilint's reporter tests and for downstream rule authors
+// who want the same end-to-end checks.
+//
+// Run tests with -update to rewrite the expected files from the current
+// output, then review the diff before committing:
+//
+//	go test ./... -run TestGolden -update
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// Updating reports whether the test binary was run with -update
+func Updating() bool {
+	return *update
+}
+
+// Assert fails t when got differs from the contents of the golden file at
+// path. With -update the file is written instead, creating directories as
+// needed.
+func Assert(t testing.TB, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("golden: failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("golden: failed to write %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden: failed to read %s (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("golden: output does not match %s (run with -update to accept)\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package golden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestAssert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatalf("Failed to write golden file: %v", err)
+	}
+
+	match := &recordingTB{TB: t}
+	Assert(match, path, []byte("hello\n"))
+	if match.failed {
+		t.Error("Expected identical output to pass")
+	}
+
+	mismatch := &recordingTB{TB: t}
+	Assert(mismatch, path, []byte("goodbye\n"))
+	if !mismatch.failed && !Updating() {
+		t.Error("Expected different output to fail")
+	}
+
+	missing := &recordingTB{TB: t}
+	Assert(missing, filepath.Join(t.TempDir(), "missing.txt"), []byte("x"))
+	if !missing.failed && !Updating() {
+		t.Error("Expected a missing golden file to fail")
+	}
+}
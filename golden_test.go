@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"clilint/golden"
+)
+
+// goldenReporters renders results in every output format. Each reporter's
+// output is compared against testdata/golden/<case>/<file>.
+var goldenReporters = []struct {
+	file   string
+	render func(results []LintResult) ([]byte, error)
+}{
+	{"output.json", func(results []LintResult) ([]byte, error) {
+		return marshalResults(results, hasLintErrors(results))
+	}},
+	{"comment.md", func(results []LintResult) ([]byte, error) {
+		return []byte(generateCommentBody(results, hasLintErrors(results))), nil
+	}},
+	{"output.txt", func(results []LintResult) ([]byte, error) {
+		var buf bytes.Buffer
+		writeResults(&buf, results)
+		return buf.Bytes(), nil
+	}},
+}
+
+// TestGolden lints each sample repository under testdata/golden/<case>/repo
+// and compares the output of every reporter against the expected files.
+// Run with -update after changing a rule or reporter.
+func TestGolden(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join("testdata", "golden", "*", "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatal("no golden cases found")
+	}
+
+	origDir, _ := os.Getwd()
+	for _, repoDir := range cases {
+		caseDir, _ := filepath.Abs(filepath.Dir(repoDir))
+		repoDir, _ := filepath.Abs(repoDir)
+
+		t.Run(filepath.Base(caseDir), func(t *testing.T) {
+			t.Cleanup(func() {
+				_ = os.Chdir(origDir)
+			})
+			if err := os.Chdir(repoDir); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := lintChallenges(".")
+			if err != nil {
+				t.Fatalf("lintChallenges() error: %v", err)
+			}
+			sortResults(results)
+
+			for _, reporter := range goldenReporters {
+				got, err := reporter.render(results)
+				if err != nil {
+					t.Fatalf("%s: %v", reporter.file, err)
+				}
+				golden.Assert(t, filepath.Join(caseDir, reporter.file), got)
+			}
+		})
+	}
+}
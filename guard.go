@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// defaultRuleTimeout bounds one rule on one challenge unless rule_timeout is set
+const defaultRuleTimeout = 2 * time.Minute
+
+// ruleTimeout returns the configured per-rule time limit
+func (c *LintConfig) ruleTimeout() time.Duration {
+	if c.RuleTimeout > 0 {
+		return c.RuleTimeout
+	}
+	return defaultRuleTimeout
+}
+
+// runRule runs a check, turning a panic or a check running longer than
+// timeout into a failure message rather than ending the lint run. A timed
+// out check cannot be stopped; it is left to finish in the background and
+// its result is discarded. Checks must therefore only write to their own
+// copies of shared state, which the caller adopts once the check finished.
+func runRule(ruleID, file string, timeout time.Duration, check func() []string) (messages []string, failure string) {
+	if untrustedRoot != "" && networkRules[ruleID] {
+		return nil, ""
+	}
+	type outcome struct {
+		messages []string
+		failure  string
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Printf("Rule %s crashed on %s: %v\n%s", ruleID, file, recovered, debug.Stack())
+				done <- outcome{failure: fmt.Sprintf("Rule '%s' crashed: %v", ruleID, recovered)}
+			}
+		}()
+		done <- outcome{messages: check()}
+	}()
+
+	start := time.Now()
+	defer func() { noteRuleTiming(ruleID, time.Since(start)) }()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		return result.messages, result.failure
+	case <-timer.C:
+		return nil, fmt.Sprintf("Rule '%s' timed out after %s", ruleID, timeout)
+	}
+}
+
+// checkErrors runs a rule and records its messages as errors. It reports
+// whether the rule finished, rather than crashing or timing out.
+func (r *LintResult) checkErrors(ruleID string, check func() []string) bool {
+	messages, failure := runRule(ruleID, r.File, r.ruleTimeout, check)
+	if failure != "" {
+		messages = append(messages, failure)
+	}
+	r.addErrors(ruleID, messages)
+	return failure == ""
+}
+
+// checkSecurity runs a rule and records its messages as security findings
+func (r *LintResult) checkSecurity(ruleID string, check func() []string) {
+	messages, failure := runRule(ruleID, r.File, r.ruleTimeout, check)
+	if failure != "" {
+		messages = append(messages, failure)
+	}
+	r.addSecurity(ruleID, messages)
+}
+
+// checkWarnings runs a rule and records its messages as warnings; a crash
+// or timeout is still an error
+func (r *LintResult) checkWarnings(ruleID string, check func() []string) {
+	messages, failure := runRule(ruleID, r.File, r.ruleTimeout, check)
+	if failure != "" {
+		r.addErrors(ruleID, []string{failure})
+	}
+	r.addWarnings(ruleID, messages)
+}
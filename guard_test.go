@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRunRule(t *testing.T) {
+	messages, failure := runRule("flags", "a/challenge.yml", time.Second, func() []string { return []string{"bad flag"} })
+	if !reflect.DeepEqual(messages, []string{"bad flag"}) || failure != "" {
+		t.Errorf("runRule() = %v, %q", messages, failure)
+	}
+
+	_, failure = runRule("binaries", "a/challenge.yml", time.Second, func() []string {
+		var elf map[string]int
+		elf["magic"] = 1
+		return nil
+	})
+	if !strings.HasPrefix(failure, "Rule 'binaries' crashed: assignment to entry in nil map") {
+		t.Errorf("crash failure = %q", failure)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	_, failure = runRule("remote-files", "a/challenge.yml", 10*time.Millisecond, func() []string {
+		<-release
+		return nil
+	})
+	if failure != "Rule 'remote-files' timed out after 10ms" {
+		t.Errorf("timeout failure = %q", failure)
+	}
+}
+
+func TestCheckWarningsCrash(t *testing.T) {
+	result := LintResult{File: "a/challenge.yml", ruleTimeout: time.Second}
+	result.checkWarnings("type", func() []string { panic("unexpected type") })
+	result.checkErrors("flags", func() []string { return []string{"Flag format is invalid"} })
+
+	if len(result.Warnings) != 0 || len(result.Errors) != 2 {
+		t.Fatalf("errors = %v, warnings = %v", result.Errors, result.Warnings)
+	}
+	if result.Errors[0].RuleID != "type" || result.Errors[0].Message != "Rule 'type' crashed: unexpected type" {
+		t.Errorf("unexpected crash finding %+v", result.Errors[0])
+	}
+}
+
+func TestCheckErrorsTimeoutKeepsState(t *testing.T) {
+	result := LintResult{File: "a/challenge.yml", ruleTimeout: 10 * time.Millisecond}
+	if !result.checkErrors("flags", func() []string { return nil }) {
+		t.Error("checkErrors() = false for a finished rule")
+	}
+
+	// The abandoned rule writes its copy, never state the caller reads
+	release := make(chan struct{})
+	done := make(chan struct{})
+	challenge := Challenge{Host: "${HOST}"}
+	expanded := challenge
+	if result.checkErrors("placeholders", func() []string {
+		<-release
+		expanded.Host = "late.example.com"
+		close(done)
+		return nil
+	}) {
+		t.Error("checkErrors() = true for a timed out rule")
+	}
+	close(release)
+	<-done
+	if challenge.Host != "${HOST}" {
+		t.Errorf("Host = %v, changed by a timed out rule", challenge.Host)
+	}
+}
+
+func TestRuleTimeoutConfig(t *testing.T) {
+	var config LintConfig
+	if err := yaml.Unmarshal([]byte("rule_timeout: 30s\n"), &config); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.ruleTimeout(); got != 30*time.Second {
+		t.Errorf("ruleTimeout() = %v, want 30s", got)
+	}
+	if got := (&LintConfig{}).ruleTimeout(); got != defaultRuleTimeout {
+		t.Errorf("default ruleTimeout() = %v, want %v", got, defaultRuleTimeout)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultHealthcheckTimeout bounds one healthcheck run unless healthcheck.timeout is set
+const defaultHealthcheckTimeout = time.Minute
+
+// healthcheckOutputLimit is the tail of the output kept for a failed healthcheck
+const healthcheckOutputLimit = 2000
+
+// HealthcheckRule configures the ctfcli-style healthcheck scripts, run with
+// --connection-info <connection_info> and passing when they exit 0
+type HealthcheckRule struct {
+	// Required makes hosted challenges, those with host or connection_info,
+	// declare a healthcheck
+	Required bool `yaml:"required"`
+	// Timeout bounds one run of clilint healthcheck --run (default 1m)
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// HealthcheckResult is the outcome of one challenge's healthcheck
+type HealthcheckResult struct {
+	File   string `json:"file"`
+	Name   string `json:"name"`
+	Script string `json:"script,omitempty"`
+	Target string `json:"target,omitempty"`
+	// Status is pass, fail, or skipped when run; ok, invalid, or missing otherwise
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration_seconds,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Output   string  `json:"output,omitempty"`
+}
+
+// isHosted reports whether players connect to the challenge
+func isHosted(challenge Challenge) bool {
+	return (challenge.Host != nil && challenge.Host != "") || strings.TrimSpace(challenge.ConnectionInfo) != ""
+}
+
+// checkHealthcheck checks that the healthcheck script exists and is
+// executable, and that hosted challenges declare one when required
+func checkHealthcheck(challengePath string, challenge Challenge, rule HealthcheckRule) []string {
+	var errors []string
+
+	if challenge.Healthcheck == "" {
+		if rule.Required && isHosted(challenge) {
+			errors = append(errors, "Field 'healthcheck' is required for hosted challenges")
+		}
+		return errors
+	}
+	path := filepath.Join(filepath.Dir(challengePath), normalizeFilePath(challenge.Healthcheck))
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		errors = append(errors, fmt.Sprintf("Healthcheck script '%s' does not exist", challenge.Healthcheck))
+	case !info.Mode().IsRegular():
+		errors = append(errors, fmt.Sprintf("Healthcheck script '%s' is not a file", challenge.Healthcheck))
+	case runtime.GOOS != "windows" && info.Mode()&0111 == 0:
+		errors = append(errors, fmt.Sprintf("Healthcheck script '%s' is not executable (chmod +x)", challenge.Healthcheck))
+	}
+	return errors
+}
+
+// healthcheckTarget is what the healthcheck is given as --connection-info:
+// connection_info, or else the host
+func healthcheckTarget(challenge Challenge) string {
+	if info := strings.TrimSpace(challenge.ConnectionInfo); info != "" {
+		return info
+	}
+	switch host := challenge.Host.(type) {
+	case string:
+		return host
+	case map[string]interface{}:
+		target := fmt.Sprint(host["host"])
+		if port, ok := host["port"]; ok {
+			target = fmt.Sprintf("%s:%v", target, port)
+		}
+		if protocol, ok := host["protocol"].(string); ok && protocol != "" {
+			target = protocol + "://" + target
+		}
+		return target
+	}
+	return ""
+}
+
+func runHealthcheck(args []string) {
+	run := false
+	jsonOutput := false
+	timeout := time.Duration(0)
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--run":
+			run = true
+		case args[i] == "--json":
+			jsonOutput = true
+		case args[i] == "--timeout" && i+1 < len(args):
+			i++
+			parsed, err := time.ParseDuration(args[i])
+			if err != nil || parsed <= 0 {
+				log.Fatalf("Invalid --timeout %s: expected a duration such as 30s", args[i])
+			}
+			timeout = parsed
+		default:
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	if timeout == 0 {
+		timeout = config.Healthcheck.Timeout
+	}
+	if timeout <= 0 {
+		timeout = defaultHealthcheckTimeout
+	}
+
+	var results []HealthcheckResult
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				log.Fatalf("Error reading %s: %v", path, err)
+			}
+			results = append(results, healthcheckChallenge(path, challenge, config, run, timeout))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+
+	if jsonOutput {
+		if results == nil {
+			results = []HealthcheckResult{}
+		}
+		jsonData, err := json.Marshal(results)
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		writeHealthchecks(os.Stdout, results)
+	}
+	for _, result := range results {
+		if result.Status == "fail" || result.Status == "invalid" || result.Status == "missing" {
+			os.Exit(1)
+		}
+	}
+}
+
+// healthcheckChallenge validates the healthcheck of a challenge and, with
+// run, executes it against the challenge's expanded connection info
+func healthcheckChallenge(path string, challenge Challenge, config *LintConfig, run bool, timeout time.Duration) HealthcheckResult {
+	result := HealthcheckResult{File: path, Name: challenge.Name, Script: challenge.Healthcheck}
+	skipped, missing := "ok", "missing"
+	if run {
+		skipped, missing = "skipped", "fail"
+	}
+
+	if placeholderErrors := expandChallenge(&challenge, config); len(placeholderErrors) > 0 {
+		result.Status, result.Message = missing, strings.Join(placeholderErrors, "; ")
+		return result
+	}
+	result.Target = healthcheckTarget(challenge)
+	if errors := checkHealthcheck(path, challenge, config.Healthcheck); len(errors) > 0 {
+		result.Status, result.Message = "invalid", strings.Join(errors, "; ")
+		if challenge.Healthcheck == "" {
+			result.Status = missing
+		}
+		return result
+	}
+	if challenge.Healthcheck == "" || !isHosted(challenge) {
+		result.Status = skipped
+		if challenge.Healthcheck == "" {
+			result.Message = "no healthcheck"
+		} else {
+			result.Message = "not hosted"
+		}
+		return result
+	}
+	if !run {
+		result.Status = "ok"
+		return result
+	}
+
+	script, err := filepath.Abs(filepath.Join(filepath.Dir(path), normalizeFilePath(challenge.Healthcheck)))
+	if err != nil {
+		result.Status, result.Message = "fail", err.Error()
+		return result
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, script, "--connection-info", result.Target)
+	cmd.Dir = filepath.Dir(path)
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	result.Duration = time.Since(start).Seconds()
+	result.Status = "pass"
+	if err != nil {
+		result.Status = "fail"
+		result.Message = err.Error()
+		if ctx.Err() != nil {
+			result.Message = fmt.Sprintf("timed out after %s", timeout)
+		}
+		text := strings.TrimSpace(string(output))
+		if len(text) > healthcheckOutputLimit {
+			text = "…" + text[len(text)-healthcheckOutputLimit:]
+		}
+		result.Output = text
+	}
+	return result
+}
+
+func writeHealthchecks(w io.Writer, results []HealthcheckResult) {
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+		label := fmt.Sprintf("%s (%s)", result.File, result.Name)
+		switch result.Status {
+		case "pass":
+			fmt.Fprintf(w, "✅ %s: healthcheck passed in %.1fs\n", label, result.Duration)
+		case "ok":
+			fmt.Fprintf(w, "✅ %s: %s\n", label, result.Script)
+		case "skipped":
+			fmt.Fprintf(w, "⏭️  %s: skipped (%s)\n", label, result.Message)
+		default:
+			fmt.Fprintf(w, "❌ %s: %s\n", label, result.Message)
+			if lines := strings.Split(result.Output, "\n"); result.Output != "" {
+				fmt.Fprintf(w, "  │ %s\n", lines[len(lines)-1])
+			}
+		}
+	}
+
+	var summary []string
+	for _, status := range []string{"pass", "ok", "fail", "invalid", "missing", "skipped"} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.Join(summary, ", "))
+}
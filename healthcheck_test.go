@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckHealthcheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not checked on Windows")
+	}
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	if err := os.WriteFile(filepath.Join(tempDir, "healthcheck.py"), []byte("#!/usr/bin/env python3\n"), 0755); err != nil {
+		t.Fatalf("Failed to create healthcheck.py: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "check.sh"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatalf("Failed to create check.sh: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(tempDir, "checks"), 0755); err != nil {
+		t.Fatalf("Failed to create checks: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		challenge Challenge
+		rule      HealthcheckRule
+		want      []string
+	}{
+		{name: "executable", challenge: Challenge{Host: "nc.example.com:1337", Healthcheck: "healthcheck.py"}},
+		{name: "not hosted", challenge: Challenge{Healthcheck: "./healthcheck.py"}},
+		{name: "optional", challenge: Challenge{Host: "nc.example.com:1337"}},
+		{name: "required but not hosted", challenge: Challenge{}, rule: HealthcheckRule{Required: true}},
+		{
+			name:      "required",
+			challenge: Challenge{ConnectionInfo: "nc ${HOST} 1337"},
+			rule:      HealthcheckRule{Required: true},
+			want:      []string{"Field 'healthcheck' is required for hosted challenges"},
+		},
+		{
+			name:      "missing",
+			challenge: Challenge{Healthcheck: "solve.py"},
+			want:      []string{"Healthcheck script 'solve.py' does not exist"},
+		},
+		{
+			name:      "directory",
+			challenge: Challenge{Healthcheck: "checks"},
+			want:      []string{"Healthcheck script 'checks' is not a file"},
+		},
+		{
+			name:      "not executable",
+			challenge: Challenge{Healthcheck: "check.sh"},
+			want:      []string{"Healthcheck script 'check.sh' is not executable (chmod +x)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkHealthcheck(challengePath, tt.challenge, tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkHealthcheck() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthcheckTarget(t *testing.T) {
+	tests := []struct {
+		challenge Challenge
+		want      string
+	}{
+		{Challenge{ConnectionInfo: " nc pwn.example.com 1337 ", Host: "ignored:1"}, "nc pwn.example.com 1337"},
+		{Challenge{Host: "pwn.example.com:1337"}, "pwn.example.com:1337"},
+		{Challenge{Host: map[string]interface{}{"host": "web.example.com", "port": 443, "protocol": "https"}}, "https://web.example.com:443"},
+		{Challenge{}, ""},
+	}
+	for _, tt := range tests {
+		if got := healthcheckTarget(tt.challenge); got != tt.want {
+			t.Errorf("healthcheckTarget(%v) = %q, want %q", tt.challenge, got, tt.want)
+		}
+	}
+}
+
+func TestHealthcheckChallenge(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("healthcheck scripts are shell scripts")
+	}
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	scripts := map[string]string{
+		// The script runs in the challenge directory and gets the connection info
+		"pass.sh":  "#!/bin/sh\ntest -f challenge.yml && test \"$1\" = --connection-info && test \"$2\" = \"nc pwn.example.com 1337\"\n",
+		"fail.sh":  "#!/bin/sh\necho connecting\necho 'flag not found'\nexit 1\n",
+		"sleep.sh": "#!/bin/sh\nexec sleep 5\n",
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	if err := os.WriteFile(challengePath, []byte("name: test\n"), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+	config := &LintConfig{Healthcheck: HealthcheckRule{Required: true}}
+	hosted := func(script string) Challenge {
+		return Challenge{Name: "test", ConnectionInfo: "nc pwn.example.com 1337", Healthcheck: script}
+	}
+
+	tests := []struct {
+		name       string
+		challenge  Challenge
+		run        bool
+		wantStatus string
+		wantOutput string
+	}{
+		{name: "pass", challenge: hosted("pass.sh"), run: true, wantStatus: "pass"},
+		{name: "fail", challenge: hosted("fail.sh"), run: true, wantStatus: "fail", wantOutput: "connecting\nflag not found"},
+		{name: "timeout", challenge: hosted("sleep.sh"), run: true, wantStatus: "fail"},
+		{name: "missing when run", challenge: hosted(""), run: true, wantStatus: "fail"},
+		{name: "missing", challenge: hosted(""), wantStatus: "missing"},
+		{name: "invalid", challenge: hosted("nope.sh"), run: true, wantStatus: "invalid"},
+		{name: "not hosted", challenge: Challenge{Name: "test", Healthcheck: "fail.sh"}, run: true, wantStatus: "skipped"},
+		{name: "valid", challenge: hosted("fail.sh"), wantStatus: "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := healthcheckChallenge(challengePath, tt.challenge, config, tt.run, 500*time.Millisecond)
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q (%s), want %q", result.Status, result.Message, tt.wantStatus)
+			}
+			if result.Output != tt.wantOutput {
+				t.Errorf("Output = %q, want %q", result.Output, tt.wantOutput)
+			}
+			if tt.name == "timeout" && !strings.Contains(result.Message, "timed out") {
+				t.Errorf("Message = %q, want a timeout", result.Message)
+			}
+		})
+	}
+}
+
+func TestWriteHealthchecks(t *testing.T) {
+	var buf bytes.Buffer
+	writeHealthchecks(&buf, []HealthcheckResult{
+		{File: "pwn/a/challenge.yml", Name: "a", Status: "pass", Duration: 1.25},
+		{File: "pwn/b/challenge.yml", Name: "b", Status: "fail", Message: "exit status 1", Output: "connecting\nflag not found"},
+		{File: "misc/c/challenge.yml", Name: "c", Status: "skipped", Message: "not hosted"},
+	})
+	output := buf.String()
+	for _, want := range []string{
+		"✅ pwn/a/challenge.yml (a): healthcheck passed in 1.2s",
+		"❌ pwn/b/challenge.yml (b): exit status 1\n  │ flag not found",
+		"⏭️  misc/c/challenge.yml (c): skipped (not hosted)",
+		"1 pass, 1 fail, 1 skipped",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Hint represents a hint in challenge.yml (map format)
+type Hint struct {
+	Content string `yaml:"content"`
+	Cost    int    `yaml:"cost"`
+}
+
+// HintItem represents a single hint that can be either a string, which is
+// free, or a Hint struct
+type HintItem struct {
+	StringValue *string
+	HintValue   *Hint
+
+	// invalid describes a hint of the wrong shape, reported by checkHints
+	invalid string
+}
+
+// hintFields are the keys of a map-style hint
+var hintFields = map[string]bool{"content": true, "cost": true}
+
+// HintsRule configures the policies over hints. Each is off unless set.
+type HintsRule struct {
+	// MaxCostPercent caps the total cost of the hints, as a percentage of the
+	// challenge value (value, or extra.initial for dynamic challenges)
+	MaxCostPercent int `yaml:"max_cost_percent"`
+	// Ordered requires hints to be ordered by increasing cost
+	Ordered bool `yaml:"ordered"`
+	// NoFlags rejects hints containing one of the challenge's static flags
+	NoFlags bool `yaml:"no_flags"`
+}
+
+// UnmarshalYAML implements custom unmarshaling for HintItem
+// Accepts either a string or a map with content and cost fields
+func (h *HintItem) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode && value.Tag == "!!str" {
+		var str string
+		if err := value.Decode(&str); err == nil {
+			h.StringValue = &str
+			return nil
+		}
+	}
+
+	if value.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, field := value.Content[i], value.Content[i+1]
+			if !hintFields[key.Value] {
+				h.invalid = fmt.Sprintf("has unknown field '%s' (expected content and cost)", key.Value)
+				return nil
+			}
+			if key.Value == "cost" && (field.Kind != yaml.ScalarNode || field.Tag != "!!int") {
+				h.invalid = "field 'cost' must be a number"
+				return nil
+			}
+		}
+		var hint Hint
+		if err := value.Decode(&hint); err == nil {
+			h.HintValue = &hint
+			return nil
+		}
+	}
+
+	h.invalid = "must be either a string or a map with content and cost fields"
+	return nil
+}
+
+// Content returns the hint text regardless of which format the hint uses
+func (h HintItem) Content() string {
+	if h.StringValue != nil {
+		return *h.StringValue
+	}
+	if h.HintValue != nil {
+		return h.HintValue.Content
+	}
+	return ""
+}
+
+// Cost returns the cost of the hint; string hints are free
+func (h HintItem) Cost() int {
+	if h.HintValue != nil {
+		return h.HintValue.Cost
+	}
+	return 0
+}
+
+// challengeValue is the points a challenge is worth before any decay
+func challengeValue(challenge Challenge) int {
+	if challenge.Value == 0 {
+		if initial, ok := challenge.Extra["initial"].(int); ok {
+			return initial
+		}
+	}
+	return challenge.Value
+}
+
+// checkHints validates the shape of each hint and applies the hint policies.
+// Flags are never echoed into reports.
+func checkHints(challenge Challenge, rule HintsRule) []string {
+	var errors []string
+
+	total := 0
+	for i, hint := range challenge.Hints {
+		n := i + 1
+		if hint.invalid != "" {
+			errors = append(errors, fmt.Sprintf("Hint %d %s", n, hint.invalid))
+			continue
+		}
+		if strings.TrimSpace(hint.Content()) == "" {
+			errors = append(errors, fmt.Sprintf("Hint %d is empty", n))
+		}
+		if hint.Cost() < 0 {
+			errors = append(errors, fmt.Sprintf("Hint %d has a negative cost (%d)", n, hint.Cost()))
+		}
+		total += hint.Cost()
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+
+	if rule.MaxCostPercent > 0 {
+		value := challengeValue(challenge)
+		if total*100 > value*rule.MaxCostPercent {
+			errors = append(errors, fmt.Sprintf("Hints cost %d in total, more than %d%% of the challenge value %d", total, rule.MaxCostPercent, value))
+		}
+	}
+
+	if rule.Ordered {
+		for i := 1; i < len(challenge.Hints); i++ {
+			if previous, cost := challenge.Hints[i-1].Cost(), challenge.Hints[i].Cost(); cost < previous {
+				errors = append(errors, fmt.Sprintf("Hint %d costs %d, less than hint %d (%d); order hints by increasing cost", i+1, cost, i, previous))
+			}
+		}
+	}
+
+	if rule.NoFlags {
+		flags := staticFlags(challenge.Flags)
+		for i, hint := range challenge.Hints {
+			content := hint.Content()
+			for _, flag := range flags {
+				if strings.Contains(content, flag.content) ||
+					(flag.caseInsensitive && strings.Contains(strings.ToLower(content), strings.ToLower(flag.content))) {
+					errors = append(errors, fmt.Sprintf("Hint %d contains a flag of the challenge", i+1))
+					break
+				}
+			}
+		}
+	}
+
+	return errors
+}
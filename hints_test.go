@@ -0,0 +1,77 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckHints(t *testing.T) {
+	all := HintsRule{MaxCostPercent: 20, Ordered: true, NoFlags: true}
+
+	tests := []struct {
+		name       string
+		challenge  string
+		rule       HintsRule
+		wantErrors []string
+	}{
+		{
+			name:      "free and paid hints",
+			challenge: "value: 500\nhints:\n  - Look at the headers\n  - {content: Try the admin cookie, cost: 50}\n  - {content: It is base64, cost: 50}\n",
+			rule:      all,
+		},
+		{
+			name:       "policies off by default",
+			challenge:  "value: 100\nflags: [\"flag{x}\"]\nhints:\n  - {content: \"flag{x}\", cost: 90}\n  - {content: b, cost: 10}\n",
+			wantErrors: nil,
+		},
+		{
+			name:       "over budget",
+			challenge:  "value: 500\nhints:\n  - {content: a, cost: 60}\n  - {content: b, cost: 60}\n",
+			rule:       all,
+			wantErrors: []string{"Hints cost 120 in total, more than 20% of the challenge value 500"},
+		},
+		{
+			name:      "budget from dynamic initial value",
+			challenge: "type: dynamic\nextra: {initial: 1000}\nhints:\n  - {content: a, cost: 200}\n",
+			rule:      all,
+		},
+		{
+			name:       "out of order",
+			challenge:  "value: 500\nhints:\n  - {content: a, cost: 50}\n  - b\n",
+			rule:       all,
+			wantErrors: []string{"Hint 2 costs 0, less than hint 1 (50); order hints by increasing cost"},
+		},
+		{
+			name:       "leaks a flag",
+			challenge:  "value: 500\nflags:\n  - type: static\n    content: flag{Leak}\n    data: case_insensitive\n  - flag{other}\nhints:\n  - The flag is FLAG{LEAK}\n  - nothing here\n",
+			rule:       all,
+			wantErrors: []string{"Hint 1 contains a flag of the challenge"},
+		},
+		{
+			name:      "malformed hints",
+			challenge: "hints:\n  - {content: a, cost: ten}\n  - {text: a}\n  - [a]\n  - {content: '', cost: -5}\n",
+			rule:      all,
+			wantErrors: []string{
+				"Hint 1 field 'cost' must be a number",
+				"Hint 2 has unknown field 'text' (expected content and cost)",
+				"Hint 3 must be either a string or a map with content and cost fields",
+				"Hint 4 is empty",
+				"Hint 4 has a negative cost (-5)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte(tt.challenge), &challenge); err != nil {
+				t.Fatalf("hints should decode without error, got %v", err)
+			}
+			if got := checkHints(challenge, tt.rule); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkHints() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultHistoryFile is the JSONL file runs are appended to and trend reads from
+const defaultHistoryFile = ".clilint-history.jsonl"
+
+// historyPath is set by --history; runs are only recorded when it is non-empty
+var historyPath string
+
+// HistoryEntry summarizes one lint run
+type HistoryEntry struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	Ref        string         `json:"ref,omitempty"`
+	Challenges int            `json:"challenges"`
+	Errors     int            `json:"errors"`
+	Warnings   int            `json:"warnings"`
+	Rules      map[string]int `json:"rules"` // findings per rule
+}
+
+// summarizeRun builds the history entry for a set of results
+func summarizeRun(results []LintResult, ref string) HistoryEntry {
+	entry := HistoryEntry{
+		Timestamp:  now().UTC(),
+		Ref:        ref,
+		Challenges: len(results),
+		Rules:      make(map[string]int),
+	}
+	for _, result := range results {
+		entry.Errors += len(result.Errors)
+		entry.Warnings += len(result.Warnings)
+		for _, finding := range append(append([]Finding{}, result.Errors...), result.Warnings...) {
+			entry.Rules[historyRule(finding)]++
+		}
+	}
+	return entry
+}
+
+// historyRule returns the rule ID a finding is counted under
+func historyRule(finding Finding) string {
+	if finding.RuleID != "" {
+		return finding.RuleID
+	}
+	return "other"
+}
+
+// currentRef identifies the linted revision: the CI commit when available,
+// otherwise the local git HEAD
+func currentRef() string {
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" {
+		return sha
+	}
+	output, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// recordHistory appends the run to the --history file, if one was given
+func recordHistory(results []LintResult) {
+	if historyPath == "" {
+		return
+	}
+	if err := appendHistory(historyPath, summarizeRun(results, currentRef())); err != nil {
+		log.Printf("Warning: failed to record history: %v", err)
+	}
+}
+
+func appendHistory(path string, entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func readHistory(path string) ([]HistoryEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, scanner.Err()
+}
+
+func runTrend(args []string) {
+	path := defaultHistoryFile
+	var since time.Time
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--history" && i+1 < len(args) {
+			i++
+			path = args[i]
+		} else if args[i] == "--since" && i+1 < len(args) {
+			i++
+			parsed, err := time.Parse("2006-01-02", args[i])
+			if err != nil {
+				log.Fatalf("Invalid --since date (want YYYY-MM-DD): %s", args[i])
+			}
+			since = parsed
+		}
+	}
+
+	entries, err := readHistory(path)
+	if err != nil {
+		log.Fatalf("Error reading history %s: %v", path, err)
+	}
+	var filtered []HistoryEntry
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(since) {
+			filtered = append(filtered, entry)
+		}
+	}
+	fmt.Print(formatTrend(filtered))
+}
+
+// formatTrend renders the runs and compares the first and last of them
+func formatTrend(entries []HistoryEntry) string {
+	var out strings.Builder
+	if len(entries) == 0 {
+		out.WriteString("No runs recorded\n")
+		return out.String()
+	}
+
+	out.WriteString(fmt.Sprintf("%-16s  %-10s  %10s  %6s  %8s\n", "Date", "Ref", "Challenges", "Errors", "Warnings"))
+	for _, entry := range entries {
+		ref := entry.Ref
+		if len(ref) > 10 {
+			ref = ref[:10]
+		}
+		out.WriteString(fmt.Sprintf("%-16s  %-10s  %10d  %6d  %8d\n",
+			entry.Timestamp.Format("2006-01-02 15:04"), ref, entry.Challenges, entry.Errors, entry.Warnings))
+	}
+
+	first, last := entries[0], entries[len(entries)-1]
+	out.WriteString("\n")
+	out.WriteString(fmt.Sprintf("Errors:   %d → %d (%s)\n", first.Errors, last.Errors, trendWord(first.Errors, last.Errors)))
+	out.WriteString(fmt.Sprintf("Warnings: %d → %d (%s)\n", first.Warnings, last.Warnings, trendWord(first.Warnings, last.Warnings)))
+
+	rules := make(map[string]bool)
+	for rule := range first.Rules {
+		rules[rule] = true
+	}
+	for rule := range last.Rules {
+		rules[rule] = true
+	}
+	var changed []string
+	for rule := range rules {
+		if first.Rules[rule] != last.Rules[rule] {
+			changed = append(changed, rule)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		di := last.Rules[changed[i]] - first.Rules[changed[i]]
+		dj := last.Rules[changed[j]] - first.Rules[changed[j]]
+		if di != dj {
+			return di > dj
+		}
+		return changed[i] < changed[j]
+	})
+	if len(changed) > 0 {
+		out.WriteString("\nBy rule:\n")
+		for _, rule := range changed {
+			out.WriteString(fmt.Sprintf("  %-20s %d → %d\n", rule, first.Rules[rule], last.Rules[rule]))
+		}
+	}
+	return out.String()
+}
+
+func trendWord(before, after int) string {
+	switch {
+	case after < before:
+		return "improving"
+	case after > before:
+		return "worsening"
+	default:
+		return "unchanged"
+	}
+}
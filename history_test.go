@@ -0,0 +1,61 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	defer func() {
+		now = time.Now
+	}()
+
+	first := LintResult{File: "a/challenge.yml"}
+	first.addErrors("state", []string{"e1"})
+	first.addErrors("files", []string{"e2", "e3"})
+	first.addWarnings("", []string{"w1"})
+	now = func() time.Time { return time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC) }
+	if err := appendHistory(path, summarizeRun([]LintResult{first}, "abc1234")); err != nil {
+		t.Fatalf("appendHistory failed: %v", err)
+	}
+
+	second := LintResult{File: "a/challenge.yml"}
+	second.addErrors("files", []string{"e2"})
+	now = func() time.Time { return time.Date(2026, 9, 8, 10, 0, 0, 0, time.UTC) }
+	if err := appendHistory(path, summarizeRun([]LintResult{second, {File: "b/challenge.yml"}}, "def5678")); err != nil {
+		t.Fatalf("appendHistory failed: %v", err)
+	}
+
+	entries, err := readHistory(path)
+	if err != nil {
+		t.Fatalf("readHistory failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Rules["files"] != 2 || entries[0].Rules["other"] != 1 || entries[0].Errors != 3 {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+
+	report := formatTrend(entries)
+	for _, want := range []string{
+		"2026-09-01 10:00  abc1234",
+		"Errors:   3 → 1 (improving)",
+		"Warnings: 1 → 0 (improving)",
+		"files                2 → 1",
+		"state                1 → 0",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected trend report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestFormatTrendEmpty(t *testing.T) {
+	if report := formatTrend(nil); report != "No runs recorded\n" {
+		t.Errorf("Unexpected report: %q", report)
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HistoryLeak is something players could recover from the git history of a
+// challenge directory once the repository is published
+type HistoryLeak struct {
+	File string `json:"file"`
+	Kind string `json:"kind"` // old-flag or deleted-solution
+	// Commits are the abbreviated commits that add or remove the leak, newest first
+	Commits []string `json:"commits"`
+	// Path is the deleted solution file
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// solutionPathWords mark deleted files as solutions when a path segment contains them
+var solutionPathWords = []string{"solution", "solve", "writeup", "exploit"}
+
+func runHistoryScan(args []string) {
+	jsonOutput := false
+	var targetDirs []string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		} else {
+			targetDirs = append(targetDirs, arg)
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	var leaks []HistoryLeak
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			found, err := scanChallengeHistory(path, config)
+			if err != nil {
+				log.Fatalf("Error scanning the history of %s: %v", path, err)
+			}
+			leaks = append(leaks, found...)
+		}
+	}
+
+	if jsonOutput {
+		if leaks == nil {
+			leaks = []HistoryLeak{}
+		}
+		jsonData, err := json.Marshal(leaks)
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		writeHistoryLeaks(os.Stdout, leaks)
+	}
+	if len(leaks) > 0 {
+		os.Exit(1)
+	}
+}
+
+// scanChallengeHistory reports flags of earlier versions of challenge.yml
+// that differ from the current flags, and solution files deleted from the
+// challenge directory, both of which stay readable in the history
+func scanChallengeHistory(challengePath string, config *LintConfig) ([]HistoryLeak, error) {
+	var leaks []HistoryLeak
+
+	data, err := readYAMLFile(challengePath, config.YAMLLimits)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := parseChallengeData(data, config)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]bool)
+	for _, flag := range staticFlags(challenge.Flags) {
+		current[flag.content] = true
+	}
+	dir := filepath.ToSlash(filepath.Dir(challengePath))
+	gitPath := filepath.ToSlash(challengePath)
+
+	output, err := exec.Command("git", "log", "--format=%h", "--", gitPath).Output()
+	if err != nil {
+		return nil, gitError(err)
+	}
+	seen := make(map[string]bool)
+	var oldFlags []string
+	for _, commit := range strings.Fields(string(output)) {
+		data, err := exec.Command("git", "show", commit+":./"+gitPath).Output()
+		if err != nil {
+			// Deleted in this commit
+			continue
+		}
+		old, err := parseChallengeData(data, config)
+		if err != nil {
+			continue
+		}
+		for _, flag := range staticFlags(old.Flags) {
+			if !current[flag.content] && !seen[flag.content] {
+				seen[flag.content] = true
+				oldFlags = append(oldFlags, flag.content)
+			}
+		}
+	}
+	for _, flag := range oldFlags {
+		output, err := exec.Command("git", "log", "--format=%h", "-S", flag, "--", dir).Output()
+		if err != nil {
+			return nil, gitError(err)
+		}
+		commits := strings.Fields(string(output))
+		leaks = append(leaks, HistoryLeak{
+			File:    challengePath,
+			Kind:    "old-flag",
+			Commits: commits,
+			Message: fmt.Sprintf("An old flag (%d characters) that differs from the current flags is in the history (%s)", len(flag), strings.Join(commits, ", ")),
+		})
+	}
+
+	output, err = exec.Command("git", "log", "--diff-filter=D", "--name-only", "--relative", "--format=%x00%h", "--", dir).Output()
+	if err != nil {
+		return nil, gitError(err)
+	}
+	deleted := make(map[string][]string)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		lines := strings.Split(strings.TrimSpace(entry), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+		commit := lines[0]
+		for _, path := range lines[1:] {
+			path = strings.TrimSpace(path)
+			if path != "" && isSolutionPath(path) && !fileExists(path) {
+				deleted[path] = append(deleted[path], commit)
+			}
+		}
+	}
+	var paths []string
+	for path := range deleted {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		commits := deleted[path]
+		leaks = append(leaks, HistoryLeak{
+			File:    challengePath,
+			Kind:    "deleted-solution",
+			Commits: commits,
+			Path:    path,
+			Message: fmt.Sprintf("Solution file '%s' was deleted in %s but can still be read from the history", path, commits[0]),
+		})
+	}
+
+	return leaks, nil
+}
+
+// isSolutionPath reports whether a path looks like a solve script or writeup
+func isSolutionPath(path string) bool {
+	for _, segment := range strings.Split(strings.ToLower(path), "/") {
+		for _, word := range solutionPathWords {
+			if strings.Contains(segment, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fileExists reports whether path exists in the working tree
+func fileExists(path string) bool {
+	_, err := os.Stat(filepath.FromSlash(path))
+	return err == nil
+}
+
+func writeHistoryLeaks(w io.Writer, leaks []HistoryLeak) {
+	if len(leaks) == 0 {
+		fmt.Fprintln(w, "✅ No old flags or deleted solutions in the history")
+		return
+	}
+	byFile := make(map[string][]HistoryLeak)
+	var files []string
+	for _, leak := range leaks {
+		if _, ok := byFile[leak.File]; !ok {
+			files = append(files, leak.File)
+		}
+		byFile[leak.File] = append(byFile[leak.File], leak)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		fmt.Fprintf(w, "🕰️  %s\n", file)
+		for _, leak := range byFile[file] {
+			fmt.Fprintf(w, "  - %s\n", leak.Message)
+		}
+	}
+	fmt.Fprintf(w, "\n%d leak(s) found; rewrite the history before publishing the repository, and make sure old flags are not accepted\n", len(leaks))
+}
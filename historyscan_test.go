@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanChallengeHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	var commits []string
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(message string) {
+		t.Helper()
+		git("add", "-A")
+		git("commit", "--quiet", "-m", message)
+		commits = append(commits, git("rev-parse", "--short", "HEAD"))
+	}
+
+	git("init", "--quiet")
+	write("web/login/challenge.yml", "name: login\nflags: [\"flag{draft}\"]\n")
+	write("web/login/solve.py", "print('flag{draft}')\n")
+	write("web/login/app.py", "print('hello')\n")
+	commit("initial")
+
+	write("web/login/challenge.yml", "name: login\nflags: [\"flag{final}\"]\n")
+	if err := os.Remove("web/login/solve.py"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove("web/login/app.py"); err != nil {
+		t.Fatal(err)
+	}
+	commit("final flag")
+
+	write("pwn/bof/challenge.yml", "name: bof\nflags: [\"flag{bof}\"]\n")
+	commit("add bof")
+
+	config := &LintConfig{}
+	leaks, err := scanChallengeHistory("web/login/challenge.yml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leaks) != 2 {
+		t.Fatalf("expected 2 leaks, got %+v", leaks)
+	}
+	if leaks[0].Kind != "old-flag" || strings.Join(leaks[0].Commits, ",") != commits[1]+","+commits[0] {
+		t.Errorf("expected the old flag in commits %s and %s, got %+v", commits[1], commits[0], leaks[0])
+	}
+	if strings.Contains(leaks[0].Message, "flag{draft}") {
+		t.Errorf("message leaks the flag: %s", leaks[0].Message)
+	}
+	if leaks[1].Kind != "deleted-solution" || leaks[1].Path != "web/login/solve.py" || leaks[1].Commits[0] != commits[1] {
+		t.Errorf("expected solve.py deleted in %s, got %+v", commits[1], leaks[1])
+	}
+
+	leaks, err = scanChallengeHistory("pwn/bof/challenge.yml", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leaks) != 0 {
+		t.Errorf("expected no leaks for a challenge with one flag, got %+v", leaks)
+	}
+
+	var out bytes.Buffer
+	writeHistoryLeaks(&out, nil)
+	if !strings.Contains(out.String(), "No old flags") {
+		t.Errorf("unexpected output without leaks: %s", out.String())
+	}
+}
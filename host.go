@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HostPolicy constrains the host field beyond its shape
+type HostPolicy struct {
+	// Schemes lists the allowed URL schemes, or map protocols, e.g. [https]
+	Schemes []string `yaml:"schemes"`
+	// RequirePort requires an explicit port, e.g. for pwn challenges served over nc
+	RequirePort bool `yaml:"require_port"`
+}
+
+// hostFields are the keys of a map-style host
+var hostFields = map[string]bool{"host": true, "port": true, "protocol": true}
+
+// parsedHost is the host field reduced to the parts policies check
+type parsedHost struct {
+	scheme string
+	port   int
+}
+
+// checkHost validates the shape of host, which must be null, a URL with a
+// scheme, or a {host, port, protocol} map, and applies the host policy
+func checkHost(host interface{}, required bool, policy HostPolicy) []string {
+	var errors []string
+
+	if host == nil || host == "" {
+		if required {
+			errors = append(errors, "Field 'host' is required for this category")
+		}
+		return errors
+	}
+
+	var parsed parsedHost
+	switch v := host.(type) {
+	case string:
+		if strings.Contains(v, "${") || strings.Contains(v, "{{") {
+			// Reported as an unresolved placeholder
+			return errors
+		}
+		u, err := url.Parse(v)
+		if err != nil || u.Scheme == "" || u.Hostname() == "" {
+			errors = append(errors, fmt.Sprintf("Field 'host' must be a URL with a scheme, e.g. https://web.example.com or tcp://pwn.example.com:1337 (got '%s')", v))
+			return errors
+		}
+		parsed.scheme = strings.ToLower(u.Scheme)
+		if u.Port() != "" {
+			port, err := strconv.Atoi(u.Port())
+			if err != nil || port < 1 || port > 65535 {
+				errors = append(errors, fmt.Sprintf("Field 'host' has an invalid port '%s'", u.Port()))
+				return errors
+			}
+			parsed.port = port
+		}
+	case map[string]interface{}:
+		var hostErrors []string
+		parsed, hostErrors = parseHostMap(v)
+		if len(hostErrors) > 0 {
+			return append(errors, hostErrors...)
+		}
+	default:
+		errors = append(errors, "Field 'host' must be null, a URL, or a map with host, port, and protocol")
+		return errors
+	}
+
+	if len(policy.Schemes) > 0 && !containsFold(policy.Schemes, parsed.scheme) {
+		scheme := parsed.scheme
+		if scheme == "" {
+			scheme = "none"
+		}
+		errors = append(errors, fmt.Sprintf("Field 'host' must use %s for this category (got %s)", strings.Join(policy.Schemes, " or "), scheme))
+	}
+	if policy.RequirePort && parsed.port == 0 {
+		errors = append(errors, "Field 'host' must include a port for this category")
+	}
+
+	return errors
+}
+
+// parseHostMap validates a {host, port, protocol} map
+func parseHostMap(host map[string]interface{}) (parsedHost, []string) {
+	var parsed parsedHost
+	var errors []string
+
+	var unknown []string
+	for key := range host {
+		if !hostFields[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errors = append(errors, fmt.Sprintf("Field 'host' has unknown key '%s' (expected host, port, and protocol)", key))
+	}
+
+	if name, ok := host["host"].(string); !ok || name == "" {
+		errors = append(errors, "Field 'host' map must have a 'host' name")
+	} else if strings.Contains(name, "://") || strings.Contains(name, "/") {
+		errors = append(errors, fmt.Sprintf("Field 'host' map 'host' must be a hostname, not a URL (got '%s')", name))
+	}
+
+	if value, ok := host["port"]; ok {
+		port, isInt := value.(int)
+		if !isInt || port < 1 || port > 65535 {
+			errors = append(errors, fmt.Sprintf("Field 'host' map 'port' must be a number from 1 to 65535 (got '%v')", value))
+		} else {
+			parsed.port = port
+		}
+	}
+
+	if value, ok := host["protocol"]; ok {
+		protocol, isString := value.(string)
+		if !isString || protocol == "" {
+			errors = append(errors, fmt.Sprintf("Field 'host' map 'protocol' must be a string (got '%v')", value))
+		} else {
+			parsed.scheme = strings.ToLower(protocol)
+		}
+	}
+
+	return parsed, errors
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckHost(t *testing.T) {
+	web := HostPolicy{Schemes: []string{"https"}}
+	pwn := HostPolicy{RequirePort: true}
+
+	tests := []struct {
+		name       string
+		host       string
+		required   bool
+		policy     HostPolicy
+		wantErrors []string
+	}{
+		{name: "null", host: "null"},
+		{name: "required but null", host: "null", required: true, wantErrors: []string{"Field 'host' is required for this category"}},
+		{name: "https URL", host: "https://web.example.com/login", policy: web},
+		{name: "tcp URL with port", host: "tcp://pwn.example.com:1337", policy: pwn},
+		{name: "map with port", host: "{host: pwn.example.com, port: 1337, protocol: tcp}", policy: pwn},
+		{name: "unresolved placeholder", host: `"https://${DOMAIN}"`},
+		{
+			name:       "bare hostname",
+			host:       "web.example.com",
+			wantErrors: []string{"Field 'host' must be a URL with a scheme, e.g. https://web.example.com or tcp://pwn.example.com:1337 (got 'web.example.com')"},
+		},
+		{
+			name:       "host and port without scheme",
+			host:       "pwn.example.com:1337",
+			wantErrors: []string{"Field 'host' must be a URL with a scheme, e.g. https://web.example.com or tcp://pwn.example.com:1337 (got 'pwn.example.com:1337')"},
+		},
+		{name: "invalid port", host: "tcp://pwn.example.com:99999", wantErrors: []string{"Field 'host' has an invalid port '99999'"}},
+		{name: "list", host: "[a, b]", wantErrors: []string{"Field 'host' must be null, a URL, or a map with host, port, and protocol"}},
+		{name: "web over http", host: "http://web.example.com", policy: web, wantErrors: []string{"Field 'host' must use https for this category (got http)"}},
+		{name: "web as map without protocol", host: "{host: web.example.com, port: 443}", policy: web, wantErrors: []string{"Field 'host' must use https for this category (got none)"}},
+		{name: "pwn without port", host: "tcp://pwn.example.com", policy: pwn, wantErrors: []string{"Field 'host' must include a port for this category"}},
+		{
+			name: "malformed map",
+			host: "{hostname: pwn.example.com, port: '1337', protocol: 6}",
+			wantErrors: []string{
+				"Field 'host' has unknown key 'hostname' (expected host, port, and protocol)",
+				"Field 'host' map must have a 'host' name",
+				"Field 'host' map 'port' must be a number from 1 to 65535 (got '1337')",
+				"Field 'host' map 'protocol' must be a string (got '6')",
+			},
+		},
+		{name: "URL in map", host: "{host: 'https://web.example.com'}", wantErrors: []string{"Field 'host' map 'host' must be a hostname, not a URL (got 'https://web.example.com')"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte("host: "+tt.host+"\n"), &challenge); err != nil {
+				t.Fatal(err)
+			}
+			if got := checkHost(challenge.Host, tt.required, tt.policy); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkHost() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestHostPolicyCategoryProfile(t *testing.T) {
+	config, err := parseLintConfig([]byte("host_policy:\n  schemes: [https, tcp]\nprofiles:\n  pwn:\n    host_policy:\n      require_port: true\n"), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.forCategory("web").HostPolicy; !reflect.DeepEqual(got, HostPolicy{Schemes: []string{"https", "tcp"}}) {
+		t.Errorf("web host policy = %+v", got)
+	}
+	if got := config.forCategory("PWN").HostPolicy; !reflect.DeepEqual(got, HostPolicy{RequirePort: true}) {
+		t.Errorf("pwn host policy = %+v", got)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// imageFields are the keys of a map-style image
+var imageFields = map[string]bool{"name": true, "build": true, "registry": true}
+
+// composeFiles are the Compose file names the image is cross-checked against
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+var (
+	imageDomainPattern    = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:[0-9]+)?$`)
+	imageComponentPattern = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	imageTagPattern       = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	imageDigestPattern    = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// imageReference is a registry reference split into its parts
+type imageReference struct {
+	repository string
+	tag        string
+	digest     string
+}
+
+// checkImage validates image, which must be null unless allowed. An allowed
+// image is a pinned registry reference, a build context such as ".", or a
+// {name, build, registry} map, and must agree with any Compose file in the
+// challenge directory
+func checkImage(challengePath string, image interface{}, allowed bool) []string {
+	var errors []string
+
+	if image == nil {
+		return errors
+	}
+	if !allowed {
+		errors = append(errors, "Field 'image' should be null")
+		return errors
+	}
+
+	var reference, build string
+	switch v := image.(type) {
+	case string:
+		if strings.Contains(v, "${") || strings.Contains(v, "{{") {
+			// Reported as an unresolved placeholder
+			return errors
+		}
+		if v == "." || strings.HasPrefix(v, "./") {
+			// ctfcli builds the image from a directory of the challenge
+			build = v
+		} else {
+			reference = v
+		}
+	case map[string]interface{}:
+		var mapErrors []string
+		reference, build, mapErrors = parseImageMap(v)
+		if len(mapErrors) > 0 {
+			return append(errors, mapErrors...)
+		}
+	default:
+		errors = append(errors, "Field 'image' must be null, a registry reference, or a map with name, build, and registry")
+		return errors
+	}
+
+	if reference != "" {
+		errors = append(errors, checkImageReference(reference)...)
+	}
+	if build != "" {
+		errors = append(errors, checkImageBuild(challengePath, build)...)
+	}
+	if reference != "" && len(errors) == 0 {
+		errors = append(errors, checkComposeImage(challengePath, reference)...)
+	}
+
+	return errors
+}
+
+// parseImageMap validates a {name, build, registry} map and returns the
+// full registry reference and the build context
+func parseImageMap(image map[string]interface{}) (string, string, []string) {
+	var errors []string
+
+	var unknown []string
+	for key := range image {
+		if !imageFields[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errors = append(errors, fmt.Sprintf("Field 'image' has unknown key '%s' (expected name, build, and registry)", key))
+	}
+
+	fields := make(map[string]string)
+	for _, key := range []string{"name", "build", "registry"} {
+		value, ok := image[key]
+		if !ok || value == nil {
+			continue
+		}
+		s, isString := value.(string)
+		if !isString || s == "" {
+			errors = append(errors, fmt.Sprintf("Field 'image' map '%s' must be a string (got '%v')", key, value))
+			continue
+		}
+		fields[key] = s
+	}
+	if _, ok := image["name"]; !ok {
+		errors = append(errors, "Field 'image' map must have a 'name'")
+	}
+	if len(errors) > 0 {
+		return "", "", errors
+	}
+
+	reference := fields["name"]
+	if registry := strings.TrimSuffix(fields["registry"], "/"); registry != "" {
+		reference = registry + "/" + reference
+	}
+	return reference, fields["build"], nil
+}
+
+// parseImageReference splits a reference of the form
+// [domain/]path[:tag][@digest], reporting whether it is well-formed
+func parseImageReference(reference string) (imageReference, bool) {
+	var ref imageReference
+
+	name := reference
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.digest = name[:i], name[i+1:]
+		if !imageDigestPattern.MatchString(ref.digest) {
+			return ref, false
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.tag = name[:i], name[i+1:]
+		if !imageTagPattern.MatchString(ref.tag) {
+			return ref, false
+		}
+	}
+	ref.repository = name
+
+	components := strings.Split(name, "/")
+	if len(components) > 1 && (strings.ContainsAny(components[0], ".:") || components[0] == "localhost") {
+		if !imageDomainPattern.MatchString(components[0]) {
+			return ref, false
+		}
+		components = components[1:]
+	}
+	for _, component := range components {
+		if !imageComponentPattern.MatchString(component) {
+			return ref, false
+		}
+	}
+	return ref, true
+}
+
+// checkImageReference requires a well-formed reference pinned to a tag
+// other than latest, or to a digest
+func checkImageReference(reference string) []string {
+	var errors []string
+
+	ref, ok := parseImageReference(reference)
+	switch {
+	case !ok:
+		errors = append(errors, fmt.Sprintf("Field 'image' is not a valid registry reference (got '%s')", reference))
+	case ref.tag == "" && ref.digest == "":
+		errors = append(errors, fmt.Sprintf("Field 'image' must pin a tag or digest (got '%s')", reference))
+	case ref.tag == "latest" && ref.digest == "":
+		errors = append(errors, fmt.Sprintf("Field 'image' must not use the 'latest' tag (got '%s')", reference))
+	}
+
+	return errors
+}
+
+// checkImageBuild requires the build context to be a directory of the
+// challenge containing a Dockerfile
+func checkImageBuild(challengePath, build string) []string {
+	var errors []string
+
+	context := filepath.Clean(filepath.FromSlash(build))
+	if filepath.IsAbs(context) || context == ".." || strings.HasPrefix(context, ".."+string(filepath.Separator)) {
+		errors = append(errors, fmt.Sprintf("Field 'image' build context '%s' must stay inside the challenge directory", build))
+		return errors
+	}
+	if _, err := os.Stat(filepath.Join(challengePath, context, "Dockerfile")); err != nil {
+		errors = append(errors, fmt.Sprintf("Field 'image' build context '%s' has no Dockerfile", build))
+	}
+
+	return errors
+}
+
+// checkComposeImage requires reference to be one of the service images of
+// the challenge's Compose file, if it has one that names any
+func checkComposeImage(challengePath, reference string) []string {
+	var errors []string
+
+	for _, name := range composeFiles {
+		data, err := os.ReadFile(filepath.Join(challengePath, name))
+		if err != nil {
+			continue
+		}
+
+		var compose struct {
+			Services map[string]struct {
+				Image string `yaml:"image"`
+			} `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			errors = append(errors, fmt.Sprintf("Could not parse %s to cross-check field 'image': %v", name, err))
+			return errors
+		}
+
+		var images []string
+		for _, service := range compose.Services {
+			if service.Image == "" || strings.Contains(service.Image, "${") {
+				continue
+			}
+			if service.Image == reference {
+				return errors
+			}
+			images = append(images, service.Image)
+		}
+		if len(images) > 0 {
+			errors = append(errors, fmt.Sprintf("Field 'image' '%s' does not match any service image in %s (%s)", reference, name, strings.Join(uniqueSorted(images), ", ")))
+		}
+		return errors
+	}
+
+	return errors
+}
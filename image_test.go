@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckImage(t *testing.T) {
+	challengePath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(challengePath, "server"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(challengePath, "server", "Dockerfile"), []byte("FROM alpine:3.20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name       string
+		image      string
+		allowed    bool
+		wantErrors []string
+	}{
+		{name: "null", image: "null"},
+		{name: "not allowed", image: "registry.example.com/pwn:1.0", wantErrors: []string{"Field 'image' should be null"}},
+		{name: "pinned tag", image: "registry.example.com/ctf/pwn-bof:1.0", allowed: true},
+		{name: "pinned digest", image: "ghcr.io/ctf/pwn@" + digest, allowed: true},
+		{name: "registry with port", image: "localhost:5000/pwn:v2", allowed: true},
+		{name: "build context", image: "./server", allowed: true},
+		{name: "unresolved placeholder", image: `"${REGISTRY}/pwn:1.0"`, allowed: true},
+		{name: "untagged", image: "registry.example.com/pwn", allowed: true, wantErrors: []string{"Field 'image' must pin a tag or digest (got 'registry.example.com/pwn')"}},
+		{name: "latest", image: "pwn:latest", allowed: true, wantErrors: []string{"Field 'image' must not use the 'latest' tag (got 'pwn:latest')"}},
+		{name: "uppercase path", image: "registry.example.com/Pwn:1.0", allowed: true, wantErrors: []string{"Field 'image' is not a valid registry reference (got 'registry.example.com/Pwn:1.0')"}},
+		{name: "short digest", image: "pwn@sha256:abc", allowed: true, wantErrors: []string{"Field 'image' is not a valid registry reference (got 'pwn@sha256:abc')"}},
+		{name: "build context without Dockerfile", image: ".", allowed: true, wantErrors: []string{"Field 'image' build context '.' has no Dockerfile"}},
+		{name: "list", image: "[a, b]", allowed: true, wantErrors: []string{"Field 'image' must be null, a registry reference, or a map with name, build, and registry"}},
+		{name: "map", image: "{name: ctf/pwn:1.0, build: server, registry: registry.example.com}", allowed: true},
+		{name: "map pinned by registry reference", image: "{name: ctf/pwn, registry: registry.example.com/}", allowed: true, wantErrors: []string{"Field 'image' must pin a tag or digest (got 'registry.example.com/ctf/pwn')"}},
+		{name: "map build outside challenge", image: "{name: pwn:1.0, build: ../other}", allowed: true, wantErrors: []string{"Field 'image' build context '../other' must stay inside the challenge directory"}},
+		{
+			name:    "malformed map",
+			image:   "{tag: '1.0', build: 1}",
+			allowed: true,
+			wantErrors: []string{
+				"Field 'image' has unknown key 'tag' (expected name, build, and registry)",
+				"Field 'image' map 'build' must be a string (got '1')",
+				"Field 'image' map must have a 'name'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var challenge Challenge
+			if err := yaml.Unmarshal([]byte("image: "+tt.image+"\n"), &challenge); err != nil {
+				t.Fatal(err)
+			}
+			if got := checkImage(challengePath, challenge.Image, tt.allowed); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkImage() = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestCheckImageCompose(t *testing.T) {
+	challengePath := t.TempDir()
+	compose := `services:
+  app:
+    image: registry.example.com/web/login:1.2
+    build: .
+  db:
+    image: postgres:16
+  proxy:
+    image: ${PROXY_IMAGE}
+`
+	if err := os.WriteFile(filepath.Join(challengePath, "docker-compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := checkImage(challengePath, "registry.example.com/web/login:1.2", true); len(errs) != 0 {
+		t.Errorf("expected the service image to match, got %v", errs)
+	}
+
+	want := []string{"Field 'image' 'registry.example.com/web/login:1.3' does not match any service image in docker-compose.yml (postgres:16, registry.example.com/web/login:1.2)"}
+	if got := checkImage(challengePath, "registry.example.com/web/login:1.3", true); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkImage() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultImagePlatform is the platform picked from multi-platform images
+const defaultImagePlatform = "linux/amd64"
+
+// ImageSizeRule bounds the registry images of container challenges; the
+// manifest is fetched only when a limit is set
+type ImageSizeRule struct {
+	// MaxSize is the largest compressed image in bytes, the sum of its layers
+	MaxSize int64 `yaml:"max_size"`
+	// MaxLayers is the largest number of layers
+	MaxLayers int `yaml:"max_layers"`
+	// Platform is the os/arch[/variant] inspected in multi-platform images
+	// (default linux/amd64)
+	Platform string `yaml:"platform"`
+}
+
+// imageManifest is the subset of an image manifest or index the size check reads
+type imageManifest struct {
+	MediaType string `json:"mediaType"`
+	Layers    []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// checkImageSize warns when the compressed registry image of a challenge is
+// larger or has more layers than configured. Images that are not pushed yet
+// are skipped.
+func checkImageSize(image interface{}, rule ImageSizeRule) []string {
+	var warnings []string
+
+	if rule.MaxSize <= 0 && rule.MaxLayers <= 0 {
+		return warnings
+	}
+	reference, manifestURL, ok := imageManifestURL(image)
+	if !ok {
+		return warnings
+	}
+	manifest, found, err := fetchImageManifest(manifestURL)
+	if err == nil && found && len(manifest.Manifests) > 0 {
+		platform := rule.Platform
+		if platform == "" {
+			platform = defaultImagePlatform
+		}
+		digest := ""
+		for _, candidate := range manifest.Manifests {
+			name := candidate.Platform.OS + "/" + candidate.Platform.Architecture
+			if name == platform || name+"/"+candidate.Platform.Variant == platform {
+				digest = candidate.Digest
+				break
+			}
+		}
+		if digest == "" {
+			return append(warnings, fmt.Sprintf("Image '%s' has no %s platform to inspect (image_size.platform)", reference, platform))
+		}
+		manifestURL = manifestURL[:strings.LastIndex(manifestURL, "/")+1] + digest
+		manifest, found, err = fetchImageManifest(manifestURL)
+	}
+	if err != nil {
+		return append(warnings, fmt.Sprintf("Image '%s' could not be inspected: %v", reference, err))
+	}
+	if !found {
+		return warnings
+	}
+
+	var size int64
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	if rule.MaxSize > 0 && size > rule.MaxSize {
+		warnings = append(warnings, fmt.Sprintf("Image '%s' is %.2f MB compressed, more than image_size.max_size (%.2f MB)",
+			reference, float64(size)/(1024*1024), float64(rule.MaxSize)/(1024*1024)))
+	}
+	if rule.MaxLayers > 0 && len(manifest.Layers) > rule.MaxLayers {
+		warnings = append(warnings, fmt.Sprintf("Image '%s' has %d layers, more than image_size.max_layers (%d)", reference, len(manifest.Layers), rule.MaxLayers))
+	}
+	return warnings
+}
+
+// fetchImageManifest gets and parses a manifest; found is false when the
+// registry does not have it
+func fetchImageManifest(manifestURL string) (manifest imageManifest, found bool, err error) {
+	status, body, err := requestManifest(http.MethodGet, manifestURL)
+	if err != nil {
+		return manifest, false, err
+	}
+	if status == http.StatusNotFound {
+		return manifest, false, nil
+	}
+	if status != http.StatusOK {
+		return manifest, false, fmt.Errorf("HTTP %d", status)
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return manifest, false, fmt.Errorf("invalid manifest: %v", err)
+	}
+	return manifest, true, nil
+}
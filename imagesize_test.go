@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckImageSize(t *testing.T) {
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/ctf/web/manifests/v1":
+			fmt.Fprint(w, `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 1000}, "layers": [{"size": 3145728}, {"size": 2097152}, {"size": 1048576}]}`)
+		case "/v2/ctf/multi/manifests/v1":
+			fmt.Fprint(w, `{"mediaType": "application/vnd.oci.image.index.v1+json", "manifests": [
+				{"digest": "sha256:arm", "platform": {"os": "linux", "architecture": "arm64", "variant": "v8"}},
+				{"digest": "sha256:amd", "platform": {"os": "linux", "architecture": "amd64"}}]}`)
+		case "/v2/ctf/multi/manifests/sha256:amd":
+			fmt.Fprint(w, `{"layers": [{"size": 10485760}]}`)
+		case "/v2/ctf/multi/manifests/sha256:arm":
+			fmt.Fprint(w, `{"layers": [{"size": 1}]}`)
+		case "/v2/ctf/broken/manifests/v1":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	origClient := httpClient
+	defer func() { httpClient = origClient }()
+	httpClient = registry.Client()
+	domain := strings.TrimPrefix(registry.URL, "https://")
+
+	tests := []struct {
+		name  string
+		image interface{}
+		rule  ImageSizeRule
+		want  []string
+	}{
+		{
+			name:  "within limits",
+			image: domain + "/ctf/web:v1",
+			rule:  ImageSizeRule{MaxSize: 10 << 20, MaxLayers: 3},
+		},
+		{
+			name:  "too large with too many layers",
+			image: domain + "/ctf/web:v1",
+			rule:  ImageSizeRule{MaxSize: 5 << 20, MaxLayers: 2},
+			want: []string{
+				fmt.Sprintf("Image '%s/ctf/web:v1' is 6.00 MB compressed, more than image_size.max_size (5.00 MB)", domain),
+				fmt.Sprintf("Image '%s/ctf/web:v1' has 3 layers, more than image_size.max_layers (2)", domain),
+			},
+		},
+		{
+			name:  "multi-platform image",
+			image: map[string]interface{}{"name": "ctf/multi:v1", "registry": domain},
+			rule:  ImageSizeRule{MaxSize: 5 << 20},
+			want:  []string{fmt.Sprintf("Image '%s/ctf/multi:v1' is 10.00 MB compressed, more than image_size.max_size (5.00 MB)", domain)},
+		},
+		{
+			name:  "platform variant",
+			image: domain + "/ctf/multi:v1",
+			rule:  ImageSizeRule{MaxSize: 5 << 20, Platform: "linux/arm64/v8"},
+		},
+		{
+			name:  "missing platform",
+			image: domain + "/ctf/multi:v1",
+			rule:  ImageSizeRule{MaxLayers: 1, Platform: "windows/amd64"},
+			want:  []string{fmt.Sprintf("Image '%s/ctf/multi:v1' has no windows/amd64 platform to inspect (image_size.platform)", domain)},
+		},
+		{
+			name:  "not pushed yet",
+			image: domain + "/ctf/new:v1",
+			rule:  ImageSizeRule{MaxSize: 1},
+		},
+		{
+			name:  "registry error",
+			image: domain + "/ctf/broken:v1",
+			rule:  ImageSizeRule{MaxSize: 1},
+			want:  []string{fmt.Sprintf("Image '%s/ctf/broken:v1' could not be inspected: HTTP 500", domain)},
+		},
+		{
+			name:  "no limits",
+			image: domain + "/ctf/broken:v1",
+		},
+		{
+			name:  "build context",
+			image: ".",
+			rule:  ImageSizeRule{MaxSize: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkImageSize(tt.image, tt.rule)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkImageSize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
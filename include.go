@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey lists shared snippets a challenge.yml builds on, e.g.
+//
+//	include: snippets/dynamic-scoring.yml
+//
+// Paths are relative to the repository root (the directory of lintrc.yaml).
+const includeKey = "include"
+
+// resolveChallengeData returns the effective challenge.yml document: the
+// documents of a multi-document file merged in order, each on top of the
+// snippets it includes. Later documents override earlier ones and a
+// document's own keys override its includes, key by key. Files with a single
+// document and no include are returned unchanged.
+func resolveChallengeData(data []byte, config *LintConfig) ([]byte, error) {
+	docs, err := yamlDocuments(data)
+	if err != nil || (len(docs) <= 1 && !hasInclude(docs)) {
+		// Parse errors are reported by the regular parse
+		return data, nil
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i, doc := range docs {
+		root, err := documentMapping(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", i+1, err)
+		}
+		if root == nil {
+			continue
+		}
+		for _, snippet := range includePaths(root) {
+			included, err := loadSnippet(snippet, config)
+			if err != nil {
+				return nil, err
+			}
+			mergeMapping(merged, included)
+		}
+		mergeMapping(merged, withoutKey(root, includeKey))
+	}
+
+	return yaml.Marshal(merged)
+}
+
+// yamlDocuments parses every document of a YAML stream
+func yamlDocuments(data []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+// documentMapping returns the root mapping of a document, nil for an empty one
+func documentMapping(doc *yaml.Node) (*yaml.Node, error) {
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping at the top level")
+	}
+	return root, nil
+}
+
+func hasInclude(docs []*yaml.Node) bool {
+	for _, doc := range docs {
+		if root, err := documentMapping(doc); err == nil && root != nil && mappingValue(root, includeKey) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// includePaths returns the snippets named by a mapping's include key, which
+// is a single path or a list of paths
+func includePaths(mapping *yaml.Node) []string {
+	value := resolveAlias(mappingValue(mapping, includeKey))
+	if value == nil {
+		return nil
+	}
+	if value.Kind == yaml.ScalarNode {
+		return []string{value.Value}
+	}
+	var paths []string
+	for _, item := range value.Content {
+		paths = append(paths, resolveAlias(item).Value)
+	}
+	return paths
+}
+
+// loadSnippet reads an included file, which must be a single mapping without
+// includes of its own. Includes of a submission to "clilint serve" resolve
+// inside the submission only.
+func loadSnippet(path string, config *LintConfig) (*yaml.Node, error) {
+	resolved := config.resolvePath(normalizeFilePath(path))
+	if untrustedRoot != "" {
+		var err error
+		if resolved, err = safeJoin(untrustedRoot, path); err != nil {
+			return nil, fmt.Errorf("include '%s' is outside the submission", path)
+		}
+	}
+	data, err := readYAMLFile(resolved, config.YAMLLimits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read include '%s': %v", path, err)
+	}
+	if err := checkAliasExpansion(data); err != nil {
+		return nil, fmt.Errorf("include '%s': %v", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("include '%s' is not valid YAML: %v", path, err)
+	}
+	root, err := documentMapping(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("include '%s': %v", path, err)
+	}
+	if root == nil {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	if mappingValue(root, includeKey) != nil {
+		return nil, fmt.Errorf("include '%s' must not include other files", path)
+	}
+	return root, nil
+}
+
+// mappingValue returns the value of key in a mapping, nil if absent
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// withoutKey returns a copy of a mapping without key
+func withoutKey(mapping *yaml.Node, key string) *yaml.Node {
+	copied := *mapping
+	copied.Content = nil
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			copied.Content = append(copied.Content, mapping.Content[i], mapping.Content[i+1])
+		}
+	}
+	return &copied
+}
+
+// mergeMapping sets every key of src in dst, replacing existing values
+func mergeMapping(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				dst.Content[j+1] = value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+}
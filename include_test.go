@@ -0,0 +1,175 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const includeChallengeBase = `name: "included"
+author: "author"
+category: "misc"
+description: "desc"
+flags:
+  - "flag{included}"
+tags:
+  - easy
+value: 100
+image: null
+host: null
+state: visible
+version: "0.1"
+`
+
+func TestResolveChallengeDataUnchanged(t *testing.T) {
+	data := []byte(includeChallengeBase)
+	resolved, err := resolveChallengeData(data, &LintConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resolved) != string(data) {
+		t.Errorf("single document without include was rewritten:\n%s", resolved)
+	}
+}
+
+func TestResolveChallengeDataMultiDocument(t *testing.T) {
+	data := []byte("name: first\nvalue: 100\n---\nvalue: 200\ntype: dynamic\n---\n")
+	resolved, err := resolveChallengeData(data, &LintConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name: first\nvalue: 200\ntype: dynamic\n"
+	if string(resolved) != want {
+		t.Errorf("resolveChallengeData() =\n%s\nwant\n%s", resolved, want)
+	}
+}
+
+func TestResolveChallengeDataInclude(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "snippets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	scoring := "type: dynamic\nextra:\n  initial: 500\n  decay: 100\n  minimum: 100\n"
+	if err := os.WriteFile(filepath.Join(root, "snippets", "scoring.yml"), []byte(scoring), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "snippets", "state.yml"), []byte("state: hidden\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := &LintConfig{baseDir: root}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "single include",
+			data: "include: snippets/scoring.yml\nname: test\n",
+			want: []string{"type: dynamic", "initial: 500", "name: test"},
+		},
+		{
+			name: "own keys override includes",
+			data: "include: [snippets/scoring.yml]\ntype: standard\n",
+			want: []string{"type: standard", "decay: 100"},
+		},
+		{
+			name: "list of includes, later ones win",
+			data: "include:\n  - snippets/state.yml\n  - snippets/scoring.yml\nstate: visible\n",
+			want: []string{"state: visible", "type: dynamic"},
+		},
+		{
+			name:    "missing include",
+			data:    "include: snippets/missing.yml\n",
+			wantErr: "failed to read include 'snippets/missing.yml'",
+		},
+		{
+			name:    "document is not a mapping",
+			data:    "name: test\n---\n- a\n",
+			wantErr: "document 2: expected a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := resolveChallengeData([]byte(tt.data), config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(resolved), "include") {
+				t.Errorf("include key left in the effective document:\n%s", resolved)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(resolved), want) {
+					t.Errorf("expected %q in\n%s", want, resolved)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveChallengeDataNestedInclude(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.yml"), []byte("include: b.yml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := resolveChallengeData([]byte("include: a.yml\n"), &LintConfig{baseDir: root})
+	if err == nil || !strings.Contains(err.Error(), "must not include other files") {
+		t.Errorf("expected nested include error, got %v", err)
+	}
+}
+
+func TestLintChallengeFileInclude(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	if err := os.WriteFile("lintrc.yaml", []byte("requirements:\n  condition: none\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("snippets", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("snippets", "scoring.yml"), []byte("type: dynamic\nextra:\n  initial: 500\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("chall", 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("chall", "challenge.yml")
+	if err := os.WriteFile(path, []byte("include: snippets/scoring.yml\n"+includeChallengeBase), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := lintChallengeFile(path)
+	if len(result.Errors) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("expected no findings, got errors %v, warnings %v", result.Errors, result.Warnings)
+	}
+
+	challenge, err := readChallenge(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.Type != "dynamic" || challenge.Extra["initial"] != 500 {
+		t.Errorf("include not applied: %+v", challenge)
+	}
+
+	if err := os.WriteFile(path, []byte("include: snippets/missing.yml\n"+includeChallengeBase), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = lintChallengeFile(path)
+	if len(result.Errors) != 1 || result.Errors[0].RuleID != "include" {
+		t.Errorf("expected an include error, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultCategories are offered by the category picker in addition to the
+// categories configured in lintrc.yaml
+var defaultCategories = []string{"web", "pwn", "crypto", "rev", "forensics", "osint", "misc"}
+
+// defaultDifficulties are offered when lintrc.yaml has no static tag pattern
+var defaultDifficulties = []string{"easy", "medium", "hard"}
+
+// promptMissingFields asks the author for the category, difficulty tag, and
+// author of a challenge when they are missing, and writes the answers back
+// into challenge.yml. It reports whether the file was changed. Empty answers
+// skip a field; end of input stops prompting.
+func promptMissingFields(path string, config *LintConfig, in *bufio.Reader, out io.Writer) (bool, error) {
+	challenge, err := readChallenge(path)
+	if err != nil {
+		// Reported as invalid YAML by the lint itself
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	original := string(data)
+
+	if strings.TrimSpace(challenge.Category) == "" {
+		fmt.Fprintf(out, "📝 %s: field 'category' is missing\n", path)
+		category, err := pick(in, out, "Category", categoryChoices(config))
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		if category != "" {
+			if data, err = setTopLevelScalar(data, "category", strconv.Quote(category)); err != nil {
+				return false, err
+			}
+			challenge.Category = category
+		}
+	}
+
+	difficulties := difficultyChoices(config.forCategory(challenge.Category).Tags)
+	if !hasAnyTag(challenge.Tags, difficulties) {
+		fmt.Fprintf(out, "📝 %s: no difficulty tag\n", path)
+		difficulty, err := pick(in, out, "Difficulty", difficulties)
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		if difficulty != "" {
+			if data, err = appendToTopLevelSequence(data, "tags", difficulty); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if strings.TrimSpace(challenge.Author) == "" {
+		fmt.Fprintf(out, "📝 %s: field 'author' is missing\n", path)
+		author, err := ask(in, out, "Author")
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		if author != "" {
+			if data, err = setTopLevelScalar(data, "author", strconv.Quote(author)); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if string(data) == original {
+		return false, nil
+	}
+	return true, os.WriteFile(path, data, 0644)
+}
+
+// ask prints a prompt and returns the trimmed answer
+func ask(in *bufio.Reader, out io.Writer, prompt string) (string, error) {
+	fmt.Fprintf(out, "%s (empty to skip): ", prompt)
+	line, err := in.ReadString('\n')
+	return strings.TrimSpace(line), err
+}
+
+// pick shows numbered choices and returns the chosen one. Answers may be the
+// number of a choice or a value typed out in full.
+func pick(in *bufio.Reader, out io.Writer, prompt string, choices []string) (string, error) {
+	for i, choice := range choices {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, choice)
+	}
+	for {
+		answer, err := ask(in, out, prompt)
+		if answer == "" {
+			return "", err
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil {
+			if n >= 1 && n <= len(choices) {
+				return choices[n-1], nil
+			}
+			fmt.Fprintf(out, "Please enter a number between 1 and %d\n", len(choices))
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// categoryChoices lists the categories of lintrc.yaml profiles and the defaults
+func categoryChoices(config *LintConfig) []string {
+	seen := make(map[string]bool)
+	var choices []string
+	var configured []string
+	for category := range config.Profiles {
+		configured = append(configured, strings.ToLower(category))
+	}
+	sort.Strings(configured)
+	for _, category := range append(configured, defaultCategories...) {
+		if !seen[category] {
+			seen[category] = true
+			choices = append(choices, category)
+		}
+	}
+	return choices
+}
+
+// difficultyChoices returns the values of the first static tag pattern, which
+// the default lintrc.yaml uses for difficulty
+func difficultyChoices(tagRule Rule) []string {
+	for _, pattern := range tagRule.Patterns {
+		if pattern.Type == "static" && len(pattern.Values) > 0 {
+			return pattern.Values
+		}
+	}
+	return defaultDifficulties
+}
+
+func hasAnyTag(tags, values []string) bool {
+	for _, tag := range tags {
+		for _, value := range values {
+			if tag == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptMissingFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "challenge.yml")
+	input := "name: \"chall\"\ntags:\n  - \"author: alice\"\nstate: visible # release\n"
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+	config := &LintConfig{
+		Tags: Rule{Condition: "and", Patterns: []Pattern{{Type: "static", Values: []string{"beginner", "easy", "hard"}}}},
+	}
+
+	// Category by number, an out of range difficulty then a valid one, author typed out
+	answers := "1\n9\n2\nアリス\n"
+	var out bytes.Buffer
+	changed, err := promptMissingFields(path, config, bufio.NewReader(strings.NewReader(answers)), &out)
+	if err != nil {
+		t.Fatalf("promptMissingFields failed: %v", err)
+	}
+	if !changed {
+		t.Fatal("Expected challenge.yml to be changed")
+	}
+
+	got, _ := os.ReadFile(path)
+	want := "name: \"chall\"\ntags:\n  - \"author: alice\"\n  - \"easy\"\nstate: visible # release\ncategory: \"web\"\nauthor: \"アリス\"\n"
+	if string(got) != want {
+		t.Errorf("challenge.yml =\n%s\nwant\n%s", got, want)
+	}
+	if !strings.Contains(out.String(), "Please enter a number between 1 and 3") {
+		t.Errorf("Expected out of range answer to be rejected, got:\n%s", out.String())
+	}
+
+	// Nothing left to ask for
+	changed, err = promptMissingFields(path, config, bufio.NewReader(strings.NewReader("")), &out)
+	if err != nil || changed {
+		t.Errorf("Expected no changes on a complete challenge, got %v (%v)", changed, err)
+	}
+}
+
+func TestPromptMissingFieldsSkip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "challenge.yml")
+	input := "name: chall\n"
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+
+	var out bytes.Buffer
+	changed, err := promptMissingFields(path, &LintConfig{}, bufio.NewReader(strings.NewReader("\n")), &out)
+	if err != nil {
+		t.Fatalf("promptMissingFields failed: %v", err)
+	}
+	if changed {
+		t.Error("Expected skipped and unanswered prompts to leave the file untouched")
+	}
+	if !strings.Contains(out.String(), "3) crypto") {
+		t.Errorf("Expected default category choices, got:\n%s", out.String())
+	}
+}
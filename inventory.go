@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadInventory reads the infrastructure inventory referenced by lintrc.yaml.
+// The file may be JSON or YAML and contain either a list of hosts or a map
+// with a "hosts" key. Each host may be a plain string or an object with a
+// "host", "hostname" or "name" field.
+func loadInventory(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %v", err)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory: %v", err)
+	}
+	if m, ok := raw.(map[string]interface{}); ok {
+		raw = m["hosts"]
+	}
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("inventory must be a list of hosts or a map with a 'hosts' list")
+	}
+
+	hosts := make(map[string]bool)
+	for _, entry := range entries {
+		switch v := entry.(type) {
+		case string:
+			hosts[strings.ToLower(hostName(v))] = true
+		case map[string]interface{}:
+			for _, key := range []string{"host", "hostname", "name"} {
+				if name, ok := v[key].(string); ok && name != "" {
+					hosts[strings.ToLower(hostName(name))] = true
+					break
+				}
+			}
+		}
+	}
+	return hosts, nil
+}
+
+// hostName extracts the bare hostname from a URL, host:port pair or hostname
+func hostName(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "://") {
+		if u, err := url.Parse(value); err == nil {
+			return u.Hostname()
+		}
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return host
+	}
+	return value
+}
+
+// challengeHosts returns the hostnames declared in the host field
+func challengeHosts(host interface{}) []string {
+	switch v := host.(type) {
+	case string:
+		if v != "" {
+			return []string{hostName(v)}
+		}
+	case map[string]interface{}:
+		if name, ok := v["host"].(string); ok && name != "" {
+			return []string{hostName(name)}
+		}
+	}
+	return nil
+}
+
+func checkInventory(host interface{}, config *LintConfig) []string {
+	var errors []string
+
+	if config.Inventory == "" {
+		return errors
+	}
+
+	inventory, err := loadInventory(config.resolvePath(config.Inventory))
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to load inventory '%s': %v", config.Inventory, err))
+		return errors
+	}
+
+	for _, name := range challengeHosts(host) {
+		if !inventory[strings.ToLower(name)] {
+			errors = append(errors, fmt.Sprintf("Field 'host' refers to '%s' which is not in the infrastructure inventory", name))
+		}
+	}
+
+	return errors
+}
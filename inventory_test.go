@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckInventory(t *testing.T) {
+	tempDir := t.TempDir()
+
+	yamlInventory := `hosts:
+  - web.ctf.example.com
+  - name: pwn.ctf.example.com
+    ip: 10.0.0.2
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "hosts.yaml"), []byte(yamlInventory), 0644); err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	jsonInventory := `["osint.ctf.example.com", {"hostname": "rev.ctf.example.com"}]`
+	if err := os.WriteFile(filepath.Join(tempDir, "hosts.json"), []byte(jsonInventory), 0644); err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		inventory string
+		host      interface{}
+		wantError string
+	}{
+		{name: "no inventory configured", inventory: "", host: "typo.example.com"},
+		{name: "null host", inventory: "hosts.yaml", host: nil},
+		{name: "url host in yaml inventory", inventory: "hosts.yaml", host: "https://web.ctf.example.com/login"},
+		{name: "host:port in yaml inventory", inventory: "hosts.yaml", host: "pwn.ctf.example.com:1337"},
+		{name: "map host in json inventory", inventory: "hosts.json", host: map[string]interface{}{"host": "REV.ctf.example.com", "port": 9001}},
+		{name: "typo in host", inventory: "hosts.yaml", host: "https://wbe.ctf.example.com", wantError: "'wbe.ctf.example.com' which is not in the infrastructure inventory"},
+		{name: "missing inventory file", inventory: "missing.yaml", host: "web.ctf.example.com", wantError: "Failed to load inventory 'missing.yaml'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &LintConfig{Inventory: tt.inventory, baseDir: tempDir}
+			errs := checkInventory(tt.host, config)
+			if tt.wantError == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no errors, but got: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0], tt.wantError) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantError, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// createIssues is set by --create-issues; tracking issues are only managed
+// when set
+var createIssues bool
+
+// defaultIssueLabel marks the tracking issues clilint manages
+const defaultIssueLabel = "clilint"
+
+// issueMarkerPattern finds the challenge file a tracking issue belongs to
+var issueMarkerPattern = regexp.MustCompile(`<!-- clilint-issue: (.+?) -->`)
+
+// IssuesRule configures the tracking issues opened by --create-issues
+type IssuesRule struct {
+	// Labels are applied to tracking issues and used to find them again (default: clilint)
+	Labels []string `yaml:"labels"`
+}
+
+func (r IssuesRule) labels() []string {
+	if len(r.Labels) == 0 {
+		return []string{defaultIssueLabel}
+	}
+	return r.Labels
+}
+
+// issueMarker identifies the challenge of a tracking issue in its body
+func issueMarker(file string) string {
+	return fmt.Sprintf("<!-- clilint-issue: %s -->", filepath.ToSlash(filepath.Clean(file)))
+}
+
+// trackIssues keeps one open issue per challenge with error-level findings:
+// it opens missing issues (assigned to the challenge's authors), updates the
+// findings of existing ones, and closes those whose challenge now passes or
+// was not linted, e.g. because it was deleted. Failures are reported as
+// warnings so that the lint run itself is unaffected.
+func trackIssues(results []LintResult) {
+	if !createIssues {
+		return
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	repository := os.Getenv("INPUT_REPOSITORY")
+	if repository == "" {
+		repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	owner, repo, ok := strings.Cut(repository, "/")
+	if token == "" || !ok {
+		log.Printf("Warning: --create-issues needs GITHUB_TOKEN and GITHUB_REPOSITORY (owner/repo)")
+		return
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Printf("Warning: failed to create issues: %v", err)
+		return
+	}
+	if err := syncIssues(token, owner, repo, results, config); err != nil {
+		log.Printf("Warning: failed to create issues: %v", err)
+	}
+}
+
+func syncIssues(token, owner, repo string, results []LintResult, config *LintConfig) error {
+	client, ctx := getGitHubClient(token)
+	labels := config.Issues.labels()
+
+	// Open tracking issues by challenge file
+	open := make(map[string]*github.Issue)
+	opt := &github.IssueListByRepoOptions{State: "open", Labels: labels, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, opt)
+		if err != nil {
+			return fmt.Errorf("error listing issues: %v", err)
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			if match := issueMarkerPattern.FindStringSubmatch(issue.GetBody()); match != nil {
+				open[match[1]] = issue
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	closeIssue := func(file string, issue *github.Issue, comment string) error {
+		if _, _, err := client.Issues.CreateComment(ctx, owner, repo, issue.GetNumber(), &github.IssueComment{Body: &comment}); err != nil {
+			return fmt.Errorf("error commenting on issue #%d: %v", issue.GetNumber(), err)
+		}
+		if _, _, err := client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{State: github.String("closed")}); err != nil {
+			return fmt.Errorf("error closing issue #%d: %v", issue.GetNumber(), err)
+		}
+		fmt.Printf("✅ %s: closed issue #%d\n", file, issue.GetNumber())
+		return nil
+	}
+
+	linted := make(map[string]bool)
+	for _, result := range results {
+		key := filepath.ToSlash(filepath.Clean(result.File))
+		linted[key] = true
+		issue, tracked := open[key]
+
+		if len(result.Errors) == 0 {
+			if !tracked {
+				continue
+			}
+			if err := closeIssue(result.File, issue, "✅ All error-level findings are resolved."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		title := issueTitle(result)
+		body := issueBody(result)
+		if tracked {
+			if issue.GetTitle() == title && issue.GetBody() == body {
+				continue
+			}
+			if _, _, err := client.Issues.Edit(ctx, owner, repo, issue.GetNumber(), &github.IssueRequest{Title: &title, Body: &body}); err != nil {
+				return fmt.Errorf("error updating issue #%d: %v", issue.GetNumber(), err)
+			}
+			fmt.Printf("📝 %s: updated issue #%d\n", result.File, issue.GetNumber())
+			continue
+		}
+
+		created, _, err := client.Issues.Create(ctx, owner, repo, &github.IssueRequest{Title: &title, Body: &body, Labels: &labels})
+		if err != nil {
+			return fmt.Errorf("error creating issue for %s: %v", result.File, err)
+		}
+		fmt.Printf("🐛 %s: opened issue #%d\n", result.File, created.GetNumber())
+
+		// Assigning fails for users without access to the repository, which
+		// should not prevent tracking the challenge
+		var assignees []string
+		for _, author := range groupByAuthor([]LintResult{result}, config.Authors) {
+			if author.handle != "" {
+				assignees = append(assignees, author.handle)
+			}
+		}
+		if len(assignees) > 0 {
+			if _, _, err := client.Issues.AddAssignees(ctx, owner, repo, created.GetNumber(), assignees); err != nil {
+				log.Printf("Warning: failed to assign issue #%d to %s: %v", created.GetNumber(), strings.Join(assignees, ", "), err)
+			}
+		}
+	}
+
+	// Issues of challenges without a result were deleted or moved away
+	var stale []string
+	for key := range open {
+		if !linted[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	for _, key := range stale {
+		if err := closeIssue(key, open[key], fmt.Sprintf("✅ `%s` is no longer linted: the challenge was removed or moved.", key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueTitle names the challenge by its path only, so that the title stays
+// the same as the findings change
+func issueTitle(result LintResult) string {
+	return fmt.Sprintf("clilint: errors in %s", filepath.ToSlash(filepath.Clean(result.File)))
+}
+
+// issueBody lists the error-level findings of a challenge, followed by the
+// marker tracking issues are found by
+func issueBody(result LintResult) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "`%s` has %d error-level finding(s) on the default branch:\n\n", filepath.ToSlash(result.File), len(result.Errors))
+	for _, finding := range result.Errors {
+		fmt.Fprintf(&body, "- ❌ %s", finding.Message)
+		if finding.Docs != "" {
+			fmt.Fprintf(&body, " ([docs](%s))", finding.Docs)
+		}
+		body.WriteString("\n")
+	}
+	body.WriteString("\nThis issue is updated on each run and closed once the findings are resolved.\n\n")
+	body.WriteString(issueMarker(result.File))
+	body.WriteString("\n")
+	return body.String()
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSyncIssues(t *testing.T) {
+	var requests []string
+	created := map[string]interface{}{}
+	edits := map[string]map[string]interface{}{}
+	var assigned []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("labels") != "clilint" || r.URL.Query().Get("state") != "open" {
+			t.Errorf("unexpected issue query %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `[
+			{"number": 1, "title": "clilint: fixed has 1 error(s)", "body": %q},
+			{"number": 2, "title": "clilint: stale has 1 error(s)", "body": %q},
+			{"number": 3, "title": "a PR", "body": %q, "pull_request": {"url": "x"}},
+			{"number": 5, "title": "clilint: errors in web/deleted/challenge.yml", "body": %q}
+		]`, issueMarker("web/fixed/challenge.yml"), issueMarker("web/stale/challenge.yml"), issueMarker("web/new/challenge.yml"), issueMarker("web/deleted/challenge.yml"))
+	})
+	mux.HandleFunc("POST /repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "create")
+		json.NewDecoder(r.Body).Decode(&created)
+		fmt.Fprint(w, `{"number": 4}`)
+	})
+	mux.HandleFunc("PATCH /repos/owner/repo/issues/{number}", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "edit "+r.PathValue("number"))
+		var edit map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&edit)
+		edits[r.PathValue("number")] = edit
+		fmt.Fprintf(w, `{"number": %s}`, r.PathValue("number"))
+	})
+	mux.HandleFunc("POST /repos/owner/repo/issues/{number}/comments", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, "comment "+r.PathValue("number"))
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("POST /repos/owner/repo/issues/{number}/assignees", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Assignees []string `json:"assignees"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		assigned = body.Assignees
+		fmt.Fprint(w, `{}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	results := []LintResult{
+		{File: "web/fixed/challenge.yml", Name: "fixed"},
+		{File: "web/stale/challenge.yml", Name: "stale", Errors: []Finding{{RuleID: "flags", Message: "Flag format is invalid"}}},
+		{File: "web/new/challenge.yml", Name: "new", author: "alice, @bob, carol", Errors: []Finding{{RuleID: "author", Message: "Field 'author' is required"}}},
+		{File: "web/ok/challenge.yml", Name: "ok"},
+	}
+	config := &LintConfig{Authors: AuthorsRule{Handles: map[string]string{"alice": "@alice-gh"}}}
+	if err := syncIssues("token", "owner", "repo", results, config); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{"comment 1", "edit 1", "edit 2", "create", "comment 5", "edit 5"}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+	if edits["1"]["state"] != "closed" {
+		t.Errorf("issue 1 was not closed: %v", edits["1"])
+	}
+	if edits["5"]["state"] != "closed" {
+		t.Errorf("issue 5 of a deleted challenge was not closed: %v", edits["5"])
+	}
+	if body, _ := edits["2"]["body"].(string); !strings.Contains(body, "has 1 error-level finding(s)") || !strings.Contains(body, "- ❌ Flag format is invalid") || !strings.Contains(body, issueMarker("web/stale/challenge.yml")) {
+		t.Errorf("unexpected body of issue 2:\n%s", body)
+	}
+	if created["title"] != "clilint: errors in web/new/challenge.yml" || !reflect.DeepEqual(created["labels"], []interface{}{"clilint"}) {
+		t.Errorf("unexpected created issue %v", created)
+	}
+	sort.Strings(assigned)
+	if want := []string{"alice-gh", "bob"}; !reflect.DeepEqual(assigned, want) {
+		t.Errorf("assignees = %v, want %v", assigned, want)
+	}
+}
+
+func TestIssueTitleIgnoresCount(t *testing.T) {
+	one := LintResult{File: "web/a/challenge.yml", Name: "a", Errors: []Finding{{Message: "x"}}}
+	two := LintResult{File: "web/a/challenge.yml", Name: "renamed", Errors: []Finding{{Message: "x"}, {Message: "y"}}}
+	if issueTitle(one) != issueTitle(two) {
+		t.Errorf("title changed with the findings: %q, %q", issueTitle(one), issueTitle(two))
+	}
+}
+
+func TestSyncIssuesUnchanged(t *testing.T) {
+	result := LintResult{File: "web/stale/challenge.yml", Name: "stale", Errors: []Finding{{Message: "Flag format is invalid"}}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"number": 2, "title": %q, "body": %q}]`, issueTitle(result), issueBody(result))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	if err := syncIssues("token", "owner", "repo", []LintResult{result}, &LintConfig{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// K8sObject represents the parts of a Kubernetes manifest the linter inspects
+type K8sObject struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   K8sMetadata            `yaml:"metadata"`
+	Spec       map[string]interface{} `yaml:"spec"`
+	Data       map[string]interface{} `yaml:"data"`
+	StringData map[string]interface{} `yaml:"stringData"`
+}
+
+type K8sMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// secretEnvPattern matches environment variable names that usually carry secrets
+var secretEnvPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|flag)`)
+
+// checkKubernetesManifests validates Kubernetes manifests and kCTF challenge.yaml
+// files found in the challenge directory
+func checkKubernetesManifests(challengePath string, challenge Challenge) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+	slug := slugify(challenge.Name)
+
+	_ = filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			// Nested challenges are linted on their own
+			if path != baseDir {
+				if _, err := os.Stat(filepath.Join(path, "challenge.yml")); err == nil {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if (ext != ".yaml" && ext != ".yml") || info.Name() == "challenge.yml" {
+			return nil
+		}
+
+		objects, err := readK8sObjects(path)
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(baseDir, path)
+		for _, obj := range objects {
+			errors = append(errors, checkK8sObject(relPath, obj, slug)...)
+		}
+		return nil
+	})
+
+	return errors
+}
+
+// readK8sObjects decodes every document in a YAML file that looks like a Kubernetes object
+func readK8sObjects(path string) ([]K8sObject, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var objects []K8sObject
+	decoder := yaml.NewDecoder(file)
+	for {
+		var obj K8sObject
+		err := decoder.Decode(&obj)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.APIVersion != "" && obj.Kind != "" {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+func checkK8sObject(file string, obj K8sObject, slug string) []string {
+	var errors []string
+	prefix := fmt.Sprintf("Kubernetes manifest '%s' (%s '%s')", file, obj.Kind, obj.Metadata.Name)
+
+	if obj.Metadata.Namespace != "" && slug != "" && obj.Metadata.Namespace != slug {
+		errors = append(errors, fmt.Sprintf("%s: namespace '%s' does not match challenge slug '%s'", prefix, obj.Metadata.Namespace, slug))
+	}
+
+	if obj.Kind == "Secret" && (len(obj.Data) > 0 || len(obj.StringData) > 0) {
+		errors = append(errors, fmt.Sprintf("%s: secret values must not be inlined in the repository", prefix))
+	}
+
+	for _, container := range k8sContainers(obj) {
+		name, _ := container["name"].(string)
+		limits := nestedMap(container, "resources", "limits")
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := limits[resource]; !ok {
+				errors = append(errors, fmt.Sprintf("%s: container '%s' has no resources.limits.%s", prefix, name, resource))
+			}
+		}
+
+		env, _ := container["env"].([]interface{})
+		for _, item := range env {
+			envVar, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			envName, _ := envVar["name"].(string)
+			if _, inlined := envVar["value"]; inlined && secretEnvPattern.MatchString(envName) {
+				errors = append(errors, fmt.Sprintf("%s: container '%s' inlines secret env '%s', use valueFrom.secretKeyRef instead", prefix, name, envName))
+			}
+		}
+	}
+
+	return errors
+}
+
+// k8sContainers returns the container specs of workload objects, including kCTF challenges
+func k8sContainers(obj K8sObject) []map[string]interface{} {
+	var podSpec map[string]interface{}
+	switch obj.Kind {
+	case "Pod":
+		podSpec = obj.Spec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		podSpec = nestedMap(obj.Spec, "template", "spec")
+	case "CronJob":
+		podSpec = nestedMap(obj.Spec, "jobTemplate", "spec", "template", "spec")
+	case "Challenge":
+		// kCTF: apiVersion kctf.dev/v1
+		podSpec = nestedMap(obj.Spec, "podTemplate", "template", "spec")
+	}
+
+	var containers []map[string]interface{}
+	for _, key := range []string{"initContainers", "containers"} {
+		list, _ := podSpec[key].([]interface{})
+		for _, item := range list {
+			if container, ok := item.(map[string]interface{}); ok {
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
+
+func nestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	current := m
+	for _, key := range keys {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// slugify converts a challenge name to its lowercase, hyphen-separated slug
+func slugify(name string) string {
+	var slug strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			slug.WriteRune(r)
+			lastHyphen = false
+		} else if !lastHyphen {
+			slug.WriteRune('-')
+			lastHyphen = true
+		}
+	}
+	return strings.TrimSuffix(slug.String(), "-")
+}
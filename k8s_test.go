@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckKubernetesManifests(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifest   string
+		wantErrors []string
+	}{
+		{
+			name: "deployment with limits in matching namespace",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: web-chall
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          resources:
+            limits:
+              cpu: 500m
+              memory: 256Mi
+`,
+			wantErrors: []string{},
+		},
+		{
+			name: "missing limits and wrong namespace",
+			manifest: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: other
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          resources:
+            limits:
+              cpu: 500m
+`,
+			wantErrors: []string{
+				"namespace 'other' does not match challenge slug 'web-chall'",
+				"container 'app' has no resources.limits.memory",
+			},
+		},
+		{
+			name: "inlined secret in multi document file",
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: flag
+stringData:
+  flag: flag{leaked}
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bot
+spec:
+  containers:
+    - name: bot
+      env:
+        - name: ADMIN_PASSWORD
+          value: hunter2
+      resources:
+        limits:
+          cpu: 100m
+          memory: 64Mi
+`,
+			wantErrors: []string{
+				"secret values must not be inlined",
+				"inlines secret env 'ADMIN_PASSWORD'",
+			},
+		},
+		{
+			name: "kctf challenge without limits",
+			manifest: `apiVersion: kctf.dev/v1
+kind: Challenge
+metadata:
+  name: web-chall
+spec:
+  podTemplate:
+    template:
+      spec:
+        containers:
+          - name: challenge
+`,
+			wantErrors: []string{
+				"container 'challenge' has no resources.limits.cpu",
+				"container 'challenge' has no resources.limits.memory",
+			},
+		},
+		{
+			name:       "non manifest yaml is ignored",
+			manifest:   "foo: bar\n",
+			wantErrors: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			challengePath := filepath.Join(dir, "challenge.yml")
+			if err := os.WriteFile(challengePath, []byte("name: Web Chall\n"), 0644); err != nil {
+				t.Fatalf("Failed to create challenge.yml: %v", err)
+			}
+			if err := os.MkdirAll(filepath.Join(dir, "k8s"), 0755); err != nil {
+				t.Fatalf("Failed to create k8s directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "k8s", "manifest.yaml"), []byte(tt.manifest), 0644); err != nil {
+				t.Fatalf("Failed to create manifest: %v", err)
+			}
+
+			errs := checkKubernetesManifests(challengePath, Challenge{Name: "Web Chall"})
+			if len(tt.wantErrors) == 0 && len(errs) != 0 {
+				t.Errorf("Expected no errors, but got: %v", errs)
+			}
+			for _, want := range tt.wantErrors {
+				found := false
+				for _, got := range errs {
+					if strings.Contains(got, want) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected error containing '%s' not found in: %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Web Chall":          "web-chall",
+		"sample_chall":       "sample-chall",
+		"  Hello,  World!! ": "hello-world",
+		"OSINT-101":          "osint-101",
+	}
+	for input, want := range tests {
+		if got := slugify(input); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CategoryDirectoryRule configures the check that a challenge's category
+// matches the directory it is filed under (<category>/<challenge>/challenge.yml)
+type CategoryDirectoryRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Mapping gives the category expected for a directory when it differs
+	// from the directory name, e.g. osint: OSINT. Unmapped directories must
+	// equal the category, ignoring case.
+	Mapping map[string]string `yaml:"mapping"`
+}
+
+// categoryDirectory returns the name of the directory containing the
+// challenge directory, "" when the challenge is at the filesystem root
+func categoryDirectory(challengePath string) string {
+	absPath, err := filepath.Abs(challengePath)
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Dir(filepath.Dir(absPath))
+	if dir == filepath.Dir(dir) {
+		return ""
+	}
+	return filepath.Base(dir)
+}
+
+func checkCategoryDirectory(challengePath, category string, rule CategoryDirectoryRule) []string {
+	var errors []string
+
+	if !rule.Enabled {
+		return errors
+	}
+	dir := categoryDirectory(challengePath)
+	if dir == "" {
+		return errors
+	}
+
+	if expected, ok := rule.Mapping[dir]; ok {
+		if category != expected {
+			errors = append(errors, fmt.Sprintf("Field 'category' should be '%s' for challenges in directory '%s', got '%s'", expected, dir, category))
+		}
+		return errors
+	}
+	if !strings.EqualFold(category, dir) {
+		errors = append(errors, fmt.Sprintf("Field 'category' is '%s' but the challenge is in directory '%s'", category, dir))
+	}
+	return errors
+}
+
+// NameSlugRule configures the check that the challenge directory is named
+// after the slug of the challenge name
+type NameSlugRule struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// slugRename returns the directory of a challenge and the path it should be
+// renamed to so that its name is the slug of the challenge name. ok is false
+// when the directory already matches or the name has no slug.
+func slugRename(challengePath, name string) (from, to string, ok bool) {
+	slug := slugify(name)
+	dir := filepath.Dir(challengePath)
+	absDir, err := filepath.Abs(dir)
+	if slug == "" || err != nil || filepath.Base(absDir) == slug {
+		return "", "", false
+	}
+	return dir, filepath.Join(dir, "..", slug), true
+}
+
+func checkNameSlug(challengePath, name string, rule NameSlugRule) []string {
+	var errors []string
+
+	if !rule.Enabled {
+		return errors
+	}
+	if from, to, ok := slugRename(challengePath, name); ok {
+		absFrom, _ := filepath.Abs(from)
+		errors = append(errors, fmt.Sprintf("Directory '%s' should be named '%s', the slug of the challenge name", filepath.Base(absFrom), filepath.Base(to)))
+	}
+	return errors
+}
+
+// slugSuggestion returns the command that renames a challenge directory to
+// the slug of its name, for --fix to print. Directories are never renamed
+// automatically, since other files and links may refer to them.
+func slugSuggestion(challengePath, name string, rule NameSlugRule) string {
+	if !rule.Enabled {
+		return ""
+	}
+	from, to, ok := slugRename(challengePath, name)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("git mv %s %s", from, to)
+}
+
+// LayoutRule configures where challenge.yml files may be placed
+type LayoutRule struct {
+	// Pattern is the path of every challenge.yml relative to lintrc.yaml,
+	// e.g. <category>/<challenge>/challenge.yml; other segments are globs
+	Pattern string `yaml:"pattern"`
+	// Categories are the directory names accepted for <category> (default:
+	// the categories of lintrc.yaml, category_directory.mapping, and the
+	// built-in categories)
+	Categories []string `yaml:"categories"`
+}
+
+// layoutCategories returns the directory names accepted for <category>
+func layoutCategories(config *LintConfig) []string {
+	if len(config.Layout.Categories) > 0 {
+		return config.Layout.Categories
+	}
+	var mapped []string
+	for dir := range config.CategoryDirectory.Mapping {
+		mapped = append(mapped, dir)
+	}
+	sort.Strings(mapped)
+	return append(categoryChoices(config), mapped...)
+}
+
+// checkLayout checks that a challenge.yml matches the layout pattern, so
+// that deployment tooling finds it where it expects challenges
+func checkLayout(challengePath string, config *LintConfig) []string {
+	var errors []string
+
+	pattern := config.Layout.Pattern
+	if pattern == "" {
+		return errors
+	}
+	base, err := filepath.Abs(config.baseDir)
+	if err != nil {
+		return errors
+	}
+	absPath, err := filepath.Abs(challengePath)
+	if err != nil {
+		return errors
+	}
+	rel, err := filepath.Rel(base, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		// Challenges outside the repository of lintrc.yaml have no layout
+		return errors
+	}
+	rel = filepath.ToSlash(rel)
+
+	segments := strings.Split(strings.Trim(pattern, "/"), "/")
+	parts := strings.Split(rel, "/")
+	if len(parts) != len(segments) {
+		return append(errors, fmt.Sprintf("challenge.yml is %d directories deep at '%s', but the layout '%s' expects %d", len(parts)-1, rel, pattern, len(segments)-1))
+	}
+
+	categories := layoutCategories(config)
+	for i, segment := range segments {
+		part := parts[i]
+		switch segment {
+		case "<challenge>":
+		case "<category>":
+			known := false
+			for _, category := range categories {
+				if strings.EqualFold(part, category) {
+					known = true
+					break
+				}
+			}
+			if !known {
+				errors = append(errors, fmt.Sprintf("Directory '%s' of '%s' is not a category directory (expected one of: %s)", part, rel, strings.Join(categories, ", ")))
+			}
+		default:
+			if matched, _ := filepath.Match(segment, part); !matched {
+				errors = append(errors, fmt.Sprintf("'%s' does not match the layout '%s' at '%s'", rel, pattern, part))
+			}
+		}
+	}
+	return errors
+}
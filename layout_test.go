@@ -0,0 +1,142 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckCategoryDirectory(t *testing.T) {
+	root := t.TempDir()
+	rule := CategoryDirectoryRule{
+		Enabled: true,
+		Mapping: map[string]string{"osint": "OSINT"},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		category  string
+		rule      CategoryDirectoryRule
+		wantError string
+	}{
+		{name: "matching directory", path: "web/login/challenge.yml", category: "web", rule: rule},
+		{name: "case is ignored without mapping", path: "web/login/challenge.yml", category: "Web", rule: rule},
+		{name: "mismatch", path: "web/login/challenge.yml", category: "pwn", rule: rule, wantError: "is in directory 'web'"},
+		{name: "mapped directory", path: "osint/trace/challenge.yml", category: "OSINT", rule: rule},
+		{name: "mapping is exact", path: "osint/trace/challenge.yml", category: "osint", rule: rule, wantError: "should be 'OSINT'"},
+		{name: "disabled", path: "web/login/challenge.yml", category: "pwn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := checkCategoryDirectory(filepath.Join(root, filepath.FromSlash(tt.path)), tt.category, tt.rule)
+			if tt.wantError == "" {
+				if len(errors) != 0 {
+					t.Errorf("expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.wantError) {
+				t.Errorf("expected error containing %q, got %v", tt.wantError, errors)
+			}
+		})
+	}
+}
+
+func TestCheckNameSlug(t *testing.T) {
+	root := t.TempDir()
+	rule := NameSlugRule{Enabled: true}
+
+	tests := []struct {
+		name      string
+		path      string
+		chall     string
+		rule      NameSlugRule
+		wantError string
+	}{
+		{name: "matching slug", path: "web/sql-injection-101/challenge.yml", chall: "SQL Injection 101", rule: rule},
+		{name: "mismatch", path: "web/sqli/challenge.yml", chall: "SQL Injection 101", rule: rule, wantError: "Directory 'sqli' should be named 'sql-injection-101'"},
+		{name: "name without slug", path: "web/chall/challenge.yml", chall: "🚩", rule: rule},
+		{name: "disabled", path: "web/sqli/challenge.yml", chall: "SQL Injection 101"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := checkNameSlug(filepath.Join(root, filepath.FromSlash(tt.path)), tt.chall, tt.rule)
+			if tt.wantError == "" {
+				if len(errors) != 0 {
+					t.Errorf("expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.wantError) {
+				t.Errorf("expected error containing %q, got %v", tt.wantError, errors)
+			}
+		})
+	}
+}
+
+func TestSlugSuggestion(t *testing.T) {
+	rule := NameSlugRule{Enabled: true}
+
+	got := slugSuggestion(filepath.Join("web", "sqli", "challenge.yml"), "SQL Injection 101", rule)
+	want := "git mv " + filepath.Join("web", "sqli") + " " + filepath.Join("web", "sql-injection-101")
+	if got != want {
+		t.Errorf("slugSuggestion() = %q, want %q", got, want)
+	}
+
+	if got := slugSuggestion(filepath.Join("web", "sql-injection-101", "challenge.yml"), "SQL Injection 101", rule); got != "" {
+		t.Errorf("expected no suggestion for a matching directory, got %q", got)
+	}
+	if got := slugSuggestion(filepath.Join("web", "sqli", "challenge.yml"), "SQL Injection 101", NameSlugRule{}); got != "" {
+		t.Errorf("expected no suggestion when disabled, got %q", got)
+	}
+}
+
+func TestCheckLayout(t *testing.T) {
+	root := t.TempDir()
+	config := &LintConfig{
+		baseDir:           root,
+		Layout:            LayoutRule{Pattern: "<category>/<challenge>/challenge.yml"},
+		CategoryDirectory: CategoryDirectoryRule{Mapping: map[string]string{"hardware": "Hardware"}},
+	}
+	custom := &LintConfig{
+		baseDir: root,
+		Layout:  LayoutRule{Pattern: "challenges/<category>/<challenge>/challenge.yml", Categories: []string{"web"}},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		config    *LintConfig
+		wantError string
+	}{
+		{name: "expected layout", path: "web/login/challenge.yml", config: config},
+		{name: "category case is ignored", path: "Pwn/heap/challenge.yml", config: config},
+		{name: "mapped directory", path: "hardware/uart/challenge.yml", config: config},
+		{name: "too deep", path: "web/login/v2/challenge.yml", config: config, wantError: "is 3 directories deep at 'web/login/v2/challenge.yml'"},
+		{name: "too shallow", path: "login/challenge.yml", config: config, wantError: "expects 2"},
+		{name: "not a category", path: "drafts/login/challenge.yml", config: config, wantError: "Directory 'drafts' of 'drafts/login/challenge.yml' is not a category directory"},
+		{name: "literal segment", path: "challenges/web/login/challenge.yml", config: custom},
+		{name: "literal mismatch", path: "archive/web/login/challenge.yml", config: custom, wantError: "does not match the layout"},
+		{name: "configured categories", path: "challenges/pwn/heap/challenge.yml", config: custom, wantError: "(expected one of: web)"},
+		{name: "outside the repository", path: "../elsewhere/challenge.yml", config: config},
+		{name: "disabled", path: "login/challenge.yml", config: &LintConfig{baseDir: root}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := checkLayout(filepath.Join(root, filepath.FromSlash(tt.path)), tt.config)
+			if tt.wantError == "" {
+				if len(errors) != 0 {
+					t.Errorf("expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || !strings.Contains(errors[0], tt.wantError) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantError, errors)
+			}
+		})
+	}
+}
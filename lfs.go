@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lfsPointerPrefix is the first line of every Git LFS pointer file
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+// LFSRule configures the Git LFS policy for attachments
+type LFSRule struct {
+	Threshold int64 `yaml:"threshold"` // attachments larger than this many bytes must be tracked by LFS (0 disables)
+}
+
+// gitAttributesPattern is an LFS-tracked pattern from a .gitattributes file
+type gitAttributesPattern struct {
+	dir     string // directory containing the .gitattributes file
+	pattern string
+}
+
+// isLFSPointer reports whether the file is a Git LFS pointer instead of real content
+func isLFSPointer(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, len(lfsPointerPrefix))
+	n, _ := file.Read(head)
+	return bytes.Equal(head[:n], []byte(lfsPointerPrefix))
+}
+
+// loadLFSPatterns collects filter=lfs patterns from .gitattributes files in dir
+// and its parents, stopping at the repository root
+func loadLFSPatterns(dir string) []gitAttributesPattern {
+	var patterns []gitAttributesPattern
+
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return patterns
+	}
+	for {
+		patterns = append(patterns, readLFSPatterns(current)...)
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return patterns
+}
+
+func readLFSPatterns(dir string) []gitAttributesPattern {
+	var patterns []gitAttributesPattern
+
+	file, err := os.Open(filepath.Join(dir, ".gitattributes"))
+	if err != nil {
+		return patterns
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				patterns = append(patterns, gitAttributesPattern{dir: dir, pattern: fields[0]})
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+// isLFSTracked reports whether any .gitattributes pattern assigns filter=lfs to path
+func isLFSTracked(path string, patterns []gitAttributesPattern) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, p := range patterns {
+		relPath, err := filepath.Rel(p.dir, absPath)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			continue
+		}
+		if matchGitPattern(p.pattern, filepath.ToSlash(relPath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGitPattern implements the subset of gitignore-style matching used in
+// .gitattributes: patterns without a slash match the basename, others match
+// the path relative to the .gitattributes directory, and "**" spans directories
+func matchGitPattern(pattern, relPath string) bool {
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		matched, _ := filepath.Match(pattern, filepath.Base(relPath))
+		return matched
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	return matchDoubleStar(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+func matchDoubleStar(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchDoubleStar(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	matched, _ := filepath.Match(pattern[0], parts[0])
+	return matched && matchDoubleStar(pattern[1:], parts[1:])
+}
+
+func checkLFS(challengePath string, files []string, rule LFSRule) []string {
+	var errors []string
+	baseDir := filepath.Dir(challengePath)
+
+	var patterns []gitAttributesPattern
+	if rule.Threshold > 0 {
+		patterns = loadLFSPatterns(baseDir)
+	}
+
+	for _, file := range files {
+		fullPath := filepath.Join(baseDir, normalizeFilePath(file))
+		fileInfo, err := os.Stat(fullPath)
+		if err != nil {
+			continue
+		}
+
+		if isLFSPointer(fullPath) {
+			errors = append(errors, fmt.Sprintf("File '%s' is a Git LFS pointer, players would download the pointer instead of the attachment", file))
+			continue
+		}
+
+		if rule.Threshold > 0 && fileInfo.Size() > rule.Threshold && !isLFSTracked(fullPath, patterns) {
+			errors = append(errors, fmt.Sprintf("File '%s' is larger than %d bytes and must be tracked by Git LFS", file, rule.Threshold))
+		}
+	}
+
+	return errors
+}
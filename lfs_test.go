@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckLFS(t *testing.T) {
+	repoDir := t.TempDir()
+	challengeDir := filepath.Join(repoDir, "osint", "chall")
+	if err := os.MkdirAll(filepath.Join(challengeDir, "public"), 0755); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(repoDir, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git: %v", err)
+	}
+	gitattributes := "*.zip filter=lfs diff=lfs merge=lfs -text\nosint/**/public/*.pcap filter=lfs diff=lfs merge=lfs -text\n*.txt text\n"
+	if err := os.WriteFile(filepath.Join(repoDir, ".gitattributes"), []byte(gitattributes), 0644); err != nil {
+		t.Fatalf("Failed to create .gitattributes: %v", err)
+	}
+
+	big := strings.Repeat("A", 2048)
+	pointer := lfsPointerPrefix + "\noid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\nsize 12345\n"
+	fixtures := map[string]string{
+		"public/big.zip":   big,
+		"public/big.pcap":  big,
+		"public/big.txt":   big,
+		"public/small.txt": "small",
+		"public/ptr.png":   pointer,
+	}
+	for name, content := range fixtures {
+		if err := os.WriteFile(filepath.Join(challengeDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	challengePath := filepath.Join(challengeDir, "challenge.yml")
+
+	tests := []struct {
+		name       string
+		files      []string
+		rule       LFSRule
+		wantErrors []string
+	}{
+		{name: "threshold disabled", files: []string{"public/big.txt"}},
+		{name: "tracked by extension", files: []string{"public/big.zip"}, rule: LFSRule{Threshold: 1024}},
+		{name: "tracked by double star path", files: []string{"public/big.pcap"}, rule: LFSRule{Threshold: 1024}},
+		{name: "below threshold", files: []string{"public/small.txt"}, rule: LFSRule{Threshold: 1024}},
+		{name: "large and untracked", files: []string{"public/big.txt"}, rule: LFSRule{Threshold: 1024}, wantErrors: []string{"File 'public/big.txt' is larger than 1024 bytes and must be tracked by Git LFS"}},
+		{name: "pointer file listed", files: []string{"public/ptr.png"}, wantErrors: []string{"File 'public/ptr.png' is a Git LFS pointer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkLFS(challengePath, tt.files, tt.rule)
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got: %v", len(tt.wantErrors), errs)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("Expected error containing '%s', got: %s", want, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatchGitPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.zip", "a/b/c.zip", true},
+		{"*.zip", "a/b/c.tar", false},
+		{"/dist/*.bin", "dist/a.bin", true},
+		{"/dist/*.bin", "x/dist/a.bin", false},
+		{"**/public/*", "web/chall/public/a.png", true},
+		{"web/**/*.png", "web/a.png", true},
+	}
+	for _, tt := range tests {
+		if got := matchGitPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGitPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// maxLintRequestSize bounds the body of a POST /lint request, and the
+// extracted size of an uploaded tarball
+const maxLintRequestSize = 32 * 1024 * 1024
+
+// lintConfigFile is the lintrc.yaml used instead of the working directory's
+// while "clilint serve" lints submitted challenges
+var lintConfigFile string
+
+// pinnedLintConfig is set while "clilint serve" lints content it does not
+// trust: without lintConfigFile the defaults apply, never a lintrc.yaml of
+// the content
+var pinnedLintConfig bool
+
+// untrustedRoot is the directory of a submission while "clilint serve" lints
+// it: includes and files must stay inside it and rules reaching the network
+// are skipped
+var untrustedRoot string
+
+// networkRules reach the network or storage with the server's credentials,
+// so they are skipped for submitted content
+var networkRules = map[string]bool{
+	"remote-files":   true,
+	"external-files": true,
+	"release-assets": true,
+	"image-size":     true,
+	"image-registry": true,
+	"host-live":      true,
+	"malware":        true,
+}
+
+// lintCredential is the bearer token POST /lint requires: the lint token,
+// else the webhook secret, or "" when /lint is disabled
+func (s *webhookServer) lintCredential() string {
+	if s.lintToken != "" {
+		return s.lintToken
+	}
+	return string(s.secret)
+}
+
+// authorizeLint reports whether the request carries the lint credential as
+// "Authorization: Bearer <token>"
+func (s *webhookServer) authorizeLint(r *http.Request) bool {
+	credential := s.lintCredential()
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && credential != "" && subtle.ConstantTimeCompare([]byte(token), []byte(credential)) == 1
+}
+
+// handleLint lints a submitted challenge and responds with the --json output.
+// The body is either a challenge.yml, linted as <path>/challenge.yml (path
+// from the query, default "challenge"), or a tar or tar.gz of a challenge
+// directory or repository. Submissions are linted with the server's
+// lintrc.yaml, without the rules in networkRules, and their attachments are
+// parsed in the sandbox.
+func (s *webhookServer) handleLint(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeLint(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxLintRequestSize))
+
+	tempDir, err := os.MkdirTemp("", "clilint-lint-")
+	if err != nil {
+		http.Error(w, "failed to create work directory", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	if isTarUpload(r, body) {
+		err = extractTar(body, tempDir)
+	} else {
+		err = writeSubmittedChallenge(body, tempDir, r.URL.Query().Get("path"))
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var results []LintResult
+	s.mu.Lock()
+	untrustedRoot = tempDir
+	err = s.withServerConfig(func() error {
+		var err error
+		results, err = lintDirectoriesIn(tempDir, []string{"."}, true)
+		return err
+	})
+	untrustedRoot = ""
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	checkFlagCollisions(results, nil)
+	sortResults(results)
+	output, err := marshalResults(results, hasLintErrors(results))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(output, '\n'))
+}
+
+// isTarUpload reports whether the body is a tarball, by content type or by
+// the gzip magic number
+func isTarUpload(r *http.Request, body *bufio.Reader) bool {
+	switch r.Header.Get("Content-Type") {
+	case "application/x-tar", "application/gzip", "application/x-gzip", "application/x-gtar":
+		return true
+	}
+	magic, err := body.Peek(2)
+	return err == nil && magic[0] == 0x1f && magic[1] == 0x8b
+}
+
+// writeSubmittedChallenge stores a submitted challenge.yml as
+// dir/<challengeDir>/challenge.yml
+func writeSubmittedChallenge(body io.Reader, dir, challengeDir string) error {
+	if challengeDir == "" {
+		challengeDir = "challenge"
+	}
+	target, err := safeJoin(dir, challengeDir)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("empty request body")
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(target, "challenge.yml"), data, 0644)
+}
+
+// extractTar unpacks a tar or tar.gz into dir. Only regular files and
+// directories are extracted; entries escaping dir are rejected and the total
+// extracted size is bounded by maxLintRequestSize.
+func extractTar(body *bufio.Reader, dir string) error {
+	var reader io.Reader = body
+	if magic, err := body.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("invalid gzip: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	archive := tar.NewReader(reader)
+	var total int64
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar: %v", err)
+		}
+		target, err := safeJoin(dir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > maxLintRequestSize {
+				return fmt.Errorf("tarball expands to more than %d bytes", maxLintRequestSize)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, io.LimitReader(archive, header.Size))
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+		// Links and special files are skipped: they could point outside dir
+	}
+}
+
+// confineFiles splits the files entries of a submitted challenge into those
+// inside the submission and errors for absolute entries and entries leaving
+// it, which no rule may stat or read
+func confineFiles(challengePath string, files []string) ([]string, []string) {
+	dir := filepath.Dir(challengePath)
+	if filepath.IsAbs(dir) {
+		dir, _ = filepath.Rel(untrustedRoot, dir)
+	}
+	var confined, errors []string
+	for _, file := range files {
+		if isRemoteFile(file) {
+			confined = append(confined, file)
+			continue
+		}
+		_, err := safeJoin(untrustedRoot, path.Join(filepath.ToSlash(dir), slashPath(file)))
+		if err != nil || path.IsAbs(slashPath(file)) || filepath.IsAbs(normalizeFilePath(file)) {
+			errors = append(errors, fmt.Sprintf("File specified in 'files' is outside the submission: %s", file))
+			continue
+		}
+		confined = append(confined, file)
+	}
+	return confined, errors
+}
+
+// safeJoin joins a slash-separated relative path to dir, rejecting absolute
+// paths and paths that leave dir
+func safeJoin(dir, name string) (string, error) {
+	cleaned := path.Clean("/" + slashPath(name))
+	if path.IsAbs(slashPath(name)) || strings.HasPrefix(path.Clean(slashPath(name)), "..") {
+		return "", fmt.Errorf("invalid path: %s", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const apiChallenge = `name: "api"
+author: "author"
+category: "web"
+description: "desc"
+flags: ["flag{api}"]
+tags: ["easy"]
+value: 100
+type: dynamic
+image: null
+host: null
+state: hidden
+version: "0.1"
+`
+
+type lintResponse struct {
+	Success bool         `json:"success"`
+	Results []LintResult `json:"results"`
+}
+
+func newLintServer(t *testing.T) *webhookServer {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "lintrc.yaml")
+	config := "tags:\n  condition: and\n  patterns:\n    - type: static\n      values: [easy, medium, hard]\nrequirements:\n  condition: none\n"
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Submissions are inspected in the sandbox
+	useTestSandbox(t)
+	server := newWebhookServer(nil, "", t.TempDir())
+	server.configFile = configFile
+	server.lintToken = "lint-token"
+	return server
+}
+
+func postLint(t *testing.T, server *webhookServer, target, contentType string, body []byte) (*httptest.ResponseRecorder, lintResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+server.lintToken)
+	recorder := httptest.NewRecorder()
+	server.routes().ServeHTTP(recorder, req)
+
+	var response lintResponse
+	if recorder.Code == http.StatusOK {
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON response: %v\n%s", err, recorder.Body.String())
+		}
+	}
+	return recorder, response
+}
+
+func TestHandleLintChallengeYAML(t *testing.T) {
+	server := newLintServer(t)
+
+	recorder, response := postLint(t, server, "/lint?path=web/api", "application/yaml", []byte(apiChallenge))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+	if response.Success || len(response.Results) != 1 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+	result := response.Results[0]
+	if result.File != filepath.Join("web", "api", "challenge.yml") {
+		t.Errorf("File = %q", result.File)
+	}
+	messages := findingMessages(result.Errors)
+	if len(messages) != 1 || messages[0] != "Field 'state' should be 'visible'" {
+		t.Errorf("unexpected errors: %v", messages)
+	}
+	if lintConfigFile != "" {
+		t.Error("lintConfigFile not reset after the request")
+	}
+}
+
+func TestHandleLintTarball(t *testing.T) {
+	server := newLintServer(t)
+
+	visible := strings.Replace(apiChallenge, "hidden", "visible", 1)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	files := map[string]string{
+		"web/api/challenge.yml":  visible,
+		"pwn/bof/challenge.yml":  strings.NewReplacer(`tags: ["easy"]`, `tags: ["insane"]`, "flag{api}", "flag{bof}").Replace(visible),
+		"web/api/public/a.txt":   "attachment",
+		"pwn/bof/public/bin.elf": "binary",
+	}
+	for name, content := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive.Close()
+	gz.Close()
+
+	// Detected by the gzip magic number without a content type
+	recorder, response := postLint(t, server, "/lint", "", buf.Bytes())
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+	if len(response.Results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", response.Results)
+	}
+	if response.Results[0].File != filepath.Join("pwn", "bof", "challenge.yml") || len(response.Results[0].Errors) != 1 {
+		t.Errorf("unexpected result for pwn/bof: %+v", response.Results[0])
+	}
+	if len(response.Results[1].Errors) != 0 {
+		t.Errorf("unexpected errors for web/api: %+v", response.Results[1].Errors)
+	}
+}
+
+func TestHandleLintRejects(t *testing.T) {
+	server := newLintServer(t)
+
+	var unsafe bytes.Buffer
+	archive := tar.NewWriter(&unsafe)
+	content := "x"
+	_ = archive.WriteHeader(&tar.Header{Name: "../escape/challenge.yml", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})
+	_, _ = archive.Write([]byte(content))
+	archive.Close()
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        []byte
+		wantStatus  int
+	}{
+		{name: "empty body", target: "/lint", wantStatus: http.StatusBadRequest},
+		{name: "path escaping the work directory", target: "/lint?path=../../etc", body: []byte(apiChallenge), wantStatus: http.StatusBadRequest},
+		{name: "tar entry escaping the work directory", target: "/lint", contentType: "application/x-tar", body: unsafe.Bytes(), wantStatus: http.StatusBadRequest},
+		{name: "too large", target: "/lint", body: bytes.Repeat([]byte("a"), maxLintRequestSize+1), wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, _ := postLint(t, server, tt.target, tt.contentType, tt.body)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleLintRequiresCredential(t *testing.T) {
+	server := newLintServer(t)
+
+	for _, header := range []string{"", "Bearer wrong", "lint-token"} {
+		req := httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader(apiChallenge))
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		server.routes().ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status = %d, want 401", header, recorder.Code)
+		}
+	}
+
+	// The webhook secret is accepted when no lint token is configured
+	server.lintToken = ""
+	server.secret = []byte("webhook-secret")
+	req := httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader(apiChallenge))
+	req.Header.Set("Authorization", "Bearer webhook-secret")
+	recorder := httptest.NewRecorder()
+	server.routes().ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Errorf("status with the webhook secret = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+
+	// Without either, /lint is not served
+	server.secret = nil
+	recorder = httptest.NewRecorder()
+	server.routes().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/lint", strings.NewReader(apiChallenge)))
+	if recorder.Code == http.StatusOK {
+		t.Error("/lint served without a credential")
+	}
+}
+
+func TestHandleLintUntrustedContent(t *testing.T) {
+	server := newLintServer(t)
+
+	// A YAML file of the server that an include must not reach
+	secret := filepath.Join(t.TempDir(), "secret.yml")
+	if err := os.WriteFile(secret, []byte("author: leaked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	visible := strings.Replace(apiChallenge, "hidden", "visible", 1)
+	for _, include := range []string{secret, "../../../../../../.." + secret} {
+		recorder, response := postLint(t, server, "/lint", "application/yaml", []byte("include: "+include+"\n"+visible))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+		}
+		messages := findingMessages(response.Results[0].Errors)
+		if len(messages) != 1 || !strings.Contains(messages[0], "is outside the submission") {
+			t.Errorf("include %s: unexpected errors: %v", include, messages)
+		}
+	}
+
+	// Remote attachments are not fetched
+	remote := strings.Replace(visible, "value: 100", "value: 100\nfiles: [\"http://127.0.0.1:1/internal\"]", 1)
+	recorder, response := postLint(t, server, "/lint", "application/yaml", []byte(remote))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+	if messages := findingMessages(response.Results[0].Errors); len(messages) != 0 {
+		t.Errorf("network rules ran on a submission: %v", messages)
+	}
+	if untrustedRoot != "" {
+		t.Error("untrustedRoot not reset after the request")
+	}
+}
+
+func TestHandleLintConfinesFiles(t *testing.T) {
+	server := newLintServer(t)
+
+	// A file of the server that attachments must not reach
+	secret := filepath.Join(t.TempDir(), "secret.zip")
+	if err := os.WriteFile(secret, []byte("PK\x03\x04"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	visible := strings.Replace(apiChallenge, "hidden", "visible", 1)
+	files := fmt.Sprintf("files: [%q, %q, \"dist/missing.zip\"]\n", secret, "../../../../../../.."+secret)
+	recorder, response := postLint(t, server, "/lint", "application/yaml", []byte(files+visible))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+	}
+	messages := findingMessages(response.Results[0].Errors)
+	want := []string{
+		"File specified in 'files' does not exist: dist/missing.zip",
+		"File specified in 'files' is outside the submission: " + secret,
+		"File specified in 'files' is outside the submission: ../../../../../../.." + secret,
+	}
+	sort.Strings(messages)
+	sort.Strings(want)
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("errors = %q, want %q", messages, want)
+	}
+}
+
+func TestWebhookDisabledWithoutSecret(t *testing.T) {
+	server := newWebhookServer(nil, "", t.TempDir())
+	recorder := httptest.NewRecorder()
+	server.routes().ServeHTTP(recorder, webhookRequest(t, "ping", `{}`, ""))
+	if recorder.Code == http.StatusOK {
+		t.Error("webhook accepted without a configured secret")
+	}
+}
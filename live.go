@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// liveMode is set by --live, the profile for hotfix PRs during the event
+var liveMode bool
+
+// liveErrorRules stay errors under --live: problems that break the running
+// event rather than matters of style
+var liveErrorRules = []string{"config", "read", "yaml", "yaml-aliases", "yaml-limits", "flags", "flag-collision", "files", "freeze", "live"}
+
+// LiveRule configures the --live profile. Security findings, liveErrorRules,
+// and Errors stay errors; other errors are downgraded to warnings. In PR
+// mode, changing the flags or state of a challenge with solves is an error.
+type LiveRule struct {
+	// CTFdURL is the CTFd instance queried for solves, with the admin token
+	// in CTFD_TOKEN. Without it, every challenge counts as solved.
+	CTFdURL string `yaml:"ctfd_url"`
+	// Errors are more rule IDs that stay errors, e.g. hints
+	Errors []string `yaml:"errors"`
+}
+
+// applyLiveProfile downgrades the errors of rules that do not stay errors
+// under --live to warnings
+func applyLiveProfile(results []LintResult, rule LiveRule) {
+	for i := range results {
+		result := &results[i]
+		kept := []Finding{}
+		for _, finding := range result.Errors {
+			if finding.Severity == SeveritySecurity || slices.Contains(liveErrorRules, finding.RuleID) || slices.Contains(rule.Errors, finding.RuleID) {
+				kept = append(kept, finding)
+				continue
+			}
+			finding.Severity = SeverityWarning
+			result.Warnings = append(result.Warnings, finding)
+		}
+		result.Errors = kept
+		sortFindings(result.Warnings)
+	}
+}
+
+// applyLive applies the --live profile, if set, with the config's rule
+func applyLive(results []LintResult) {
+	if !liveMode {
+		return
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lint config: %v", err)
+	}
+	applyLiveProfile(results, config.Live)
+}
+
+// ctfdChallenge is a challenge as listed by CTFd's API
+type ctfdChallenge struct {
+	Name   string `json:"name"`
+	Solves int    `json:"solves"`
+}
+
+// fetchSolves returns the solve count of each challenge of a CTFd instance,
+// by name
+func fetchSolves(client *http.Client, baseURL, token string) (map[string]int, error) {
+	if token == "" {
+		return nil, fmt.Errorf("CTFD_TOKEN is not set")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/api/v1/challenges?view=admin", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Token "+token)
+	req.Header.Set("Content-Type", "application/json")
+	var listing struct {
+		Success bool            `json:"success"`
+		Data    []ctfdChallenge `json:"data"`
+	}
+	if err := doJSON(client, req, &listing); err != nil {
+		return nil, err
+	}
+	if !listing.Success {
+		return nil, fmt.Errorf("CTFd did not list the challenges")
+	}
+	solves := make(map[string]int)
+	for _, challenge := range listing.Data {
+		solves[challenge.Name] = challenge.Solves
+	}
+	return solves, nil
+}
+
+// liveChanges describes the changes to a challenge that --live forbids once
+// it has solves; solves is -1 when unknown
+func liveChanges(old, current Challenge, solves int) []changeError {
+	var changes []changeError
+	if solves == 0 {
+		return changes
+	}
+	solved := "this solved challenge"
+	if solves > 0 {
+		solved = fmt.Sprintf("this challenge solved by %d teams", solves)
+	}
+
+	if note := diffFlags(old.Flags, current.Flags); note != "" {
+		changes = append(changes, changeError{"flags", fmt.Sprintf("Flags of %s changed (%s) during the event", solved, note)})
+	}
+	if old.State != current.State {
+		changes = append(changes, changeError{"state", fmt.Sprintf("Field 'state' of %s changed (%s → %s) during the event", solved, formatDiffValue(old.State), formatDiffValue(current.State))})
+	}
+	return changes
+}
+
+// checkLive reports, with --live, PR changes to the flags or state of
+// challenges that teams have already solved
+func checkLive(env Env, results []LintResult, prFiles []*github.CommitFile) error {
+	if !liveMode {
+		return nil
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		return err
+	}
+
+	var solves map[string]int
+	if config.Live.CTFdURL == "" {
+		log.Printf("Warning: live.ctfd_url is not set; every changed challenge counts as solved")
+	} else if solves, err = fetchSolves(http.DefaultClient, config.Live.CTFdURL, os.Getenv("CTFD_TOKEN")); err != nil {
+		return fmt.Errorf("error getting solves from CTFd: %v", err)
+	}
+
+	client, ctx := getGitHubClient(env.token)
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return fmt.Errorf("error getting PR: %v", err)
+	}
+	return comparePRChallenges(env, pr, results, prFiles, config, func(result *LintResult, file string, old, current Challenge, data []byte) {
+		count := -1
+		if solves != nil {
+			count = solves[old.Name]
+		}
+		for _, change := range liveChanges(old, current, count) {
+			result.addErrorAt("live", change, data)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestApplyLiveProfile(t *testing.T) {
+	results := []LintResult{{
+		File: "web/a/challenge.yml",
+		Errors: []Finding{
+			{RuleID: "state", Severity: SeverityError, Message: "Field 'state' should be 'visible'"},
+			{RuleID: "flags", Severity: SeverityError, Message: "Flag 1 is empty"},
+			{RuleID: "malware", Severity: SeveritySecurity, Message: "Attachment is infected"},
+			{RuleID: "hints", Severity: SeverityError, Message: "Hint 1 is empty"},
+		},
+		Warnings: []Finding{{RuleID: "type", Severity: SeverityWarning, Message: "Field 'type' is 'standard'"}},
+	}}
+	applyLiveProfile(results, LiveRule{Errors: []string{"hints"}})
+
+	var errors, warnings []string
+	for _, finding := range results[0].Errors {
+		errors = append(errors, finding.RuleID)
+	}
+	for _, finding := range results[0].Warnings {
+		warnings = append(warnings, finding.RuleID+":"+finding.Severity)
+	}
+	if want := []string{"flags", "malware", "hints"}; !reflect.DeepEqual(errors, want) {
+		t.Errorf("errors = %v, want %v", errors, want)
+	}
+	if want := []string{"state:warning", "type:warning"}; !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+}
+
+func TestFetchSolves(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/challenges" || r.URL.Query().Get("view") != "admin" || r.Header.Get("Authorization") != "Token secret" {
+			http.Error(w, "unexpected request", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"success": true, "data": [{"id": 1, "name": "web", "solves": 12}, {"id": 2, "name": "pwn", "solves": 0}]}`)
+	}))
+	defer server.Close()
+
+	solves, err := fetchSolves(server.Client(), server.URL+"/", "secret")
+	if err != nil {
+		t.Fatalf("fetchSolves() error: %v", err)
+	}
+	if want := map[string]int{"web": 12, "pwn": 0}; !reflect.DeepEqual(solves, want) {
+		t.Errorf("fetchSolves() = %v, want %v", solves, want)
+	}
+	if _, err := fetchSolves(server.Client(), server.URL, "wrong"); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+	if _, err := fetchSolves(server.Client(), server.URL, ""); err == nil {
+		t.Error("expected an error without a token")
+	}
+}
+
+func TestLiveChanges(t *testing.T) {
+	flag := func(content string) FlagItem { return FlagItem{StringValue: &content} }
+	old := Challenge{State: "visible", Value: 300, Flags: []FlagItem{flag("flag{a}")}}
+	current := Challenge{State: "hidden", Value: 500, Flags: []FlagItem{flag("flag{a}"), flag("flag{b}")}}
+
+	if got := liveChanges(old, current, 0); len(got) != 0 {
+		t.Errorf("unsolved challenge: got %v", got)
+	}
+	want := []changeError{
+		{"flags", "Flags of this challenge solved by 12 teams changed (1 added) during the event"},
+		{"state", "Field 'state' of this challenge solved by 12 teams changed (visible → hidden) during the event"},
+	}
+	if got := liveChanges(old, current, 12); !reflect.DeepEqual(got, want) {
+		t.Errorf("liveChanges() =\n%v\nwant\n%v", got, want)
+	}
+	if got := liveChanges(old, current, -1); len(got) != 2 || got[0].message != "Flags of this solved challenge changed (1 added) during the event" {
+		t.Errorf("unknown solves: got %v", got)
+	}
+}
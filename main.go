@@ -1,15 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v65/github"
 	"golang.org/x/oauth2"
@@ -27,6 +34,10 @@ type Flag struct {
 type FlagItem struct {
 	StringValue *string
 	FlagValue   *Flag
+
+	// invalid describes a flag of the wrong shape, reported by checkFlags
+	// rather than failing the whole document
+	invalid string
 }
 
 // UnmarshalYAML implements custom unmarshaling for FlagItem
@@ -43,6 +54,17 @@ func (f *FlagItem) UnmarshalYAML(value *yaml.Node) error {
 
 	// Check if it's a mapping node (object/map)
 	if value.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, field := value.Content[i], value.Content[i+1]
+			if !flagFields[key.Value] {
+				f.invalid = fmt.Sprintf("has unknown field '%s' (expected type, content, and data)", key.Value)
+				return nil
+			}
+			if field.Kind != yaml.ScalarNode || (field.Tag != "!!str" && field.Tag != "!!null") {
+				f.invalid = fmt.Sprintf("field '%s' must be a string", key.Value)
+				return nil
+			}
+		}
 		var flag Flag
 		if err := value.Decode(&flag); err == nil {
 			f.FlagValue = &flag
@@ -50,7 +72,19 @@ func (f *FlagItem) UnmarshalYAML(value *yaml.Node) error {
 		}
 	}
 
-	return fmt.Errorf("flag must be either a string or a map with type, content, and optional data fields")
+	f.invalid = "must be either a string or a map with type, content, and optional data fields"
+	return nil
+}
+
+// Content returns the flag text regardless of which format the flag uses
+func (f FlagItem) Content() string {
+	if f.StringValue != nil {
+		return *f.StringValue
+	}
+	if f.FlagValue != nil {
+		return f.FlagValue.Content
+	}
+	return ""
 }
 
 // Challenge represents the structure of challenge.yml
@@ -70,7 +104,26 @@ type Challenge struct {
 	Host         interface{}            `yaml:"host"`
 	State        string                 `yaml:"state"`
 	Version      string                 `yaml:"version"`
-	Hints        []interface{}          `yaml:"hints"`
+	Hints        []HintItem             `yaml:"hints"`
+	// Wave is the release wave of the challenge, see Schedule
+	Wave int `yaml:"wave"`
+	// DescriptionJA is the Japanese translation of description, see BilingualRule
+	DescriptionJA string `yaml:"description_ja"`
+	// ConnectionInfo tells players how to reach the challenge, e.g. "nc ${HOST} 1337"
+	ConnectionInfo string `yaml:"connection_info"`
+	// FlagTests are submissions the flags must accept or reject. Like notes,
+	// they are removed from the data exported for the platform.
+	FlagTests *FlagTests `yaml:"flag_tests"`
+	// Prizes are the sponsored prizes of the challenge, see PrizesRule
+	Prizes interface{} `yaml:"prizes"`
+	// Notes are for organizers only, e.g. QA signoff; see NotesRule. They are
+	// removed from the data exported for the platform.
+	Notes interface{} `yaml:"notes"`
+	// Healthcheck is the script checking that the deployed challenge is solvable, see HealthcheckRule
+	Healthcheck string `yaml:"healthcheck"`
+
+	ExternalFiles []ExternalFile `yaml:"external_files"`
+	Build         *BuildSpec     `yaml:"build"`
 }
 
 type Pattern struct {
@@ -85,16 +138,225 @@ type Rule struct {
 }
 
 type LintConfig struct {
-	Tags         Rule `yaml:"tags"`
-	Requirements Rule `yaml:"requirements"`
+	Tags         Rule         `yaml:"tags"`
+	Requirements Rule         `yaml:"requirements"`
+	Inventory    string       `yaml:"inventory"` // optional path to a JSON/YAML list of provisioned hosts
+	Binaries     BinaryRule   `yaml:"binaries"`
+	Checksums    ChecksumRule `yaml:"checksums"`
+	LFS          LFSRule      `yaml:"lfs"`
+	MaxFileSize  int64        `yaml:"max_file_size"` // bytes, defaults to 1 MB
+	AllowImage   bool         `yaml:"allow_image"`
+	RequireHost  bool         `yaml:"require_host"`
+
+	// HostPolicy constrains the scheme and port of host, usually per category
+	HostPolicy HostPolicy `yaml:"host_policy"`
+
+	// Ignore lists gitignore-style patterns of paths to skip while searching for challenges
+	Ignore []string `yaml:"ignore"`
+
+	// CategoryDirectory checks that category matches the challenge's parent directory
+	CategoryDirectory CategoryDirectoryRule `yaml:"category_directory"`
+
+	// NameSlug checks that the challenge directory is the slug of the name
+	NameSlug NameSlugRule `yaml:"name_slug"`
+
+	// Checklist is appended to each challenge in the PR comment for reviewers
+	Checklist []ChecklistItem `yaml:"checklist"`
+
+	// Onboarding is prepended to the PR comment for first-time contributors
+	Onboarding OnboardingRule `yaml:"onboarding"`
+
+	// Signatures requires a verified detached signature next to every attachment
+	Signatures SignaturesRule `yaml:"signatures"`
+
+	// Releases configures the checks of release://tag/asset files entries
+	Releases ReleasesRule `yaml:"releases"`
+
+	// Authors routes PR comments to the authors of the touched challenges
+	Authors AuthorsRule `yaml:"authors"`
+
+	// Reviewers routes challenge PRs to reviewers per category in serve mode
+	Reviewers ReviewersRule `yaml:"reviewers"`
+
+	// Live configures the --live profile for hotfix PRs during the event
+	Live LiveRule `yaml:"live"`
+
+	// Freeze forbids PRs to change the scoring of visible challenges before
+	// and during the event
+	Freeze FreezeRule `yaml:"freeze"`
+
+	// Prizes validates the prizes block of challenges
+	Prizes PrizesRule `yaml:"prizes"`
+
+	// Notes configures the organizer-only notes field
+	Notes NotesRule `yaml:"notes"`
+
+	// Value bounds challenge values and keeps them on a step
+	Value ValueRule `yaml:"value"`
+
+	// Filenames are naming rules for attachments
+	Filenames FilenameRule `yaml:"filenames"`
+
+	// YAMLLimits bounds the size and nesting of challenge.yml files
+	YAMLLimits YAMLLimitsRule `yaml:"yaml_limits"`
+
+	// RequiredVersion constrains the clilint release that may lint with this
+	// config, e.g. ">= 1.4.0, < 2" (checked by clilint version --check)
+	RequiredVersion string `yaml:"required_version"`
+
+	// ConfigVersion is the schema version of this file, upgraded by clilint
+	// config migrate
+	ConfigVersion int `yaml:"config_version"`
+
+	// Policy is the organization's policy bundle this lintrc.yaml builds on
+	Policy PolicyRule `yaml:"policy"`
+
+	// CodeOwners lists the CODEOWNERS owners of failing challenges
+	CodeOwners CodeOwnersRule `yaml:"codeowners"`
+
+	// Healthcheck configures the healthcheck scripts of hosted challenges
+	Healthcheck HealthcheckRule `yaml:"healthcheck"`
+
+	// ImageSize bounds the compressed size and layer count of registry images
+	ImageSize ImageSizeRule `yaml:"image_size"`
+
+	// Bilingual requires English and Japanese descriptions
+	Bilingual BilingualRule `yaml:"bilingual"`
+
+	// Difficulty warns when value, hints, or binaries seem inconsistent with
+	// the difficulty tag
+	Difficulty DifficultyRule `yaml:"difficulty"`
+
+	// Publish is the profile of clilint publish-check, run before the
+	// repository is made public
+	Publish PublishRule `yaml:"publish"`
+
+	// Artifacts lists files that must not be committed in challenge directories
+	Artifacts ArtifactsRule `yaml:"artifacts"`
+
+	// Layout is the expected path of challenge.yml files, e.g.
+	// <category>/<challenge>/challenge.yml
+	Layout LayoutRule `yaml:"layout"`
+
+	// MissingChallenge reports directories that look like challenges but
+	// have no challenge.yml
+	MissingChallenge MissingChallengeRule `yaml:"missing_challenge"`
+
+	// Malware scans attachments with ClamAV or VirusTotal hash lookups
+	Malware MalwareRule `yaml:"malware"`
+
+	// Archives bounds the decompression of zip attachments
+	Archives ArchiveRule `yaml:"archives"`
+
+	// Sandbox sets the limits of content-inspecting rules run by --sandbox
+	Sandbox SandboxRule `yaml:"sandbox"`
+
+	// RuleTimeout bounds each rule on each challenge, e.g. 30s (default 2m);
+	// a rule that takes longer is reported as timed out
+	RuleTimeout time.Duration `yaml:"rule_timeout"`
+
+	// Audit configures the summary posted by --audit
+	Audit AuditRule `yaml:"audit"`
+
+	// Issues configures the tracking issues opened by --create-issues
+	Issues IssuesRule `yaml:"issues"`
+
+	// Sync names the Notion database or Confluence space --sync keeps in sync
+	Sync SyncRule `yaml:"sync"`
+
+	// Simulation sets the team count and solve rates of clilint simulate
+	Simulation SimulationRule `yaml:"simulation"`
+
+	// Readiness weighs rule outcomes and readiness items into the score
+	// gated by clilint readiness
+	Readiness ReadinessRule `yaml:"readiness"`
+
+	// Schedule is a YAML event calendar with start, end, and wave release
+	// times; challenges of unreleased waves must be hidden
+	Schedule string `yaml:"schedule"`
+
+	// Hints configures the cost budget, ordering, and flag leak checks of hints
+	Hints HintsRule `yaml:"hints"`
+
+	// ExtraSchemas describes the extra keys of each challenge type, replacing
+	// the built-in schemas of standard and dynamic
+	ExtraSchemas map[string]map[string]ExtraField `yaml:"extra_schemas"`
+
+	// Template is a challenge.yml whose keys every challenge must contain, in order
+	Template string `yaml:"template"`
+
+	// VarsFile is a YAML map of values for ${VAR} placeholders in host and
+	// connection_info, relative to lintrc.yaml; environment variables win
+	VarsFile string `yaml:"vars_file"`
+
+	// DocsBaseURL is the guideline page findings link to, with the rule's anchor appended
+	DocsBaseURL string `yaml:"docs_base_url"`
+
+	Deprecations []Deprecation `yaml:"deprecations"`
+
+	// Profiles overrides rules for challenges whose category matches the key
+	// (the top-level "categories" before config_version 2)
+	Profiles map[string]CategoryProfile `yaml:"profiles"`
+
+	// baseDir is the directory of the loaded lintrc.yaml, used to resolve relative paths
+	baseDir string
+	// policyDir is the extracted policy bundle, where relative paths missing
+	// from baseDir are looked up
+	policyDir string
+	// policyRules are the CEL rules of the policy bundle
+	policyRules []celRule
+	// sandbox runs the content-inspecting rules in the sandbox, for the
+	// challenge being linted
+	sandbox bool
+}
+
+// CategoryProfile overrides top-level rules for a single category.
+// Unset fields keep the top-level value.
+type CategoryProfile struct {
+	Tags         *Rule       `yaml:"tags"`
+	Requirements *Rule       `yaml:"requirements"`
+	MaxFileSize  *int64      `yaml:"max_file_size"`
+	AllowImage   *bool       `yaml:"allow_image"`
+	RequireHost  *bool       `yaml:"require_host"`
+	HostPolicy   *HostPolicy `yaml:"host_policy"`
 }
 
 type LintResult struct {
-	File        string
-	Errors      []string
-	Warnings    []string
-	Name        string
-	Description string
+	File         string
+	Errors       []Finding
+	Warnings     []Finding
+	Deprecations []Finding
+	Name         string
+	Description  string
+	Fixes        []Fix
+	Checklist    []ChecklistEntry `json:",omitempty"`
+	// Owners are the CODEOWNERS owners of the challenge, see CodeOwnersRule
+	Owners []string `json:",omitempty"`
+
+	// docsBaseURL is the docs_base_url of the config the file was linted with
+	docsBaseURL string
+	// ruleTimeout bounds each rule run on the file
+	ruleTimeout time.Duration
+	// author is the challenge's author field, used to mention it in PR comments
+	author string
+	// category is the challenge's category field, used to route reviews
+	category string
+	// mentionOwners @-mentions Owners in PR comments
+	mentionOwners bool
+	// flags and regexes are the static and regex flags, and flagsAt the
+	// position of the flags field, compared across challenges by
+	// checkFlagCollisions
+	flags   []parsedFlag
+	regexes []*regexp.Regexp
+	flagsAt [2]int
+	// fixDiff is the unified diff of Fixes, computed with --diff
+	fixDiff string
+	// data is the challenge.yml as linted, which for --fetch-contents is the
+	// PR head rather than the local checkout
+	data []byte
+	// challenge is the challenge as linted, for reports that list its
+	// fields; anonymous mode leaves its authors out
+	challenge Challenge
 }
 
 type Env struct {
@@ -108,30 +370,277 @@ type Env struct {
 func main() {
 	if len(os.Args) > 1 && os.Args[1] == "-h" {
 		fmt.Println("Usage: clilint [options] [directory...]")
+		fmt.Println("       clilint <command> [arguments]")
 		fmt.Println("Lints challenge.yml files in the specified directories (default: current directory)")
 		fmt.Println("Options:")
 		fmt.Println("  --json           Output results in JSON format for GitHub Actions")
+		fmt.Println("  --format FORMAT  Output format: text (default), json, or sqlite")
+		fmt.Println("  --output FILE    File written by --format=sqlite (default: clilint.db)")
 		fmt.Println("  --comment-pr     Post results as PR comment (requires GitHub environment)")
+		fmt.Println("  --suggest        With --comment-pr, post fixable findings as review suggestions")
+		fmt.Println("  --changed-lines-only")
+		fmt.Println("                   With --comment-pr, only report findings on fields changed by the PR")
+		fmt.Println("  --fetch-contents With --comment-pr, lint the PR head fetched via the API instead of the checkout")
+		fmt.Println("  --freeze         With --comment-pr, fail changes to flags, value, or files of visible challenges")
+		fmt.Println("  --live           During the event: downgrade style errors to warnings, and with --comment-pr,")
+		fmt.Println("                   fail changes to flags or state of solved challenges")
+		fmt.Println("  --anonymous      Leave author names, CODEOWNERS owners, and notes out of the output and PR comment")
+		fmt.Println("  --fix            Automatically fix state, version, and image fields")
+		fmt.Println("  --diff           Print the fixes as unified diffs instead of writing them; with --comment-pr,")
+		fmt.Println("                   attach them to the comment")
+		fmt.Println("  --interactive    Prompt for missing category, difficulty tag, and author and write them back")
+		fmt.Println("  --max-depth N    Do not search for challenge.yml more than N directories deep")
+		fmt.Println("  --follow-symlinks")
+		fmt.Println("                   Follow symlinked directories when searching for challenge.yml")
+		fmt.Println("  --history FILE   Append a summary of this run to a JSONL history file")
+		fmt.Println("  --audit          Lint every challenge, also checking hosts and registry images, and post a summary")
+		fmt.Println("                   with the changes since the previous audit (issue, DISCORD_WEBHOOK_URL)")
+		fmt.Println("  --sandbox        Run rules parsing attachments in a subprocess without network, confined to a")
+		fmt.Println("                   copy of the challenge, with memory/CPU limits (default for fork PRs)")
+		fmt.Println("  --create-issues  Open, update, and close a tracking issue per challenge with errors (GITHUB_TOKEN)")
+		fmt.Println("  --sync           Upsert each challenge's status into the Notion database or Confluence space in lintrc.yaml")
+		fmt.Println("  --vars FILE      Values for ${VAR} placeholders in host and connection_info (overrides vars_file)")
+		fmt.Println("  --metrics-file FILE")
+		fmt.Println("                   Write challenge, finding, and duration metrics in Prometheus text format")
+		fmt.Println("  --summary-file FILE")
+		fmt.Println("                   Write a JSON summary of the run (result, counts, changed directories) to FILE")
+		fmt.Println("  --run-report FILE")
+		fmt.Println("                   Write version, config digest, rule timings, and counts to FILE for bug reports")
+		fmt.Println("  --cpuprofile FILE")
+		fmt.Println("                   Write a CPU profile of the run to FILE (inspect with go tool pprof)")
+		fmt.Println("  --memprofile FILE")
+		fmt.Println("                   Write a heap profile at the end of the run to FILE")
+		fmt.Println("  --as-action      Run as a GitHub Action, reading options from INPUT_* variables")
+		fmt.Println("Commands:")
+		fmt.Println("  checksum [directory...]  Write a SHA256SUMS manifest of the files entries of each challenge")
+		fmt.Println("  build [--verify] [directory...]")
+		fmt.Println("                           Run each challenge's build; --verify checks the output matches the committed files")
+		fmt.Println("  multi [--repos repos.yaml] [--json]")
+		fmt.Println("                           Fetch and lint several challenge repositories, checking for cross-repo collisions")
+		fmt.Println("  trend [--history FILE] [--since YYYY-MM-DD]")
+		fmt.Println("                           Report whether lint results improved over the recorded runs")
+		fmt.Println("  serve [--addr :8080] [--work-dir DIR] [--config lintrc.yaml]")
+		fmt.Println("                           Lint submitted challenges on POST /lint, and pull requests on GitHub")
+		fmt.Println("                           webhook deliveries when GITHUB_WEBHOOK_SECRET and GITHUB_TOKEN are set")
+		fmt.Println("  release --wave N [--force] [directory...]")
+		fmt.Println("                           Set state: visible on the challenges of a wave once the schedule releases it")
+		fmt.Println("  set [--filter field==value]... [--dry-run] field=value... [directory...]")
+		fmt.Println("                           Set top-level fields of the matching challenges, keeping comments and layout")
+		fmt.Println("  diff <ref> [--json] [directory...]")
+		fmt.Println("                           Show challenge metadata changes between a git ref and the working tree")
+		fmt.Println("  readiness [--min N] [--json] [directory...]")
+		fmt.Println("                           Score each challenge's readiness and fail if any scores below N")
+		fmt.Println("  authors [--format markdown|csv] [--writeup GLOB] [directory...]")
+		fmt.Println("                           Summarize challenges, points, findings, and missing writeups per author")
+		fmt.Println("  simulate [--teams N] [--json] [directory...]")
+		fmt.Println("                           Project final dynamic values from per-difficulty solve rates")
+		fmt.Println("  export [--format csv|json|yaml] [--output FILE] [--sheet ID [--sheet-range RANGE]] [directory...]")
+		fmt.Println("                           Export the challenge inventory, or write it to a Google Sheet; yaml writes")
+		fmt.Println("                           the challenges for the platform, without organizer-only notes and flag tests")
+		fmt.Println("  history-scan [--json] [directory...]")
+		fmt.Println("                           Find old flags and deleted solution files in the git history of each challenge")
+		fmt.Println("  publish-check [--json] [directory...]")
+		fmt.Println("                           Check for credentials, internal hostnames, denied strings, and missing writeups")
+		fmt.Println("                           before the repository is made public")
+		fmt.Println("  preview [--output FILE] [--anonymous] <directory>...")
+		fmt.Println("                           Render descriptions as the platform shows them, in the terminal or an HTML file")
+		fmt.Println("  healthcheck [--run] [--json] [--timeout D] [directory...]")
+		fmt.Println("                           Check the healthcheck scripts of hosted challenges, and with --run execute them")
+		fmt.Println("  package --blind <outdir> [directory...]")
+		fmt.Println("                           Copy only the description, files, and connection info of passing challenges")
+		fmt.Println("                           to a new directory for external playtesters")
+		fmt.Println("  explain [rule-id]        Describe a rule: rationale, configuration, examples, and auto-fix;")
+		fmt.Println("                           without a rule ID, list the rules")
+		fmt.Println("  schema --output-format   Print the JSON Schema of the --json output")
+		fmt.Println("  version [--check]        Print the version; --check compares it with the latest release and")
+		fmt.Println("                           required_version, and fails if lintrc.yaml uses settings it does not know")
+		fmt.Println("  config migrate [--write] [lintrc.yaml]")
+		fmt.Println("                           Upgrade lintrc.yaml to the current config_version, printing it or with")
+		fmt.Println("                           --write updating it in place")
 		return
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "--as-action" {
+		args, err := actionArgs()
+		if err != nil {
+			log.Fatalf("Error reading action inputs: %v", err)
+		}
+		os.Args = append([]string{os.Args[0]}, args...)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "checksum":
+			runChecksum(os.Args[2:])
+			return
+		case "build":
+			runBuild(os.Args[2:])
+			return
+		case "multi":
+			runMulti(os.Args[2:])
+			return
+		case "trend":
+			runTrend(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "release":
+			runRelease(os.Args[2:])
+			return
+		case "set":
+			runSet(os.Args[2:])
+			return
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "readiness":
+			runReadiness(os.Args[2:])
+			return
+		case "authors":
+			runAuthors(os.Args[2:])
+			return
+		case "simulate":
+			runSimulate(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "history-scan":
+			runHistoryScan(os.Args[2:])
+			return
+		case "publish-check":
+			runPublishCheck(os.Args[2:])
+			return
+		case "preview":
+			runPreview(os.Args[2:])
+			return
+		case "healthcheck":
+			runHealthcheck(os.Args[2:])
+			return
+		case "package":
+			runPackage(os.Args[2:])
+			return
+		case "explain":
+			runExplain(os.Args[2:])
+			return
+		case "schema":
+			runSchema(os.Args[2:])
+			return
+		case "version":
+			runVersion(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:])
+			return
+		case "__sandbox":
+			// Internal: the child process of sandboxed rules
+			runSandbox(os.Args[2:])
+			return
+		}
+	}
+
 	jsonOutput := false
 	commentPR := false
+	suggest := false
+	changedLinesOnly := false
+	fix := false
+	interactive := false
+	sqliteOutput := false
+	outputPath := ""
 	var targetDirs []string
 
 	// Parse arguments
-	for _, arg := range os.Args[1:] {
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
 		if arg == "--json" {
 			jsonOutput = true
 		} else if arg == "--comment-pr" {
 			commentPR = true
+		} else if arg == "--suggest" {
+			suggest = true
+		} else if arg == "--changed-lines-only" {
+			changedLinesOnly = true
+		} else if arg == "--fetch-contents" {
+			fetchContents = true
+		} else if arg == "--freeze" {
+			freezeMode = true
+		} else if arg == "--live" {
+			liveMode = true
+		} else if arg == "--sync" {
+			syncEnabled = true
+		} else if arg == "--create-issues" {
+			createIssues = true
+		} else if arg == "--audit" {
+			auditMode = true
+		} else if arg == "--sandbox" {
+			sandboxMode = true
+		} else if arg == "--fix" {
+			fix = true
+		} else if arg == "--diff" {
+			diffMode = true
+		} else if arg == "--anonymous" {
+			anonymousMode = true
+		} else if arg == "--interactive" {
+			interactive = true
+		} else if value, ok := flagValue(os.Args, &i, "--max-depth"); ok {
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 0 {
+				log.Fatalf("Invalid --max-depth value: %s", value)
+			}
+			walkFlags.MaxDepth = depth
+		} else if arg == "--follow-symlinks" {
+			walkFlags.FollowSymlinks = true
+		} else if value, ok := flagValue(os.Args, &i, "--history"); ok {
+			historyPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--format"); ok {
+			switch value {
+			case "text":
+			case "json":
+				jsonOutput = true
+			case "sqlite":
+				if err := checkSQLite(); err != nil {
+					log.Fatal(err)
+				}
+				sqliteOutput = true
+			default:
+				log.Fatalf("Invalid --format value: %s (want text, json, or sqlite)", value)
+			}
+		} else if value, ok := flagValue(os.Args, &i, "--output"); ok {
+			outputPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--vars"); ok {
+			varsPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--metrics-file"); ok {
+			metricsPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--summary-file"); ok {
+			summaryPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--run-report"); ok {
+			runReportPath = value
+		} else if value, ok := flagValue(os.Args, &i, "--cpuprofile"); ok {
+			cpuProfilePath = value
+		} else if value, ok := flagValue(os.Args, &i, "--memprofile"); ok {
+			memProfilePath = value
 		} else if !strings.HasPrefix(arg, "--") {
 			targetDirs = append(targetDirs, arg)
 		}
 	}
 
+	if summaryPath == "" {
+		summaryPath = os.Getenv("CLILINT_SUMMARY_FILE")
+	}
+
+	if err := startProfiles(); err != nil {
+		log.Fatalf("Error starting profiling: %v", err)
+	}
+	defer stopProfiles()
+
 	var allResults []LintResult
 
+	if auditMode {
+		// Audits cover the whole repository rather than a PR
+		commentPR = false
+	}
+
 	// GitHub Actions mode: detect changed directories
 	if commentPR {
 		env, err := getEnv()
@@ -139,10 +648,38 @@ func main() {
 			log.Fatalf("Error getting environment: %v", err)
 		}
 
-		changedDirs, err := findChangedDirectories(env)
+		if !sandboxMode {
+			fork, err := isForkPR(env)
+			if err != nil {
+				log.Printf("Warning: sandboxing content-inspecting rules: %v", err)
+			}
+			sandboxMode = fork || err != nil
+		}
+
+		prFiles, err := listPRFiles(env)
 		if err != nil {
 			log.Fatalf("Error finding changed directories: %v", err)
 		}
+		// The files of the PR, plus those of the base and head tree diff
+		dirFiles := prFiles
+		if compared, err := comparePRFiles(env); err != nil {
+			log.Printf("Warning: comparing the PR's base and head: %v", err)
+		} else {
+			dirFiles = mergePRFiles(prFiles, compared)
+		}
+		// With --fetch-contents, challenges are found in the PR head's tree,
+		// which also has the challenges the PR adds
+		var head *prHead
+		var changedDirs []string
+		if fetchContents {
+			head, err = fetchPRHead(env)
+			if err != nil {
+				log.Fatalf("Error linting PR contents: %v", err)
+			}
+			changedDirs = changedDirectoriesWith(dirFiles, head.hasChallenge)
+		} else {
+			changedDirs = changedDirectories(dirFiles)
+		}
 
 		if len(changedDirs) == 0 {
 			// No changes, post comment and exit
@@ -150,18 +687,47 @@ func main() {
 			if err != nil {
 				log.Fatalf("Error posting comment: %v", err)
 			}
+			writeActionOutputs(nil, false)
+			recordSummary(nil, nil)
+			recordRunReport(nil)
 			return
 		}
 
 		// Lint changed directories
-		for _, dir := range changedDirs {
-			results, err := lintChallenges(dir)
+		if fetchContents {
+			allResults, err = lintPRContents(head, changedDirs)
 			if err != nil {
-				log.Fatalf("Error linting directory %s: %v", dir, err)
+				log.Fatalf("Error linting PR contents: %v", err)
+			}
+		} else {
+			for _, dir := range changedDirs {
+				results, err := lintChallenges(dir)
+				if err != nil {
+					log.Fatalf("Error linting directory %s: %v", dir, err)
+				}
+				allResults = append(allResults, results...)
 			}
-			allResults = append(allResults, results...)
 		}
 
+		// Flags may also collide with challenges the PR does not touch
+		repoChallenges, err := findChallengeFiles(".")
+		if err != nil {
+			log.Printf("Warning: failed to list challenges for flag collisions: %v", err)
+		}
+		checkFlagCollisions(allResults, repoChallenges)
+		if err := checkFreeze(env, allResults, dirFiles); err != nil {
+			log.Fatalf("Error checking the freeze: %v", err)
+		}
+		if err := checkLive(env, allResults, dirFiles); err != nil {
+			log.Fatalf("Error checking solved challenges: %v", err)
+		}
+		applyLive(allResults)
+
+		if changedLinesOnly {
+			allResults = filterToChangedLines(allResults, prFiles)
+		}
+		sortResults(allResults)
+
 		// Post PR comment
 		hasErrors := hasLintErrors(allResults)
 		err = postPRComment(allResults, hasErrors, env)
@@ -169,8 +735,20 @@ func main() {
 			log.Fatalf("Error posting PR comment: %v", err)
 		}
 
+		if suggest {
+			// Suggestions are best effort: lines outside the diff are rejected by GitHub
+			if err := postReviewSuggestions(allResults, env); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+
+		writeActionOutputs(allResults, hasErrors)
+		recordHistory(allResults)
+		recordMetrics(allResults)
+		recordSummary(allResults, changedDirs)
+		recordRunReport(allResults)
 		if hasErrors {
-			os.Exit(1)
+			exit(1)
 		}
 		return
 	}
@@ -186,59 +764,184 @@ func main() {
 			log.Fatalf("Error linting directory %s: %v", dir, err)
 		}
 		allResults = append(allResults, results...)
+		allResults = append(allResults, lintMissingChallenges(dir, results)...)
+	}
+	checkFlagCollisions(allResults, nil)
+	applyLive(allResults)
+
+	if interactive {
+		config, err := loadLintConfig()
+		if err != nil {
+			log.Fatalf("Error loading lint config: %v", err)
+		}
+		reader := bufio.NewReader(os.Stdin)
+		for i, result := range allResults {
+			changed, err := promptMissingFields(result.File, config, reader, os.Stdout)
+			if err != nil {
+				log.Fatalf("Error updating %s: %v", result.File, err)
+			}
+			if changed {
+				fmt.Printf("✏️  %s: updated\n", result.File)
+				allResults[i] = lintChallengeFile(result.File)
+			}
+		}
 	}
 
+	if fix || diffMode {
+		for i, result := range allResults {
+			if len(result.Fixes) == 0 {
+				continue
+			}
+			if diffMode {
+				if !jsonOutput {
+					fmt.Print(result.fixDiff)
+				}
+				continue
+			}
+			if err := applyFixes(result.File, result.Fixes); err != nil {
+				log.Fatalf("Error fixing %s: %v", result.File, err)
+			}
+			if !jsonOutput {
+				fmt.Printf("🔧 %s: applied %d fix(es)\n", result.File, len(result.Fixes))
+			}
+			allResults[i] = lintChallengeFile(result.File)
+		}
+
+		config, err := loadLintConfig()
+		if err != nil {
+			log.Fatalf("Error loading lint config: %v", err)
+		}
+		for _, result := range allResults {
+			if suggestion := slugSuggestion(result.File, result.Name, config.NameSlug); suggestion != "" && !jsonOutput {
+				fmt.Printf("💡 %s: rename the directory to match the name: %s\n", result.File, suggestion)
+			}
+		}
+	}
+
+	sortResults(allResults)
 	hasErrors := hasLintErrors(allResults)
+	writeActionOutputs(allResults, hasErrors)
+	recordHistory(allResults)
+	recordMetrics(allResults)
+	recordSummary(allResults, nil)
+	recordRunReport(allResults)
+	syncWiki(allResults)
+	trackIssues(allResults)
+	postAudit(allResults)
+
+	// Handle SQLite output
+	if sqliteOutput {
+		if outputPath == "" {
+			outputPath = defaultSQLitePath
+		}
+		if err := writeSQLite(outputPath, allResults); err != nil {
+			log.Fatalf("Failed to write SQLite output: %v", err)
+		}
+		fmt.Printf("Wrote results of %d challenge(s) to %s\n", len(allResults), outputPath)
 
-	// Handle JSON output
-	if jsonOutput {
-		output := map[string]interface{}{
-			"success": !hasErrors,
-			"results": allResults,
+		if hasErrors {
+			exit(1)
 		}
+		return
+	}
 
-		jsonData, err := json.Marshal(output)
+	// Handle JSON output
+	if jsonOutput {
+		jsonData, err := marshalResults(allResults, hasErrors)
 		if err != nil {
 			log.Fatalf("Failed to marshal JSON output: %v", err)
 		}
 		fmt.Println(string(jsonData))
 
 		if hasErrors {
-			os.Exit(1)
+			exit(1)
 		}
 		return
 	}
 
 	// Handle standard output
-	for _, result := range allResults {
+	writeResults(os.Stdout, allResults)
+
+	if hasErrors {
+		exit(1)
+	} else {
+		fmt.Println("All challenge.yml files passed linting! 🎉")
+	}
+}
+
+// flagValue matches a value option given as "--name value" or "--name=value",
+// advancing *i past a separate value argument
+func flagValue(args []string, i *int, name string) (string, bool) {
+	arg := args[*i]
+	if value, ok := strings.CutPrefix(arg, name+"="); ok {
+		return value, true
+	}
+	if arg != name {
+		return "", false
+	}
+	if *i+1 < len(args) {
+		*i++
+		return args[*i], true
+	}
+	return "", true
+}
+
+// writeResults writes lint results in the human readable CLI format
+func writeResults(w io.Writer, results []LintResult) {
+	for _, result := range results {
 		if len(result.Errors) > 0 {
-			fmt.Printf("❌ %s:\n", result.File)
+			fmt.Fprintf(w, "❌ %s:\n", result.File)
+			if len(result.Owners) > 0 {
+				fmt.Fprintf(w, "  👤 Owners: %s\n", strings.Join(result.Owners, ", "))
+			}
 			for _, err := range result.Errors {
-				fmt.Printf("  - %s\n", err)
+				if err.Severity == SeveritySecurity {
+					fmt.Fprintf(w, "  - 🛡️  %s\n", err.Message)
+				} else {
+					fmt.Fprintf(w, "  - %s\n", err.Message)
+				}
+				writeExcerpt(w, err.Excerpt)
+				writeDocs(w, err.Docs)
 			}
 			if len(result.Warnings) > 0 {
 				for _, warn := range result.Warnings {
-					fmt.Printf("  ⚠️  %s\n", warn)
+					fmt.Fprintf(w, "  ⚠️  %s\n", warn.Message)
+					writeExcerpt(w, warn.Excerpt)
+					writeDocs(w, warn.Docs)
 				}
 			}
-			fmt.Println()
+			for _, dep := range result.Deprecations {
+				fmt.Fprintf(w, "  ⏳ %s\n", dep.Message)
+			}
+			fmt.Fprintln(w)
 		} else {
-			if len(result.Warnings) > 0 {
-				fmt.Printf("⚠️  %s:\n", result.File)
+			if len(result.Warnings) > 0 || len(result.Deprecations) > 0 {
+				fmt.Fprintf(w, "⚠️  %s:\n", result.File)
 				for _, warn := range result.Warnings {
-					fmt.Printf("  - %s\n", warn)
+					fmt.Fprintf(w, "  - %s\n", warn.Message)
+					writeExcerpt(w, warn.Excerpt)
+					writeDocs(w, warn.Docs)
+				}
+				for _, dep := range result.Deprecations {
+					fmt.Fprintf(w, "  ⏳ %s\n", dep.Message)
 				}
-				fmt.Println()
+				fmt.Fprintln(w)
 			} else {
-				fmt.Printf("✅ %s: OK\n", result.File)
+				fmt.Fprintf(w, "✅ %s: OK\n", result.File)
 			}
 		}
 	}
+}
 
-	if hasErrors {
-		os.Exit(1)
-	} else {
-		fmt.Println("All challenge.yml files passed linting! 🎉")
+func writeExcerpt(w io.Writer, excerpt string) {
+	if excerpt != "" {
+		fmt.Fprintln(w, indentLines(excerpt, "    "))
+	}
+}
+
+func writeDocs(w io.Writer, url string) {
+	if url != "" {
+		fmt.Fprintf(w, "    📖 %s\n", url)
 	}
 }
 
@@ -286,54 +989,120 @@ func getEnv() (Env, error) {
 
 func getGitHubClient(token string) (*github.Client, context.Context) {
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+	// Without a token, requests are unauthenticated and limited to public data
+	var tc *http.Client
+	if token != "" {
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		tc = oauth2.NewClient(ctx, ts)
+	}
 	client := github.NewClient(tc)
+	// Set by GitHub Actions; differs from api.github.com on GitHub Enterprise Server
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		if baseURL, err := url.Parse(strings.TrimSuffix(apiURL, "/") + "/"); err == nil {
+			client.BaseURL = baseURL
+		}
+	}
 	return client, ctx
 }
 
-func findChangedDirectories(env Env) ([]string, error) {
+// prFetchConcurrency bounds the concurrent GitHub API requests of a run
+const prFetchConcurrency = 8
+
+// listPRFiles returns the files changed by the PR, including their patches.
+// After the first page, the remaining pages are fetched concurrently.
+func listPRFiles(env Env) ([]*github.CommitFile, error) {
 	client, ctx := getGitHubClient(env.token)
 
-	var allFiles []string
 	opt := &github.ListOptions{PerPage: 100}
-
-	for {
-		files, resp, err := client.PullRequests.ListFiles(ctx, env.owner, env.repo, env.prNumber, opt)
-		if err != nil {
-			return nil, fmt.Errorf("error getting PR files: %v", err)
+	files, resp, err := client.PullRequests.ListFiles(ctx, env.owner, env.repo, env.prNumber, opt)
+	if err != nil {
+		return nil, fmt.Errorf("error getting PR files: %v", err)
+	}
+	if resp.NextPage == 0 {
+		return files, nil
+	}
+	if resp.LastPage == 0 {
+		// Without a last page link, follow the pages one by one
+		allFiles := files
+		for resp.NextPage != 0 {
+			opt.Page = resp.NextPage
+			files, resp, err = client.PullRequests.ListFiles(ctx, env.owner, env.repo, env.prNumber, opt)
+			if err != nil {
+				return nil, fmt.Errorf("error getting PR files: %v", err)
+			}
+			allFiles = append(allFiles, files...)
 		}
+		return allFiles, nil
+	}
 
-		for _, file := range files {
-			allFiles = append(allFiles, file.GetFilename())
-		}
+	pages := make([][]*github.CommitFile, resp.LastPage+1)
+	pages[1] = files
+	errs := make([]error, resp.LastPage+1)
+	sem := make(chan struct{}, prFetchConcurrency)
+	var wg sync.WaitGroup
+	for page := resp.NextPage; page <= resp.LastPage; page++ {
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			pages[page], _, errs[page] = client.PullRequests.ListFiles(ctx, env.owner, env.repo, env.prNumber,
+				&github.ListOptions{PerPage: opt.PerPage, Page: page})
+		}(page)
+	}
+	wg.Wait()
 
-		if resp.NextPage == 0 {
-			break
+	var allFiles []*github.CommitFile
+	for page := range pages {
+		if errs[page] != nil {
+			return nil, fmt.Errorf("error getting PR files: %v", errs[page])
 		}
-		opt.Page = resp.NextPage
+		allFiles = append(allFiles, pages[page]...)
 	}
+	return allFiles, nil
+}
 
-	// Find directories containing challenge.yml files
+// changedDirectories returns the challenge directories affected by the PR
+// files, looking for challenge.yml in the local checkout
+func changedDirectories(prFiles []*github.CommitFile) []string {
+	return changedDirectoriesIn(".", prFiles)
+}
+
+// changedDirectoriesIn returns the challenge directories affected by the PR
+// files, looking for challenge.yml in the checkout at root
+func changedDirectoriesIn(root string, prFiles []*github.CommitFile) []string {
+	return changedDirectoriesWith(prFiles, func(dir string) bool {
+		_, err := os.Stat(filepath.Join(root, dir, "challenge.yml"))
+		return err == nil
+	})
+}
+
+// changedDirectoriesWith returns the challenge directories affected by the
+// PR files, where hasChallenge reports whether a directory holds a challenge.yml
+func changedDirectoriesWith(prFiles []*github.CommitFile, hasChallenge func(dir string) bool) []string {
+	resolver := &challengeResolver{hasChallenge: hasChallenge, cache: make(map[string]string)}
 	dirSet := make(map[string]bool)
 
-	for _, file := range allFiles {
+	for _, prFile := range prFiles {
+		// GitHub always reports forward slashes
+		file := filepath.FromSlash(prFile.GetFilename())
 		dir := filepath.Dir(file)
 
-		// Check if the file is challenge.yml or if the directory contains challenge.yml
-		if filepath.Base(file) == "challenge.yml" {
+		// Check if the file is challenge.yml or if a parent directory contains
+		// challenge.yml. A removed challenge.yml leaves nothing to lint, unless
+		// a parent is a challenge too.
+		if filepath.Base(file) == "challenge.yml" && prFile.GetStatus() != "removed" {
 			dirSet[dir] = true
-		} else {
-			// Check parent directories for challenge.yml
-			current := dir
-			for current != "." && current != "/" {
-				if _, err := os.Stat(filepath.Join(current, "challenge.yml")); err == nil {
-					dirSet[current] = true
-					break
-				}
-				current = filepath.Dir(current)
+		} else if challengeDir := resolver.resolve(dir); challengeDir != "" {
+			dirSet[challengeDir] = true
+		}
+
+		// A file moved out of a challenge changes the challenge it left
+		if previous := prFile.GetPreviousFilename(); previous != "" {
+			if challengeDir := resolver.resolve(filepath.Dir(filepath.FromSlash(previous))); challengeDir != "" {
+				dirSet[challengeDir] = true
 			}
 		}
 	}
@@ -342,8 +1111,34 @@ func findChangedDirectories(env Env) ([]string, error) {
 	for dir := range dirSet {
 		directories = append(directories, dir)
 	}
+	sort.Strings(directories)
+
+	return directories
+}
 
-	return directories, nil
+// challengeResolver finds the challenge directory a directory belongs to,
+// remembering the answer for every directory on the way: large PRs change
+// many files under the same few directories
+type challengeResolver struct {
+	hasChallenge func(dir string) bool
+	cache        map[string]string
+}
+
+// resolve returns the nearest of dir and its parents that holds a
+// challenge.yml, or "" when none does
+func (r *challengeResolver) resolve(dir string) string {
+	if dir == "." || dir == string(filepath.Separator) {
+		return ""
+	}
+	if challengeDir, ok := r.cache[dir]; ok {
+		return challengeDir
+	}
+	challengeDir := dir
+	if !r.hasChallenge(dir) {
+		challengeDir = r.resolve(filepath.Dir(dir))
+	}
+	r.cache[dir] = challengeDir
+	return challengeDir
 }
 
 func hasLintErrors(results []LintResult) bool {
@@ -361,8 +1156,30 @@ func postNoChangesComment(env Env) error {
 }
 
 func postPRComment(results []LintResult, hasErrors bool, env Env) error {
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Printf("Warning: failed to load config: %v", err)
+	}
+	return createComment(env, prCommentBody(results, hasErrors, env, config))
+}
+
+// prCommentBody renders the PR comment with the sections of the config, and
+// requests reviews from the authors of the touched challenges if configured.
+// Without a config only the lint results are rendered.
+func prCommentBody(results []LintResult, hasErrors bool, env Env, config *LintConfig) string {
 	commentBody := generateCommentBody(results, hasErrors)
-	return createComment(env, commentBody)
+	if config == nil {
+		return commentBody
+	}
+	if anonymousMode {
+		// The onboarding section and mentions address the authors
+		return commentBody
+	}
+	commentBody = onboardingSection(env, config) + commentBody + authorsSection(results, config.Authors)
+	if err := requestAuthorReviews(env, results, config.Authors); err != nil {
+		log.Printf("Warning: failed to request author reviews: %v", err)
+	}
+	return commentBody
 }
 
 func generateCommentBody(results []LintResult, hasErrors bool) string {
@@ -377,9 +1194,22 @@ func generateCommentBody(results []LintResult, hasErrors bool) string {
 		body.WriteString("### 📋 Checked Challenges in This PR:\n\n")
 	}
 
+	// Findings shared by many challenges, e.g. after a lintrc.yaml change,
+	// are reported once rather than under each challenge
+	groups := groupFindings(results, findingGroupThreshold)
+	writeFindingGroups(&body, groups)
+
 	for _, result := range results {
+		result, grouped := withoutGroupedFindings(result, groups)
+		if grouped && len(result.Errors) == 0 && len(result.Warnings) == 0 && len(result.Deprecations) == 0 && len(result.Checklist) == 0 {
+			// Listed under its groups
+			continue
+		}
 		if len(result.Errors) > 0 {
 			body.WriteString(fmt.Sprintf("#### ❌ **%s** (`%s`)\n\n", result.Name, result.File))
+			if len(result.Owners) > 0 {
+				body.WriteString(fmt.Sprintf("**Owners:** %s\n\n", ownersText(result.Owners, result.mentionOwners)))
+			}
 			if result.Description != "" {
 				body.WriteString("**Description:**\n")
 				body.WriteString(result.Description)
@@ -387,38 +1217,73 @@ func generateCommentBody(results []LintResult, hasErrors bool) string {
 			}
 			body.WriteString("**Issues found:**\n")
 			for _, err := range result.Errors {
-				body.WriteString(fmt.Sprintf("- %s\n", err))
+				body.WriteString(fmt.Sprintf("- %s%s\n", err.Message, markdownDocsLink(err.Docs)))
+				body.WriteString(markdownExcerpt(err.Excerpt))
 			}
 			if len(result.Warnings) > 0 {
 				body.WriteString("\n**Warnings:**\n")
 				for _, warn := range result.Warnings {
-					body.WriteString(fmt.Sprintf("- ⚠️ %s\n", warn))
+					body.WriteString(fmt.Sprintf("- ⚠️ %s%s\n", warn.Message, markdownDocsLink(warn.Docs)))
+					body.WriteString(markdownExcerpt(warn.Excerpt))
+				}
+			}
+			if len(result.Deprecations) > 0 {
+				body.WriteString("\n**Deprecations:**\n")
+				for _, dep := range result.Deprecations {
+					body.WriteString(fmt.Sprintf("- ⏳ %s\n", dep.Message))
 				}
 			}
+			if len(result.Checklist) > 0 {
+				body.WriteString("\n")
+				writeChecklist(&body, result.Checklist)
+			}
+			writeFixDiff(&body, result.fixDiff)
 			body.WriteString("\n---\n\n")
 		} else {
-			if len(result.Warnings) > 0 {
+			if len(result.Warnings) > 0 || len(result.Deprecations) > 0 {
 				body.WriteString(fmt.Sprintf("#### ⚠️ **%s** (`%s`)\n\n", result.Name, result.File))
 				if result.Description != "" {
 					body.WriteString("**Description:**\n")
 					body.WriteString(result.Description)
 					body.WriteString("\n\n")
 				}
-				body.WriteString("**Warnings:**\n")
-				for _, warn := range result.Warnings {
-					body.WriteString(fmt.Sprintf("- %s\n", warn))
+				if len(result.Warnings) > 0 {
+					body.WriteString("**Warnings:**\n")
+					for _, warn := range result.Warnings {
+						body.WriteString(fmt.Sprintf("- %s%s\n", warn.Message, markdownDocsLink(warn.Docs)))
+						body.WriteString(markdownExcerpt(warn.Excerpt))
+					}
+				}
+				if len(result.Deprecations) > 0 {
+					body.WriteString("**Deprecations:**\n")
+					for _, dep := range result.Deprecations {
+						body.WriteString(fmt.Sprintf("- ⏳ %s\n", dep.Message))
+					}
+				}
+				if len(result.Checklist) > 0 {
+					body.WriteString("\n")
+					writeChecklist(&body, result.Checklist)
 				}
 				body.WriteString("\n---\n\n")
 			} else {
 				body.WriteString(fmt.Sprintf("#### 🚩 **%s** (`%s`)\n\n", result.Name, result.File))
 				if result.Description != "" {
 					body.WriteString(result.Description)
-					body.WriteString("\n\n---\n\n")
+					body.WriteString("\n\n")
+				}
+				writeChecklist(&body, result.Checklist)
+				if len(result.Checklist) > 0 {
+					body.WriteString("\n")
+				}
+				if result.Description != "" || len(result.Checklist) > 0 {
+					body.WriteString("---\n\n")
 				}
 			}
 		}
 	}
 
+	writeDeprecationSummary(&body, results)
+
 	if hasErrors {
 		body.WriteString("⚠️ Please fix the issues above and try again.")
 	} else {
@@ -428,6 +1293,23 @@ func generateCommentBody(results []LintResult, hasErrors bool) string {
 	return body.String()
 }
 
+// markdownExcerpt renders an excerpt as a code block nested in the list item
+// of its finding, or "" without one
+func markdownExcerpt(excerpt string) string {
+	if excerpt == "" {
+		return ""
+	}
+	return indentLines("```\n"+excerpt+"\n```", "  ") + "\n"
+}
+
+// markdownDocsLink renders a guideline link to append to a finding, or "" without one
+func markdownDocsLink(url string) string {
+	if url == "" {
+		return ""
+	}
+	return fmt.Sprintf(" ([docs](%s))", url)
+}
+
 func findExistingComment(env Env) (*int64, error) {
 	client, ctx := getGitHubClient(env.token)
 	opt := &github.IssueListCommentsOptions{
@@ -477,47 +1359,185 @@ func createComment(env Env, body string) error {
 }
 
 func lintChallenges(rootDir string) ([]LintResult, error) {
+	return lintChallengesWith(rootDir, sandboxMode)
+}
+
+// lintChallengesWith lints the challenges under rootDir, running the
+// content-inspecting rules in the sandbox when sandbox is set
+func lintChallengesWith(rootDir string, sandbox bool) ([]LintResult, error) {
 	var results []LintResult
 
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	paths, err := findChallengeFiles(rootDir)
+	for _, path := range paths {
+		result := lintChallengeFileWith(path, sandbox)
+		results = append(results, result)
+	}
 
-		if info.Name() == "challenge.yml" {
-			result := lintChallengeFile(path)
-			results = append(results, result)
-		}
+	return results, err
+}
 
-		return nil
-	})
+// readChallenge reads and parses a challenge.yml file
+func readChallenge(filePath string) (Challenge, error) {
+	var challenge Challenge
 
-	return results, err
+	config, err := loadLintConfig()
+	if err != nil {
+		return challenge, err
+	}
+	data, err := readYAMLFile(filePath, config.YAMLLimits)
+	var limitErr *yamlLimitError
+	if errors.As(err, &limitErr) {
+		return challenge, err
+	}
+	if err != nil {
+		return challenge, fmt.Errorf("failed to read file: %v", err)
+	}
+	if err := checkAliasExpansion(data); err != nil {
+		return challenge, err
+	}
+	data, err = resolveChallengeData(data, config)
+	if err != nil {
+		return challenge, err
+	}
+
+	err = yaml.Unmarshal(data, &challenge)
+	if err != nil {
+		return challenge, fmt.Errorf("invalid YAML format: %v", err)
+	}
+
+	return challenge, nil
 }
 
 func loadLintConfig() (*LintConfig, error) {
-	configPath := "lintrc.yaml"
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		configPath = filepath.Join(filepath.Dir(os.Args[0]), "lintrc.yaml")
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			return getDefaultLintConfig(), nil
+	configPath, data, err := readLintConfig()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		noteRunConfig("", nil, nil)
+		return getDefaultLintConfig(), nil
+	}
+
+	baseDir := "."
+	if configPath != "inline" {
+		baseDir = filepath.Dir(configPath)
+	}
+	config, err := parseLintConfig(data, baseDir)
+	noteRunConfig(configPath, data, config)
+	return config, err
+}
+
+// readLintConfig returns the lintrc.yaml of the run: its path ("inline" for
+// --config) and contents, or no contents when the defaults apply
+func readLintConfig() (string, []byte, error) {
+	if inlineConfig != "" {
+		return "inline", []byte(inlineConfig), nil
+	}
+
+	configPath := lintConfigFile
+	if configPath == "" && pinnedLintConfig {
+		return "", nil, nil
+	}
+	if configPath == "" {
+		configPath = findLintConfig()
+		if configPath == "" {
+			return "", nil, nil
 		}
 	}
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read lintrc.yaml: %v", err)
+		return "", nil, fmt.Errorf("failed to read lintrc.yaml: %v", err)
 	}
+	return configPath, data, nil
+}
 
+// findLintConfig returns the lintrc.yaml in the working directory, or else
+// next to the executable, or "" if there is neither. In action mode the
+// executable is built in the action's checkout, whose sample lintrc.yaml
+// must not apply to the consumer repository, so only the working directory
+// counts.
+func findLintConfig() string {
+	candidates := []string{"lintrc.yaml"}
+	if !actionMode {
+		candidates = append(candidates, filepath.Join(filepath.Dir(os.Args[0]), "lintrc.yaml"))
+	}
+	for _, configPath := range candidates {
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
+		}
+	}
+	return ""
+}
+
+// resolvePath resolves a path from lintrc.yaml relative to the config's directory
+func (c *LintConfig) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	resolved := filepath.Join(c.baseDir, path)
+	if c.policyDir != "" {
+		if _, err := os.Stat(resolved); err != nil {
+			if candidate := filepath.Join(c.policyDir, path); fileExists(candidate) {
+				return candidate
+			}
+		}
+	}
+	return resolved
+}
+
+// parseLintConfig parses lintrc.yaml content, resolving relative paths against baseDir
+func parseLintConfig(data []byte, baseDir string) (*LintConfig, error) {
+	data, err := upgradeConfig(data)
+	if err != nil {
+		return nil, err
+	}
 	var config LintConfig
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse lintrc.yaml: %v", err)
 	}
+	config.baseDir = baseDir
+	if config.Policy.Ref != "" {
+		return applyPolicy(data, &config)
+	}
 
 	return &config, nil
 }
 
+// defaultMaxFileSize is the maximum attachment size unless configured otherwise
+const defaultMaxFileSize = 1024 * 1024 // 1MB in bytes
+
+// forCategory returns a copy of the config with the matching category profile applied
+func (c *LintConfig) forCategory(category string) *LintConfig {
+	merged := *c
+	for key, profile := range c.Profiles {
+		if !strings.EqualFold(key, category) {
+			continue
+		}
+		if profile.Tags != nil {
+			merged.Tags = *profile.Tags
+		}
+		if profile.Requirements != nil {
+			merged.Requirements = *profile.Requirements
+		}
+		if profile.MaxFileSize != nil {
+			merged.MaxFileSize = *profile.MaxFileSize
+		}
+		if profile.AllowImage != nil {
+			merged.AllowImage = *profile.AllowImage
+		}
+		if profile.RequireHost != nil {
+			merged.RequireHost = *profile.RequireHost
+		}
+		if profile.HostPolicy != nil {
+			merged.HostPolicy = *profile.HostPolicy
+		}
+		break
+	}
+	return &merged
+}
+
 func getDefaultLintConfig() *LintConfig {
 	return &LintConfig{
 		Tags: Rule{
@@ -540,61 +1560,194 @@ func getDefaultLintConfig() *LintConfig {
 }
 
 func lintChallengeFile(filePath string) LintResult {
+	return lintChallengeFileWith(filePath, sandboxMode)
+}
+
+// lintChallengeFileWith lints a challenge.yml, running the content-inspecting
+// rules in the sandbox when sandbox is set
+func lintChallengeFileWith(filePath string, sandbox bool) LintResult {
 	result := LintResult{
-		File:        filePath,
-		Errors:      []string{},
-		Warnings:    []string{},
-		Name:        "",
-		Description: "",
+		File:         filePath,
+		Errors:       []Finding{},
+		Warnings:     []Finding{},
+		Deprecations: []Finding{},
+		Name:         "",
+		Description:  "",
 	}
 
 	// Load lint configuration
 	config, err := loadLintConfig()
 	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("Failed to load lint config: %v", err))
+		result.addErrors("config", []string{fmt.Sprintf("Failed to load lint config: %v", err)})
 		return result
 	}
 
-	// Read file
-	data, err := os.ReadFile(filePath)
+	result.docsBaseURL = config.DocsBaseURL
+	result.ruleTimeout = config.ruleTimeout()
+
+	// Read file, refusing files too large or deep to be a challenge
+	data, err := readYAMLFile(filePath, config.YAMLLimits)
+	var limitErr *yamlLimitError
+	if errors.As(err, &limitErr) {
+		result.addErrors("yaml-limits", []string{limitErr.Error()})
+		return result
+	}
 	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("Failed to read file: %v", err))
+		result.addErrors("read", []string{fmt.Sprintf("Failed to read file: %v", err)})
 		return result
 	}
+	result.data = data
 
-	// Parse YAML
+	// Parse YAML, refusing alias bombs before they are expanded
+	if err := checkAliasExpansion(data); err != nil {
+		result.addErrors("yaml-aliases", []string{err.Error()})
+		return result
+	}
+	// Documents and includes merged into the challenge that is linted; data
+	// keeps the file as written for locating findings
+	effective, err := resolveChallengeData(data, config)
+	if err != nil {
+		result.addErrors("include", []string{err.Error()})
+		return result
+	}
 	var challenge Challenge
-	err = yaml.Unmarshal(data, &challenge)
+	err = yaml.Unmarshal(effective, &challenge)
 	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("Invalid YAML format: %v", err))
+		result.addErrors("yaml", []string{fmt.Sprintf("Invalid YAML format: %v", err)})
 		return result
 	}
 
+	// Field-level view of the document for rules that inspect arbitrary keys
+	var raw map[string]interface{}
+	_ = yaml.Unmarshal(effective, &raw)
+
 	// Store challenge info for PR display
 	result.Name = challenge.Name
 	result.Description = challenge.Description
+	result.author = challenge.Author
+	result.category = challenge.Category
+	result.challenge = challenge
+
+	// Apply the category profile, if any
+	config = config.forCategory(challenge.Category)
+	config.sandbox = sandbox
+
+	// Expand ${VAR} placeholders before checking host and connection_info.
+	// Rules work on copies that are only adopted once they finished: a timed
+	// out rule keeps running in the background.
+	expanded := challenge
+	if result.checkErrors("placeholders", func() []string { return expandChallenge(&expanded, config) }) {
+		challenge = expanded
+	}
+	if untrustedRoot != "" {
+		var outside []string
+		challenge.Files, outside = confineFiles(filePath, challenge.Files)
+		result.addErrors("files", outside)
+	}
 
-	// Lint checks
-	result.Errors = append(result.Errors, checkFiles(filePath, challenge.Files)...)
-	result.Errors = append(result.Errors, checkRequirements(challenge, config.Requirements)...)
-	result.Errors = append(result.Errors, checkImage(challenge.Image)...)
-	result.Errors = append(result.Errors, checkState(challenge.State)...)
-	result.Errors = append(result.Errors, checkVersion(challenge.Version)...)
-	result.Errors = append(result.Errors, checkTags(challenge.Tags, config.Tags)...)
-	result.Warnings = append(result.Warnings, checkType(challenge.Type)...)
+	// Lint checks, attributed to the field they concern
+	result.checkErrors("flags", func() []string { return checkFlags(challenge.Flags) })
+	result.checkErrors("flag-tests", func() []string { return checkFlagTests(challenge) })
+	result.checkErrors("files", func() []string { return checkFiles(filePath, challenge.Files, config.MaxFileSize) })
+	result.checkErrors("files-entries", func() []string { return checkFileEntries(filePath, challenge.Files, config) })
+	result.checkErrors("filenames", func() []string { return checkFilenames(challenge, config.Filenames) })
+	result.checkErrors("requirements", func() []string { return checkRequirements(challenge, config.Requirements) })
+	result.checkErrors("image", func() []string { return checkImage(filepath.Dir(filePath), challenge.Image, config.AllowImage) })
+	result.checkErrors("host", func() []string { return checkHost(challenge.Host, config.RequireHost, config.HostPolicy) })
+	result.checkErrors("hints", func() []string { return checkHints(challenge, config.Hints) })
+	result.checkErrors("value", func() []string { return checkValue(challenge, config.Value) })
+	result.checkErrors("notes", func() []string { return checkNotes(challenge, config.Notes) })
+	result.checkErrors("prizes", func() []string { return checkPrizes(challenge, config.Prizes) })
+	result.checkErrors("extra", func() []string { return checkExtra(challenge.Type, challenge.Extra, config) })
+	result.checkErrors("schedule", func() []string { return checkSchedule(challenge, config) })
+	if gatedWave(challenge, config) == 0 {
+		result.checkErrors("state", func() []string { return checkState(challenge.State) })
+	}
+	result.checkErrors("version", func() []string { return checkVersion(challenge.Version) })
+	result.checkErrors("tags", func() []string { return checkTags(challenge.Tags, config.Tags) })
+	result.checkErrors("kubernetes", func() []string { return checkKubernetesManifests(filePath, challenge) })
+	result.checkErrors("inventory", func() []string { return checkInventory(challenge.Host, config) })
+	result.checkErrors("binaries", func() []string { return inspectContent("binaries", filePath, challenge, config) })
+	result.checkErrors("checksums", func() []string { return checkChecksums(filePath, challenge.Files, config.Checksums) })
+	result.checkErrors("lfs", func() []string { return checkLFS(filePath, challenge.Files, config.LFS) })
+	result.checkErrors("remote-files", func() []string { return checkExternalFiles(challenge.Files, nil) })
+	result.checkErrors("external-files", func() []string { return checkExternalFiles(nil, challenge.ExternalFiles) })
+	result.checkErrors("release-assets", func() []string { return checkReleaseAssets(challenge.Files, config.Releases) })
+	if auditMode {
+		result.checkErrors("host-live", func() []string { return checkHostLive(challenge.Host) })
+		result.checkErrors("image-registry", func() []string { return checkImageRegistry(challenge.Image) })
+	}
+	result.checkErrors("signatures", func() []string { return checkSignatures(filePath, challenge.Files, config.Signatures, config) })
+	result.checkSecurity("archive-limits", func() []string { return inspectContent("archive-limits", filePath, challenge, config) })
+	result.checkErrors("archive-password", func() []string { return inspectContent("archive-password", filePath, challenge, config) })
+	result.checkErrors("template", func() []string { return checkTemplate(effective, config) })
+	result.checkErrors("category-directory", func() []string { return checkCategoryDirectory(filePath, challenge.Category, config.CategoryDirectory) })
+	result.checkErrors("name-slug", func() []string { return checkNameSlug(filePath, challenge.Name, config.NameSlug) })
+	result.checkErrors("layout", func() []string { return checkLayout(filePath, config) })
+	result.checkErrors("policy-rules", func() []string { return checkCELRules(filePath, raw, config.policyRules) })
+	result.checkWarnings("type", func() []string { return checkType(challenge.Type) })
+	result.checkWarnings("malware", func() []string { return checkMalware(filePath, challenge.Files, config.Malware) })
+	result.checkWarnings("content-type", func() []string { return checkContentTypes(filePath, challenge.Files) })
+	result.checkErrors("healthcheck", func() []string { return checkHealthcheck(filePath, challenge, config.Healthcheck) })
+	result.checkWarnings("image-size", func() []string { return checkImageSize(challenge.Image, config.ImageSize) })
+	result.checkErrors("description-files", func() []string { return checkDescriptionFileURLs(challenge.Description) })
+	result.checkWarnings("description-files", func() []string { return checkDescriptionFiles(challenge) })
+	result.checkErrors("bilingual", func() []string { return checkBilingual(challenge, config.Bilingual) })
+	result.checkWarnings("bilingual", func() []string { return checkBilingualLength(challenge, config.Bilingual) })
+	result.checkWarnings("difficulty", func() []string { return inspectContent("difficulty", filePath, challenge, config) })
+	result.checkWarnings("artifacts", func() []string { return checkArtifacts(filePath, config.Artifacts) })
+
+	var deprecated []string
+	if result.checkErrors("deprecations", func() []string {
+		var expired []string
+		deprecated, expired = checkDeprecations(raw, config.Deprecations)
+		return expired
+	}) {
+		result.addDeprecations("deprecations", deprecated)
+	}
+
+	result.Fixes = fixesFor(challenge, config)
+	if diffMode && len(result.Fixes) > 0 {
+		// Best effort: a fix that cannot be applied is still reported by --fix
+		result.fixDiff, _ = fixDiff(filePath, data, result.Fixes, flagValues(challenge))
+	}
+	result.Checklist = evaluateChecklist(config.Checklist, filePath, result)
+	result.Owners = challengeOwners(filePath, config)
+	result.mentionOwners = config.CodeOwners.Mention
+	result.locateFindings(data, flagValues(challenge))
+	result.flags = staticFlags(challenge.Flags)
+	result.regexes = regexFlags(challenge.Flags)
+	if location, err := findTopLevelField(data, "flags"); err == nil && location != nil {
+		result.flagsAt = [2]int{location.Key.Line, location.Key.Column}
+	}
+	if anonymousMode {
+		anonymizeResult(&result, challenge)
+	}
+	sortFindings(result.Errors)
+	sortFindings(result.Warnings)
+	sortFindings(result.Deprecations)
 
 	return result
 }
 
-func checkFiles(challengePath string, files []string) []string {
+func checkFiles(challengePath string, files []string, maxFileSize int64) []string {
 	var errors []string
 	baseDir := filepath.Dir(challengePath)
-	const maxFileSize = 1024 * 1024 // 1MB in bytes
+	if maxFileSize <= 0 {
+		maxFileSize = defaultMaxFileSize
+	}
 
 	for _, file := range files {
-		fullPath := filepath.Join(baseDir, file)
+		if isRemoteFile(file) {
+			// Checked by checkExternalFiles and checkReleaseAssets
+			continue
+		}
+		fullPath := filepath.Join(baseDir, normalizeFilePath(file))
 		fileInfo, err := os.Stat(fullPath)
-		if os.IsNotExist(err) {
+		if err != nil && isSymlink(fullPath) {
+			target, _ := os.Readlink(fullPath)
+			errors = append(errors, fmt.Sprintf("File specified in 'files' is a broken symlink: %s -> %s", file, target))
+		} else if os.IsNotExist(err) {
 			errors = append(errors, fmt.Sprintf("File specified in 'files' does not exist: %s", file))
 		} else if err != nil {
 			errors = append(errors, fmt.Sprintf("Error accessing file: %s (%v)", file, err))
@@ -602,7 +1755,8 @@ func checkFiles(challengePath string, files []string) []string {
 			// Check file size
 			if fileInfo.Size() > maxFileSize {
 				sizeMB := float64(fileInfo.Size()) / (1024 * 1024)
-				errors = append(errors, fmt.Sprintf("File '%s' is too large: %.2f MB (maximum allowed: 1.00 MB)", file, sizeMB))
+				maxMB := float64(maxFileSize) / (1024 * 1024)
+				errors = append(errors, fmt.Sprintf("File '%s' is too large: %.2f MB (maximum allowed: %.2f MB)", file, sizeMB, maxMB))
 			}
 		}
 	}
@@ -610,6 +1764,17 @@ func checkFiles(challengePath string, files []string) []string {
 	return errors
 }
 
+// slashPath converts a files entry written with either separator to forward slashes
+func slashPath(file string) string {
+	return strings.ReplaceAll(file, `\`, "/")
+}
+
+// normalizeFilePath converts a files entry written with either separator to a
+// native path, so challenges authored on Windows lint the same on Linux CI
+func normalizeFilePath(file string) string {
+	return filepath.FromSlash(slashPath(file))
+}
+
 func checkRequirements(challenge Challenge, reqRule Rule) []string {
 	var errors []string
 
@@ -643,16 +1808,6 @@ func checkRequirements(challenge Challenge, reqRule Rule) []string {
 	return errors
 }
 
-func checkImage(image interface{}) []string {
-	var errors []string
-
-	if image != nil {
-		errors = append(errors, "Field 'image' should be null")
-	}
-
-	return errors
-}
-
 func checkState(state string) []string {
 	var errors []string
 
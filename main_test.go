@@ -2,10 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/google/go-github/v65/github"
 )
 
 func TestLintChallengeFile(t *testing.T) {
@@ -425,7 +432,7 @@ flags:
     }
   - {
       type: "static",
-      content: "flag{wat}",
+      content: "flag{other}",
     }
 tags:
   - easy
@@ -460,7 +467,7 @@ flags:
     content: "(.*)STUFF(.*)"
     data: "case_insensitive"
   - type: static
-    content: "flag{wat}"
+    content: "flag{other}"
 tags:
   - easy
 files: []
@@ -504,7 +511,7 @@ state: visible
 version: "0.1"
 `,
 			files:      []string{},
-			wantErrors: []string{"Invalid YAML format"},
+			wantErrors: []string{"Flag 1 must be either a string or a map"},
 		},
 		{
 			name: "ignore list with custom pattern - should skip requirements",
@@ -851,7 +858,7 @@ requirements:
 					for _, wantError := range tt.wantErrors {
 						found := false
 						for _, gotError := range result.Errors {
-							if strings.Contains(gotError, wantError) {
+							if strings.Contains(gotError.Message, wantError) {
 								found = true
 								break
 							}
@@ -875,7 +882,7 @@ requirements:
 					for _, wantWarning := range tt.wantWarnings {
 						found := false
 						for _, gotWarning := range result.Warnings {
-							if strings.Contains(gotWarning, wantWarning) {
+							if strings.Contains(gotWarning.Message, wantWarning) {
 								found = true
 								break
 							}
@@ -1008,7 +1015,7 @@ invalid yaml content:
 
 	found := false
 	for _, err := range result.Errors {
-		if strings.Contains(err, "Invalid YAML format") {
+		if strings.Contains(err.Message, "Invalid YAML format") {
 			found = true
 			break
 		}
@@ -1133,7 +1140,7 @@ func TestJSONOutputMarshalError(t *testing.T) {
 	output := map[string]interface{}{
 		"success": true,
 		"results": []LintResult{
-			{File: "test.yml", Errors: []string{}, Warnings: []string{}},
+			{File: "test.yml", Errors: []Finding{}, Warnings: []Finding{}},
 		},
 	}
 	jsonData, err := json.Marshal(output)
@@ -1146,3 +1153,269 @@ func TestJSONOutputMarshalError(t *testing.T) {
 		t.Fatalf("Output should be valid JSON: %v", err)
 	}
 }
+
+func TestCategoryProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	lintrcContent := `tags:
+  condition: and
+  patterns:
+    - type: static
+      values:
+        - easy
+        - medium
+        - hard
+requirements:
+  condition: none
+profiles:
+  web:
+    require_host: true
+  OSINT:
+    max_file_size: 512
+  pwn:
+    allow_image: true
+    tags:
+      condition: none`
+	if err := os.WriteFile(filepath.Join(tempDir, "lintrc.yaml"), []byte(lintrcContent), 0644); err != nil {
+		t.Fatalf("Failed to create lintrc.yaml: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	tests := []struct {
+		name       string
+		category   string
+		host       string
+		image      string
+		tag        string
+		wantErrors []string
+	}{
+		{name: "web without host", category: "web", host: "null", image: "null", tag: "easy", wantErrors: []string{"Field 'host' is required for this category"}},
+		{name: "web with host", category: "web", host: "https://web.example.com", image: "null", tag: "easy"},
+		{name: "osint attachment over profile limit", category: "osint", host: "null", image: "null", tag: "easy", wantErrors: []string{"is too large: 0.00 MB (maximum allowed: 0.00 MB)"}},
+		{name: "pwn allows image and skips tags", category: "pwn", host: "null", image: "registry.example.com/pwn:1.0", tag: "unknown"},
+		{name: "other category uses defaults", category: "crypto", host: "null", image: "registry.example.com/crypto:1.0", tag: "easy", wantErrors: []string{"Field 'image' should be null"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(tempDir, strings.ReplaceAll(tt.name, " ", "_"))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "attachment.txt"), []byte(strings.Repeat("A", 1024)), 0644); err != nil {
+				t.Fatalf("Failed to create attachment: %v", err)
+			}
+			yamlContent := `name: "chall"
+category: "` + tt.category + `"
+tags:
+  - ` + tt.tag + `
+files:
+  - attachment.txt
+image: ` + tt.image + `
+host: ` + tt.host + `
+state: visible
+version: "0.1"
+`
+			yamlPath := filepath.Join(dir, "challenge.yml")
+			if err := os.WriteFile(yamlPath, []byte(yamlContent), 0644); err != nil {
+				t.Fatalf("Failed to create challenge.yml: %v", err)
+			}
+
+			result := lintChallengeFile(yamlPath)
+			if len(result.Errors) != len(tt.wantErrors) {
+				t.Fatalf("Expected errors %v, got: %v", tt.wantErrors, result.Errors)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(result.Errors[i].Message, want) {
+					t.Errorf("Expected error containing '%s', got: %s", want, result.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckFilesBrokenSymlink(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	if err := os.Symlink("build/flag.zip", filepath.Join(tempDir, "flag.zip")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	errors := checkFiles(challengePath, []string{"flag.zip", "missing.txt"}, 0)
+	if len(errors) != 2 {
+		t.Fatalf("Expected 2 errors, got: %v", errors)
+	}
+	if !strings.Contains(errors[0], "broken symlink: flag.zip -> build/flag.zip") {
+		t.Errorf("Expected broken symlink error, got: %s", errors[0])
+	}
+	if !strings.Contains(errors[1], "does not exist: missing.txt") {
+		t.Errorf("Expected missing file error, got: %s", errors[1])
+	}
+}
+
+func TestWindowsStyleFilePaths(t *testing.T) {
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	if err := os.MkdirAll(filepath.Join(tempDir, "dist"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "dist", "flag.txt"), []byte("flag"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if errors := checkFiles(challengePath, []string{`dist\flag.txt`, "dist/flag.txt"}, 0); len(errors) != 0 {
+		t.Errorf("Expected both separators to resolve, got: %v", errors)
+	}
+
+	if _, err := writeChecksumManifest(challengePath, []string{`dist\flag.txt`}); err != nil {
+		t.Fatalf("writeChecksumManifest failed: %v", err)
+	}
+	if errors := checkChecksums(challengePath, []string{"dist/flag.txt"}, ChecksumRule{Required: true}); len(errors) != 0 {
+		t.Errorf("Expected manifest written from a Windows path to match, got: %v", errors)
+	}
+
+	if !isIgnored("web/wip", true, []string{`web\wip\`}) {
+		t.Error("Expected Windows-style ignore pattern to match")
+	}
+}
+
+func TestChangedDirectoriesMemoized(t *testing.T) {
+	var prFiles []*github.CommitFile
+	for i := 0; i < 50; i++ {
+		prFiles = append(prFiles, &github.CommitFile{Filename: github.String(fmt.Sprintf("web/app/src/static/file%d.js", i))})
+	}
+	prFiles = append(prFiles,
+		&github.CommitFile{Filename: github.String("web/app/src/main.go")},
+		&github.CommitFile{Filename: github.String("docs/README.md")},
+		&github.CommitFile{Filename: github.String("pwn/new/challenge.yml")},
+	)
+
+	calls := make(map[string]int)
+	got := changedDirectoriesWith(prFiles, func(dir string) bool {
+		calls[filepath.ToSlash(dir)]++
+		return filepath.ToSlash(dir) == "web/app"
+	})
+	want := []string{filepath.FromSlash("pwn/new"), filepath.FromSlash("web/app")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedDirectoriesWith() = %v, want %v", got, want)
+	}
+	for dir, n := range calls {
+		if n != 1 {
+			t.Errorf("%s was checked %d times, want once", dir, n)
+		}
+	}
+	if calls["web"] != 0 {
+		t.Error("directories above the challenge should not be checked")
+	}
+}
+
+func TestChangedDirectoriesRenames(t *testing.T) {
+	file := func(name, status, previous string) *github.CommitFile {
+		f := &github.CommitFile{Filename: github.String(name), Status: github.String(status)}
+		if previous != "" {
+			f.PreviousFilename = github.String(previous)
+		}
+		return f
+	}
+	// The challenges at the head of the PR
+	challenges := map[string]bool{"web/renamed": true, "web/app": true, "crypto/rsa": true}
+	hasChallenge := func(dir string) bool { return challenges[filepath.ToSlash(dir)] }
+
+	tests := []struct {
+		name  string
+		files []*github.CommitFile
+		want  []string
+	}{
+		{
+			name:  "directory rename",
+			files: []*github.CommitFile{file("web/renamed/challenge.yml", "renamed", "web/original/challenge.yml"), file("web/renamed/app.py", "renamed", "web/original/app.py")},
+			want:  []string{"web/renamed"},
+		},
+		{
+			name:  "case-only rename",
+			files: []*github.CommitFile{file("crypto/rsa/challenge.yml", "renamed", "Crypto/RSA/challenge.yml")},
+			want:  []string{"crypto/rsa"},
+		},
+		{
+			name:  "removed challenge",
+			files: []*github.CommitFile{file("pwn/gone/challenge.yml", "removed", ""), file("pwn/gone/chall", "removed", "")},
+		},
+		{
+			name:  "file moved between challenges",
+			files: []*github.CommitFile{file("web/renamed/lib.js", "renamed", "web/app/static/lib.js")},
+			want:  []string{"web/app", "web/renamed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := changedDirectoriesWith(tt.files, hasChallenge)
+			var want []string
+			for _, dir := range tt.want {
+				want = append(want, filepath.FromSlash(dir))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("changedDirectoriesWith() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestListPRFilesPages(t *testing.T) {
+	const lastPage = 5
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		page := 1
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		if page < lastPage {
+			link := func(p int) string { return fmt.Sprintf("<%s%s?page=%d&per_page=100>", server.URL, r.URL.Path, p) }
+			w.Header().Set("Link", link(page+1)+`; rel="next", `+link(lastPage)+`; rel="last"`)
+		}
+		var files []string
+		for i := 0; i < 2; i++ {
+			files = append(files, fmt.Sprintf(`{"filename": "dir%d/file%d"}`, page, i))
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(files, ","))
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	files, err := listPRFiles(Env{owner: "owner", repo: "repo", prNumber: 1})
+	if err != nil {
+		t.Fatalf("listPRFiles() error: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.GetFilename())
+	}
+	var want []string
+	for page := 1; page <= lastPage; page++ {
+		want = append(want, fmt.Sprintf("dir%d/file0", page), fmt.Sprintf("dir%d/file1", page))
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("listPRFiles() = %v, want pages in order %v", names, want)
+	}
+	if requests.Load() != lastPage {
+		t.Errorf("made %d requests, want %d", requests.Load(), lastPage)
+	}
+}
+
+func TestChangedDirectoriesSorted(t *testing.T) {
+	var prFiles []*github.CommitFile
+	for _, name := range []string{"web/z/challenge.yml", "pwn/b/challenge.yml", "web/a/challenge.yml", "misc/c/challenge.yml"} {
+		prFiles = append(prFiles, &github.CommitFile{Filename: github.String(name)})
+	}
+	for i := 0; i < 5; i++ {
+		got := changedDirectories(prFiles)
+		want := []string{"misc/c", "pwn/b", "web/a", "web/z"}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("changedDirectories() = %v, want %v", got, want)
+		}
+	}
+}
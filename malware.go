@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// virusTotalAPIURL is the VirusTotal API endpoint, replaced in tests
+var virusTotalAPIURL = "https://www.virustotal.com"
+
+// clamdChunkSize is the size of the chunks streamed to clamd
+const clamdChunkSize = 64 * 1024
+
+// clamdTimeout bounds a clamd scan of one file
+const clamdTimeout = 2 * time.Minute
+
+// MalwareRule configures scanning attachments for malware
+type MalwareRule struct {
+	// ClamAV is the clamd socket, unix:///path or tcp://host:port
+	ClamAV string `yaml:"clamav"`
+	// VirusTotal looks up the SHA256 of each attachment with the API key in
+	// VT_API_KEY; files are never uploaded
+	VirusTotal bool `yaml:"virustotal"`
+	// MinDetections is the number of VirusTotal engines that must flag a
+	// file for a finding (default 1)
+	MinDetections int `yaml:"min_detections"`
+}
+
+// checkMalware scans the local attachments with the configured scanners
+func checkMalware(challengePath string, files []string, rule MalwareRule) []string {
+	var warnings []string
+
+	if rule.ClamAV == "" && !rule.VirusTotal {
+		return warnings
+	}
+	apiKey := os.Getenv("VT_API_KEY")
+	if rule.VirusTotal && apiKey == "" {
+		warnings = append(warnings, "VirusTotal lookups are enabled but VT_API_KEY is not set")
+	}
+	baseDir := filepath.Dir(challengePath)
+
+	for _, file := range files {
+		if isRemoteFile(file) {
+			continue
+		}
+		path := filepath.Join(baseDir, normalizeFilePath(file))
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			// Missing files are reported by checkFiles
+			continue
+		}
+
+		if rule.ClamAV != "" {
+			signature, err := clamdScan(rule.ClamAV, path)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("Attachment '%s' could not be scanned by ClamAV: %v", file, err))
+			} else if signature != "" {
+				warnings = append(warnings, fmt.Sprintf("Attachment '%s' is detected by ClamAV as %s", file, signature))
+			}
+		}
+
+		if rule.VirusTotal && apiKey != "" {
+			malicious, total, err := virusTotalLookup(path, apiKey)
+			minimum := rule.MinDetections
+			if minimum <= 0 {
+				minimum = 1
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("Attachment '%s' could not be looked up on VirusTotal: %v", file, err))
+			} else if malicious >= minimum {
+				warnings = append(warnings, fmt.Sprintf("Attachment '%s' is flagged as malicious by %d of %d VirusTotal engines", file, malicious, total))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// clamdScan streams a file to clamd with the INSTREAM command and returns
+// the detected signature, or "" when the file is clean
+func clamdScan(address, path string) (string, error) {
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "unix" && u.Scheme != "tcp") {
+		return "", fmt.Errorf("clamav must be unix:///path or tcp://host:port (got '%s')", address)
+	}
+	network, target := u.Scheme, u.Host
+	if network == "unix" {
+		target = u.Path
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	conn, err := net.DialTimeout(network, target, dialTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(clamdTimeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", err
+	}
+	chunk := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := file.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(append(size, chunk[:n]...)); err != nil {
+				return "", err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return "", err
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+	response := strings.TrimSpace(strings.TrimRight(string(reply), "\x00"))
+	response = strings.TrimPrefix(response, "stream: ")
+	switch {
+	case response == "OK":
+		return "", nil
+	case strings.HasSuffix(response, " FOUND"):
+		return strings.TrimSuffix(response, " FOUND"), nil
+	default:
+		return "", fmt.Errorf("clamd replied '%s'", response)
+	}
+}
+
+// virusTotalLookup looks up the SHA256 of a file and returns how many
+// engines flag it as malicious out of those that analyzed it. Files
+// VirusTotal has never seen count as clean.
+func virusTotalLookup(path, apiKey string) (int, int, error) {
+	sum, err := sha256File(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, virusTotalAPIURL+"/api/v3/files/"+sum, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("x-apikey", apiKey)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var report struct {
+		Data struct {
+			Attributes struct {
+				Stats map[string]int `json:"last_analysis_stats"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return 0, 0, fmt.Errorf("invalid response: %v", err)
+	}
+	stats := report.Data.Attributes.Stats
+	total := 0
+	for _, category := range []string{"malicious", "suspicious", "undetected", "harmless"} {
+		total += stats[category]
+	}
+	return stats["malicious"], total, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeClamd answers INSTREAM scans, detecting files that contain "EICAR"
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "clamd.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			command, _ := reader.ReadString(0)
+			if command != "zINSTREAM\x00" {
+				conn.Write([]byte("UNKNOWN COMMAND\x00"))
+				conn.Close()
+				continue
+			}
+			var data bytes.Buffer
+			size := make([]byte, 4)
+			for {
+				if _, err := io.ReadFull(reader, size); err != nil {
+					break
+				}
+				n := binary.BigEndian.Uint32(size)
+				if n == 0 {
+					break
+				}
+				io.CopyN(&data, reader, int64(n))
+			}
+			if bytes.Contains(data.Bytes(), []byte("EICAR")) {
+				conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			} else {
+				conn.Write([]byte("stream: OK\x00"))
+			}
+			conn.Close()
+		}
+	}()
+	return "unix://" + socket
+}
+
+func TestCheckMalwareClamAV(t *testing.T) {
+	address := fakeClamd(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "clean.bin"), []byte("hello"), 0644)
+	// Larger than one chunk, with the signature in the second chunk
+	os.WriteFile(filepath.Join(dir, "infected.bin"), append(make([]byte, clamdChunkSize+10), "EICAR"...), 0644)
+
+	files := []string{"clean.bin", "infected.bin", "missing.bin", "https://example.com/remote.bin"}
+	warnings := checkMalware(filepath.Join(dir, "challenge.yml"), files, MalwareRule{ClamAV: address})
+	want := []string{"Attachment 'infected.bin' is detected by ClamAV as Eicar-Test-Signature"}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+
+	warnings = checkMalware(filepath.Join(dir, "challenge.yml"), []string{"clean.bin"}, MalwareRule{ClamAV: "http://localhost:3310"})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "clamav must be unix:///path or tcp://host:port") {
+		t.Errorf("warnings = %v", warnings)
+	}
+}
+
+func TestCheckMalwareVirusTotal(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "known.exe"), []byte("known"), 0644)
+	os.WriteFile(filepath.Join(dir, "unknown.exe"), []byte("unknown"), 0644)
+	knownHash, err := sha256File(filepath.Join(dir, "known.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-apikey") != "vt-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet {
+			uploaded = true
+		}
+		hash := strings.TrimPrefix(r.URL.Path, "/api/v3/files/")
+		if hash == knownHash {
+			fmt.Fprint(w, `{"data": {"attributes": {"last_analysis_stats": {"malicious": 3, "suspicious": 1, "undetected": 60, "harmless": 0, "type-unsupported": 5}}}}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	origURL := virusTotalAPIURL
+	defer func() { virusTotalAPIURL = origURL }()
+	virusTotalAPIURL = server.URL
+
+	challengePath := filepath.Join(dir, "challenge.yml")
+	files := []string{"known.exe", "unknown.exe"}
+
+	t.Setenv("VT_API_KEY", "vt-key")
+	want := []string{"Attachment 'known.exe' is flagged as malicious by 3 of 64 VirusTotal engines"}
+	if warnings := checkMalware(challengePath, files, MalwareRule{VirusTotal: true}); !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+	if warnings := checkMalware(challengePath, files, MalwareRule{VirusTotal: true, MinDetections: 5}); len(warnings) != 0 {
+		t.Errorf("warnings below min_detections: %v", warnings)
+	}
+	if uploaded {
+		t.Error("a file was uploaded to VirusTotal")
+	}
+
+	t.Setenv("VT_API_KEY", "")
+	want = []string{"VirusTotal lookups are enabled but VT_API_KEY is not set"}
+	if warnings := checkMalware(challengePath, files, MalwareRule{VirusTotal: true}); !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+}
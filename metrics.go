@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// metricsPath is set by --metrics-file; metrics are only written when it is non-empty
+var metricsPath string
+
+// lintStart is when linting began, for the duration metric
+var lintStart = time.Now()
+
+// formatMetrics renders a run in the Prometheus text exposition format
+func formatMetrics(results []LintResult, duration time.Duration) string {
+	var out strings.Builder
+
+	failed := 0
+	counts := make(map[[2]string]int)
+	for _, result := range results {
+		if len(result.Errors) > 0 {
+			failed++
+		}
+		for _, findings := range [][]Finding{result.Errors, result.Warnings, result.Deprecations} {
+			for _, finding := range findings {
+				counts[[2]string{historyRule(finding), finding.Severity}]++
+			}
+		}
+	}
+
+	writeMetric(&out, "clilint_challenges", "Number of challenge.yml files linted.", float64(len(results)))
+	writeMetric(&out, "clilint_challenges_failed", "Number of challenges with at least one error.", float64(failed))
+
+	out.WriteString("# HELP clilint_findings Number of findings by rule and severity.\n")
+	out.WriteString("# TYPE clilint_findings gauge\n")
+	keys := make([][2]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		fmt.Fprintf(&out, "clilint_findings{rule=\"%s\",severity=\"%s\"} %d\n", labelValue(key[0]), labelValue(key[1]), counts[key])
+	}
+
+	writeMetric(&out, "clilint_lint_duration_seconds", "Time taken by the lint run.", duration.Seconds())
+	writeMetric(&out, "clilint_last_run_timestamp_seconds", "Unix time the lint run finished.", float64(now().Unix()))
+	return out.String()
+}
+
+func writeMetric(out *strings.Builder, name, help string, value float64) {
+	fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, value)
+}
+
+// labelValue escapes a Prometheus label value
+func labelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// recordMetrics writes the --metrics-file, if one was given. The file is
+// replaced atomically so collectors such as node_exporter's textfile
+// collector never read a partial file.
+func recordMetrics(results []LintResult) {
+	if metricsPath == "" {
+		return
+	}
+	if err := writeFileAtomic(metricsPath, formatMetrics(results, time.Since(lintStart))); err != nil {
+		log.Printf("Warning: failed to write metrics: %v", err)
+	}
+}
+
+func writeFileAtomic(path, content string) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), ".clilint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.WriteString(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormatMetrics(t *testing.T) {
+	origNow := now
+	now = func() time.Time { return time.Unix(1792000000, 0) }
+	defer func() {
+		now = origNow
+	}()
+
+	results := []LintResult{
+		{
+			File: "web/a/challenge.yml",
+			Errors: []Finding{
+				{RuleID: "state", Severity: SeverityError},
+				{RuleID: "files", Severity: SeverityError},
+				{RuleID: "files", Severity: SeverityError},
+			},
+			Warnings: []Finding{{RuleID: "type", Severity: SeverityWarning}},
+		},
+		{
+			File:     "web/b/challenge.yml",
+			Warnings: []Finding{{Severity: SeverityWarning}},
+		},
+	}
+
+	want := `# HELP clilint_challenges Number of challenge.yml files linted.
+# TYPE clilint_challenges gauge
+clilint_challenges 2
+# HELP clilint_challenges_failed Number of challenges with at least one error.
+# TYPE clilint_challenges_failed gauge
+clilint_challenges_failed 1
+# HELP clilint_findings Number of findings by rule and severity.
+# TYPE clilint_findings gauge
+clilint_findings{rule="files",severity="error"} 2
+clilint_findings{rule="other",severity="warning"} 1
+clilint_findings{rule="state",severity="error"} 1
+clilint_findings{rule="type",severity="warning"} 1
+# HELP clilint_lint_duration_seconds Time taken by the lint run.
+# TYPE clilint_lint_duration_seconds gauge
+clilint_lint_duration_seconds 1.5
+# HELP clilint_last_run_timestamp_seconds Unix time the lint run finished.
+# TYPE clilint_last_run_timestamp_seconds gauge
+clilint_last_run_timestamp_seconds 1.792e+09
+`
+	if got := formatMetrics(results, 1500*time.Millisecond); got != want {
+		t.Errorf("formatMetrics() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	if got := labelValue("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("labelValue() = %s", got)
+	}
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "clilint.prom")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(path, "clilint_challenges 1\n"); err != nil {
+		t.Fatalf("writeFileAtomic() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "clilint_challenges 1\n" {
+		t.Errorf("unexpected contents: %q", data)
+	}
+
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Errorf("temporary file left behind: %v", entries)
+	}
+}
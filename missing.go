@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// solveScriptNames are the base names, without extension, of files that
+// mark a directory as a challenge
+var solveScriptNames = map[string]bool{
+	"solve":   true,
+	"solver":  true,
+	"exploit": true,
+}
+
+// MissingChallengeRule configures the search for directories that look like
+// challenges but have no challenge.yml
+type MissingChallengeRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Markers are additional file or directory names that mark a challenge
+	// directory, e.g. docker-compose.yml
+	Markers []string `yaml:"markers"`
+}
+
+// lintMissingChallenges reports the directories under rootDir that look like
+// challenges but have no challenge.yml, one result per directory, given the
+// results of the challenges found there
+func lintMissingChallenges(rootDir string, challenges []LintResult) []LintResult {
+	var results []LintResult
+
+	config, err := loadLintConfig()
+	if err != nil || !config.MissingChallenge.Enabled {
+		return results
+	}
+	var challengePaths []string
+	for _, challenge := range challenges {
+		challengePaths = append(challengePaths, challenge.File)
+	}
+	options := walkFlags
+	options.Ignore = config.Ignore
+	dirs, err := findMissingChallenges(rootDir, challengePaths, options, config.MissingChallenge)
+	if err != nil {
+		// The walk for challenge.yml files reports the same error
+		return results
+	}
+
+	for _, dir := range dirs {
+		result := LintResult{File: dir.Path, docsBaseURL: config.DocsBaseURL}
+		result.addWarnings("missing-challenge", []string{
+			fmt.Sprintf("Directory looks like a challenge (%s) but has no challenge.yml", strings.Join(dir.Reasons, ", ")),
+		})
+		results = append(results, result)
+	}
+	return results
+}
+
+// missingChallenge is a directory that looks like a challenge
+type missingChallenge struct {
+	Path    string
+	Reasons []string
+}
+
+// findMissingChallenges walks rootDir for directories that contain dist/, a
+// Dockerfile, a solve script, or one of the rule's markers, or that sit next
+// to challenges in a category directory, but have no challenge.yml.
+// Directories inside a challenge, above one, or inside a reported directory
+// are not reported.
+func findMissingChallenges(rootDir string, challengePaths []string, options walkOptions, rule MissingChallengeRule) ([]missingChallenge, error) {
+	challengeDirs := make(map[string]bool)
+	containsChallenge := make(map[string]bool)
+	categoryDirs := make(map[string]bool)
+	for _, path := range challengePaths {
+		dir := filepath.Clean(filepath.Dir(path))
+		challengeDirs[dir] = true
+		categoryDirs[filepath.Dir(dir)] = true
+		for parent := filepath.Dir(dir); ; parent = filepath.Dir(parent) {
+			containsChallenge[parent] = true
+			if parent == filepath.Dir(parent) || parent == "." {
+				break
+			}
+		}
+	}
+
+	var missing []missingChallenge
+	root := filepath.Clean(rootDir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if prunedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".") || isIgnored(rel, true, options.Ignore) || exceedsDepth(rel, options.MaxDepth) {
+			return filepath.SkipDir
+		}
+		if challengeDirs[path] {
+			return filepath.SkipDir
+		}
+		if containsChallenge[path] {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, "challenge.yml")); err == nil {
+			// A template or a challenge excluded from the lint
+			return filepath.SkipDir
+		}
+
+		reasons := challengeMarkers(path, rule.Markers)
+		if categoryDirs[filepath.Dir(path)] {
+			reasons = append(reasons, fmt.Sprintf("next to challenges in '%s'", filepath.Base(filepath.Dir(path))))
+		}
+		if len(reasons) == 0 {
+			return nil
+		}
+		missing = append(missing, missingChallenge{Path: path, Reasons: reasons})
+		return filepath.SkipDir
+	})
+	return missing, err
+}
+
+// challengeMarkers returns why the entries of dir make it look like a challenge
+func challengeMarkers(dir string, markers []string) []string {
+	var reasons []string
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return reasons
+	}
+	extra := make(map[string]bool)
+	for _, marker := range markers {
+		extra[marker] = true
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		switch {
+		case name == "dist" && entry.IsDir():
+			reasons = append(reasons, "has dist/")
+		case name == "Dockerfile":
+			reasons = append(reasons, "has a Dockerfile")
+		case solveScriptNames[strings.ToLower(base)] && !entry.IsDir():
+			reasons = append(reasons, fmt.Sprintf("has solve script '%s'", name))
+		case extra[name]:
+			reasons = append(reasons, fmt.Sprintf("has '%s'", name))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindMissingChallenges(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, file := range []string{
+		"web/login/challenge.yml",
+		"web/login/server/Dockerfile",
+		"web/forgotten/README.md",
+		"pwn/heap/challenge.yml",
+		"pwn/wip/Dockerfile",
+		"pwn/wip/solve.py",
+		"pwn/wip/src/Dockerfile",
+		"rev/crackme/dist/crackme",
+		"misc/notes.md",
+		"misc/drafts/compose/docker-compose.yml",
+		"templates/challenge/challenge.yml",
+		"templates/example/Dockerfile",
+		".github/workflows/Dockerfile",
+	} {
+		path := filepath.Join(tempDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+	challenges := []string{
+		filepath.Join(tempDir, "web/login/challenge.yml"),
+		filepath.Join(tempDir, "pwn/heap/challenge.yml"),
+	}
+
+	tests := []struct {
+		name    string
+		ignore  []string
+		markers []string
+		want    map[string][]string
+	}{
+		{
+			name: "markers and category siblings",
+			want: map[string][]string{
+				"web/forgotten":     {"next to challenges in 'web'"},
+				"pwn/wip":           {"has a Dockerfile", "has solve script 'solve.py'", "next to challenges in 'pwn'"},
+				"rev/crackme":       {"has dist/"},
+				"templates/example": {"has a Dockerfile"},
+			},
+		},
+		{
+			name:    "custom markers and ignore patterns",
+			ignore:  []string{"templates/", "web/forgotten/"},
+			markers: []string{"docker-compose.yml"},
+			want: map[string][]string{
+				"pwn/wip":             {"has a Dockerfile", "has solve script 'solve.py'", "next to challenges in 'pwn'"},
+				"rev/crackme":         {"has dist/"},
+				"misc/drafts/compose": {"has 'docker-compose.yml'"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, err := findMissingChallenges(tempDir, challenges, walkOptions{Ignore: tt.ignore}, MissingChallengeRule{Enabled: true, Markers: tt.markers})
+			if err != nil {
+				t.Fatalf("findMissingChallenges() error = %v", err)
+			}
+			got := make(map[string][]string)
+			for _, dir := range missing {
+				rel, _ := filepath.Rel(tempDir, dir.Path)
+				got[filepath.ToSlash(rel)] = dir.Reasons
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findMissingChallenges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintMissingChallenges(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, file := range []string{"crypto/rsa/challenge.yml", "crypto/ecc/solver.sage"} {
+		path := filepath.Join(tempDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("name: rsa\n"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+	}
+	challenges := []LintResult{{File: filepath.Join(tempDir, "crypto/rsa/challenge.yml")}}
+	t.Cleanup(func() { inlineConfig = "" })
+
+	inlineConfig = "version: \"0.1\"\n"
+	if results := lintMissingChallenges(tempDir, challenges); len(results) != 0 {
+		t.Errorf("lintMissingChallenges() without missing_challenge = %v, want none", results)
+	}
+
+	inlineConfig = "missing_challenge:\n  enabled: true\n"
+	results := lintMissingChallenges(tempDir, challenges)
+	if len(results) != 1 {
+		t.Fatalf("lintMissingChallenges() = %v, want one result", results)
+	}
+	if results[0].File != filepath.Join(tempDir, "crypto/ecc") {
+		t.Errorf("File = %s, want the crypto/ecc directory", results[0].File)
+	}
+	if len(results[0].Warnings) != 1 || results[0].Warnings[0].RuleID != "missing-challenge" ||
+		!strings.Contains(results[0].Warnings[0].Message, "has solve script 'solver.sage', next to challenges in 'crypto'") {
+		t.Errorf("Warnings = %v, want a missing-challenge warning naming the solve script", results[0].Warnings)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRepoCacheDir is where multi clones repositories unless configured otherwise
+const defaultRepoCacheDir = ".clilint-repos"
+
+// RepoSpec is one challenge repository of a federated lint run
+type RepoSpec struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`  // cloned or fetched into the cache directory
+	Ref  string `yaml:"ref"`  // branch or tag, defaults to the remote's default branch
+	Path string `yaml:"path"` // existing checkout, used instead of url
+}
+
+// MultiConfig is the repos.yaml file read by the multi command
+type MultiConfig struct {
+	CacheDir string     `yaml:"cache_dir"`
+	Repos    []RepoSpec `yaml:"repos"`
+}
+
+// repoChallenge identifies a challenge of one repository for collision checks
+type repoChallenge struct {
+	repo  string
+	index int // index of the challenge's LintResult
+	name  string
+	flags []parsedFlag
+}
+
+func runMulti(args []string) {
+	reposPath := "repos.yaml"
+	jsonOutput := false
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--json" {
+			jsonOutput = true
+		} else if args[i] == "--repos" && i+1 < len(args) {
+			i++
+			reposPath = args[i]
+		} else if value, ok := strings.CutPrefix(args[i], "--repos="); ok {
+			reposPath = value
+		}
+	}
+
+	config, err := loadMultiConfig(reposPath)
+	if err != nil {
+		log.Fatalf("Error loading %s: %v", reposPath, err)
+	}
+
+	var allResults []LintResult
+	var challenges []repoChallenge
+	for _, repo := range config.Repos {
+		dir, err := fetchRepo(repo, config.CacheDir)
+		if err != nil {
+			log.Fatalf("Error fetching repository %s: %v", repo.Name, err)
+		}
+		if !jsonOutput {
+			fmt.Printf("📦 %s (%s)\n", repo.Name, dir)
+		}
+
+		results, found, err := lintRepo(repo.Name, dir)
+		if err != nil {
+			log.Fatalf("Error linting repository %s: %v", repo.Name, err)
+		}
+		for _, c := range found {
+			c.index += len(allResults)
+			challenges = append(challenges, c)
+		}
+		allResults = append(allResults, results...)
+	}
+
+	checkCrossRepoCollisions(allResults, challenges)
+	sortResults(allResults)
+
+	hasErrors := hasLintErrors(allResults)
+	if jsonOutput {
+		jsonData, err := marshalResults(allResults, hasErrors)
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		writeResults(os.Stdout, allResults)
+	}
+
+	if hasErrors {
+		os.Exit(1)
+	} else if !jsonOutput {
+		fmt.Printf("All challenge.yml files in %d repositories passed linting! 🎉\n", len(config.Repos))
+	}
+}
+
+// loadMultiConfig reads repos.yaml, resolving relative paths against its directory
+func loadMultiConfig(path string) (*MultiConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config MultiConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Repos) == 0 {
+		return nil, fmt.Errorf("no repos listed")
+	}
+
+	baseDir := filepath.Dir(path)
+	if config.CacheDir == "" {
+		config.CacheDir = defaultRepoCacheDir
+	}
+	if !filepath.IsAbs(config.CacheDir) {
+		config.CacheDir = filepath.Join(baseDir, config.CacheDir)
+	}
+
+	seen := make(map[string]bool)
+	for i, repo := range config.Repos {
+		if repo.Name == "" {
+			return nil, fmt.Errorf("repos[%d]: name is required", i)
+		}
+		if seen[repo.Name] {
+			return nil, fmt.Errorf("repos[%d]: duplicate name '%s'", i, repo.Name)
+		}
+		seen[repo.Name] = true
+		if repo.URL == "" && repo.Path == "" {
+			return nil, fmt.Errorf("repos[%d]: one of url or path is required", i)
+		}
+		if repo.Path != "" && !filepath.IsAbs(repo.Path) {
+			config.Repos[i].Path = filepath.Join(baseDir, repo.Path)
+		}
+	}
+
+	return &config, nil
+}
+
+// fetchRepo makes a shallow checkout of repo available and returns its directory
+func fetchRepo(repo RepoSpec, cacheDir string) (string, error) {
+	if repo.Path != "" {
+		return repo.Path, nil
+	}
+
+	dir := filepath.Join(cacheDir, repo.Name)
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		ref := repo.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		if output, err := exec.Command("git", "-C", dir, "fetch", "--depth", "1", "origin", ref).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git fetch failed: %v\n%s", err, output)
+		}
+		if output, err := exec.Command("git", "-C", dir, "checkout", "--force", "FETCH_HEAD").CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git checkout failed: %v\n%s", err, output)
+		}
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	cloneArgs := []string{"clone", "--depth", "1"}
+	if repo.Ref != "" {
+		cloneArgs = append(cloneArgs, "--branch", repo.Ref)
+	}
+	cloneArgs = append(cloneArgs, repo.URL, dir)
+	if output, err := exec.Command("git", cloneArgs...).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git clone failed: %v\n%s", err, output)
+	}
+	return dir, nil
+}
+
+// lintRepo lints every challenge of a repository with the repository's own
+// lintrc.yaml. Result paths are prefixed with the repository name.
+func lintRepo(name, dir string) ([]LintResult, []repoChallenge, error) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+
+	results, err := lintChallenges(".")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var challenges []repoChallenge
+	for i := range results {
+		challenge, err := readChallenge(results[i].File)
+		results[i].File = fmt.Sprintf("%s:%s", name, filepath.ToSlash(results[i].File))
+		if err != nil {
+			// Reported as invalid YAML by the lint itself
+			continue
+		}
+		challenges = append(challenges, repoChallenge{repo: name, index: i, name: challenge.Name, flags: staticFlags(challenge.Flags)})
+	}
+	return results, challenges, nil
+}
+
+// checkCrossRepoCollisions reports challenge names and flags shared by
+// challenges of different repositories: names clash once the events share a
+// scoreboard, and shared flags let one event's solvers spoil the other
+func checkCrossRepoCollisions(results []LintResult, challenges []repoChallenge) {
+	names := make(map[string][]repoChallenge)
+	for _, c := range challenges {
+		if c.name != "" {
+			names[c.name] = append(names[c.name], c)
+		}
+	}
+
+	for _, c := range challenges {
+		result := &results[c.index]
+		if others := otherRepoFiles(results, c, names[c.name]); c.name != "" && len(others) > 0 {
+			result.addErrors("cross-repo-name", []string{fmt.Sprintf("Challenge name '%s' is also used by %s", c.name, strings.Join(others, ", "))})
+		}
+
+		// Flags clash when they are equal or CTFd compares them case-insensitively
+		var others []flagOwner
+		for _, other := range challenges {
+			if other.repo != c.repo {
+				others = append(others, flagOwner{file: results[other.index].File, flags: other.flags})
+			}
+		}
+		result.addErrors("cross-repo-flag", flagCollisions(flagOwner{file: result.File, flags: c.flags}, others))
+	}
+}
+
+// otherRepoFiles returns the sorted files of the challenges in users that
+// belong to a different repository than c
+func otherRepoFiles(results []LintResult, c repoChallenge, users []repoChallenge) []string {
+	var files []string
+	for _, other := range users {
+		if other.repo != c.repo {
+			files = append(files, results[other.index].File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
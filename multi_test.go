@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRepoChallenge(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+}
+
+func TestLoadMultiConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "repos.yaml")
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing name", "repos:\n  - url: https://example.com/a.git\n", "name is required"},
+		{"duplicate name", "repos:\n  - name: a\n    path: a\n  - name: a\n    path: b\n", "duplicate name 'a'"},
+		{"missing source", "repos:\n  - name: a\n", "one of url or path is required"},
+		{"empty", "cache_dir: cache\n", "no repos listed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write repos.yaml: %v", err)
+			}
+			_, err := loadMultiConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := os.WriteFile(path, []byte("repos:\n  - name: main\n    path: main\n  - name: beginners\n    url: https://example.com/b.git\n"), 0644); err != nil {
+		t.Fatalf("Failed to write repos.yaml: %v", err)
+	}
+	config, err := loadMultiConfig(path)
+	if err != nil {
+		t.Fatalf("loadMultiConfig failed: %v", err)
+	}
+	if config.Repos[0].Path != filepath.Join(tempDir, "main") {
+		t.Errorf("Expected path relative to repos.yaml, got %s", config.Repos[0].Path)
+	}
+	if config.CacheDir != filepath.Join(tempDir, defaultRepoCacheDir) {
+		t.Errorf("Expected default cache dir, got %s", config.CacheDir)
+	}
+}
+
+func TestCrossRepoCollisions(t *testing.T) {
+	tempDir := t.TempDir()
+	writeRepoChallenge(t, filepath.Join(tempDir, "main", "web", "login"),
+		"name: login\nflags:\n  - Alpaca{shared}\n")
+	writeRepoChallenge(t, filepath.Join(tempDir, "main", "web", "other"),
+		"name: other\nflags:\n  - Alpaca{only_main}\n")
+	writeRepoChallenge(t, filepath.Join(tempDir, "beginners", "web", "login"),
+		"name: login\nflags:\n  - type: static\n    content: Alpaca{shared}\n")
+
+	var allResults []LintResult
+	var challenges []repoChallenge
+	for _, name := range []string{"main", "beginners"} {
+		results, found, err := lintRepo(name, filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("lintRepo(%s) failed: %v", name, err)
+		}
+		for _, c := range found {
+			c.index += len(allResults)
+			challenges = append(challenges, c)
+		}
+		allResults = append(allResults, results...)
+	}
+	checkCrossRepoCollisions(allResults, challenges)
+
+	collisions := make(map[string][]string)
+	for _, result := range allResults {
+		for _, err := range result.Errors {
+			if strings.Contains(err.Message, "also used by") {
+				collisions[result.File] = append(collisions[result.File], err.Message)
+			}
+		}
+	}
+
+	mainLogin := collisions["main:web/login/challenge.yml"]
+	if len(mainLogin) != 2 {
+		t.Fatalf("Expected name and flag collisions for main:web/login, got: %v", collisions)
+	}
+	if !strings.Contains(mainLogin[0], "Challenge name 'login' is also used by beginners:web/login/challenge.yml") {
+		t.Errorf("Unexpected name collision: %s", mainLogin[0])
+	}
+	if strings.Contains(mainLogin[1], "Alpaca{shared}") {
+		t.Errorf("Flag collision must not reveal the flag: %s", mainLogin[1])
+	}
+	if len(collisions["beginners:web/login/challenge.yml"]) != 2 {
+		t.Errorf("Expected collisions reported on both sides, got: %v", collisions)
+	}
+	if len(collisions["main:web/other/challenge.yml"]) != 0 {
+		t.Errorf("Expected no collisions for main:web/other, got: %v", collisions)
+	}
+}
+
+func TestFetchRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tempDir := t.TempDir()
+	source := filepath.Join(tempDir, "source")
+	writeRepoChallenge(t, filepath.Join(source, "web", "chall"), "name: chall\n")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", source, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	repo := RepoSpec{Name: "events", URL: source, Ref: "main"}
+	cacheDir := filepath.Join(tempDir, "cache")
+	dir, err := fetchRepo(repo, cacheDir)
+	if err != nil {
+		t.Fatalf("fetchRepo (clone) failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "web", "chall", "challenge.yml")); err != nil {
+		t.Errorf("Expected challenge to be cloned: %v", err)
+	}
+
+	writeRepoChallenge(t, filepath.Join(source, "pwn", "chall"), "name: pwn\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "second")
+	if _, err := fetchRepo(repo, cacheDir); err != nil {
+		t.Fatalf("fetchRepo (fetch) failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pwn", "chall", "challenge.yml")); err != nil {
+		t.Errorf("Expected fetch to update the checkout: %v", err)
+	}
+}
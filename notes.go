@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// internalFields are the challenge.yml keys meant for organizers only; they
+// are removed from the challenge data clilint hands on to the platform
+var internalFields = []string{"notes", "flag_tests"}
+
+// NotesRule configures the organizer-only notes field of challenge.yml,
+// e.g. for QA signoff text
+type NotesRule struct {
+	// Required requires every challenge to have notes
+	Required bool `yaml:"required"`
+	// Pattern is a regular expression the notes must match, e.g.
+	// "(?m)^QA: .+" for a signoff line
+	Pattern string `yaml:"pattern"`
+}
+
+// notesText renders the notes of a challenge, which may be any YAML
+func notesText(notes interface{}) string {
+	switch v := notes.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	default:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return strings.TrimSpace(string(data))
+	}
+}
+
+func checkNotes(challenge Challenge, rule NotesRule) []string {
+	var errors []string
+
+	notes := notesText(challenge.Notes)
+	if notes == "" {
+		if rule.Required {
+			errors = append(errors, "Field 'notes' is required (organizer-only, e.g. QA signoff)")
+		}
+		return errors
+	}
+	if rule.Pattern != "" {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid notes.pattern %q: %v", rule.Pattern, err))
+		} else if !pattern.MatchString(notes) {
+			errors = append(errors, fmt.Sprintf("Field 'notes' does not match %q", rule.Pattern))
+		}
+	}
+	return errors
+}
+
+// platformData returns a challenge.yml as the platform should receive it:
+// documents and includes resolved, and internal fields removed
+func platformData(path string, config *LintConfig) ([]byte, error) {
+	data, err := readYAMLFile(path, config.YAMLLimits)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAliasExpansion(data); err != nil {
+		return nil, err
+	}
+	data, err = resolveChallengeData(data, config)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML format: %v", err)
+	}
+	root, err := documentMapping(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("empty document")
+	}
+	for _, key := range internalFields {
+		root = withoutKey(root, key)
+	}
+	return yaml.Marshal(root)
+}
+
+// writeExportYAML writes challenges as a YAML stream, one document each,
+// headed by a comment naming the challenge.yml it came from
+func writeExportYAML(w io.Writer, paths []string, docs [][]byte) error {
+	for i, doc := range docs {
+		if _, err := fmt.Fprintf(w, "---\n# %s\n%s", paths[i], doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckNotes(t *testing.T) {
+	rule := NotesRule{Required: true, Pattern: `(?m)^QA: .+`}
+	tests := []struct {
+		name  string
+		notes interface{}
+		rule  NotesRule
+		want  []string
+	}{
+		{name: "optional and missing"},
+		{name: "required and missing", rule: rule, want: []string{"Field 'notes' is required (organizer-only, e.g. QA signoff)"}},
+		{name: "blank", notes: "  \n", rule: rule, want: []string{"Field 'notes' is required (organizer-only, e.g. QA signoff)"}},
+		{name: "signed off", notes: "Tested on staging.\nQA: alice 2026-10-01\n", rule: rule},
+		{name: "not signed off", notes: "Tested on staging.", rule: rule, want: []string{`Field 'notes' does not match "(?m)^QA: .+"`}},
+		{name: "mapping", notes: map[string]interface{}{"QA": "bob"}, rule: rule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkNotes(Challenge{Notes: tt.notes}, tt.rule)
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkNotes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatformDataStripsNotes(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "scoring.yml"), []byte("type: dynamic\nnotes: snippet remark\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(tempDir, "challenge.yml")
+	content := `include: scoring.yml
+name: "web"
+description: "Find the flag"
+notes: |
+  Solver is flaky on staging.
+  QA: alice
+flag_tests:
+  accept: ["flag{1}"]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := platformData(path, &LintConfig{baseDir: tempDir})
+	if err != nil {
+		t.Fatalf("platformData() error: %v", err)
+	}
+	var got map[string]interface{}
+	if err := yaml.Unmarshal(doc, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"name": "web", "description": "Find the flag", "type": "dynamic"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("platformData() = %v, want %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := writeExportYAML(&buf, []string{path}, [][]byte{doc}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "QA") || !strings.HasPrefix(buf.String(), "---\n# "+path+"\n") {
+		t.Errorf("unexpected export:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptySHA256 is the hex SHA256 of an empty request body
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// storageObject is an object in S3 or Google Cloud Storage, written in
+// challenge.yml as s3://bucket/key or gs://bucket/key
+type storageObject struct {
+	scheme string
+	bucket string
+	key    string
+}
+
+// isObjectStorageFile reports whether a files entry is an s3:// or gs:// URL
+func isObjectStorageFile(file string) bool {
+	return strings.HasPrefix(file, "s3://") || strings.HasPrefix(file, "gs://")
+}
+
+// parseStorageObject splits s3://bucket/key or gs://bucket/key
+func parseStorageObject(file string) (storageObject, bool) {
+	scheme, rest, found := strings.Cut(file, "://")
+	if !found || (scheme != "s3" && scheme != "gs") {
+		return storageObject{}, false
+	}
+	bucket, key, found := strings.Cut(rest, "/")
+	if !found || bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return storageObject{}, false
+	}
+	return storageObject{scheme: scheme, bucket: bucket, key: key}, true
+}
+
+// downloadURL is the object's https URL, the link players download from.
+// AWS_ENDPOINT_URL_S3/AWS_ENDPOINT_URL and STORAGE_EMULATOR_HOST select
+// S3-compatible storage and the GCS emulator, addressed path-style.
+func (o storageObject) downloadURL() string {
+	var segments []string
+	for _, segment := range strings.Split(o.key, "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	key := strings.Join(segments, "/")
+
+	if o.scheme == "gs" {
+		endpoint := "https://storage.googleapis.com"
+		if emulator := os.Getenv("STORAGE_EMULATOR_HOST"); emulator != "" {
+			endpoint = emulator
+			if !strings.Contains(endpoint, "://") {
+				endpoint = "http://" + endpoint
+			}
+		}
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(endpoint, "/"), o.bucket, key)
+	}
+
+	for _, name := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if endpoint := os.Getenv(name); endpoint != "" {
+			return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(endpoint, "/"), o.bucket, key)
+		}
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", o.bucket, awsRegion(), key)
+}
+
+// awsRegion returns the region from the environment, defaulting to us-east-1
+func awsRegion() string {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			return region
+		}
+	}
+	return "us-east-1"
+}
+
+// checkObjectStorageFile verifies that an object exists, is publicly
+// readable, and matches its declared size, checksum, and content type. The
+// public-read check is an anonymous request; with credentials in the
+// environment, objects that are not public are told apart from missing ones.
+func checkObjectStorageFile(ext ExternalFile) []string {
+	object, ok := parseStorageObject(ext.URL)
+	if !ok {
+		return []string{fmt.Sprintf("External file '%s' must be of the form %s://bucket/key", ext.URL, strings.SplitN(ext.URL, ":", 2)[0])}
+	}
+	downloadURL := object.downloadURL()
+
+	resp, err := httpClient.Head(downloadURL)
+	if err != nil {
+		return []string{fmt.Sprintf("External file '%s' is unreachable: %v", ext.URL, err)}
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return checkExternalMetadata(ext.URL, ext, downloadURL, resp.Header)
+	}
+
+	anonymousStatus := resp.StatusCode
+	req, err := http.NewRequest(http.MethodHead, downloadURL, nil)
+	if err != nil {
+		return []string{fmt.Sprintf("External file '%s' could not be checked: %v", ext.URL, err)}
+	}
+	if !object.authorize(req, time.Now()) {
+		if anonymousStatus == http.StatusForbidden || anonymousStatus == http.StatusUnauthorized {
+			return []string{fmt.Sprintf("External file '%s' is missing or not public-read (HTTP %d)", ext.URL, anonymousStatus)}
+		}
+		return []string{fmt.Sprintf("External file '%s' returned HTTP %d", ext.URL, anonymousStatus)}
+	}
+
+	resp, err = httpClient.Do(req)
+	if err != nil {
+		return []string{fmt.Sprintf("External file '%s' is unreachable: %v", ext.URL, err)}
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return []string{fmt.Sprintf("External file '%s' exists but is not public-read (HTTP %d for anonymous downloads)", ext.URL, anonymousStatus)}
+	case resp.StatusCode == http.StatusNotFound:
+		return []string{fmt.Sprintf("External file '%s' does not exist", ext.URL)}
+	default:
+		return []string{fmt.Sprintf("External file '%s' returned HTTP %d", ext.URL, resp.StatusCode)}
+	}
+}
+
+// authorize adds credentials from the environment to a request for the
+// object: AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY (and AWS_SESSION_TOKEN) for
+// S3, GOOGLE_OAUTH_ACCESS_TOKEN for GCS. It reports false without credentials.
+func (o storageObject) authorize(req *http.Request, now time.Time) bool {
+	if o.scheme == "gs" {
+		token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+		if token == "" {
+			return false
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return true
+	}
+
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return false
+	}
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signS3Request(req, accessKey, secretKey, awsRegion(), now)
+	return true
+}
+
+// signS3Request signs a request without a body with AWS Signature Version 4,
+// covering the host and every header already set on the request
+func signS3Request(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptySHA256,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
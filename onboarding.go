@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// OnboardingRule configures the guidance prepended to the PR comment of
+// authors who have not had a pull request merged yet
+type OnboardingRule struct {
+	Enabled bool `yaml:"enabled"`
+	// Message is the markdown shown to first-time contributors; File reads it
+	// from a file relative to lintrc.yaml instead. Without either a default
+	// message is used.
+	Message string `yaml:"message"`
+	File    string `yaml:"file"`
+}
+
+// defaultOnboardingMessage introduces the linter to first-time contributors
+const defaultOnboardingMessage = `### 👋 Welcome, first-time contributor!
+
+Thanks for your first challenge! A few conventions every challenge follows:
+
+- **Flag format:** flags are listed under ` + "`flags`" + ` and use the event's flag format, e.g. ` + "`flag{...}`" + `
+- **Naming:** each challenge lives in ` + "`<category>/<challenge>/challenge.yml`" + `
+- **Metadata:** ` + "`state: visible`" + `, ` + "`version: \"0.1\"`" + `, and a difficulty tag are required
+
+Run the same checks locally before pushing:
+
+` + "```bash\ngo install github.com/diver-osint-ctf/clilint@latest\nclilint path/to/your/challenge\nclilint --fix path/to/your/challenge\n```"
+
+// onboardingSection returns the onboarding markdown for the pull request's
+// author, or "" when onboarding is disabled or the author has contributed
+// before. Lookup failures only skip the section.
+func onboardingSection(env Env, config *LintConfig) string {
+	rule := config.Onboarding
+	if !rule.Enabled {
+		return ""
+	}
+	firstTime, err := isFirstTimeContributor(env)
+	if err != nil {
+		log.Printf("Warning: failed to look up previous contributions: %v", err)
+		return ""
+	}
+	if !firstTime {
+		return ""
+	}
+
+	message := rule.Message
+	if rule.File != "" {
+		data, err := os.ReadFile(config.resolvePath(rule.File))
+		if err != nil {
+			log.Printf("Warning: failed to read onboarding file: %v", err)
+			return ""
+		}
+		message = string(data)
+	}
+	if strings.TrimSpace(message) == "" {
+		message = defaultOnboardingMessage
+	}
+	return strings.TrimRight(message, "\n") + "\n\n---\n\n"
+}
+
+// isFirstTimeContributor reports whether the author of the pull request has
+// no merged pull requests in the repository
+func isFirstTimeContributor(env Env) (bool, error) {
+	client, ctx := getGitHubClient(env.token)
+
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return false, fmt.Errorf("error getting PR: %v", err)
+	}
+	author := pr.GetUser().GetLogin()
+	if author == "" {
+		return false, nil
+	}
+
+	query := fmt.Sprintf("repo:%s/%s is:pr is:merged author:%s", env.owner, env.repo, author)
+	result, _, err := client.Search.Issues(ctx, query, nil)
+	if err != nil {
+		return false, fmt.Errorf("error searching merged PRs: %v", err)
+	}
+	return result.GetTotal() == 0, nil
+}
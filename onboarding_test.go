@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newContributorAPI serves the pull request and merged-PR search endpoints
+// used by isFirstTimeContributor
+func newContributorAPI(t *testing.T, mergedPRs int) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 7, "user": {"login": "newbie"}}`)
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); q != "repo:owner/repo is:pr is:merged author:newbie" {
+			t.Errorf("unexpected search query %q", q)
+		}
+		fmt.Fprintf(w, `{"total_count": %d, "items": []}`, mergedPRs)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("GITHUB_API_URL", server.URL)
+}
+
+func TestOnboardingSection(t *testing.T) {
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	baseDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(baseDir, "welcome.md"), []byte("Welcome from file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		rule      OnboardingRule
+		mergedPRs int
+		want      string
+	}{
+		{name: "disabled", rule: OnboardingRule{}, mergedPRs: 0, want: ""},
+		{name: "returning contributor", rule: OnboardingRule{Enabled: true}, mergedPRs: 3, want: ""},
+		{name: "default message", rule: OnboardingRule{Enabled: true}, mergedPRs: 0, want: defaultOnboardingMessage + "\n\n---\n\n"},
+		{name: "custom message", rule: OnboardingRule{Enabled: true, Message: "Hello!\n"}, mergedPRs: 0, want: "Hello!\n\n---\n\n"},
+		{name: "message file", rule: OnboardingRule{Enabled: true, File: "welcome.md"}, mergedPRs: 0, want: "Welcome from file\n\n---\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newContributorAPI(t, tt.mergedPRs)
+			config := &LintConfig{Onboarding: tt.rule, baseDir: baseDir}
+			if got := onboardingSection(env, config); got != tt.want {
+				t.Errorf("onboardingSection() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnboardingSectionAPIError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	config := &LintConfig{Onboarding: OnboardingRule{Enabled: true}}
+	if got := onboardingSection(env, config); got != "" {
+		t.Errorf("expected no onboarding when the lookup fails, got %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// runPackage implements "clilint package --blind <outdir> [directory...]":
+// it copies what players see of each passing challenge, and nothing else, to
+// a clean directory tree for external playtesters
+func runPackage(args []string) {
+	blind := false
+	var positional []string
+	for _, arg := range args {
+		if arg == "--blind" {
+			blind = true
+		} else if strings.HasPrefix(arg, "-") {
+			log.Fatalf("Unknown option %s", arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	if !blind || len(positional) == 0 {
+		log.Fatalf("Usage: clilint package --blind <outdir> [directory...]")
+	}
+	outDir, targetDirs := positional[0], positional[1:]
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+	if err := createPackageDir(outDir); err != nil {
+		log.Fatal(err)
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+
+	var results []LintResult
+	for _, dir := range targetDirs {
+		dirResults, err := lintChallenges(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		results = append(results, dirResults...)
+	}
+	sortResults(results)
+
+	used := make(map[string]bool)
+	packaged, skipped := 0, 0
+	for _, result := range results {
+		if len(result.Errors) > 0 {
+			fmt.Printf("⏭️  %s: skipped, %d lint error(s)\n", result.File, len(result.Errors))
+			skipped++
+			continue
+		}
+		challenge, err := readChallenge(result.File)
+		if err != nil {
+			fmt.Printf("⏭️  %s: skipped, %v\n", result.File, err)
+			skipped++
+			continue
+		}
+		dir := packageChallengeDir(outDir, challenge, used)
+		warnings, err := packageChallenge(result.File, challenge, config, dir)
+		if err != nil {
+			log.Fatalf("Error packaging %s: %v", result.File, err)
+		}
+		for _, warning := range warnings {
+			log.Printf("Warning: %s: %s", result.File, warning)
+		}
+		fmt.Printf("📦 %s → %s\n", result.File, dir)
+		packaged++
+	}
+	fmt.Printf("Packaged %d challenge(s) into %s, skipped %d\n", packaged, outDir, skipped)
+}
+
+// createPackageDir creates the output directory of a package, which must not
+// exist or be empty so that nothing else ends up in the archive
+func createPackageDir(outDir string) error {
+	entries, err := os.ReadDir(outDir)
+	if err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty; package into a new directory", outDir)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.MkdirAll(outDir, 0755)
+}
+
+// packageChallengeDir returns the unused directory <category>/<name> of a
+// challenge in outDir, slugified, with a numeric suffix for duplicate names
+func packageChallengeDir(outDir string, challenge Challenge, used map[string]bool) string {
+	category, name := slugify(challenge.Category), slugify(challenge.Name)
+	if category == "" {
+		category = "uncategorized"
+	}
+	if name == "" {
+		name = "challenge"
+	}
+	dir := filepath.Join(outDir, category, name)
+	for n := 2; used[dir]; n++ {
+		dir = filepath.Join(outDir, category, name+"-"+strconv.Itoa(n))
+	}
+	used[dir] = true
+	return dir
+}
+
+// packageChallenge writes the player-visible parts of a challenge to dir: a
+// README.md with its name, category, value, description, and connection
+// info, and its local files[] under files/. challenge.yml itself, with the
+// flags, hints, notes, and flag tests, and the rest of the challenge
+// directory are never copied. It returns warnings for files[] entries it
+// could not include.
+func packageChallenge(challengePath string, challenge Challenge, config *LintConfig, dir string) ([]string, error) {
+	var warnings []string
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, expandChallenge(&challenge, config)...)
+
+	baseDir := filepath.Dir(challengePath)
+	var files []string
+	for _, file := range challenge.Files {
+		switch {
+		case isHTTPFile(file):
+			files = append(files, fmt.Sprintf("- <%s>", file))
+		case isRemoteFile(file):
+			warnings = append(warnings, fmt.Sprintf("Remote file '%s' is not packaged", file))
+		case !filepath.IsLocal(normalizeFilePath(file)):
+			warnings = append(warnings, fmt.Sprintf("File '%s' is outside the challenge directory and is not packaged", file))
+		default:
+			rel := normalizeFilePath(file)
+			source, err := resolveInside(baseDir, rel)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("File '%s' %v and is not packaged", file, err))
+				continue
+			}
+			info, err := os.Stat(source)
+			if err != nil || !info.Mode().IsRegular() {
+				warnings = append(warnings, fmt.Sprintf("File '%s' is not a regular file and is not packaged", file))
+				continue
+			}
+			target := filepath.Join(dir, "files", rel)
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			if err := copyFile(source, target, info.Mode().Perm()); err != nil {
+				return nil, err
+			}
+			files = append(files, fmt.Sprintf("- [%s](files/%s)", filepath.Base(rel), filepath.ToSlash(rel)))
+		}
+	}
+
+	var readme strings.Builder
+	fmt.Fprintf(&readme, "# %s\n\n", challenge.Name)
+	fmt.Fprintf(&readme, "Category: %s\n", challenge.Category)
+	if challenge.Value > 0 {
+		fmt.Fprintf(&readme, "Value: %d\n", challenge.Value)
+	}
+	fmt.Fprintf(&readme, "\n%s\n", strings.TrimSpace(challenge.Description))
+	if description := strings.TrimSpace(challenge.DescriptionJA); description != "" {
+		fmt.Fprintf(&readme, "\n%s\n", description)
+	}
+	if target := healthcheckTarget(challenge); target != "" {
+		fmt.Fprintf(&readme, "\n## Connection\n\n```\n%s\n```\n", target)
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&readme, "\n## Files\n\n%s\n", strings.Join(files, "\n"))
+	}
+	return warnings, os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme.String()), 0644)
+}
+
+// resolveInside resolves the symlinks of rel under baseDir, failing when the
+// file is missing or its target is outside baseDir
+func resolveInside(baseDir, rel string) (string, error) {
+	root, err := filepath.EvalSymlinks(baseDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot be resolved")
+	}
+	real, err := filepath.EvalSymlinks(filepath.Join(baseDir, rel))
+	if err != nil {
+		return "", fmt.Errorf("is not a regular file")
+	}
+	if inside, err := filepath.Rel(root, real); err != nil || !filepath.IsLocal(inside) {
+		return "", fmt.Errorf("links outside the challenge directory")
+	}
+	return real, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageChallenge(t *testing.T) {
+	challengeDir := t.TempDir()
+	challengePath := filepath.Join(challengeDir, "challenge.yml")
+	for name, content := range map[string]string{
+		"challenge.yml":   "name: Echo\n",
+		"dist/echo.zip":   "zip",
+		"solve.py":        "print('flag')",
+		"writeup.md":      "the answer",
+		"dist/.flag.txt":  "Diver24{secret}",
+		"dist/unused.bin": "unused",
+	} {
+		path := filepath.Join(challengeDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	flag, hint := "Diver24{secret}", "use nc"
+	challenge := Challenge{
+		Name:           "Echo",
+		Category:       "Misc",
+		Value:          100,
+		Description:    "Say it back.",
+		ConnectionInfo: "nc echo.example.com 1337",
+		Files:          []string{"dist/echo.zip", "https://example.com/big.iso", "s3://bucket/key", "../other/secret.txt"},
+		Flags:          []FlagItem{{StringValue: &flag}},
+		Hints:          []HintItem{{StringValue: &hint}},
+		Notes:          "QA: solved by organizer",
+		Author:         "alice",
+	}
+
+	outDir := filepath.Join(t.TempDir(), "misc", "echo")
+	warnings, err := packageChallenge(challengePath, challenge, getDefaultLintConfig(), outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "s3://bucket/key") || !strings.Contains(warnings[1], "../other/secret.txt") {
+		t.Errorf("warnings = %q", warnings)
+	}
+
+	var packaged []string
+	err = filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(outDir, path)
+			packaged = append(packaged, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(packaged, ",") != "README.md,files/dist/echo.zip" {
+		t.Errorf("packaged %v, want only README.md and files/dist/echo.zip", packaged)
+	}
+
+	readme, err := os.ReadFile(filepath.Join(outDir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Echo\n\nCategory: Misc\nValue: 100\n\nSay it back.\n\n## Connection\n\n```\nnc echo.example.com 1337\n```\n\n## Files\n\n- [echo.zip](files/dist/echo.zip)\n- <https://example.com/big.iso>\n"
+	if string(readme) != want {
+		t.Errorf("README.md =\n%s\nwant\n%s", readme, want)
+	}
+	for _, leak := range []string{"secret", "use nc", "QA", "alice"} {
+		if strings.Contains(string(readme), leak) {
+			t.Errorf("README.md contains %q", leak)
+		}
+	}
+}
+
+func TestPackageChallengeFilePaths(t *testing.T) {
+	challengeDir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(outside, []byte("private key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(challengeDir, "dist"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(challengeDir, "dist", "echo.zip"), []byte("zip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"dist/key.txt": outside, "dist/alias.zip": "echo.zip"} {
+		if err := os.Symlink(target, filepath.Join(challengeDir, filepath.FromSlash(link))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	challenge := Challenge{Name: "Echo", Files: []string{`dist\echo.zip`, "dist/key.txt", "dist/alias.zip"}}
+	outDir := t.TempDir()
+	warnings, err := packageChallenge(filepath.Join(challengeDir, "challenge.yml"), challenge, getDefaultLintConfig(), outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "'dist/key.txt' links outside the challenge directory") {
+		t.Errorf("warnings = %q", warnings)
+	}
+	for name, want := range map[string]string{"files/dist/echo.zip": "zip", "files/dist/alias.zip": "zip"} {
+		if data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(name))); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "files", "dist", "key.txt")); !os.IsNotExist(err) {
+		t.Errorf("symlink outside the challenge directory was packaged: %v", err)
+	}
+}
+
+func TestPackageChallengeDir(t *testing.T) {
+	used := make(map[string]bool)
+	first := packageChallengeDir("out", Challenge{Name: "Hello World!", Category: "Web"}, used)
+	second := packageChallengeDir("out", Challenge{Name: "hello world", Category: "web"}, used)
+	third := packageChallengeDir("out", Challenge{Name: "日本語"}, used)
+	if first != filepath.Join("out", "web", "hello-world") || second != filepath.Join("out", "web", "hello-world-2") || third != filepath.Join("out", "uncategorized", "challenge") {
+		t.Errorf("got %s, %s, %s", first, second, third)
+	}
+}
+
+func TestCreatePackageDir(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "blind")
+	if err := createPackageDir(outDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := createPackageDir(outDir); err != nil {
+		t.Errorf("empty directory rejected: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outDir, "leftover"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := createPackageDir(outDir); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxPolicySize bounds a downloaded policy bundle
+const maxPolicySize = 32 << 20
+
+// Media types of the layers of a policy bundle pushed as an OCI artifact
+// (oras push ref bundle.tar.gz:application/vnd.clilint.policy.v1.tar+gzip)
+const (
+	policyLayerMediaType     = "application/vnd.clilint.policy.v1.tar+gzip"
+	policySignatureMediaType = "application/vnd.clilint.policy.signature.v1+minisign"
+)
+
+// PolicyRule references the organization's policy bundle: a tar.gz holding
+// a lintrc.yaml and the templates, dictionaries, and other files it names.
+// The repository's lintrc.yaml is applied on top of the bundle's.
+type PolicyRule struct {
+	// Ref is an OCI reference (ghcr.io/org/clilint-policy:v3), an https URL
+	// of a tar.gz, or a path relative to lintrc.yaml; "@sha256:<hex>"
+	// pins the manifest of an OCI reference or the tar.gz of others
+	Ref string `yaml:"ref"`
+	// PublicKeys verify the bundle's minisign signature, in the formats of
+	// signatures.public_keys
+	PublicKeys []string `yaml:"public_keys"`
+}
+
+// UnmarshalYAML accepts the short form "policy: <ref>"
+func (p *PolicyRule) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&p.Ref)
+	}
+	type plain PolicyRule
+	return value.Decode((*plain)(p))
+}
+
+// policyBundle is a fetched, verified, and extracted policy bundle
+type policyBundle struct {
+	dir    string
+	config []byte
+	rules  []celRule
+	err    error
+}
+
+// policyBundles caches the bundles fetched by this process, by reference
+// and keys: the config is loaded once per challenge
+var policyBundles sync.Map
+
+// policyCacheDir is where bundles are extracted, by the digest of their tar.gz
+var policyCacheDir = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "clilint", "policy")
+}
+
+// applyPolicy returns the config of a lintrc.yaml that references a policy:
+// the bundle's lintrc.yaml with data applied on top of it
+func applyPolicy(data []byte, config *LintConfig) (*LintConfig, error) {
+	bundle := loadPolicy(config.Policy, config.baseDir)
+	if bundle.err != nil {
+		return nil, fmt.Errorf("policy %s: %v", config.Policy.Ref, bundle.err)
+	}
+
+	bundleConfig, err := upgradeConfig(bundle.config)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %v", config.Policy.Ref, err)
+	}
+	var merged LintConfig
+	if err := yaml.Unmarshal(bundleConfig, &merged); err != nil {
+		return nil, fmt.Errorf("policy %s: failed to parse lintrc.yaml: %v", config.Policy.Ref, err)
+	}
+	if merged.Policy.Ref != "" {
+		return nil, fmt.Errorf("policy %s: a policy cannot reference another policy", config.Policy.Ref)
+	}
+	// Keys present in the repository's lintrc.yaml replace the policy's
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return nil, fmt.Errorf("failed to parse lintrc.yaml: %v", err)
+	}
+	merged.baseDir = config.baseDir
+	merged.policyDir = bundle.dir
+	merged.policyRules = bundle.rules
+	return &merged, nil
+}
+
+// loadPolicy fetches, verifies, and extracts a policy bundle, once per process
+func loadPolicy(rule PolicyRule, baseDir string) *policyBundle {
+	key := rule.Ref + "\x00" + baseDir + "\x00" + strings.Join(rule.PublicKeys, "\x00")
+	if cached, ok := policyBundles.Load(key); ok {
+		return cached.(*policyBundle)
+	}
+	bundle := &policyBundle{}
+	bundle.dir, bundle.config, bundle.err = fetchPolicy(rule, baseDir)
+	if bundle.err == nil {
+		bundle.rules, bundle.err = compileCELRules(bundle.dir)
+	}
+	policyBundles.Store(key, bundle)
+	return bundle
+}
+
+func fetchPolicy(rule PolicyRule, baseDir string) (string, []byte, error) {
+	ref, pin := rule.Ref, ""
+	isOCI := !strings.Contains(ref, "://") && !isLocalPolicy(ref)
+	if i := strings.LastIndex(ref, "@sha256:"); i >= 0 && !isOCI {
+		ref, pin = ref[:i], ref[i+1:]
+	} else if isOCI {
+		if parsed, ok := parseImageReference(ref); ok {
+			pin = parsed.digest
+		}
+	}
+	if pin == "" && len(rule.PublicKeys) == 0 {
+		return "", nil, fmt.Errorf("bundle is not verified: set policy.public_keys or pin its digest with @sha256:<hex>")
+	}
+
+	var tarball, signature []byte
+	var err error
+	switch {
+	case isOCI:
+		tarball, signature, err = fetchPolicyArtifact(ref)
+	case strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://"):
+		if tarball, err = fetchPolicyURL(ref); err == nil && len(rule.PublicKeys) > 0 {
+			signature, err = fetchPolicyURL(ref + ".minisig")
+		}
+	default:
+		path := ref
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, filepath.FromSlash(path))
+		}
+		if tarball, err = os.ReadFile(path); err == nil && len(rule.PublicKeys) > 0 {
+			signature, err = os.ReadFile(path + ".minisig")
+		}
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	sum := sha256.Sum256(tarball)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if pin != "" && !isOCI && pin != digest {
+		return "", nil, fmt.Errorf("bundle digest is %s, not the pinned %s", digest, pin)
+	}
+
+	dir := filepath.Join(policyCacheDir(), hex.EncodeToString(sum[:]))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, err
+	}
+	tarballPath := filepath.Join(dir, "bundle.tar.gz")
+	if err := writeFileAtomic(tarballPath, string(tarball)); err != nil {
+		return "", nil, err
+	}
+	if len(rule.PublicKeys) > 0 {
+		keys, err := loadSignatureKeys(rule.PublicKeys, &LintConfig{baseDir: baseDir})
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid public key in policy.public_keys: %v", err)
+		}
+		if len(signature) == 0 {
+			return "", nil, fmt.Errorf("bundle has no signature")
+		}
+		if err := verifyMinisign(tarballPath, signature, keys); err != nil {
+			return "", nil, fmt.Errorf("signature does not verify: %v", err)
+		}
+	}
+
+	contents, err := extractPolicy(dir, tarball)
+	if err != nil {
+		return "", nil, err
+	}
+	config, err := os.ReadFile(filepath.Join(contents, "lintrc.yaml"))
+	if err != nil {
+		return "", nil, fmt.Errorf("bundle has no lintrc.yaml")
+	}
+	return contents, config, nil
+}
+
+// extractPolicy extracts a verified bundle into dir/extracted once. Runs
+// sharing the cache never see a partial extraction: each extracts into a
+// directory of its own, and the first to finish renames it into place.
+func extractPolicy(dir string, tarball []byte) (string, error) {
+	contents := filepath.Join(dir, "extracted")
+	if _, err := os.Stat(contents); err == nil {
+		return contents, nil
+	}
+	staging, err := os.MkdirTemp(dir, ".extract-*")
+	if err != nil {
+		return "", err
+	}
+	if err := extractTar(bufio.NewReader(bytes.NewReader(tarball)), staging); err != nil {
+		os.RemoveAll(staging)
+		return "", fmt.Errorf("invalid bundle: %v", err)
+	}
+	if err := os.Rename(staging, contents); err != nil {
+		os.RemoveAll(staging)
+		// Another run renamed its extraction into place first
+		if _, statErr := os.Stat(contents); statErr != nil {
+			return "", err
+		}
+	}
+	return contents, nil
+}
+
+// isLocalPolicy reports whether a policy reference is a file path
+func isLocalPolicy(ref string) bool {
+	ref, _, _ = strings.Cut(ref, "@")
+	return strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") || filepath.IsAbs(ref) ||
+		strings.HasSuffix(ref, ".tar.gz") || strings.HasSuffix(ref, ".tgz")
+}
+
+// fetchPolicyURL downloads a bundle or signature over HTTP
+func fetchPolicyURL(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: HTTP %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPolicySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPolicySize {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxPolicySize)
+	}
+	return data, nil
+}
+
+// fetchPolicyArtifact downloads the bundle and signature layers of an OCI
+// artifact, checking the manifest against a pinned digest and every layer
+// against the digest in the manifest
+func fetchPolicyArtifact(ref string) (tarball, signature []byte, err error) {
+	_, manifestURL, ok := imageManifestURL(ref)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid reference")
+	}
+	status, body, err := requestManifest(http.MethodGet, manifestURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status != http.StatusOK {
+		return nil, nil, fmt.Errorf("manifest: HTTP %d", status)
+	}
+	if parsed, _ := parseImageReference(ref); parsed.digest != "" {
+		sum := sha256.Sum256(body)
+		if digest := "sha256:" + hex.EncodeToString(sum[:]); digest != parsed.digest {
+			return nil, nil, fmt.Errorf("manifest digest is %s, not the pinned %s", digest, parsed.digest)
+		}
+	}
+
+	var manifest struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	blobsURL := manifestURL[:strings.LastIndex(manifestURL, "/manifests/")] + "/blobs/"
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != policyLayerMediaType && layer.MediaType != policySignatureMediaType {
+			continue
+		}
+		status, blob, err := requestRegistry(http.MethodGet, blobsURL+layer.Digest, []string{layer.MediaType}, maxPolicySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		if status != http.StatusOK {
+			return nil, nil, fmt.Errorf("layer %s: HTTP %d", layer.Digest, status)
+		}
+		sum := sha256.Sum256(blob)
+		if "sha256:"+hex.EncodeToString(sum[:]) != layer.Digest {
+			return nil, nil, fmt.Errorf("layer %s does not match its digest", layer.Digest)
+		}
+		if layer.MediaType == policyLayerMediaType {
+			tarball = blob
+		} else {
+			signature = blob
+		}
+	}
+	if tarball == nil {
+		return nil, nil, fmt.Errorf("artifact has no %s layer", policyLayerMediaType)
+	}
+	return tarball, signature, nil
+}
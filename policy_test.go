@@ -0,0 +1,307 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// policyTarball builds a policy bundle holding files
+func policyTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// isolatePolicies gives a test its own bundle cache
+func isolatePolicies(t *testing.T) {
+	t.Helper()
+	cacheDir := t.TempDir()
+	origCacheDir := policyCacheDir
+	policyCacheDir = func() string { return cacheDir }
+	policyBundles = sync.Map{}
+	t.Cleanup(func() {
+		policyCacheDir = origCacheDir
+		policyBundles = sync.Map{}
+	})
+}
+
+var policyFiles = map[string]string{
+	"lintrc.yaml": `max_file_size: 2048
+require_host: true
+docs_base_url: https://wiki.example.com/ctf
+checklist_file: templates/checklist.md
+tags:
+  condition: and
+  patterns:
+    - type: static
+      values: [easy, medium, hard]
+`,
+	"templates/checklist.md": "- [ ] Writeup\n",
+}
+
+func TestPolicyLocalPinned(t *testing.T) {
+	isolatePolicies(t)
+	tarball := policyTarball(t, policyFiles)
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "policy.tar.gz"), tarball, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := parseLintConfig([]byte(fmt.Sprintf("policy: ./policy.tar.gz@%s\nmax_file_size: 4096\n", sha256Digest(tarball))), repoDir)
+	if err != nil {
+		t.Fatalf("parseLintConfig() error: %v", err)
+	}
+	// The repository overrides the policy; keys it does not set come from the policy
+	if config.MaxFileSize != 4096 {
+		t.Errorf("MaxFileSize = %d, want the repository's 4096", config.MaxFileSize)
+	}
+	if !config.RequireHost || config.DocsBaseURL != "https://wiki.example.com/ctf" || config.Tags.Condition != "and" {
+		t.Errorf("policy keys were not applied: %+v", config)
+	}
+	if config.baseDir != repoDir {
+		t.Errorf("baseDir = %s, want the repository", config.baseDir)
+	}
+	// Files of the bundle are found when the repository has none
+	data, err := os.ReadFile(config.resolvePath("templates/checklist.md"))
+	if err != nil || string(data) != "- [ ] Writeup\n" {
+		t.Errorf("resolvePath() did not find the bundle's template: %q, %v", data, err)
+	}
+	if got := config.resolvePath("missing.txt"); got != filepath.Join(repoDir, "missing.txt") {
+		t.Errorf("resolvePath() = %s for a file in neither", got)
+	}
+
+	_, err = parseLintConfig([]byte("policy: ./policy.tar.gz@sha256:"+strings.Repeat("0", 64)+"\n"), repoDir)
+	if err == nil || !strings.Contains(err.Error(), "not the pinned") {
+		t.Errorf("expected a digest mismatch, got %v", err)
+	}
+	_, err = parseLintConfig([]byte("policy: ./policy.tar.gz\n"), repoDir)
+	if err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Errorf("expected an unverified bundle to be rejected, got %v", err)
+	}
+}
+
+func TestPolicyURLSigned(t *testing.T) {
+	isolatePolicies(t)
+	tarball := policyTarball(t, policyFiles)
+	public, private, _ := ed25519.GenerateKey(nil)
+	id := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	signature := minisignSignature(t, private, id, tarball, "ED")
+	_, otherPrivate, _ := ed25519.GenerateKey(nil)
+	forged := minisignSignature(t, otherPrivate, id, tarball, "ED")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/policy.tar.gz", "/forged/policy.tar.gz", "/unsigned/policy.tar.gz":
+			w.Write(tarball)
+		case "/v3/policy.tar.gz.minisig":
+			fmt.Fprint(w, signature)
+		case "/forged/policy.tar.gz.minisig":
+			fmt.Fprint(w, forged)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	origClient := httpClient
+	defer func() { httpClient = origClient }()
+	httpClient = server.Client()
+
+	lintrc := func(path string) []byte {
+		return []byte(fmt.Sprintf("policy:\n  ref: %s%s\n  public_keys: [%s]\n", server.URL, path, minisignKey(public, id)))
+	}
+	config, err := parseLintConfig(lintrc("/v3/policy.tar.gz"), t.TempDir())
+	if err != nil {
+		t.Fatalf("parseLintConfig() error: %v", err)
+	}
+	if config.MaxFileSize != 2048 {
+		t.Errorf("MaxFileSize = %d, want the policy's 2048", config.MaxFileSize)
+	}
+
+	_, err = parseLintConfig(lintrc("/forged/policy.tar.gz"), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "signature does not verify") {
+		t.Errorf("expected a forged signature to be rejected, got %v", err)
+	}
+	_, err = parseLintConfig(lintrc("/unsigned/policy.tar.gz"), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("expected a missing signature to be rejected, got %v", err)
+	}
+}
+
+func TestPolicyOCIArtifact(t *testing.T) {
+	isolatePolicies(t)
+	tarball := policyTarball(t, policyFiles)
+	manifest := fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "application/vnd.oci.image.manifest.v1+json", "layers": [{"mediaType": %q, "digest": %q, "size": %d}]}`,
+		policyLayerMediaType, sha256Digest(tarball), len(tarball))
+
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/diver/clilint-policy/manifests/v3", "/v2/diver/clilint-policy/manifests/" + sha256Digest([]byte(manifest)):
+			fmt.Fprint(w, manifest)
+		case "/v2/diver/clilint-policy/blobs/" + sha256Digest(tarball):
+			w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer registry.Close()
+	origClient := httpClient
+	defer func() { httpClient = origClient }()
+	httpClient = registry.Client()
+	domain := strings.TrimPrefix(registry.URL, "https://")
+
+	ref := domain + "/diver/clilint-policy@" + sha256Digest([]byte(manifest))
+	config, err := parseLintConfig([]byte("policy: "+ref+"\n"), t.TempDir())
+	if err != nil {
+		t.Fatalf("parseLintConfig() error: %v", err)
+	}
+	if config.MaxFileSize != 2048 || config.Policy.Ref != ref {
+		t.Errorf("config = %+v, want the policy applied", config)
+	}
+
+	_, err = parseLintConfig([]byte("policy: "+domain+"/diver/clilint-policy:v3\n"), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Errorf("expected a tag without keys to be rejected, got %v", err)
+	}
+}
+
+func TestPolicyInvalidBundles(t *testing.T) {
+	isolatePolicies(t)
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "no lintrc.yaml", files: map[string]string{"README.md": "policy"}, want: "no lintrc.yaml"},
+		{name: "nested policy", files: map[string]string{"lintrc.yaml": "policy: ./other.tar.gz\n"}, want: "cannot reference another policy"},
+		{name: "invalid CEL rule", files: map[string]string{"lintrc.yaml": "", "rules/flag.cel": "size(challenge.flags) >"}, want: "CEL rule rules/flag.cel"},
+		{name: "CEL rule of another type", files: map[string]string{"lintrc.yaml": "", "rules/value.cel": "1 + 1"}, want: "not a bool, string, or list of strings"},
+		{name: "path traversal", files: map[string]string{"lintrc.yaml": "", "../escape.txt": "x"}, want: "invalid path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tarball := policyTarball(t, tt.files)
+			repoDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(repoDir, "policy.tgz"), tarball, 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := parseLintConfig([]byte("policy: policy.tgz@"+sha256Digest(tarball)+"\n"), repoDir)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseLintConfig() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCELRules(t *testing.T) {
+	isolatePolicies(t)
+	tarball := policyTarball(t, map[string]string{
+		"lintrc.yaml":        "",
+		"rules/author.cel":   "// author must be set\nhas(challenge.author) && challenge.author != ''",
+		"rules/flags.cel":    "has(challenge.flags) ? [] : ['flags are missing from ' + path]",
+		"rules/category.cel": "challenge.category == 'web' ? '' : 'unexpected category ' + string(challenge.category)",
+	})
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "policy.tgz"), tarball, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := parseLintConfig([]byte("policy: policy.tgz@"+sha256Digest(tarball)+"\n"), repoDir)
+	if err != nil {
+		t.Fatalf("parseLintConfig() error: %v", err)
+	}
+	if len(config.policyRules) != 3 {
+		t.Fatalf("compiled %d CEL rules, want 3", len(config.policyRules))
+	}
+
+	challenge := map[string]interface{}{"category": "pwn", "value": 100}
+	got := checkCELRules("pwn/a/challenge.yml", challenge, config.policyRules)
+	want := []string{
+		"Policy rule rules/author.cel: author must be set",
+		"Policy rule rules/category.cel: unexpected category pwn",
+		"Policy rule rules/flags.cel: flags are missing from pwn/a/challenge.yml",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkCELRules() = %q, want %q", got, want)
+	}
+
+	challenge = map[string]interface{}{"category": "web", "author": "alice", "flags": []interface{}{"flag{x}"}}
+	if got := checkCELRules("web/a/challenge.yml", challenge, config.policyRules); len(got) != 0 {
+		t.Errorf("checkCELRules() = %q for a conforming challenge", got)
+	}
+}
+
+func TestPolicyExtractionIsShared(t *testing.T) {
+	isolatePolicies(t)
+	tarball := policyTarball(t, policyFiles)
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "policy.tgz"), tarball, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lintrc := []byte("policy: policy.tgz@" + sha256Digest(tarball) + "\n")
+
+	// Concurrent runs share the cache without clearing each other's files
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = fetchPolicy(PolicyRule{Ref: "policy.tgz@" + sha256Digest(tarball)}, repoDir)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("fetchPolicy() error: %v", err)
+		}
+	}
+
+	config, err := parseLintConfig(lintrc, repoDir)
+	if err != nil {
+		t.Fatalf("parseLintConfig() error: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(config.policyDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			t.Errorf("leftover staging entry %s", entry.Name())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(config.policyDir, "templates", "checklist.md")); err != nil {
+		t.Errorf("bundle is not extracted: %v", err)
+	}
+}
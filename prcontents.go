@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// maxFetchedFileSize bounds the files downloaded by --fetch-contents; larger
+// files are created with their size but without content, which is enough
+// for the existence and size checks
+const maxFetchedFileSize = 10 * 1024 * 1024
+
+// fetchContents is set by --fetch-contents: in PR mode the changed
+// directories are linted as fetched from the PR head via the API rather than
+// from the local checkout, so workflows triggered by pull_request_target never
+// check out untrusted code
+var fetchContents bool
+
+// prHead is the head commit of a PR with its recursive tree, fetched once
+// with the Git Trees API for both finding and fetching challenges
+type prHead struct {
+	token       string
+	owner, repo string
+	sha         string
+	tree        *github.Tree
+	// challengeDirs are the OS paths of the directories holding a challenge.yml
+	challengeDirs map[string]bool
+}
+
+// fetchPRHead gets the tree of the PR head commit, in the fork for PRs from forks
+func fetchPRHead(env Env) (*prHead, error) {
+	client, ctx := getGitHubClient(env.token)
+
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("error getting PR: %v", err)
+	}
+	headRepo := pr.GetHead().GetRepo()
+	head := &prHead{token: env.token, owner: headRepo.GetOwner().GetLogin(), repo: headRepo.GetName(), sha: pr.GetHead().GetSHA()}
+	if head.owner == "" || head.repo == "" {
+		head.owner, head.repo = env.owner, env.repo
+	}
+
+	head.tree, _, err = client.Git.GetTree(ctx, head.owner, head.repo, head.sha, true)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tree of %s: %v", head.sha, err)
+	}
+	if head.tree.GetTruncated() {
+		return nil, fmt.Errorf("tree of %s is too large to fetch via the API", head.sha)
+	}
+	head.challengeDirs = make(map[string]bool)
+	for _, entry := range head.tree.Entries {
+		if entry.GetType() == "blob" && path.Base(entry.GetPath()) == "challenge.yml" {
+			head.challengeDirs[filepath.FromSlash(path.Dir(entry.GetPath()))] = true
+		}
+	}
+	return head, nil
+}
+
+// hasChallenge reports whether dir holds a challenge.yml at the PR head
+func (h *prHead) hasChallenge(dir string) bool {
+	return h.challengeDirs[dir]
+}
+
+// lintPRContents fetches the changed directories at the PR head into a
+// temporary directory and lints them with the local lintrc.yaml
+func lintPRContents(head *prHead, dirs []string) ([]LintResult, error) {
+	tempDir, err := os.MkdirTemp("", "clilint-pr-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := fetchPRContents(head, dirs, tempDir); err != nil {
+		return nil, err
+	}
+
+	// Pin the config of the base checkout: the PR must not bring its own
+	if lintConfigFile == "" {
+		if configPath := findLintConfig(); configPath != "" {
+			absPath, err := filepath.Abs(configPath)
+			if err != nil {
+				return nil, err
+			}
+			lintConfigFile = absPath
+			defer func() {
+				lintConfigFile = ""
+			}()
+		}
+	}
+
+	return lintDirectoriesIn(tempDir, dirs, sandboxMode)
+}
+
+// fetchPRContents writes the files of dirs at the PR head commit into dest,
+// downloading up to prFetchConcurrency blobs at a time
+func fetchPRContents(head *prHead, dirs []string, dest string) error {
+	client, ctx := getGitHubClient(head.token)
+
+	var blobs []*github.TreeEntry
+	for _, entry := range head.tree.Entries {
+		// Submodules and symlinks are not fetched
+		if entry.GetType() != "blob" || entry.GetMode() == "120000" {
+			continue
+		}
+		name := entry.GetPath()
+		if !inDirectories(name, dirs) || name == "lintrc.yaml" {
+			continue
+		}
+		blobs = append(blobs, entry)
+	}
+
+	errs := make([]error, len(blobs))
+	sem := make(chan struct{}, prFetchConcurrency)
+	var wg sync.WaitGroup
+	for i, entry := range blobs {
+		wg.Add(1)
+		go func(i int, entry *github.TreeEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = fetchBlob(ctx, client, head, entry, dest)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBlob writes one file of the PR head into dest
+func fetchBlob(ctx context.Context, client *github.Client, head *prHead, entry *github.TreeEntry, dest string) error {
+	name := entry.GetPath()
+	target, err := safeJoin(dest, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if entry.GetSize() > maxFetchedFileSize {
+		file, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		err = file.Truncate(int64(entry.GetSize()))
+		file.Close()
+		return err
+	}
+
+	data, _, err := client.Git.GetBlobRaw(ctx, head.owner, head.repo, entry.GetSHA())
+	if err != nil {
+		return fmt.Errorf("error fetching %s: %v", name, err)
+	}
+	return os.WriteFile(target, data, 0644)
+}
+
+// inDirectories reports whether the slash-separated path is inside one of dirs
+func inDirectories(name string, dirs []string) bool {
+	for _, dir := range dirs {
+		dir = slashPath(dir)
+		if dir == "." || strings.HasPrefix(name, strings.TrimSuffix(path.Clean(dir), "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// newContentsAPI serves a PR whose head commit holds the given blobs
+func newContentsAPI(t *testing.T, blobs map[string]string, sizes map[string]int) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 7, "head": {"sha": "abc123", "repo": {"name": "fork", "owner": {"login": "contributor"}}}}`)
+	})
+	mux.HandleFunc("GET /repos/contributor/fork/git/trees/abc123", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("recursive") == "" {
+			t.Error("expected a recursive tree request")
+		}
+		var entries []string
+		for name, content := range blobs {
+			size := len(content)
+			if s, ok := sizes[name]; ok {
+				size = s
+			}
+			entries = append(entries, fmt.Sprintf(`{"path": %q, "mode": "100644", "type": "blob", "sha": %q, "size": %d}`, name, "sha-"+name, size))
+		}
+		entries = append(entries, `{"path": "web/api/link", "mode": "120000", "type": "blob", "sha": "sha-link", "size": 4}`)
+		fmt.Fprintf(w, `{"sha": "abc123", "truncated": false, "tree": [%s]}`, strings.Join(entries, ","))
+	})
+	mux.HandleFunc("GET /repos/contributor/fork/git/blobs/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/repos/contributor/fork/git/blobs/sha-")
+		if _, ok := sizes[name]; ok {
+			t.Errorf("oversized blob %s was downloaded", name)
+		}
+		content, ok := blobs[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	t.Setenv("GITHUB_API_URL", server.URL)
+}
+
+func TestFetchPRContents(t *testing.T) {
+	blobs := map[string]string{
+		"web/api/challenge.yml":   "name: api\n",
+		"web/api/public/app.zip":  "",
+		"pwn/other/challenge.yml": "name: other\n",
+		"lintrc.yaml":             "ignore: ['**']\n",
+	}
+	newContentsAPI(t, blobs, map[string]int{"web/api/public/app.zip": maxFetchedFileSize + 1})
+
+	dest := t.TempDir()
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	head, err := fetchPRHead(env)
+	if err != nil {
+		t.Fatalf("fetchPRHead() error = %v", err)
+	}
+	if err := fetchPRContents(head, []string{filepath.Join("web", "api")}, dest); err != nil {
+		t.Fatalf("fetchPRContents() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "web", "api", "challenge.yml"))
+	if err != nil || string(data) != "name: api\n" {
+		t.Errorf("challenge.yml = %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(dest, "web", "api", "public", "app.zip"))
+	if err != nil || info.Size() != maxFetchedFileSize+1 {
+		t.Errorf("expected an empty placeholder of the blob's size, got %v, %v", info, err)
+	}
+	for _, name := range []string{"pwn/other/challenge.yml", "lintrc.yaml", "web/api/link"} {
+		if _, err := os.Stat(filepath.Join(dest, filepath.FromSlash(name))); !os.IsNotExist(err) {
+			t.Errorf("%s should not have been fetched", name)
+		}
+	}
+}
+
+func TestFetchPRHead(t *testing.T) {
+	newContentsAPI(t, map[string]string{
+		"web/api/challenge.yml":       "name: api\n",
+		"web/api/src/app.py":          "",
+		"crypto/added/challenge.yml":  "name: added\n",
+		"crypto/added/files/out.txt":  "",
+		"docs/challenge.yml.template": "",
+	}, nil)
+
+	head, err := fetchPRHead(Env{token: "token", owner: "owner", repo: "repo", prNumber: 7})
+	if err != nil {
+		t.Fatalf("fetchPRHead() error = %v", err)
+	}
+	if head.owner != "contributor" || head.repo != "fork" || head.sha != "abc123" {
+		t.Errorf("head = %s/%s@%s, want contributor/fork@abc123", head.owner, head.repo, head.sha)
+	}
+
+	// Challenges the PR adds are found without a checkout of the PR
+	var prFiles []*github.CommitFile
+	for _, name := range []string{"web/api/src/app.py", "crypto/added/files/out.txt", "docs/challenge.yml.template"} {
+		prFiles = append(prFiles, &github.CommitFile{Filename: github.String(name)})
+	}
+	got := changedDirectoriesWith(prFiles, head.hasChallenge)
+	want := []string{filepath.Join("crypto", "added"), filepath.Join("web", "api")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changedDirectoriesWith() = %v, want %v", got, want)
+	}
+}
+
+func TestLintPRContents(t *testing.T) {
+	challenge := strings.Replace(apiChallenge, "hidden", "visible", 1)
+	newContentsAPI(t, map[string]string{
+		"web/api/challenge.yml": strings.Replace(challenge, `tags: ["easy"]`, `tags: ["insane"]`, 1),
+		"lintrc.yaml":           "tags:\n  condition: none\n",
+	}, nil)
+
+	// The base checkout's lintrc.yaml applies, not the PR's
+	tempDir := t.TempDir()
+	config := "tags:\n  condition: and\n  patterns:\n    - type: static\n      values: [easy, medium, hard]\nrequirements:\n  condition: none\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "lintrc.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	env := Env{token: "token", owner: "owner", repo: "repo", prNumber: 7}
+	head, err := fetchPRHead(env)
+	if err != nil {
+		t.Fatalf("fetchPRHead() error = %v", err)
+	}
+	results, err := lintPRContents(head, []string{filepath.Join("web", "api")})
+	if err != nil {
+		t.Fatalf("lintPRContents() error = %v", err)
+	}
+	if len(results) != 1 || results[0].File != filepath.Join("web", "api", "challenge.yml") {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(results[0].Errors) != 1 || !strings.Contains(results[0].Errors[0].Message, "exactly one of") {
+		t.Errorf("expected the tag error from the base config, got %+v", results[0].Errors)
+	}
+	if lintConfigFile != "" {
+		t.Error("lintConfigFile not reset after linting")
+	}
+}
+
+func TestInDirectories(t *testing.T) {
+	tests := []struct {
+		name string
+		dirs []string
+		want bool
+	}{
+		{"web/api/challenge.yml", []string{"web/api"}, true},
+		{"web/api2/challenge.yml", []string{"web/api"}, false},
+		{"web/api/public/a.txt", []string{"pwn", "web/api/"}, true},
+		{"lintrc.yaml", []string{"."}, true},
+		{"lintrc.yaml", []string{"web"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := inDirectories(tt.name, tt.dirs); got != tt.want {
+			t.Errorf("inDirectories(%q, %v) = %v, want %v", tt.name, tt.dirs, got, tt.want)
+		}
+	}
+}
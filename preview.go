@@ -0,0 +1,409 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// previewAllowedTags are the HTML tags the platform's sanitizer keeps, with
+// the attributes it keeps on each; everything else is stripped
+var previewAllowedTags = map[string]map[string]bool{
+	"a":          {"href": true, "title": true},
+	"img":        {"src": true, "alt": true, "title": true, "width": true, "height": true},
+	"p":          {},
+	"br":         {},
+	"hr":         {},
+	"b":          {},
+	"strong":     {},
+	"i":          {},
+	"em":         {},
+	"del":        {},
+	"s":          {},
+	"sub":        {},
+	"sup":        {},
+	"kbd":        {},
+	"code":       {"class": true},
+	"pre":        {},
+	"blockquote": {},
+	"ul":         {},
+	"ol":         {"start": true},
+	"li":         {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"table":      {},
+	"thead":      {},
+	"tbody":      {},
+	"tr":         {},
+	"th":         {"align": true},
+	"td":         {"align": true},
+	"details":    {},
+	"summary":    {},
+	"span":       {},
+	"div":        {},
+}
+
+// previewDroppedContent are the tags whose content is removed with them
+var previewDroppedContent = []string{"script", "style"}
+
+var (
+	htmlTagPattern  = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^<>]*?)?)(/?)>`)
+	htmlAttrPattern = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+))?`)
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+(.*)$`)
+	ruleLinePattern = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	// Links and images are matched after escaping, so quotes are &#34;
+	imagePattern         = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	linkPattern          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+&#34;(.*?)&#34;)?\)`)
+	autolinkPattern      = regexp.MustCompile(`&lt;(https?://\S+?)&gt;`)
+	strongPattern        = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	emphasisPattern      = regexp.MustCompile(`(^|[^*\w])[*_](\S(?:.*?\S)?)[*_]($|[^*\w])`)
+	strikethroughPattern = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	emojiShortcode       = regexp.MustCompile(`(?:^|\s):([a-z][a-z0-9_+-]*):(?:$|\s|[.,!?])`)
+)
+
+func runPreview(args []string) {
+	outputPath := ""
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--output" && i+1 < len(args) {
+			i++
+			outputPath = args[i]
+		} else if args[i] == "--anonymous" {
+			anonymousMode = true
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if len(targetDirs) == 0 {
+		log.Fatalf("Usage: clilint preview [--output FILE] [--anonymous] <directory>...")
+	}
+
+	var previews []challengePreview
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				log.Fatalf("Error reading %s: %v", path, err)
+			}
+			if anonymousMode {
+				challenge.Description = redactNames(challenge.Description, authorNames(challenge))
+			}
+			rendered, warnings := renderDescription(challenge.Description)
+			previews = append(previews, challengePreview{File: path, Challenge: challenge, HTML: rendered, Warnings: warnings})
+		}
+	}
+	sort.Slice(previews, func(i, j int) bool { return previews[i].File < previews[j].File })
+
+	if outputPath == "" {
+		writeTerminalPreview(os.Stdout, previews)
+		return
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		log.Fatalf("Error creating %s: %v", outputPath, err)
+	}
+	writeHTMLPreview(file, previews)
+	if err := file.Close(); err != nil {
+		log.Fatalf("Error writing %s: %v", outputPath, err)
+	}
+	fmt.Printf("📝 %s\n", outputPath)
+	for _, preview := range previews {
+		for _, warning := range preview.Warnings {
+			fmt.Printf("⚠️  %s: %s\n", preview.File, warning)
+		}
+	}
+}
+
+// challengePreview is a challenge with its description rendered
+type challengePreview struct {
+	File      string
+	Challenge Challenge
+	HTML      string
+	Warnings  []string
+}
+
+// renderDescription renders a Markdown description to HTML as the platform
+// shows it, and returns warnings for everything its sanitizer strips
+func renderDescription(description string) (string, []string) {
+	renderer := &markdownRenderer{warned: make(map[string]bool)}
+	source := renderer.dropContent(strings.ReplaceAll(description, "\r\n", "\n"))
+	return renderer.renderBlocks(strings.Split(source, "\n")), renderer.warnings
+}
+
+type markdownRenderer struct {
+	warnings []string
+	warned   map[string]bool
+}
+
+func (m *markdownRenderer) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if !m.warned[message] {
+		m.warned[message] = true
+		m.warnings = append(m.warnings, message)
+	}
+}
+
+// dropContent removes the tags whose content the sanitizer drops as well
+func (m *markdownRenderer) dropContent(source string) string {
+	for _, tag := range previewDroppedContent {
+		pattern := regexp.MustCompile(`(?is)<` + tag + `\b[^>]*>.*?(</` + tag + `\s*>|$)`)
+		if pattern.MatchString(source) {
+			m.warn("Raw HTML <%s> is removed with its content", tag)
+			source = pattern.ReplaceAllString(source, "")
+		}
+	}
+	return source
+}
+
+// renderBlocks renders headings, fenced code, block quotes, lists, rules,
+// and paragraphs
+func (m *markdownRenderer) renderBlocks(lines []string) string {
+	var out strings.Builder
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(&out, "<p>%s</p>\n", m.renderInline(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence := trimmed[:3]
+			language := strings.TrimSpace(trimmed[3:])
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if language != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(language))
+			}
+			fmt.Fprintf(&out, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(code, "\n")))
+		case headingPattern.MatchString(trimmed):
+			flush()
+			match := headingPattern.FindStringSubmatch(trimmed)
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", len(match[1]), m.renderInline(match[2]), len(match[1]))
+		case ruleLinePattern.MatchString(trimmed) && len(paragraph) == 0:
+			fmt.Fprintln(&out, "<hr>")
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			fmt.Fprintf(&out, "<blockquote>\n%s</blockquote>\n", m.renderBlocks(quoted))
+		case listItemPattern.MatchString(line) && len(paragraph) == 0:
+			tag := "ul"
+			if marker := listItemPattern.FindStringSubmatch(line)[1]; marker[0] >= '0' && marker[0] <= '9' {
+				tag = "ol"
+			}
+			fmt.Fprintf(&out, "<%s>\n", tag)
+			for ; i < len(lines) && listItemPattern.MatchString(lines[i]); i++ {
+				fmt.Fprintf(&out, "<li>%s</li>\n", m.renderInline(listItemPattern.FindStringSubmatch(lines[i])[2]))
+			}
+			i--
+			fmt.Fprintf(&out, "</%s>\n", tag)
+		default:
+			paragraph = append(paragraph, trimmed)
+			if strings.HasSuffix(line, "  ") {
+				paragraph[len(paragraph)-1] += "<br>"
+			}
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// renderInline renders code spans, raw HTML, images, links, and emphasis.
+// Code spans are rendered first and kept out of the other replacements.
+func (m *markdownRenderer) renderInline(text string) string {
+	var spans []string
+	parts := strings.Split(text, "`")
+	var out strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			spans = append(spans, "<code>"+html.EscapeString(part)+"</code>")
+			fmt.Fprintf(&out, "\x00%d\x00", len(spans)-1)
+			continue
+		}
+		if i%2 == 1 {
+			out.WriteString("`")
+		}
+		out.WriteString(m.renderText(part))
+	}
+	result := out.String()
+	for i, span := range spans {
+		result = strings.Replace(result, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return result
+}
+
+// renderText renders text outside code spans
+func (m *markdownRenderer) renderText(text string) string {
+	for _, match := range emojiShortcode.FindAllStringSubmatch(text, -1) {
+		m.warn("Emoji shortcode ':%s:' is shown literally; use the emoji character instead", match[1])
+	}
+
+	// Sanitize raw HTML, escape everything else
+	var out strings.Builder
+	last := 0
+	for _, loc := range htmlTagPattern.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(html.EscapeString(text[last:loc[0]]))
+		out.WriteString(m.sanitizeTag(text[loc[2]:loc[3]] == "/", strings.ToLower(text[loc[4]:loc[5]]), text[loc[6]:loc[7]], text[loc[8]:loc[9]] == "/"))
+		last = loc[1]
+	}
+	out.WriteString(html.EscapeString(text[last:]))
+	text = out.String()
+
+	text = autolinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		url := autolinkPattern.FindStringSubmatch(match)[1]
+		return fmt.Sprintf(`<a href="%s">%s</a>`, url, url)
+	})
+	text = imagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := imagePattern.FindStringSubmatch(match)
+		src := m.safeURL(parts[2], "image")
+		return fmt.Sprintf(`<img src="%s" alt="%s"%s>`, src, parts[1], titleAttr(parts[3]))
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := linkPattern.FindStringSubmatch(match)
+		href := m.safeURL(parts[2], "link")
+		return fmt.Sprintf(`<a href="%s"%s>%s</a>`, href, titleAttr(parts[3]), parts[1])
+	})
+	text = strongPattern.ReplaceAllString(text, "<strong>$2</strong>")
+	text = emphasisPattern.ReplaceAllString(text, "$1<em>$2</em>$3")
+	text = strikethroughPattern.ReplaceAllString(text, "<del>$1</del>")
+	return text
+}
+
+// sanitizeTag keeps an allowed tag with its allowed attributes, and drops
+// anything else with a warning
+func (m *markdownRenderer) sanitizeTag(closing bool, name, attrs string, selfClosing bool) string {
+	allowed, ok := previewAllowedTags[name]
+	if !ok {
+		if !closing {
+			m.warn("Raw HTML <%s> is stripped by the platform", name)
+		}
+		return ""
+	}
+	if closing {
+		return "</" + name + ">"
+	}
+
+	var out strings.Builder
+	out.WriteString("<" + name)
+	for _, attr := range htmlAttrPattern.FindAllStringSubmatch(attrs, -1) {
+		key := strings.ToLower(attr[1])
+		value := strings.Trim(attr[2], `"'`)
+		if !allowed[key] {
+			m.warn("Attribute '%s' of <%s> is stripped by the platform", key, name)
+			continue
+		}
+		if key == "href" || key == "src" {
+			value = m.safeURL(html.UnescapeString(value), name)
+		} else {
+			value = html.EscapeString(html.UnescapeString(value))
+		}
+		fmt.Fprintf(&out, ` %s="%s"`, key, value)
+	}
+	if selfClosing {
+		out.WriteString(" /")
+	}
+	out.WriteString(">")
+	return out.String()
+}
+
+// safeURL escapes a URL, dropping the schemes the sanitizer removes
+func (m *markdownRenderer) safeURL(url, context string) string {
+	scheme := strings.ToLower(strings.TrimSpace(url))
+	for _, unsafe := range []string{"javascript:", "vbscript:", "data:text/html"} {
+		if strings.HasPrefix(scheme, unsafe) {
+			m.warn("%s URL of %s is stripped by the platform", strings.TrimSuffix(unsafe, ":"), context)
+			return ""
+		}
+	}
+	return html.EscapeString(html.UnescapeString(url))
+}
+
+func titleAttr(title string) string {
+	if title == "" {
+		return ""
+	}
+	return fmt.Sprintf(` title="%s"`, title)
+}
+
+// writeHTMLPreview writes a standalone page with every challenge as the
+// platform's challenge modal would show it
+func writeHTMLPreview(w io.Writer, previews []challengePreview) {
+	fmt.Fprintln(w, "<!DOCTYPE html>")
+	fmt.Fprintln(w, `<html><head><meta charset="utf-8"><title>clilint preview</title>`)
+	fmt.Fprintln(w, "<style>body{font-family:sans-serif;max-width:50em;margin:2em auto}section{border:1px solid #ccc;border-radius:6px;padding:1em;margin:1em 0}.meta{color:#666}.warning{color:#a60}pre{background:#f4f4f4;padding:.5em;overflow:auto}</style>")
+	fmt.Fprintln(w, "</head><body>")
+	for _, preview := range previews {
+		challenge := preview.Challenge
+		fmt.Fprintln(w, "<section>")
+		fmt.Fprintf(w, "<h2>%s</h2>\n", html.EscapeString(challenge.Name))
+		fmt.Fprintf(w, "<p class=\"meta\">%s · %d points · %s</p>\n", html.EscapeString(challenge.Category), challenge.Value, html.EscapeString(preview.File))
+		fmt.Fprint(w, preview.HTML)
+		if len(challenge.Files) > 0 {
+			fmt.Fprintln(w, "<ul class=\"files\">")
+			for _, file := range challenge.Files {
+				fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(file))
+			}
+			fmt.Fprintln(w, "</ul>")
+		}
+		for _, warning := range preview.Warnings {
+			fmt.Fprintf(w, "<p class=\"warning\">⚠️ %s</p>\n", html.EscapeString(warning))
+		}
+		fmt.Fprintln(w, "</section>")
+	}
+	fmt.Fprintln(w, "</body></html>")
+}
+
+// writeTerminalPreview prints each description as plain text with the tags
+// of the rendered HTML removed
+func writeTerminalPreview(w io.Writer, previews []challengePreview) {
+	for _, preview := range previews {
+		fmt.Fprintf(w, "👁️  %s (%s)\n", preview.File, preview.Challenge.Name)
+		text := htmlTagPattern.ReplaceAllStringFunc(preview.HTML, func(tag string) string {
+			name := strings.ToLower(htmlTagPattern.FindStringSubmatch(tag)[2])
+			switch name {
+			case "li":
+				if !strings.HasPrefix(tag, "</") {
+					return "• "
+				}
+			case "br":
+				return "\n"
+			}
+			return ""
+		})
+		for _, line := range strings.Split(strings.TrimSpace(html.UnescapeString(text)), "\n") {
+			fmt.Fprintf(w, "  │ %s\n", line)
+		}
+		for _, warning := range preview.Warnings {
+			fmt.Fprintf(w, "  ⚠️  %s\n", warning)
+		}
+		fmt.Fprintln(w)
+	}
+}
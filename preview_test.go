@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderDescription(t *testing.T) {
+	tests := []struct {
+		name         string
+		description  string
+		wantHTML     string
+		wantWarnings []string
+	}{
+		{
+			name:        "markdown",
+			description: "# Title\n\nSome **bold**, _em_, ~~old~~ and `<b>code</b>`.\nSecond [link](https://example.com \"site\") line\n\n- a\n- b\n\n1. one\n\n> quoted\n\n---\n\n```sh\nnc host 1337 < input\n```",
+			wantHTML: "<h1>Title</h1>\n" +
+				"<p>Some <strong>bold</strong>, <em>em</em>, <del>old</del> and <code>&lt;b&gt;code&lt;/b&gt;</code>.\nSecond <a href=\"https://example.com\" title=\"site\">link</a> line</p>\n" +
+				"<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n" +
+				"<ol>\n<li>one</li>\n</ol>\n" +
+				"<blockquote>\n<p>quoted</p>\n</blockquote>\n" +
+				"<hr>\n" +
+				"<pre><code class=\"language-sh\">nc host 1337 &lt; input</code></pre>\n",
+		},
+		{
+			name:        "allowed raw HTML",
+			description: "<details><summary>Hint</summary>Look <b>closer</b> at ![map](map.png)</details>",
+			wantHTML:    "<p><details><summary>Hint</summary>Look <b>closer</b> at <img src=\"map.png\" alt=\"map\"></details></p>\n",
+		},
+		{
+			name:        "stripped HTML",
+			description: "Watch <iframe src=\"https://youtube.com/embed/x\"></iframe> <span style=\"color:red\" onclick=\"x()\">here</span>\n<script>alert(1)</script>",
+			wantHTML:    "<p>Watch  <span>here</span></p>\n",
+			wantWarnings: []string{
+				"Raw HTML <script> is removed with its content",
+				"Raw HTML <iframe> is stripped by the platform",
+				"Attribute 'style' of <span> is stripped by the platform",
+				"Attribute 'onclick' of <span> is stripped by the platform",
+			},
+		},
+		{
+			name:         "javascript URL",
+			description:  "[click](javascript:alert(1))",
+			wantHTML:     "<p><a href=\"\">click</a>)</p>\n",
+			wantWarnings: []string{"javascript URL of link is stripped by the platform"},
+		},
+		{
+			name:         "emoji shortcodes",
+			description:  "Good luck :tada: at 10:30:00 and `:code:`",
+			wantHTML:     "<p>Good luck :tada: at 10:30:00 and <code>:code:</code></p>\n",
+			wantWarnings: []string{"Emoji shortcode ':tada:' is shown literally; use the emoji character instead"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, warnings := renderDescription(tt.description)
+			if html != tt.wantHTML {
+				t.Errorf("renderDescription() html =\n%s\nwant\n%s", html, tt.wantHTML)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("renderDescription() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestWritePreviews(t *testing.T) {
+	rendered, warnings := renderDescription("Connect with:\n\n- `nc host 1337`\n\n<iframe></iframe>")
+	previews := []challengePreview{{
+		File:      "web/login/challenge.yml",
+		Challenge: Challenge{Name: "Login <1>", Category: "web", Value: 100, Files: []string{"dist/app.zip"}},
+		HTML:      rendered,
+		Warnings:  warnings,
+	}}
+
+	var out bytes.Buffer
+	writeTerminalPreview(&out, previews)
+	want := "👁️  web/login/challenge.yml (Login <1>)\n  │ Connect with:\n  │ \n  │ • nc host 1337\n  ⚠️  Raw HTML <iframe> is stripped by the platform\n\n"
+	if out.String() != want {
+		t.Errorf("writeTerminalPreview() =\n%q\nwant\n%q", out.String(), want)
+	}
+
+	out.Reset()
+	writeHTMLPreview(&out, previews)
+	for _, fragment := range []string{
+		"<h2>Login &lt;1&gt;</h2>",
+		"<li><code>nc host 1337</code></li>",
+		"<li>dist/app.zip</li>",
+		"⚠️ Raw HTML &lt;iframe&gt; is stripped by the platform",
+	} {
+		if !strings.Contains(out.String(), fragment) {
+			t.Errorf("writeHTMLPreview() is missing %q:\n%s", fragment, out.String())
+		}
+	}
+}
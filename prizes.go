@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// currencyCode matches ISO 4217 currency codes such as USD or JPY
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// prizeKeys are the keys of a prize, and prizeKinds the prizes a challenge
+// may offer
+var (
+	prizeKeys  = []string{"amount", "currency", "sponsor"}
+	prizeKinds = []string{"first_blood"}
+)
+
+// PrizesRule validates the optional prizes block of challenges, e.g.
+//
+//	prizes:
+//	  first_blood: {amount: 100, currency: USD, sponsor: Acme}
+type PrizesRule struct {
+	// Currencies are the allowed currencies (default: any ISO 4217 code)
+	Currencies []string `yaml:"currencies"`
+	// Sponsors are the allowed sponsors (default: any)
+	Sponsors []string `yaml:"sponsors"`
+	// RequireSponsor requires every prize to name its sponsor
+	RequireSponsor bool `yaml:"require_sponsor"`
+	// MaxAmount bounds the amount of a prize, in its currency
+	MaxAmount float64 `yaml:"max_amount"`
+}
+
+// Prize is a validated prize of a challenge
+type Prize struct {
+	Amount   float64
+	Currency string
+	Sponsor  string
+}
+
+// String renders a prize as "100 USD (Acme)"
+func (p Prize) String() string {
+	text := strconv.FormatFloat(p.Amount, 'f', -1, 64) + " " + p.Currency
+	if p.Sponsor != "" {
+		text += " (" + p.Sponsor + ")"
+	}
+	return text
+}
+
+// firstBloodPrize returns the first blood prize of a challenge, if it has a
+// valid one
+func firstBloodPrize(challenge Challenge) (Prize, bool) {
+	prizes, ok := challenge.Prizes.(map[string]interface{})
+	if !ok {
+		return Prize{}, false
+	}
+	prize, errors := parsePrize(prizes["first_blood"], "prizes.first_blood", PrizesRule{})
+	return prize, prizes["first_blood"] != nil && len(errors) == 0
+}
+
+func checkPrizes(challenge Challenge, rule PrizesRule) []string {
+	var errors []string
+
+	if challenge.Prizes == nil {
+		return errors
+	}
+	prizes, ok := challenge.Prizes.(map[string]interface{})
+	if !ok {
+		return append(errors, "Field 'prizes' must be a mapping of prizes, e.g. first_blood")
+	}
+	keys := make([]string, 0, len(prizes))
+	for key := range prizes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !slices.Contains(prizeKinds, key) {
+			errors = append(errors, fmt.Sprintf("Field 'prizes' has unknown key '%s' (expected %s)", key, strings.Join(prizeKinds, ", ")))
+			continue
+		}
+		_, prizeErrors := parsePrize(prizes[key], "prizes."+key, rule)
+		errors = append(errors, prizeErrors...)
+	}
+	return errors
+}
+
+// parsePrize reads and validates one prize
+func parsePrize(value interface{}, field string, rule PrizesRule) (Prize, []string) {
+	var prize Prize
+	var errors []string
+
+	entry, ok := value.(map[string]interface{})
+	if !ok {
+		return prize, []string{fmt.Sprintf("Field '%s' must be a mapping with amount, currency, and sponsor", field)}
+	}
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !slices.Contains(prizeKeys, key) {
+			errors = append(errors, fmt.Sprintf("Field '%s' has unknown key '%s' (expected amount, currency, and sponsor)", field, key))
+		}
+	}
+
+	numeric := true
+	switch amount := entry["amount"].(type) {
+	case int:
+		prize.Amount = float64(amount)
+	case float64:
+		prize.Amount = amount
+	case nil:
+		numeric = false
+		errors = append(errors, fmt.Sprintf("Field '%s.amount' is required", field))
+	default:
+		numeric = false
+		errors = append(errors, fmt.Sprintf("Field '%s.amount' must be a number, got '%v'", field, amount))
+	}
+	switch {
+	case numeric && prize.Amount <= 0:
+		errors = append(errors, fmt.Sprintf("Field '%s.amount' must be positive", field))
+	case rule.MaxAmount > 0 && prize.Amount > rule.MaxAmount:
+		errors = append(errors, fmt.Sprintf("Field '%s.amount' is %s, above prizes.max_amount (%s)", field,
+			strconv.FormatFloat(prize.Amount, 'f', -1, 64), strconv.FormatFloat(rule.MaxAmount, 'f', -1, 64)))
+	}
+
+	prize.Currency, _ = entry["currency"].(string)
+	switch {
+	case prize.Currency == "":
+		errors = append(errors, fmt.Sprintf("Field '%s.currency' is required", field))
+	case len(rule.Currencies) > 0 && !containsFold(rule.Currencies, prize.Currency):
+		errors = append(errors, fmt.Sprintf("Field '%s.currency' is '%s', expected one of %s", field, prize.Currency, strings.Join(rule.Currencies, ", ")))
+	case len(rule.Currencies) == 0 && !currencyCode.MatchString(prize.Currency):
+		errors = append(errors, fmt.Sprintf("Field '%s.currency' is '%s', expected an ISO 4217 code such as USD", field, prize.Currency))
+	}
+
+	prize.Sponsor, _ = entry["sponsor"].(string)
+	switch {
+	case prize.Sponsor == "" && rule.RequireSponsor:
+		errors = append(errors, fmt.Sprintf("Field '%s.sponsor' is required", field))
+	case prize.Sponsor != "" && len(rule.Sponsors) > 0 && !containsFold(rule.Sponsors, prize.Sponsor):
+		errors = append(errors, fmt.Sprintf("Field '%s.sponsor' is '%s', which is not in prizes.sponsors", field, prize.Sponsor))
+	}
+	return prize, errors
+}
+
+// formatPrize renders the first blood prize of a challenge for the export
+func formatPrize(challenge Challenge) string {
+	if prize, ok := firstBloodPrize(challenge); ok {
+		return prize.String()
+	}
+	return ""
+}
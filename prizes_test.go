@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckPrizes(t *testing.T) {
+	rule := PrizesRule{Currencies: []string{"USD", "JPY"}, Sponsors: []string{"Acme"}, RequireSponsor: true, MaxAmount: 500}
+	prize := func(entry map[string]interface{}) interface{} {
+		return map[string]interface{}{"first_blood": entry}
+	}
+	tests := []struct {
+		name   string
+		prizes interface{}
+		rule   PrizesRule
+		want   []string
+	}{
+		{
+			name: "no prizes",
+			rule: rule,
+		},
+		{
+			name:   "valid",
+			prizes: prize(map[string]interface{}{"amount": 100, "currency": "USD", "sponsor": "acme"}),
+			rule:   rule,
+		},
+		{
+			name:   "any ISO code without a rule",
+			prizes: prize(map[string]interface{}{"amount": 12.5, "currency": "EUR"}),
+		},
+		{
+			name:   "not a mapping",
+			prizes: "100 USD",
+			want:   []string{"Field 'prizes' must be a mapping of prizes, e.g. first_blood"},
+		},
+		{
+			name:   "unknown kinds and keys",
+			prizes: map[string]interface{}{"second_blood": 50, "first_blood": map[string]interface{}{"amount": 100, "currency": "USD", "note": "x"}},
+			want: []string{
+				"Field 'prizes.first_blood' has unknown key 'note' (expected amount, currency, and sponsor)",
+				"Field 'prizes' has unknown key 'second_blood' (expected first_blood)",
+			},
+		},
+		{
+			name:   "invalid amount and currency",
+			prizes: prize(map[string]interface{}{"amount": "100", "currency": "usd"}),
+			want: []string{
+				"Field 'prizes.first_blood.amount' must be a number, got '100'",
+				"Field 'prizes.first_blood.currency' is 'usd', expected an ISO 4217 code such as USD",
+			},
+		},
+		{
+			name:   "against the rule",
+			prizes: prize(map[string]interface{}{"amount": 1000, "currency": "EUR", "sponsor": "Globex"}),
+			rule:   rule,
+			want: []string{
+				"Field 'prizes.first_blood.amount' is 1000, above prizes.max_amount (500)",
+				"Field 'prizes.first_blood.currency' is 'EUR', expected one of USD, JPY",
+				"Field 'prizes.first_blood.sponsor' is 'Globex', which is not in prizes.sponsors",
+			},
+		},
+		{
+			name:   "missing fields",
+			prizes: prize(map[string]interface{}{"amount": 0}),
+			rule:   rule,
+			want: []string{
+				"Field 'prizes.first_blood.amount' must be positive",
+				"Field 'prizes.first_blood.currency' is required",
+				"Field 'prizes.first_blood.sponsor' is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkPrizes(Challenge{Prizes: tt.prizes}, tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkPrizes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstBloodPrize(t *testing.T) {
+	challenge := Challenge{Prizes: map[string]interface{}{"first_blood": map[string]interface{}{"amount": 100, "currency": "USD", "sponsor": "Acme"}}}
+	if got := formatPrize(challenge); got != "100 USD (Acme)" {
+		t.Errorf("formatPrize() = %q, want %q", got, "100 USD (Acme)")
+	}
+	invalid := Challenge{Prizes: map[string]interface{}{"first_blood": map[string]interface{}{"amount": -1, "currency": "USD"}}}
+	if got := formatPrize(invalid); got != "" {
+		t.Errorf("formatPrize(invalid) = %q, want empty", got)
+	}
+	if got := formatPrize(Challenge{}); got != "" {
+		t.Errorf("formatPrize(none) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// cpuProfilePath and memProfilePath are set by --cpuprofile and --memprofile
+var (
+	cpuProfilePath string
+	memProfilePath string
+	cpuProfileFile *os.File
+)
+
+// startProfiles starts the CPU profile requested by --cpuprofile
+func startProfiles() error {
+	if cpuProfilePath == "" {
+		return nil
+	}
+	file, err := os.Create(cpuProfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CPU profile: %v", err)
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to start CPU profile: %v", err)
+	}
+	cpuProfileFile = file
+	return nil
+}
+
+// stopProfiles stops the CPU profile and writes the heap profile requested by
+// --memprofile. It must run before the process exits for the files to be
+// complete.
+func stopProfiles() {
+	if cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		if err := cpuProfileFile.Close(); err != nil {
+			log.Printf("Warning: failed to write CPU profile: %v", err)
+		}
+		cpuProfileFile = nil
+	}
+
+	if memProfilePath == "" {
+		return
+	}
+	file, err := os.Create(memProfilePath)
+	if err != nil {
+		log.Printf("Warning: failed to create memory profile: %v", err)
+		return
+	}
+	defer file.Close()
+	// Up-to-date statistics of allocations
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		log.Printf("Warning: failed to write memory profile: %v", err)
+	}
+}
+
+// exit stops profiling before exiting with code
+func exit(code int) {
+	stopProfiles()
+	os.Exit(code)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	cpuProfilePath = filepath.Join(tempDir, "cpu.pprof")
+	memProfilePath = filepath.Join(tempDir, "mem.pprof")
+	defer func() {
+		cpuProfilePath, memProfilePath = "", ""
+	}()
+
+	if err := startProfiles(); err != nil {
+		t.Fatalf("startProfiles() error: %v", err)
+	}
+	stopProfiles()
+
+	for _, path := range []string{cpuProfilePath, memProfilePath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("profile not written: %v", err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s is empty", path)
+		}
+	}
+}
+
+func TestProfilesDisabled(t *testing.T) {
+	if err := startProfiles(); err != nil {
+		t.Fatalf("startProfiles() error: %v", err)
+	}
+	stopProfiles()
+	if cpuProfileFile != nil {
+		t.Error("CPU profile started without --cpuprofile")
+	}
+}
+
+func TestProfilesInvalidPath(t *testing.T) {
+	cpuProfilePath = filepath.Join(t.TempDir(), "missing", "cpu.pprof")
+	defer func() {
+		cpuProfilePath = ""
+	}()
+
+	if err := startProfiles(); err == nil {
+		stopProfiles()
+		t.Error("startProfiles() succeeded for an uncreatable file")
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// publishMaxFileSize is the largest file publish-check reads; larger files
+// are reported as skipped
+const publishMaxFileSize = 10 << 20
+
+// credentialPatterns match infrastructure credentials by their format
+var credentialPatterns = []struct {
+	Name    string
+	Pattern *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Discord webhook", regexp.MustCompile(`https://(?:\w+\.)?discord(?:app)?\.com/api/webhooks/\d+/[\w-]+`)},
+	{"private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED )?PRIVATE KEY-----`)},
+}
+
+// PublishRule is the profile of clilint publish-check, run before the
+// repository is made public after the event
+type PublishRule struct {
+	// InternalDomains are domains of the event infrastructure; any hostname
+	// in them is reported, e.g. ctf.internal or corp.example.com
+	InternalDomains []string `yaml:"internal_domains"`
+	// Deny lists strings that must not remain anywhere, compared case-insensitively
+	Deny []string `yaml:"deny"`
+	// Writeup matches the writeup every challenge directory must contain
+	// (default "writeup*")
+	Writeup string `yaml:"writeup"`
+	// Ignore lists gitignore-style patterns of paths not scanned, e.g. the
+	// keys of a crypto challenge
+	Ignore []string `yaml:"ignore"`
+}
+
+// PublishFinding is one problem that blocks publishing the repository
+type PublishFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Kind    string `json:"kind"` // credential, internal-host, denied, writeup, or skipped
+	Message string `json:"message"`
+}
+
+func runPublishCheck(args []string) {
+	jsonOutput := false
+	var targetDirs []string
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		} else {
+			targetDirs = append(targetDirs, arg)
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	var findings []PublishFinding
+	for _, dir := range targetDirs {
+		found, err := publishCheck(dir, config.Publish)
+		if err != nil {
+			log.Fatalf("Error checking directory %s: %v", dir, err)
+		}
+		findings = append(findings, found...)
+	}
+
+	if jsonOutput {
+		if findings == nil {
+			findings = []PublishFinding{}
+		}
+		jsonData, err := json.Marshal(findings)
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		writePublishFindings(os.Stdout, findings)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
+
+// publishCheck scans every file under rootDir for credentials, internal
+// hostnames, and denied strings, and checks that every challenge has a writeup
+func publishCheck(rootDir string, rule PublishRule) ([]PublishFinding, error) {
+	var findings []PublishFinding
+
+	hostPattern := internalHostPattern(rule.InternalDomains)
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(rootDir, path)
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == defaultRepoCacheDir || isIgnored(rel, true, rule.Ignore) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// lintrc.yaml lists the denied strings and domains itself
+		if !d.Type().IsRegular() || d.Name() == "lintrc.yaml" || isIgnored(rel, false, rule.Ignore) {
+			return nil
+		}
+		found, err := scanPublishFile(path, hostPattern, rule.Deny)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, found...)
+		return nil
+	})
+	if err != nil {
+		return findings, err
+	}
+
+	paths, err := findChallengeFiles(rootDir)
+	if err != nil {
+		return findings, err
+	}
+	writeup := rule.Writeup
+	if writeup == "" {
+		writeup = defaultWriteupPattern
+	}
+	for _, path := range paths {
+		if !hasWriteup(path, writeup) {
+			findings = append(findings, PublishFinding{
+				File:    path,
+				Kind:    "writeup",
+				Message: fmt.Sprintf("Challenge has no writeup matching '%s'", writeup),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+// internalHostPattern matches hostnames in the internal domains, nil when none are configured
+func internalHostPattern(domains []string) *regexp.Regexp {
+	var quoted []string
+	for _, domain := range domains {
+		domain = strings.Trim(strings.TrimSpace(domain), ".")
+		if domain != "" {
+			quoted = append(quoted, regexp.QuoteMeta(domain))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:[a-z0-9-]+\.)*(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// scanPublishFile reports the credentials, internal hostnames, and denied
+// strings of one file line by line. Matches are shortened so that the
+// report does not repeat a secret.
+func scanPublishFile(path string, hostPattern *regexp.Regexp, deny []string) ([]PublishFinding, error) {
+	var findings []PublishFinding
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > publishMaxFileSize {
+		return append(findings, PublishFinding{
+			File:    path,
+			Kind:    "skipped",
+			Message: fmt.Sprintf("File is larger than %d MB and was not scanned; review it manually or add it to publish.ignore", publishMaxFileSize>>20),
+		}), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), publishMaxFileSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		for _, credential := range credentialPatterns {
+			if match := credential.Pattern.FindString(line); match != "" {
+				findings = append(findings, PublishFinding{
+					File:    path,
+					Line:    lineNumber,
+					Kind:    "credential",
+					Message: fmt.Sprintf("Possible %s: %s", credential.Name, redact(match)),
+				})
+			}
+		}
+		if hostPattern != nil {
+			for _, host := range hostPattern.FindAllString(line, -1) {
+				findings = append(findings, PublishFinding{
+					File:    path,
+					Line:    lineNumber,
+					Kind:    "internal-host",
+					Message: fmt.Sprintf("Contains the internal hostname '%s'", host),
+				})
+			}
+		}
+		lower := strings.ToLower(line)
+		for _, denied := range deny {
+			if denied != "" && strings.Contains(lower, strings.ToLower(denied)) {
+				findings = append(findings, PublishFinding{
+					File:    path,
+					Line:    lineNumber,
+					Kind:    "denied",
+					Message: fmt.Sprintf("Contains the denied string '%s'", denied),
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return findings, nil
+}
+
+// redact keeps the first characters of a secret, enough to find it
+func redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:8] + "…"
+}
+
+func writePublishFindings(w io.Writer, findings []PublishFinding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "✅ Ready to publish")
+		return
+	}
+	current := ""
+	for _, finding := range findings {
+		if finding.File != current {
+			current = finding.File
+			fmt.Fprintf(w, "🚫 %s\n", current)
+		}
+		if finding.Line > 0 {
+			fmt.Fprintf(w, "  - line %d: %s\n", finding.Line, finding.Message)
+		} else {
+			fmt.Fprintf(w, "  - %s\n", finding.Message)
+		}
+	}
+	fmt.Fprintf(w, "\n%d finding(s) block publishing the repository\n", len(findings))
+}
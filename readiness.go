@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// readinessCommandTimeout bounds each readiness command, e.g. a solver run
+const readinessCommandTimeout = 5 * time.Minute
+
+// ReadinessRule configures the readiness score reported by clilint readiness
+type ReadinessRule struct {
+	// Min is the score every challenge must reach unless --min is given
+	// (default: 100)
+	Min int `yaml:"min"`
+	// Weights maps rule IDs to their weight in the score; other rules weigh 1,
+	// and rules weighted 0 do not count
+	Weights map[string]int `yaml:"weights"`
+	// Items are further conditions, such as a writeup or a passing solver
+	Items []ReadinessItem `yaml:"items"`
+}
+
+// ReadinessItem is a weighted readiness condition. Like a checklist item it
+// may require rules to pass and a file to exist; it may also run a command
+// in the challenge directory or request a URL, which must succeed.
+type ReadinessItem struct {
+	ChecklistItem `yaml:",inline"`
+	// Weight is the item's weight in the score (default: 1)
+	Weight int `yaml:"weight"`
+	// Command is run with sh -c in the challenge directory and must exit 0
+	Command string `yaml:"command"`
+	// URL must respond with 2xx; ${VAR} placeholders are expanded
+	URL string `yaml:"url"`
+}
+
+// ReadinessScore is the readiness of one challenge
+type ReadinessScore struct {
+	File  string `json:"file"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+	// Missing lists the rules and items that cost points, with their weight
+	Missing []ReadinessGap `json:"missing,omitempty"`
+}
+
+// ReadinessGap is a rule with errors or an unmet readiness item
+type ReadinessGap struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+func runReadiness(args []string) {
+	jsonOutput := false
+	minScore := -1
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--json" {
+			jsonOutput = true
+		} else if args[i] == "--min" && i+1 < len(args) {
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 || n > 100 {
+				log.Fatalf("Invalid --min (want a score from 0 to 100): %s", args[i])
+			}
+			minScore = n
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	if minScore < 0 {
+		minScore = config.Readiness.Min
+		if minScore == 0 {
+			minScore = 100
+		}
+	}
+
+	var scores []ReadinessScore
+	for _, dir := range targetDirs {
+		results, err := lintChallenges(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		checkFlagCollisions(results, nil)
+		for _, result := range results {
+			scores = append(scores, scoreReadiness(result, config))
+		}
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].File < scores[j].File })
+
+	if jsonOutput {
+		jsonData, err := json.Marshal(map[string]interface{}{"min": minScore, "challenges": scores})
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+	} else {
+		writeReadiness(os.Stdout, scores, minScore)
+	}
+
+	for _, score := range scores {
+		if score.Score < minScore {
+			os.Exit(1)
+		}
+	}
+}
+
+// scoreReadiness weighs the rule outcomes of a lint result and the readiness
+// items of the config into a score from 0 to 100
+func scoreReadiness(result LintResult, config *LintConfig) ReadinessScore {
+	score := ReadinessScore{File: result.File, Name: result.Name}
+	rule := config.Readiness
+
+	failing := make(map[string]bool)
+	for _, finding := range result.Errors {
+		failing[finding.RuleID] = true
+	}
+
+	var ruleIDs []string
+	for ruleID := range lintRules {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+
+	possible, earned := 0, 0
+	for _, ruleID := range ruleIDs {
+		weight, ok := rule.Weights[ruleID]
+		if !ok {
+			weight = 1
+		}
+		if weight <= 0 {
+			continue
+		}
+		possible += weight
+		if failing[ruleID] {
+			score.Missing = append(score.Missing, ReadinessGap{Name: ruleID, Weight: weight})
+		} else {
+			earned += weight
+		}
+	}
+
+	for _, item := range rule.Items {
+		weight := item.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		possible += weight
+		if readinessItemMet(item, result, config) {
+			earned += weight
+		} else {
+			score.Missing = append(score.Missing, ReadinessGap{Name: item.Item, Weight: weight})
+		}
+	}
+
+	if possible > 0 {
+		score.Score = int(math.Floor(float64(earned) * 100 / float64(possible)))
+	}
+	return score
+}
+
+// readinessItemMet evaluates every condition of a readiness item
+func readinessItemMet(item ReadinessItem, result LintResult, config *LintConfig) bool {
+	if len(item.Passes) > 0 || item.Exists != "" {
+		if !evaluateChecklist([]ChecklistItem{item.ChecklistItem}, result.File, result)[0].Checked {
+			return false
+		}
+	}
+	if item.Command != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), readinessCommandTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", item.Command)
+		cmd.Dir = filepath.Dir(result.File)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			log.Printf("Warning: %s: '%s' failed: %v\n%s", result.File, item.Command, err, output)
+			return false
+		}
+	}
+	if item.URL != "" {
+		lookup, err := loadVars(config)
+		if err != nil {
+			log.Printf("Warning: %s: %v", result.File, err)
+			return false
+		}
+		url, missing, err := expandPlaceholders(item.URL, lookup)
+		if err != nil || len(missing) > 0 {
+			log.Printf("Warning: %s: cannot resolve '%s'", result.File, item.URL)
+			return false
+		}
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Get(url)
+		if err != nil {
+			log.Printf("Warning: %s: %v", result.File, err)
+			return false
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			log.Printf("Warning: %s: %s returned HTTP %d", result.File, url, resp.StatusCode)
+			return false
+		}
+	}
+	return len(item.Passes) > 0 || item.Exists != "" || item.Command != "" || item.URL != ""
+}
+
+// writeReadiness writes the scores and what each challenge is missing
+func writeReadiness(w io.Writer, scores []ReadinessScore, minScore int) {
+	ready := 0
+	for _, score := range scores {
+		mark := "✅"
+		if score.Score < minScore {
+			mark = "❌"
+		} else {
+			ready++
+		}
+		fmt.Fprintf(w, "%s %3d  %s (%s)\n", mark, score.Score, score.File, score.Name)
+		for _, gap := range score.Missing {
+			fmt.Fprintf(w, "       - %s (weight %d)\n", gap.Name, gap.Weight)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d challenge(s) reach a readiness score of %d\n", ready, len(scores), minScore)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScoreReadiness(t *testing.T) {
+	dir := t.TempDir()
+	challengePath := filepath.Join(dir, "challenge.yml")
+	if err := os.WriteFile(filepath.Join(dir, "writeup.md"), []byte("# Writeup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer healthy.Close()
+	t.Setenv("CLILINT_TEST_HEALTH", healthy.URL)
+
+	// Every rule weighs 0 except host, so the score is driven by the items
+	weights := map[string]int{"host": 2}
+	for ruleID := range lintRules {
+		if ruleID != "host" {
+			weights[ruleID] = 0
+		}
+	}
+	config := &LintConfig{Readiness: ReadinessRule{
+		Weights: weights,
+		Items: []ReadinessItem{
+			{ChecklistItem: ChecklistItem{Item: "Writeup present", Exists: "writeup.md"}, Weight: 2},
+			{ChecklistItem: ChecklistItem{Item: "Solver passes"}, Command: "test -f writeup.md", Weight: 2},
+			{ChecklistItem: ChecklistItem{Item: "Deployment healthy"}, URL: "${CLILINT_TEST_HEALTH}/health", Weight: 2},
+			{ChecklistItem: ChecklistItem{Item: "Solver script present", Exists: "solve.py"}},
+			{ChecklistItem: ChecklistItem{Item: "Status page up"}, URL: "${CLILINT_TEST_HEALTH}/status", Weight: 1},
+		},
+	}}
+
+	result := LintResult{File: challengePath, Name: "login", Errors: []Finding{{RuleID: "host", Message: "Field 'host' is required for this category"}}}
+	got := scoreReadiness(result, config)
+	want := ReadinessScore{
+		File:  challengePath,
+		Name:  "login",
+		Score: 60, // 6 of 10
+		Missing: []ReadinessGap{
+			{Name: "host", Weight: 2},
+			{Name: "Solver script present", Weight: 1},
+			{Name: "Status page up", Weight: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scoreReadiness() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScoreReadinessDefaultWeights(t *testing.T) {
+	result := LintResult{File: "challenge.yml", Name: "clean"}
+	if got := scoreReadiness(result, &LintConfig{}); got.Score != 100 || len(got.Missing) != 0 {
+		t.Errorf("expected a clean challenge to score 100, got %+v", got)
+	}
+
+	result.Errors = []Finding{{RuleID: "state"}, {RuleID: "state"}}
+	got := scoreReadiness(result, &LintConfig{})
+	if want := (len(lintRules) - 1) * 100 / len(lintRules); got.Score != want {
+		t.Errorf("score = %d, want %d", got.Score, want)
+	}
+}
+
+func TestWriteReadiness(t *testing.T) {
+	scores := []ReadinessScore{
+		{File: "pwn/bof/challenge.yml", Name: "bof", Score: 72, Missing: []ReadinessGap{{Name: "Writeup present", Weight: 20}}},
+		{File: "web/login/challenge.yml", Name: "login", Score: 100},
+	}
+	var out bytes.Buffer
+	writeReadiness(&out, scores, 90)
+	for _, want := range []string{
+		"❌  72  pwn/bof/challenge.yml (bof)\n       - Writeup present (weight 20)\n",
+		"✅ 100  web/login/challenge.yml (login)\n",
+		"1 of 2 challenge(s) reach a readiness score of 90\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// releaseScheme prefixes files entries of the form release://tag/asset,
+// attachments uploaded to a GitHub release of the repository
+const releaseScheme = "release://"
+
+// defaultMaxReleaseAssetSize is GitHub's limit for a single release asset
+const defaultMaxReleaseAssetSize = 2 * 1024 * 1024 * 1024
+
+// ReleasesRule configures the checks of release://tag/asset files entries
+type ReleasesRule struct {
+	// Repository is the owner/repo holding the releases (default: GITHUB_REPOSITORY)
+	Repository string `yaml:"repository"`
+	// MaxSize is the largest allowed asset in bytes (default: 2 GB)
+	MaxSize int64 `yaml:"max_size"`
+}
+
+// releaseCache holds the releases already fetched in this run, keyed by
+// owner/repo@tag; a nil entry records a release that does not exist
+var releaseCache = make(map[string]*github.RepositoryRelease)
+
+// releaseCacheMu guards releaseCache, which a timed out rule may still use
+var releaseCacheMu sync.Mutex
+
+// isReleaseFile reports whether a files entry refers to a release asset
+func isReleaseFile(file string) bool {
+	return strings.HasPrefix(file, releaseScheme)
+}
+
+// parseReleaseFile splits release://tag/asset into its tag and asset name.
+// Tags may contain slashes; asset names cannot.
+func parseReleaseFile(file string) (tag, asset string, ok bool) {
+	ref := strings.TrimPrefix(file, releaseScheme)
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:i], ref[i+1:], true
+}
+
+// checkReleaseAssets verifies that the release and asset of every
+// release://tag/asset files entry exist and that the asset is within the
+// size limit
+func checkReleaseAssets(files []string, rule ReleasesRule) []string {
+	var errors []string
+
+	repository := rule.Repository
+	if repository == "" {
+		repository = os.Getenv("GITHUB_REPOSITORY")
+	}
+	maxSize := rule.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxReleaseAssetSize
+	}
+
+	for _, file := range files {
+		if !isReleaseFile(file) {
+			continue
+		}
+		tag, name, ok := parseReleaseFile(file)
+		if !ok {
+			errors = append(errors, fmt.Sprintf("Release file '%s' must be of the form release://tag/asset", file))
+			continue
+		}
+		owner, repo, found := strings.Cut(repository, "/")
+		if !found || owner == "" || repo == "" {
+			errors = append(errors, fmt.Sprintf("Release file '%s' needs a repository: set releases.repository or GITHUB_REPOSITORY", file))
+			continue
+		}
+
+		release, err := fetchRelease(owner, repo, tag)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Release file '%s' could not be verified: %v", file, err))
+			continue
+		}
+		if release == nil {
+			errors = append(errors, fmt.Sprintf("Release '%s' does not exist in %s", tag, repository))
+			continue
+		}
+
+		var asset *github.ReleaseAsset
+		for _, candidate := range release.Assets {
+			if candidate.GetName() == name {
+				asset = candidate
+				break
+			}
+		}
+		if asset == nil {
+			errors = append(errors, fmt.Sprintf("Release '%s' has no asset '%s'", tag, name))
+			continue
+		}
+		if size := int64(asset.GetSize()); size > maxSize {
+			sizeMB := float64(size) / (1024 * 1024)
+			maxMB := float64(maxSize) / (1024 * 1024)
+			errors = append(errors, fmt.Sprintf("Release asset '%s' is too large: %.2f MB (maximum allowed: %.2f MB)", file, sizeMB, maxMB))
+		}
+	}
+
+	return errors
+}
+
+// fetchRelease returns the release of a tag, or nil if there is none
+func fetchRelease(owner, repo, tag string) (*github.RepositoryRelease, error) {
+	key := fmt.Sprintf("%s/%s@%s", owner, repo, tag)
+	releaseCacheMu.Lock()
+	release, ok := releaseCache[key]
+	releaseCacheMu.Unlock()
+	if ok {
+		return release, nil
+	}
+
+	client, ctx := getGitHubClient(os.Getenv("GITHUB_TOKEN"))
+	release, _, err := client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	var apiErr *github.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.Response.StatusCode == http.StatusNotFound {
+		release, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	releaseCacheMu.Lock()
+	releaseCache[key] = release
+	releaseCacheMu.Unlock()
+	return release, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestCheckReleaseAssets(t *testing.T) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/releases/tags/v1.0", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"tag_name": "v1.0", "assets": [{"name": "dump.zip", "size": 1024}, {"name": "huge.zip", "size": 2097152}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/releases/tags/web/v2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "web/v2", "assets": [{"name": "app.tar.gz", "size": 10}]}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+	releaseCache = make(map[string]*github.RepositoryRelease)
+	defer func() {
+		releaseCache = make(map[string]*github.RepositoryRelease)
+	}()
+
+	tests := []struct {
+		name  string
+		files []string
+		rule  ReleasesRule
+		want  []string
+	}{
+		{
+			name:  "existing assets",
+			files: []string{"release://v1.0/dump.zip", "release://web/v2/app.tar.gz", "public/local.zip", "https://example.com/a.zip"},
+			rule:  ReleasesRule{MaxSize: 1024 * 1024},
+		},
+		{
+			name:  "asset too large",
+			files: []string{"release://v1.0/huge.zip"},
+			rule:  ReleasesRule{MaxSize: 1024 * 1024},
+			want:  []string{"Release asset 'release://v1.0/huge.zip' is too large: 2.00 MB (maximum allowed: 1.00 MB)"},
+		},
+		{
+			name:  "missing asset",
+			files: []string{"release://v1.0/missing.zip"},
+			want:  []string{"Release 'v1.0' has no asset 'missing.zip'"},
+		},
+		{
+			name:  "missing release",
+			files: []string{"release://v9.9/dump.zip"},
+			want:  []string{"Release 'v9.9' does not exist in owner/repo"},
+		},
+		{
+			name:  "malformed entry",
+			files: []string{"release://dump.zip", "release://v1.0/"},
+			want: []string{
+				"Release file 'release://dump.zip' must be of the form release://tag/asset",
+				"Release file 'release://v1.0/' must be of the form release://tag/asset",
+			},
+		},
+		{
+			name:  "other repository",
+			files: []string{"release://v1.0/dump.zip"},
+			rule:  ReleasesRule{Repository: "owner/other"},
+			want:  []string{"Release 'v1.0' does not exist in owner/other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkReleaseAssets(tt.files, tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkReleaseAssets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if requests != 1 {
+		t.Errorf("expected the v1.0 release to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestCheckReleaseAssetsWithoutRepository(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "")
+	got := checkReleaseAssets([]string{"release://v1.0/dump.zip"}, ReleasesRule{})
+	want := []string{"Release file 'release://v1.0/dump.zip' needs a repository: set releases.repository or GITHUB_REPOSITORY"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkReleaseAssets() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// ReviewersRule routes challenge PRs to reviewers in serve mode. It is read
+// from the --config lintrc.yaml of clilint serve, never from the PR.
+type ReviewersRule struct {
+	// Categories maps a category to the GitHub logins reviewing it; the
+	// reviewers of "*" review categories without an entry
+	Categories map[string][]string `yaml:"categories"`
+}
+
+// reviewersFor returns the reviewers of a category
+func (r ReviewersRule) reviewersFor(category string) []string {
+	for key, reviewers := range r.Categories {
+		if strings.EqualFold(key, category) {
+			return reviewers
+		}
+	}
+	return r.Categories["*"]
+}
+
+// reviewAssignment is a category of a challenge PR routed to a reviewer
+type reviewAssignment struct {
+	Repo      string     `json:"repo"`
+	PR        int        `json:"pr"`
+	Category  string     `json:"category"`
+	Reviewer  string     `json:"reviewer"`
+	Assigned  time.Time  `json:"assigned"`
+	Completed *time.Time `json:"completed,omitempty"`
+}
+
+// reviewQueue keeps the assignments of clilint serve in a JSON file, so that
+// a reviewer's load survives restarts
+type reviewQueue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (q *reviewQueue) load() ([]reviewAssignment, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var assignments []reviewAssignment
+	if err := json.Unmarshal(data, &assignments); err != nil {
+		return nil, fmt.Errorf("invalid review queue %s: %v", q.path, err)
+	}
+	return assignments, nil
+}
+
+func (q *reviewQueue) save(assignments []reviewAssignment) error {
+	data, err := json.MarshalIndent(assignments, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(q.path, append(data, '\n'), 0644)
+}
+
+// assign routes each category of a PR that has no reviewer yet to the
+// least-loaded reviewer of the category other than the PR author. It returns
+// the assignments of the PR and those it added.
+func (q *reviewQueue) assign(repo string, pr int, author string, categories []string, rule ReviewersRule) ([]reviewAssignment, []reviewAssignment, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	assignments, err := q.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added []reviewAssignment
+	for _, category := range categories {
+		if prAssignment(assignments, repo, pr, category) != nil {
+			continue
+		}
+		var candidates []string
+		for _, reviewer := range rule.reviewersFor(category) {
+			reviewer = strings.TrimPrefix(reviewer, "@")
+			// GitHub rejects review requests for the PR author
+			if !strings.EqualFold(reviewer, author) {
+				candidates = append(candidates, reviewer)
+			}
+		}
+		reviewer := leastLoaded(candidates, assignments)
+		if reviewer == "" {
+			continue
+		}
+		assignment := reviewAssignment{Repo: repo, PR: pr, Category: category, Reviewer: reviewer, Assigned: now()}
+		assignments = append(assignments, assignment)
+		added = append(added, assignment)
+	}
+	if len(added) > 0 {
+		if err := q.save(assignments); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var current []reviewAssignment
+	for _, assignment := range assignments {
+		if assignment.Repo == repo && assignment.PR == pr {
+			current = append(current, assignment)
+		}
+	}
+	return current, added, nil
+}
+
+// complete marks the open assignments of a PR as done: those of reviewer
+// when it submitted a review, or all of them when reviewer is "" because the
+// PR was closed. It returns how many it completed.
+func (q *reviewQueue) complete(repo string, pr int, reviewer string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	assignments, err := q.load()
+	if err != nil {
+		return 0, err
+	}
+	completed := 0
+	for i, assignment := range assignments {
+		if assignment.Repo != repo || assignment.PR != pr || assignment.Completed != nil {
+			continue
+		}
+		if reviewer != "" && !strings.EqualFold(assignment.Reviewer, reviewer) {
+			continue
+		}
+		at := now()
+		assignments[i].Completed = &at
+		completed++
+	}
+	if completed == 0 {
+		return 0, nil
+	}
+	return completed, q.save(assignments)
+}
+
+// prAssignment returns the assignment of a category of a PR, or nil
+func prAssignment(assignments []reviewAssignment, repo string, pr int, category string) *reviewAssignment {
+	for i, assignment := range assignments {
+		if assignment.Repo == repo && assignment.PR == pr && strings.EqualFold(assignment.Category, category) {
+			return &assignments[i]
+		}
+	}
+	return nil
+}
+
+// leastLoaded returns the candidate with the fewest open assignments across
+// all repositories; ties go to the one assigned least recently, then to the
+// first in the config
+func leastLoaded(candidates []string, assignments []reviewAssignment) string {
+	open := make(map[string]int)
+	last := make(map[string]time.Time)
+	for _, assignment := range assignments {
+		login := strings.ToLower(assignment.Reviewer)
+		if assignment.Completed == nil {
+			open[login]++
+		}
+		if assignment.Assigned.After(last[login]) {
+			last[login] = assignment.Assigned
+		}
+	}
+
+	best := ""
+	for _, candidate := range candidates {
+		login := strings.ToLower(candidate)
+		if best == "" {
+			best = candidate
+			continue
+		}
+		bestLogin := strings.ToLower(best)
+		if open[login] < open[bestLogin] || (open[login] == open[bestLogin] && last[login].Before(last[bestLogin])) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// challengeCategories returns the distinct categories of the linted
+// challenges, sorted
+func challengeCategories(results []LintResult) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, result := range results {
+		category := strings.TrimSpace(result.category)
+		if category == "" || seen[strings.ToLower(category)] {
+			continue
+		}
+		seen[strings.ToLower(category)] = true
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// reviewersSection renders the reviewer assignments of a PR, appended to
+// the PR comment
+func reviewersSection(assignments []reviewAssignment) string {
+	if len(assignments) == 0 {
+		return ""
+	}
+	var body strings.Builder
+	body.WriteString("\n\n### 🧑‍⚖️ Reviewers\n\n")
+	for _, assignment := range assignments {
+		status := "review requested"
+		if assignment.Completed != nil {
+			status = "✅ reviewed"
+		}
+		body.WriteString(fmt.Sprintf("- %s: @%s (%s)\n", assignment.Category, assignment.Reviewer, status))
+	}
+	return strings.TrimRight(body.String(), "\n")
+}
+
+// requestReviewers requests reviews from the newly assigned reviewers
+func requestReviewers(env Env, assignments []reviewAssignment) error {
+	seen := make(map[string]bool)
+	var reviewers []string
+	for _, assignment := range assignments {
+		if login := strings.ToLower(assignment.Reviewer); !seen[login] {
+			seen[login] = true
+			reviewers = append(reviewers, assignment.Reviewer)
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil
+	}
+	client, ctx := getGitHubClient(env.token)
+	_, _, err := client.PullRequests.RequestReviewers(ctx, env.owner, env.repo, env.prNumber, github.ReviewersRequest{Reviewers: reviewers})
+	if err != nil {
+		return fmt.Errorf("error requesting reviewers: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewQueue(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	clock := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	rule := ReviewersRule{Categories: map[string][]string{
+		"web": {"alice", "@bob"},
+		"*":   {"carol"},
+	}}
+	queue := &reviewQueue{path: filepath.Join(t.TempDir(), "reviewers.json")}
+	assign := func(pr int, author string, categories ...string) ([]reviewAssignment, []reviewAssignment) {
+		t.Helper()
+		current, added, err := queue.assign("org/ctf", pr, author, categories, rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return current, added
+	}
+	reviewers := func(assignments []reviewAssignment) string {
+		var logins []string
+		for _, assignment := range assignments {
+			logins = append(logins, assignment.Category+":"+assignment.Reviewer)
+		}
+		return strings.Join(logins, ",")
+	}
+
+	if _, added := assign(1, "dave", "Web"); reviewers(added) != "Web:alice" {
+		t.Errorf("PR 1: added %s, want Web:alice", reviewers(added))
+	}
+	if _, added := assign(2, "dave", "web", "pwn"); reviewers(added) != "web:bob,pwn:carol" {
+		t.Errorf("PR 2: added %s, want the less loaded bob and the fallback carol", reviewers(added))
+	}
+	// A PR keeps its reviewers when it is linted again
+	if current, added := assign(1, "dave", "web"); len(added) != 0 || reviewers(current) != "Web:alice" {
+		t.Errorf("PR 1 again: current %s, added %s", reviewers(current), reviewers(added))
+	}
+	// The PR author never reviews their own PR
+	if _, added := assign(3, "Alice", "web"); reviewers(added) != "web:bob" {
+		t.Errorf("PR 3 by alice: added %s, want web:bob", reviewers(added))
+	}
+
+	// alice's review of PR 1 frees her for the next PR
+	if completed, err := queue.complete("org/ctf", 1, "ALICE"); err != nil || completed != 1 {
+		t.Fatalf("complete() = %d, %v", completed, err)
+	}
+	if _, added := assign(4, "dave", "web"); reviewers(added) != "web:alice" {
+		t.Errorf("PR 4: added %s, want web:alice", reviewers(added))
+	}
+	current, _ := assign(1, "dave", "web")
+	if section := reviewersSection(current); !strings.Contains(section, "- Web: @alice (✅ reviewed)") {
+		t.Errorf("reviewersSection() = %q", section)
+	}
+
+	// Closing a PR completes all of its assignments
+	if completed, err := queue.complete("org/ctf", 2, ""); err != nil || completed != 2 {
+		t.Errorf("closing PR 2: completed %d, %v", completed, err)
+	}
+}
+
+func TestLeastLoaded(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 10, 16, hour, 0, 0, 0, time.UTC) }
+	done := at(12)
+	assignments := []reviewAssignment{
+		{Reviewer: "alice", Assigned: at(1)},
+		{Reviewer: "bob", Assigned: at(2), Completed: &done},
+		{Reviewer: "carol", Assigned: at(3), Completed: &done},
+	}
+	// bob and carol have no open reviews; bob was assigned longer ago
+	if got := leastLoaded([]string{"alice", "carol", "bob"}, assignments); got != "bob" {
+		t.Errorf("leastLoaded() = %s, want bob", got)
+	}
+	if got := leastLoaded([]string{"dave", "bob"}, assignments); got != "dave" {
+		t.Errorf("leastLoaded() = %s, want the never assigned dave", got)
+	}
+	if got := leastLoaded(nil, assignments); got != "" {
+		t.Errorf("leastLoaded() = %s, want none", got)
+	}
+}
+
+func TestHandleReviewWebhook(t *testing.T) {
+	server := newWebhookServer([]byte(testWebhookSecret), "token", t.TempDir())
+	if _, _, err := server.reviews.assign("org/ctf", 7, "dave", []string{"web"}, ReviewersRule{Categories: map[string][]string{"web": {"alice"}}}); err != nil {
+		t.Fatal(err)
+	}
+	review := `{"action":"submitted","review":{"state":"%s","user":{"login":"alice"}},"pull_request":{"number":7},"repository":{"name":"ctf","full_name":"org/ctf","owner":{"login":"org"}}}`
+
+	deliver := func(state string) string {
+		recorder := httptest.NewRecorder()
+		server.routes().ServeHTTP(recorder, webhookRequest(t, "pull_request_review", strings.Replace(review, "%s", state, 1), testWebhookSecret))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s)", recorder.Code, recorder.Body.String())
+		}
+		return strings.TrimSpace(recorder.Body.String())
+	}
+	if got := deliver("commented"); got != "ignored" {
+		t.Errorf("comment review: %s", got)
+	}
+	if got := deliver("approved"); got != "completed 1 review(s)" {
+		t.Errorf("approval: %s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import "strings"
+
+// lintRule describes a check by its rule ID
+type lintRule struct {
+	// Field is the top-level challenge.yml field the rule checks, "" when its
+	// findings cannot be attributed to a single field
+	Field string
+	// Anchor is the rule's section on the guideline page at docs_base_url
+	Anchor string
+}
+
+// lintRules lists every rule that can produce a finding, keyed by rule ID
+var lintRules = map[string]lintRule{
+	"config":             {Field: "", Anchor: "config"},
+	"read":               {Field: "", Anchor: "read"},
+	"yaml":               {Field: "", Anchor: "yaml"},
+	"yaml-aliases":       {Field: "", Anchor: "yaml-aliases"},
+	"yaml-limits":        {Field: "", Anchor: "yaml-limits"},
+	"include":            {Field: "include", Anchor: "include"},
+	"flags":              {Field: "flags", Anchor: "flags"},
+	"flag-tests":         {Field: "flag_tests", Anchor: "flag-tests"},
+	"files":              {Field: "files", Anchor: "files"},
+	"files-entries":      {Field: "files", Anchor: "files-entries"},
+	"content-type":       {Field: "files", Anchor: "content-type"},
+	"filenames":          {Field: "files", Anchor: "filenames"},
+	"value":              {Field: "value", Anchor: "value"},
+	"notes":              {Field: "notes", Anchor: "notes"},
+	"prizes":             {Field: "prizes", Anchor: "prizes"},
+	"requirements":       {Field: "requirements", Anchor: "requirements"},
+	"image":              {Field: "image", Anchor: "image"},
+	"host":               {Field: "host", Anchor: "host"},
+	"placeholders":       {Field: "", Anchor: "placeholders"},
+	"state":              {Field: "state", Anchor: "state"},
+	"schedule":           {Field: "state", Anchor: "schedule"},
+	"version":            {Field: "version", Anchor: "version"},
+	"tags":               {Field: "tags", Anchor: "tags"},
+	"type":               {Field: "type", Anchor: "type"},
+	"extra":              {Field: "extra", Anchor: "extra"},
+	"hints":              {Field: "hints", Anchor: "hints"},
+	"kubernetes":         {Field: "", Anchor: "kubernetes"},
+	"inventory":          {Field: "host", Anchor: "inventory"},
+	"host-live":          {Field: "host", Anchor: "host-live"},
+	"healthcheck":        {Field: "healthcheck", Anchor: "healthcheck"},
+	"image-size":         {Field: "image", Anchor: "image-size"},
+	"image-registry":     {Field: "image", Anchor: "image-registry"},
+	"binaries":           {Field: "files", Anchor: "binaries"},
+	"checksums":          {Field: "files", Anchor: "checksums"},
+	"lfs":                {Field: "files", Anchor: "lfs"},
+	"remote-files":       {Field: "files", Anchor: "remote-files"},
+	"external-files":     {Field: "external_files", Anchor: "external-files"},
+	"release-assets":     {Field: "files", Anchor: "release-assets"},
+	"archive-password":   {Field: "files", Anchor: "archive-password"},
+	"archive-limits":     {Field: "files", Anchor: "archive-limits"},
+	"malware":            {Field: "files", Anchor: "malware"},
+	"artifacts":          {Field: "", Anchor: "artifacts"},
+	"difficulty":         {Field: "tags", Anchor: "difficulty"},
+	"bilingual":          {Field: "description", Anchor: "bilingual"},
+	"description-files":  {Field: "description", Anchor: "description-files"},
+	"signatures":         {Field: "files", Anchor: "signatures"},
+	"deprecations":       {Field: "", Anchor: "deprecations"},
+	"template":           {Field: "", Anchor: "template"},
+	"category-directory": {Field: "category", Anchor: "category-directory"},
+	"name-slug":          {Field: "name", Anchor: "name-slug"},
+	"missing-challenge":  {Field: "", Anchor: "missing-challenge"},
+	"layout":             {Field: "", Anchor: "layout"},
+	"policy-rules":       {Field: "", Anchor: "policy-rules"},
+	"flag-collision":     {Field: "flags", Anchor: "flag-collision"},
+	"freeze":             {Field: "", Anchor: "freeze"},
+	"live":               {Field: "", Anchor: "live"},
+	"cross-repo-name":    {Field: "name", Anchor: "cross-repo-name"},
+	"cross-repo-flag":    {Field: "flags", Anchor: "cross-repo-flag"},
+}
+
+// ruleField returns the field checked by a rule, "" for unknown rules
+func ruleField(ruleID string) string {
+	return lintRules[ruleID].Field
+}
+
+// docsURL links a rule to its section of the guideline page, or returns ""
+// when no docs_base_url is configured
+func docsURL(baseURL, ruleID string) string {
+	if baseURL == "" || ruleID == "" {
+		return ""
+	}
+	anchor := ruleID
+	if rule, ok := lintRules[ruleID]; ok && rule.Anchor != "" {
+		anchor = rule.Anchor
+	}
+	return strings.TrimSuffix(baseURL, "#") + "#" + anchor
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocsURL(t *testing.T) {
+	tests := []struct {
+		base, rule, want string
+	}{
+		{"", "state", ""},
+		{"https://wiki.example.com/ctf/lint", "state", "https://wiki.example.com/ctf/lint#state"},
+		{"https://wiki.example.com/ctf/lint#", "archive-password", "https://wiki.example.com/ctf/lint#archive-password"},
+		{"https://wiki.example.com/ctf/lint", "custom", "https://wiki.example.com/ctf/lint#custom"},
+	}
+	for _, tt := range tests {
+		if got := docsURL(tt.base, tt.rule); got != tt.want {
+			t.Errorf("docsURL(%q, %q) = %q, want %q", tt.base, tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestFindingDocsLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	config := "docs_base_url: https://wiki.example.com/ctf/lint\n"
+	if err := os.WriteFile("lintrc.yaml", []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create lintrc.yaml: %v", err)
+	}
+	content := "name: chall\nstate: hidden\nversion: \"0.1\"\ntype: standard\n"
+	if err := os.WriteFile("challenge.yml", []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+
+	result := lintChallengeFile("challenge.yml")
+	if len(result.Errors) != 1 || result.Errors[0].Docs != "https://wiki.example.com/ctf/lint#state" {
+		t.Errorf("Expected docs link for state, got: %+v", result.Errors)
+	}
+
+	body := generateCommentBody([]LintResult{result}, true)
+	if !strings.Contains(body, "Field 'state' should be 'visible'") || !strings.Contains(body, "([docs](https://wiki.example.com/ctf/lint#state))") {
+		t.Errorf("Expected PR comment to link the state rule, got:\n%s", body)
+	}
+	if !strings.Contains(body, "([docs](https://wiki.example.com/ctf/lint#type))") {
+		t.Errorf("Expected PR comment to link the type warning, got:\n%s", body)
+	}
+
+	_ = os.Remove(filepath.Join(tempDir, "lintrc.yaml"))
+	if result := lintChallengeFile("challenge.yml"); result.Errors[0].Docs != "" {
+		t.Errorf("Expected no docs links without docs_base_url, got: %+v", result.Errors)
+	}
+}
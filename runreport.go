@@ -0,0 +1,145 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// runReportPath is set by --run-report; the report is only written, and rule
+// timings only collected, when it is non-empty
+var runReportPath string
+
+// RunReport describes a lint run for attaching to bug reports: the version,
+// configuration, and timings needed to reproduce it. It is written locally
+// and never sent anywhere.
+type RunReport struct {
+	Version   string   `json:"version"`
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Args      []string `json:"args"`
+	// Config is the lintrc.yaml used, by path and SHA-256 of its contents
+	Config          RunReportConfig `json:"config"`
+	Started         time.Time       `json:"started"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Challenges      int             `json:"challenges"`
+	Failed          int             `json:"failed"`
+	Errors          int             `json:"errors"`
+	Warnings        int             `json:"warnings"`
+	Deprecations    int             `json:"deprecations"`
+	Rules           []RuleTiming    `json:"rules"`
+}
+
+// RunReportConfig identifies the configuration of a run without including it
+type RunReportConfig struct {
+	// Path is the lintrc.yaml file, "inline" for --config, or "" for the defaults
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+	Policy string `json:"policy,omitempty"`
+}
+
+// RuleTiming is the time spent in one rule across all challenges
+type RuleTiming struct {
+	Rule         string  `json:"rule"`
+	Calls        int     `json:"calls"`
+	TotalSeconds float64 `json:"total_seconds"`
+	MaxSeconds   float64 `json:"max_seconds"`
+}
+
+var (
+	runReportMu     sync.Mutex
+	runReportConfig RunReportConfig
+	ruleTimings     = make(map[string]*RuleTiming)
+)
+
+// noteRunConfig records the lintrc.yaml a run loaded for the run report
+func noteRunConfig(path string, data []byte, config *LintConfig) {
+	if runReportPath == "" {
+		return
+	}
+	entry := RunReportConfig{Path: path}
+	if data != nil {
+		sum := sha256.Sum256(data)
+		entry.SHA256 = hex.EncodeToString(sum[:])
+	}
+	if config != nil {
+		entry.Policy = config.Policy.Ref
+	}
+	runReportMu.Lock()
+	runReportConfig = entry
+	runReportMu.Unlock()
+}
+
+// noteRuleTiming adds the duration of one rule on one challenge
+func noteRuleTiming(ruleID string, duration time.Duration) {
+	if runReportPath == "" {
+		return
+	}
+	runReportMu.Lock()
+	defer runReportMu.Unlock()
+	timing := ruleTimings[ruleID]
+	if timing == nil {
+		timing = &RuleTiming{Rule: ruleID}
+		ruleTimings[ruleID] = timing
+	}
+	timing.Calls++
+	timing.TotalSeconds += duration.Seconds()
+	if duration.Seconds() > timing.MaxSeconds {
+		timing.MaxSeconds = duration.Seconds()
+	}
+}
+
+// buildRunReport assembles the report of a run, slowest rules first
+func buildRunReport(results []LintResult, duration time.Duration) RunReport {
+	summary := summarizeResults(results, nil)
+	report := RunReport{
+		Version:         toolVersion(),
+		GoVersion:       runtime.Version(),
+		Platform:        runtime.GOOS + "/" + runtime.GOARCH,
+		Args:            append([]string{}, os.Args[1:]...),
+		Started:         lintStart.UTC().Truncate(time.Second),
+		DurationSeconds: duration.Seconds(),
+		Challenges:      summary.Challenges,
+		Failed:          summary.Failed,
+		Errors:          summary.Errors,
+		Warnings:        summary.Warnings,
+		Deprecations:    summary.Deprecations,
+		Rules:           []RuleTiming{},
+	}
+
+	runReportMu.Lock()
+	report.Config = runReportConfig
+	for _, timing := range ruleTimings {
+		report.Rules = append(report.Rules, *timing)
+	}
+	runReportMu.Unlock()
+	sort.Slice(report.Rules, func(i, j int) bool {
+		if report.Rules[i].TotalSeconds != report.Rules[j].TotalSeconds {
+			return report.Rules[i].TotalSeconds > report.Rules[j].TotalSeconds
+		}
+		return report.Rules[i].Rule < report.Rules[j].Rule
+	})
+	return report
+}
+
+// recordRunReport writes the --run-report, if one was given. Like the
+// metrics file it is replaced atomically.
+func recordRunReport(results []LintResult) {
+	if runReportPath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(buildRunReport(results, time.Since(lintStart)), "", "  ")
+	if err != nil {
+		log.Printf("Warning: failed to write the run report: %v", err)
+		return
+	}
+	if err := writeFileAtomic(runReportPath, string(data)+"\n"); err != nil {
+		log.Printf("Warning: failed to write the run report: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordRunReport(t *testing.T) {
+	tempDir := t.TempDir()
+	runReportPath = filepath.Join(tempDir, "report.json")
+	t.Cleanup(func() {
+		runReportPath = ""
+		runReportConfig = RunReportConfig{}
+		ruleTimings = make(map[string]*RuleTiming)
+	})
+
+	configPath := filepath.Join(tempDir, "lintrc.yaml")
+	if err := os.WriteFile(configPath, []byte("max_file_size: 1024\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origConfig := lintConfigFile
+	lintConfigFile = configPath
+	t.Cleanup(func() { lintConfigFile = origConfig })
+	if _, err := loadLintConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	noteRuleTiming("state", 10*time.Millisecond)
+	noteRuleTiming("state", 30*time.Millisecond)
+	noteRuleTiming("tags", 50*time.Millisecond)
+	result := LintResult{File: "web/a/challenge.yml", ruleTimeout: time.Second}
+	result.checkErrors("host", func() []string { return []string{"Field 'host' is required"} })
+
+	recordRunReport([]LintResult{result, {File: "pwn/b/challenge.yml", Warnings: []Finding{{Message: "w"}}}})
+
+	data, err := os.ReadFile(runReportPath)
+	if err != nil {
+		t.Fatalf("Failed to read the run report: %v", err)
+	}
+	var report RunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Run report is not valid JSON: %v\n%s", err, data)
+	}
+	if report.Version == "" || !strings.Contains(report.Platform, "/") || report.GoVersion == "" {
+		t.Errorf("missing build information: %+v", report)
+	}
+	if report.Config.Path != configPath || len(report.Config.SHA256) != 64 {
+		t.Errorf("Config = %+v, want the lintrc.yaml and its digest", report.Config)
+	}
+	if report.Challenges != 2 || report.Failed != 1 || report.Errors != 1 || report.Warnings != 1 {
+		t.Errorf("counts = %d/%d/%d/%d", report.Challenges, report.Failed, report.Errors, report.Warnings)
+	}
+	if len(report.Rules) != 3 || report.Rules[0].Rule != "tags" || report.Rules[1].Rule != "state" {
+		t.Fatalf("Rules = %+v, want slowest first", report.Rules)
+	}
+	state := report.Rules[1]
+	if state.Calls != 2 || state.MaxSeconds != 0.03 || state.TotalSeconds < 0.039 || state.TotalSeconds > 0.041 {
+		t.Errorf("state timing = %+v", state)
+	}
+	if report.Rules[2].Rule != "host" || report.Rules[2].Calls != 1 {
+		t.Errorf("runRule was not timed: %+v", report.Rules[2])
+	}
+}
+
+func TestRunReportDisabled(t *testing.T) {
+	noteRuleTiming("state", time.Second)
+	noteRunConfig("lintrc.yaml", []byte("x"), nil)
+	if len(ruleTimings) != 0 || runReportConfig.Path != "" {
+		t.Error("timings were collected without --run-report")
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// sandboxMode is set by --sandbox, and with --comment-pr for pull requests
+// from forks: content-inspecting rules then run in a restricted subprocess.
+// "clilint serve" decides per pull request instead.
+var sandboxMode bool
+
+// Defaults of the sandbox resource limits
+const (
+	defaultSandboxMemory = 512 // MiB
+	defaultSandboxCPU    = 60  // seconds
+)
+
+// errSandboxUnavailable reports that the namespaces of the sandbox cannot be created
+var errSandboxUnavailable = errors.New("sandbox isolation is unavailable")
+
+// sandboxCommand starts the sandbox child process, replaced in tests
+var sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+	return exec.CommandContext(ctx, executable, "__sandbox", root)
+}
+
+// SandboxRule configures the sandbox of content-inspecting rules
+type SandboxRule struct {
+	// Memory is the address space limit of the sandbox in MiB (default 512)
+	Memory int64 `yaml:"memory"`
+	// CPU is the CPU time limit of the sandbox in seconds (default 60)
+	CPU int64 `yaml:"cpu"`
+	// Strict fails the rule instead of running it with resource limits only
+	// when network and filesystem isolation are unavailable
+	Strict bool `yaml:"strict"`
+}
+
+func (r SandboxRule) limits() sandboxLimits {
+	limits := sandboxLimits{Memory: r.Memory, CPU: r.CPU}
+	if limits.Memory <= 0 {
+		limits.Memory = defaultSandboxMemory
+	}
+	if limits.CPU <= 0 {
+		limits.CPU = defaultSandboxCPU
+	}
+	return limits
+}
+
+// sandboxLimits are the resource limits applied by the sandbox child
+type sandboxLimits struct {
+	Memory int64 `json:"memory"`
+	CPU    int64 `json:"cpu"`
+}
+
+// sandboxRequest is sent to the sandbox child on stdin
+type sandboxRequest struct {
+	Rule      string        `json:"rule"`
+	Challenge Challenge     `json:"challenge"`
+	Config    LintConfig    `json:"config"`
+	Limits    sandboxLimits `json:"limits"`
+	// Isolated tells the child it runs in its own user, network, and mount
+	// namespaces and can chroot into the root it is given
+	Isolated bool `json:"isolated"`
+}
+
+// inspectContent runs a content-inspecting rule, in the sandbox when the
+// config's sandbox is set
+func inspectContent(ruleID, filePath string, challenge Challenge, config *LintConfig) []string {
+	if !config.sandbox {
+		return runContentRule(ruleID, filePath, challenge, config)
+	}
+	errors, err := runSandboxed(ruleID, filePath, challenge, config)
+	if err != nil {
+		return []string{fmt.Sprintf("Rule '%s' failed in the sandbox: %v", ruleID, err)}
+	}
+	return errors
+}
+
+// runContentRule runs a content-inspecting rule, one that parses
+// attachments, in this process
+func runContentRule(ruleID, filePath string, challenge Challenge, config *LintConfig) []string {
+	switch ruleID {
+	case "binaries":
+		return checkBinaries(filePath, challenge, config.Binaries)
+	case "archive-password":
+		return checkArchivePasswords(filePath, challenge, config.Archives)
+	case "archive-limits":
+		return checkArchiveLimits(filePath, challenge, config.Archives)
+	case "difficulty":
+		return checkDifficulty(filePath, challenge, config.Difficulty)
+	}
+	return []string{fmt.Sprintf("Rule '%s' cannot run in the sandbox", ruleID)}
+}
+
+// isForkEvent reports whether a webhook's PR head is in another repository
+// than its base, or in a deleted one
+func isForkEvent(event *github.PullRequestEvent) bool {
+	pr := event.GetPullRequest()
+	head := pr.GetHead().GetRepo().GetFullName()
+	return head == "" || head != pr.GetBase().GetRepo().GetFullName()
+}
+
+// isForkPR reports whether the PR's head is in another repository, whose
+// attachments come from external contributors
+func isForkPR(env Env) (bool, error) {
+	client, ctx := getGitHubClient(env.token)
+	pr, _, err := client.PullRequests.Get(ctx, env.owner, env.repo, env.prNumber)
+	if err != nil {
+		return false, fmt.Errorf("error getting PR: %v", err)
+	}
+	return pr.GetHead().GetRepo().GetFullName() != pr.GetBase().GetRepo().GetFullName(), nil
+}
+
+// runSandboxed copies the challenge directory into a temporary root and runs
+// the rule there in a child process without network access, confined to
+// that root, and with memory and CPU limits. Without namespace support the
+// child runs with the resource limits only, unless sandbox.strict is set.
+func runSandboxed(ruleID, filePath string, challenge Challenge, config *LintConfig) ([]string, error) {
+	root, err := os.MkdirTemp("", "clilint-sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(root)
+	if err := copyChallengeDir(filepath.Dir(filePath), filepath.Join(root, "challenge")); err != nil {
+		return nil, fmt.Errorf("failed to copy the challenge: %v", err)
+	}
+	// Temporary files of the rules, such as spooled nested archives
+	if err := os.Mkdir(filepath.Join(root, "tmp"), 0700); err != nil {
+		return nil, err
+	}
+
+	request := sandboxRequest{Rule: ruleID, Challenge: challenge, Config: *config, Limits: config.Sandbox.limits(), Isolated: true}
+	errors, err := runSandboxChild(request, root, config.ruleTimeout())
+	if err == errSandboxUnavailable {
+		if config.Sandbox.Strict {
+			return nil, fmt.Errorf("isolation is unavailable on this system")
+		}
+		log.Printf("Warning: sandbox isolation is unavailable; running '%s' with resource limits only", ruleID)
+		request.Isolated = false
+		errors, err = runSandboxChild(request, root, config.ruleTimeout())
+	}
+	return errors, err
+}
+
+func runSandboxChild(request sandboxRequest, root string, timeout time.Duration) ([]string, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := sandboxCommand(ctx, root)
+	cmd.Dir = root
+	// Nothing from the CI environment, such as tokens, reaches the child
+	tempDir := filepath.Join(root, "tmp")
+	if request.Isolated {
+		tempDir = "/tmp"
+	}
+	cmd.Env = []string{"PATH=/usr/bin:/bin", "HOME=/", "TMPDIR=" + tempDir}
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if request.Isolated {
+		cmd.SysProcAttr = sandboxAttr()
+		if cmd.SysProcAttr == nil {
+			return nil, errSandboxUnavailable
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		if request.Isolated {
+			return nil, errSandboxUnavailable
+		}
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("%v (crashed or exceeded the %d MiB / %d s limits)", err, request.Limits.Memory, request.Limits.CPU)
+	}
+
+	var errors []string
+	if err := json.Unmarshal(stdout.Bytes(), &errors); err != nil {
+		return nil, fmt.Errorf("invalid output: %v", err)
+	}
+	return errors, nil
+}
+
+// runSandbox is the sandbox child: it confines itself to root, applies the
+// resource limits, runs the requested rule, and writes its errors as JSON
+func runSandbox(args []string) {
+	if len(args) != 1 {
+		log.Fatalf("Usage: clilint __sandbox ROOT")
+	}
+	var request sandboxRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		log.Fatalf("Invalid sandbox request: %v", err)
+	}
+	root := args[0]
+	if err := enterSandbox(root, request.Isolated, request.Limits); err != nil {
+		log.Fatalf("Failed to enter the sandbox: %v", err)
+	}
+	if request.Isolated {
+		root = "/"
+	}
+
+	config := request.Config
+	config.baseDir = filepath.Join(root, "challenge")
+	errors := runContentRule(request.Rule, filepath.Join(root, "challenge", "challenge.yml"), request.Challenge, &config)
+	if errors == nil {
+		errors = []string{}
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(errors); err != nil {
+		log.Fatalf("Failed to write sandbox output: %v", err)
+	}
+}
+
+// copyChallengeDir copies the regular files of a challenge directory;
+// symlinks are not followed so that nothing outside it is exposed
+func copyChallengeDir(src, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		if info.IsDir() {
+			if info.Name() == ".git" && path != src {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// sandboxAttr runs the sandbox child in new user, network, mount, and IPC
+// namespaces, as root of its user namespace mapped to the current user. The
+// new network namespace has no interfaces besides a down loopback.
+func sandboxAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags:                 syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET | syscall.CLONE_NEWNS | syscall.CLONE_NEWIPC,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+}
+
+// enterSandbox chroots into root when isolated and applies the memory and
+// CPU limits to the current process
+func enterSandbox(root string, isolated bool, limits sandboxLimits) error {
+	if isolated {
+		if err := syscall.Chroot(root); err != nil {
+			return err
+		}
+		if err := syscall.Chdir("/"); err != nil {
+			return err
+		}
+	}
+	memory := uint64(limits.Memory) << 20
+	if err := syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: memory, Max: memory}); err != nil {
+		return err
+	}
+	cpu := uint64(limits.CPU)
+	return syscall.Setrlimit(syscall.RLIMIT_CPU, &syscall.Rlimit{Cur: cpu, Max: cpu})
+}
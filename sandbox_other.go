@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import "syscall"
+
+// sandboxAttr returns nil: the sandbox namespaces are only available on Linux
+func sandboxAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// enterSandbox applies no limits outside Linux
+func enterSandbox(root string, isolated bool, limits sandboxLimits) error {
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// TestSandboxHelper is the sandbox child when started by useTestSandbox
+func TestSandboxHelper(t *testing.T) {
+	args := flag.Args()
+	if len(args) != 2 || args[0] != "__sandbox" {
+		t.Skip("only runs as the sandbox child")
+	}
+	runSandbox(args[1:])
+	os.Exit(0)
+}
+
+// useTestSandbox starts this test binary as the sandbox child
+func useTestSandbox(t *testing.T) {
+	orig := sandboxCommand
+	t.Cleanup(func() { sandboxCommand = orig })
+	sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+		return exec.CommandContext(ctx, os.Args[0], "-test.run=^TestSandboxHelper$", "--", "__sandbox", root)
+	}
+}
+
+func TestInspectContentSandboxed(t *testing.T) {
+	useTestSandbox(t)
+
+	dir := t.TempDir()
+	challengePath := filepath.Join(dir, "challenge.yml")
+	if err := os.WriteFile(challengePath, []byte("name: zip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeTestZip(t, filepath.Join(dir, "dist.zip"), "zipcrypto", "infected")
+	challenge := Challenge{Name: "zip", Description: "The password is `wrong`", Files: []string{"dist.zip"}}
+
+	config := &LintConfig{}
+	want := runContentRule("archive-password", challengePath, challenge, config)
+	if len(want) == 0 {
+		t.Fatal("expected the unsandboxed rule to report the wrong password")
+	}
+	config.sandbox = true
+	got := inspectContent("archive-password", challengePath, challenge, config)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sandboxed = %v, want %v", got, want)
+	}
+}
+
+func TestSandboxChildEnvironment(t *testing.T) {
+	orig := sandboxCommand
+	defer func() { sandboxCommand = orig }()
+	t.Setenv("GITHUB_TOKEN", "secret")
+	request := sandboxRequest{Rule: "binaries", Limits: sandboxLimits{Memory: 512, CPU: 60}}
+
+	sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", "-c", `if [ -n "$GITHUB_TOKEN" ]; then echo '["leaked"]'; else echo '[]'; fi`)
+	}
+	errors, err := runSandboxChild(request, t.TempDir(), defaultRuleTimeout)
+	if err != nil || len(errors) != 0 {
+		t.Errorf("runSandboxChild() = %v, %v; the child must not see GITHUB_TOKEN", errors, err)
+	}
+
+	sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sh", "-c", "exit 3")
+	}
+	_, err = runSandboxChild(request, t.TempDir(), defaultRuleTimeout)
+	if err == nil || err.Error() != "exit status 3 (crashed or exceeded the 512 MiB / 60 s limits)" {
+		t.Errorf("err = %v", err)
+	}
+
+	sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+		return exec.CommandContext(ctx, "sleep", "10")
+	}
+	_, err = runSandboxChild(request, t.TempDir(), 50*time.Millisecond)
+	if err == nil || err.Error() != "timed out after 50ms" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestIsForkPR(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/{number}", func(w http.ResponseWriter, r *http.Request) {
+		head := "owner/repo"
+		if r.PathValue("number") == "2" {
+			head = "someone/repo"
+		}
+		fmt.Fprintf(w, `{"head": {"repo": {"full_name": %q}}, "base": {"repo": {"full_name": "owner/repo"}}}`, head)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	for number, want := range map[int]bool{1: false, 2: true} {
+		fork, err := isForkPR(Env{token: "token", owner: "owner", repo: "repo", prNumber: number})
+		if err != nil || fork != want {
+			t.Errorf("isForkPR(#%d) = %v, %v, want %v", number, fork, err, want)
+		}
+	}
+}
+
+func TestIsForkEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want bool
+	}{
+		{name: "same repository", head: "org/ctf", want: false},
+		{name: "fork", head: "someone/ctf", want: true},
+		{name: "deleted fork", head: "", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &github.PullRequestEvent{PullRequest: &github.PullRequest{
+				Head: &github.PullRequestBranch{Repo: &github.Repository{FullName: github.String(tt.head)}},
+				Base: &github.PullRequestBranch{Repo: &github.Repository{FullName: github.String("org/ctf")}},
+			}}
+			if tt.head == "" {
+				event.PullRequest.Head.Repo = nil
+			}
+			if got := isForkEvent(event); got != tt.want {
+				t.Errorf("isForkEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintChallengeFileWithSandbox(t *testing.T) {
+	useTestSandbox(t)
+	sandboxed := false
+	orig := sandboxCommand
+	sandboxCommand = func(ctx context.Context, root string) *exec.Cmd {
+		sandboxed = true
+		return orig(ctx, root)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "challenge.yml")
+	if err := os.WriteFile(path, []byte("name: zip\nfiles: [dist.zip]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeTestZip(t, filepath.Join(dir, "dist.zip"), "", "")
+
+	lintChallengeFileWith(path, false)
+	if sandboxed {
+		t.Error("content rules ran in the sandbox without sandbox")
+	}
+	lintChallengeFileWith(path, true)
+	if !sandboxed {
+		t.Error("content rules did not run in the sandbox")
+	}
+}
+
+func TestCopyChallengeDir(t *testing.T) {
+	src := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	os.WriteFile(outside, []byte("secret"), 0644)
+	os.MkdirAll(filepath.Join(src, "dist"), 0755)
+	os.WriteFile(filepath.Join(src, "dist", "chall"), []byte("ELF"), 0644)
+	if err := os.Symlink(outside, filepath.Join(src, "dist", "leak")); err != nil {
+		t.Skip("symlinks unsupported")
+	}
+
+	dest := filepath.Join(t.TempDir(), "challenge")
+	if err := copyChallengeDir(src, dest); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "dist", "chall")); err != nil || string(data) != "ELF" {
+		t.Errorf("dist/chall = %q, %v", data, err)
+	}
+	if _, err := os.Lstat(filepath.Join(dest, "dist", "leak")); !os.IsNotExist(err) {
+		t.Errorf("symlink was copied: %v", err)
+	}
+}
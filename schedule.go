@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// waveTagPrefix assigns a challenge to a wave by tag, e.g. wave-2, as an
+// alternative to the wave field
+const waveTagPrefix = "wave-"
+
+// Schedule is the event calendar read from the schedule file
+type Schedule struct {
+	Start time.Time `yaml:"start"`
+	End   time.Time `yaml:"end"`
+	// Waves maps a wave to its release time; wave 1 is released at start
+	// unless listed
+	Waves map[int]time.Time `yaml:"waves"`
+}
+
+// scheduleCache holds the parsed schedule files, keyed by path
+var scheduleCache = map[string]*Schedule{}
+
+// scheduleCacheMu guards scheduleCache, which a timed out rule may still use
+var scheduleCacheMu sync.Mutex
+
+// loadSchedule reads the schedule file of the config, or returns nil when
+// none is configured
+func loadSchedule(config *LintConfig) (*Schedule, error) {
+	if config.Schedule == "" {
+		return nil, nil
+	}
+	path := config.resolvePath(config.Schedule)
+	scheduleCacheMu.Lock()
+	cached, ok := scheduleCache[path]
+	scheduleCacheMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule file: %v", err)
+	}
+	var schedule Schedule
+	if err := yaml.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule file: %v", err)
+	}
+	if !schedule.Start.IsZero() && !schedule.End.IsZero() && !schedule.End.After(schedule.Start) {
+		return nil, fmt.Errorf("schedule file: end %s is not after start %s", formatTime(schedule.End), formatTime(schedule.Start))
+	}
+	for _, wave := range schedule.waveNumbers() {
+		at := schedule.Waves[wave]
+		if !schedule.End.IsZero() && !at.Before(schedule.End) {
+			return nil, fmt.Errorf("schedule file: wave %d is released at %s, after the event ends at %s", wave, formatTime(at), formatTime(schedule.End))
+		}
+	}
+
+	scheduleCacheMu.Lock()
+	scheduleCache[path] = &schedule
+	scheduleCacheMu.Unlock()
+	return &schedule, nil
+}
+
+// waveNumbers returns the listed waves in order
+func (s *Schedule) waveNumbers() []int {
+	var waves []int
+	for wave := range s.Waves {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+	return waves
+}
+
+// release returns the release time of a wave, and whether it is scheduled
+func (s *Schedule) release(wave int) (time.Time, bool) {
+	if at, ok := s.Waves[wave]; ok {
+		return at, true
+	}
+	if wave == 1 && !s.Start.IsZero() {
+		return s.Start, true
+	}
+	return time.Time{}, false
+}
+
+// challengeWave returns the wave a challenge is assigned to by its wave
+// field or a wave-N tag, or 0 when it has none
+func challengeWave(challenge Challenge) int {
+	if challenge.Wave > 0 {
+		return challenge.Wave
+	}
+	for _, tag := range challenge.Tags {
+		if strings.HasPrefix(tag, waveTagPrefix) {
+			if wave, err := strconv.Atoi(strings.TrimPrefix(tag, waveTagPrefix)); err == nil && wave > 0 {
+				return wave
+			}
+		}
+	}
+	return 0
+}
+
+// gatedWave returns the wave of a challenge when it is not released yet,
+// or 0 when the challenge should be visible
+func gatedWave(challenge Challenge, config *LintConfig) int {
+	schedule, err := loadSchedule(config)
+	if err != nil || schedule == nil {
+		return 0
+	}
+	wave := challengeWave(challenge)
+	if at, ok := schedule.release(wave); ok && now().Before(at) {
+		return wave
+	}
+	return 0
+}
+
+// checkSchedule checks the wave of a challenge against the schedule: it must
+// be scheduled, and the challenge must be hidden until the wave is released.
+// Released and unassigned challenges are left to checkState.
+func checkSchedule(challenge Challenge, config *LintConfig) []string {
+	var errors []string
+
+	schedule, err := loadSchedule(config)
+	if err != nil {
+		errors = append(errors, err.Error())
+		return errors
+	}
+	wave := challengeWave(challenge)
+	if schedule == nil || wave == 0 {
+		return errors
+	}
+
+	at, ok := schedule.release(wave)
+	if !ok {
+		errors = append(errors, fmt.Sprintf("Wave %d is not in the schedule", wave))
+		return errors
+	}
+	if now().Before(at) && challenge.State != "hidden" {
+		errors = append(errors, fmt.Sprintf("Field 'state' should be 'hidden' until wave %d is released at %s", wave, formatTime(at)))
+	}
+
+	return errors
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// runRelease flips the challenges of a released wave to state: visible
+func runRelease(args []string) {
+	wave := 0
+	force := false
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--wave" && i+1 < len(args) {
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				log.Fatalf("Invalid --wave (want a positive number): %s", args[i])
+			}
+			wave = n
+		} else if args[i] == "--force" {
+			force = true
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if wave == 0 {
+		log.Fatalf("Usage: clilint release --wave N [--force] [directory...]")
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	released, err := releaseWave(targetDirs, wave, force)
+	for _, path := range released {
+		fmt.Printf("🚀 %s: visible\n", path)
+	}
+	if err != nil {
+		log.Fatalf("Error releasing wave %d: %v", wave, err)
+	}
+	if len(released) == 0 {
+		fmt.Printf("No hidden challenges in wave %d\n", wave)
+	}
+}
+
+// releaseWave sets state: visible on the hidden challenges of a wave, and
+// returns the files it changed. Unless forced, the wave must be released
+// according to the schedule.
+func releaseWave(targetDirs []string, wave int, force bool) ([]string, error) {
+	config, err := loadLintConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		schedule, err := loadSchedule(config)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			return nil, fmt.Errorf("no schedule configured in lintrc.yaml; use --force to release anyway")
+		}
+		at, ok := schedule.release(wave)
+		if !ok {
+			return nil, fmt.Errorf("wave %d is not in the schedule", wave)
+		}
+		if now().Before(at) {
+			return nil, fmt.Errorf("wave %d is not released until %s; use --force to release early", wave, formatTime(at))
+		}
+	}
+
+	var released []string
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			return released, fmt.Errorf("error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				return released, fmt.Errorf("%s: %v", path, err)
+			}
+			if challengeWave(challenge) != wave || challenge.State == "visible" {
+				continue
+			}
+			if err := applyFixes(path, []Fix{{Field: "state", Value: "visible"}}); err != nil {
+				return released, fmt.Errorf("%s: %v", filepath.Clean(path), err)
+			}
+			released = append(released, path)
+		}
+	}
+	return released, nil
+}
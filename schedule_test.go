@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSchedule = `start: 2026-11-01T00:00:00Z
+end: 2026-11-02T00:00:00Z
+waves:
+  2: 2026-11-01T12:00:00Z
+`
+
+func writeSchedule(t *testing.T, content string) *LintConfig {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "schedule.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &LintConfig{Schedule: "schedule.yaml", baseDir: dir}
+}
+
+func TestCheckSchedule(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+	now = func() time.Time { return time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC) }
+
+	config := writeSchedule(t, testSchedule)
+
+	tests := []struct {
+		name       string
+		challenge  Challenge
+		wantErrors []string
+		wantGated  int
+	}{
+		{name: "no wave", challenge: Challenge{State: "visible"}},
+		{name: "wave 1 released at start", challenge: Challenge{Wave: 1, State: "visible"}},
+		{name: "wave 2 hidden", challenge: Challenge{Wave: 2, State: "hidden"}, wantGated: 2},
+		{
+			name:       "wave 2 by tag visible early",
+			challenge:  Challenge{Tags: []string{"easy", "wave-2"}, State: "visible"},
+			wantErrors: []string{"Field 'state' should be 'hidden' until wave 2 is released at 2026-11-01T12:00:00Z"},
+			wantGated:  2,
+		},
+		{name: "unscheduled wave", challenge: Challenge{Wave: 3, State: "hidden"}, wantErrors: []string{"Wave 3 is not in the schedule"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkSchedule(tt.challenge, config); !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("checkSchedule() = %v, want %v", got, tt.wantErrors)
+			}
+			if got := gatedWave(tt.challenge, config); got != tt.wantGated {
+				t.Errorf("gatedWave() = %d, want %d", got, tt.wantGated)
+			}
+		})
+	}
+
+	// Once released, the hidden challenge falls back to the visible state check
+	now = func() time.Time { return time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC) }
+	if got := gatedWave(Challenge{Wave: 2, State: "hidden"}, config); got != 0 {
+		t.Errorf("gatedWave() after release = %d, want 0", got)
+	}
+}
+
+func TestLoadScheduleInvalid(t *testing.T) {
+	config := writeSchedule(t, "start: 2026-11-01T00:00:00Z\nend: 2026-11-02T00:00:00Z\nwaves:\n  2: 2026-11-03T00:00:00Z\n")
+	_, err := loadSchedule(config)
+	if err == nil || !strings.Contains(err.Error(), "wave 2 is released at 2026-11-03T00:00:00Z, after the event ends") {
+		t.Errorf("expected a wave after the end error, got %v", err)
+	}
+}
+
+func TestReleaseWave(t *testing.T) {
+	origNow := now
+	defer func() { now = origNow }()
+
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("schedule.yaml", []byte(testSchedule), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("lintrc.yaml", []byte("schedule: schedule.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	challenges := map[string]string{
+		"web/early/challenge.yml": "name: early\ntags: [easy]\nstate: visible\n",
+		"web/late/challenge.yml":  "name: late\n# released in the second wave\nwave: 2\nstate: hidden # flipped by release\n",
+		"pwn/late/challenge.yml":  "name: late-pwn\ntags: [hard, wave-2]\nstate: hidden\n",
+	}
+	for path, content := range challenges {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = func() time.Time { return time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC) }
+	if _, err := releaseWave([]string{"."}, 2, false); err == nil || !strings.Contains(err.Error(), "not released until 2026-11-01T12:00:00Z") {
+		t.Fatalf("expected an early release error, got %v", err)
+	}
+
+	now = func() time.Time { return time.Date(2026, 11, 1, 12, 30, 0, 0, time.UTC) }
+	released, err := releaseWave([]string{"."}, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(released) != 2 {
+		t.Fatalf("expected both wave 2 challenges to be released, got %v", released)
+	}
+
+	data, _ := os.ReadFile("web/late/challenge.yml")
+	if want := "name: late\n# released in the second wave\nwave: 2\nstate: visible # flipped by release\n"; string(data) != want {
+		t.Errorf("unexpected rewrite:\n%s", data)
+	}
+	data, _ = os.ReadFile("web/early/challenge.yml")
+	if string(data) != challenges["web/early/challenge.yml"] {
+		t.Errorf("wave 1 challenge should be untouched, got:\n%s", data)
+	}
+}
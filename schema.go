@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+// reportSchemaVersion is the version of the --json report, MAJOR.MINOR.
+// Adding a field bumps MINOR; removing, renaming, or retyping a field bumps
+// MAJOR. Consumers should check MAJOR and ignore fields they do not know.
+const reportSchemaVersion = "1.2"
+
+// LintReport is the --json output of a lint run
+type LintReport struct {
+	// SchemaVersion is reportSchemaVersion
+	SchemaVersion string `json:"schema_version"`
+	// Success is false when any challenge has errors
+	Success bool `json:"success"`
+	// Results holds one entry per linted challenge.yml
+	Results []LintResult `json:"results"`
+}
+
+// reportSchemaDescriptions documents the report's fields in the JSON
+// Schema, keyed by <type>.<JSON name>
+var reportSchemaDescriptions = map[string]string{
+	"LintReport.schema_version": "MAJOR.MINOR version of this report; MAJOR changes when a field is removed, renamed, or retyped",
+	"LintReport.success":        "false when any challenge has errors",
+	"LintReport.results":        "one entry per linted challenge.yml",
+	"LintResult.File":           "path of the challenge.yml",
+	"LintResult.Errors":         "findings that fail the run",
+	"LintResult.Warnings":       "findings that do not fail the run",
+	"LintResult.Deprecations":   "fields or values that will become errors",
+	"LintResult.Name":           "challenge name",
+	"LintResult.Description":    "challenge description",
+	"LintResult.Fixes":          "changes --fix applies",
+	"LintResult.Checklist":      "review checklist items and whether they are satisfied",
+	"LintResult.Owners":         "CODEOWNERS owners of the challenge, with codeowners.enabled (since 1.1)",
+	"Finding.rule_id":           "ID of the rule, see clilint rules",
+	"Finding.severity":          "error, security, warning, or deprecation",
+	"Finding.message":           "human readable message",
+	"Finding.field":             "top-level key of challenge.yml the finding concerns",
+	"Finding.line":              "1-based line of field",
+	"Finding.column":            "1-based column of field",
+	"Finding.excerpt":           "numbered challenge.yml lines around line, with flags redacted (since 1.2)",
+	"Finding.fixable":           "whether --fix corrects the finding",
+	"Finding.docs":              "link to the rule's guideline section",
+	"Fix.Field":                 "top-level key to replace",
+	"Fix.Value":                 "replacement value as YAML text",
+	"Fix.Message":               "finding resolved by the fix",
+	"ChecklistEntry.item":       "checklist item",
+	"ChecklistEntry.checked":    "whether the item is satisfied",
+}
+
+// marshalResults renders the --json output
+func marshalResults(results []LintResult, hasErrors bool) ([]byte, error) {
+	return json.Marshal(LintReport{SchemaVersion: reportSchemaVersion, Success: !hasErrors, Results: results})
+}
+
+func runSchema(args []string) {
+	if len(args) != 1 || args[0] != "--output-format" {
+		log.Fatalf("Usage: clilint schema --output-format")
+	}
+	jsonData, err := json.MarshalIndent(reportSchema(), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal JSON schema: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
+
+// reportSchema returns the JSON Schema of the --json report, derived from
+// LintReport so that it cannot drift from the output
+func reportSchema() map[string]interface{} {
+	schema := jsonSchema(reflect.TypeOf(LintReport{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "clilint --json report"
+	schema["description"] = fmt.Sprintf("Output of clilint --json, schema version %s", reportSchemaVersion)
+	properties := schema["properties"].(map[string]interface{})
+	version := properties["schema_version"].(map[string]interface{})
+	major := strings.SplitN(reportSchemaVersion, ".", 2)[0]
+	version["pattern"] = `^` + major + `\.[0-9]+$`
+	return schema
+}
+
+// jsonSchema describes how encoding/json marshals a value of type t
+func jsonSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		// nil slices are marshaled as null
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Ptr:
+		schema := jsonSchema(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+		return schema
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			property := jsonSchema(field.Type)
+			if description, ok := reportSchemaDescriptions[t.Name()+"."+name]; ok {
+				property["description"] = description
+			}
+			properties[name] = property
+			if !strings.Contains(","+options+",", ",omitempty,") {
+				required = append(required, name)
+			}
+		}
+		// Fields added in later minor versions must not break validation
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required, "additionalProperties": true}
+	}
+	return map[string]interface{}{}
+}
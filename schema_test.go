@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validateSchema checks value against the subset of JSON Schema emitted by
+// jsonSchema. Unlike the schema itself, it rejects undocumented properties,
+// so that a field added to the report without the schema fails the test.
+func validateSchema(path string, value interface{}, schema map[string]interface{}) error {
+	types := fmt.Sprint(schema["type"])
+	kind := "null"
+	switch value.(type) {
+	case map[string]interface{}:
+		kind = "object"
+	case []interface{}:
+		kind = "array"
+	case string:
+		kind = "string"
+	case bool:
+		kind = "boolean"
+	case float64:
+		kind = "number"
+		if strings.Contains(types, "integer") && value.(float64) == float64(int64(value.(float64))) {
+			kind = "integer"
+		}
+	}
+	if !strings.Contains(types, kind) {
+		return fmt.Errorf("%s: %s is not %s", path, kind, types)
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, item := range value {
+			property, ok := properties[key].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: property %q is not in the schema", path, key)
+			}
+			if err := validateSchema(path+"."+key, item, property); err != nil {
+				return err
+			}
+		}
+		required, _ := schema["required"].([]string)
+		for _, key := range required {
+			if _, ok := value[key]; !ok {
+				return fmt.Errorf("%s: required property %q is missing", path, key)
+			}
+		}
+	case []interface{}:
+		items := schema["items"].(map[string]interface{})
+		for i, item := range value {
+			if err := validateSchema(fmt.Sprintf("%s[%d]", path, i), item, items); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestReportSchema(t *testing.T) {
+	results := []LintResult{{
+		File:         "pwn/bof/challenge.yml",
+		Name:         "bof",
+		Description:  "Overflow it",
+		Errors:       []Finding{{RuleID: "state", Severity: SeverityError, Message: "Field 'state' should be 'visible'", Field: "state", Line: 4, Column: 1, Fixable: true, Docs: "https://wiki.example.com#state"}},
+		Warnings:     []Finding{},
+		Deprecations: nil,
+		Fixes:        []Fix{{Field: "state", Value: "visible", Message: "Field 'state' should be 'visible'"}},
+		Checklist:    []ChecklistEntry{{Item: "Writeup", Checked: false}},
+	}}
+	data, err := marshalResults(results, true)
+	if err != nil {
+		t.Fatalf("marshalResults() error: %v", err)
+	}
+	var report map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to parse the report: %v", err)
+	}
+	if report["schema_version"] != reportSchemaVersion {
+		t.Errorf("schema_version = %v, want %s", report["schema_version"], reportSchemaVersion)
+	}
+
+	// Round-trip the schema as clilint schema --output-format prints it
+	schemaData, err := json.Marshal(reportSchema())
+	if err != nil {
+		t.Fatalf("Failed to marshal the schema: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(schemaData, &schema); err != nil {
+		t.Fatalf("Failed to parse the schema: %v", err)
+	}
+	fixRequired(schema)
+	if err := validateSchema("$", report, schema); err != nil {
+		t.Error(err)
+	}
+
+	// The golden reports are real runs over the sample repositories
+	goldens, _ := filepath.Glob(filepath.Join("testdata", "golden", "*", "output.json"))
+	for _, golden := range goldens {
+		data, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report interface{}
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("Failed to parse %s: %v", golden, err)
+		}
+		if err := validateSchema("$", report, schema); err != nil {
+			t.Errorf("%s: %v", golden, err)
+		}
+	}
+
+	version := schema["properties"].(map[string]interface{})["schema_version"].(map[string]interface{})
+	if version["pattern"] != `^1\.[0-9]+$` {
+		t.Errorf("schema_version pattern = %v", version["pattern"])
+	}
+}
+
+// fixRequired turns the decoded required lists back into []string
+func fixRequired(schema map[string]interface{}) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		keys := make([]string, len(required))
+		for i, key := range required {
+			keys[i] = key.(string)
+		}
+		schema["required"] = keys
+	}
+	for _, key := range []string{"properties", "items"} {
+		switch child := schema[key].(type) {
+		case map[string]interface{}:
+			if key == "items" {
+				fixRequired(child)
+				continue
+			}
+			for _, property := range child {
+				fixRequired(property.(map[string]interface{}))
+			}
+		}
+	}
+}
+
+func TestReportSchemaRequired(t *testing.T) {
+	schema := reportSchema()
+	finding := schema["properties"].(map[string]interface{})["results"].(map[string]interface{})["items"].(map[string]interface{})["properties"].(map[string]interface{})["Errors"].(map[string]interface{})["items"].(map[string]interface{})
+	required := fmt.Sprint(finding["required"])
+	if required != "[rule_id severity message]" {
+		t.Errorf("Finding required = %s, want the fields without omitempty", required)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// defaultServeAddr is the address "clilint serve" listens on unless --addr is given
+const defaultServeAddr = ":8080"
+
+// defaultServeWorkDir holds the pull request checkouts of "clilint serve"
+const defaultServeWorkDir = ".clilint-serve"
+
+// webhookServer receives GitHub webhook deliveries and lints pull requests
+type webhookServer struct {
+	secret  []byte
+	token   string
+	workDir string
+	// lintToken authenticates POST /lint requests; the webhook secret is
+	// used when it is empty
+	lintToken string
+	// configFile is the lintrc.yaml applied to POST /lint submissions
+	configFile string
+
+	// lintPullRequest lints a pull request and posts the comment; replaced in tests
+	lintPullRequest func(event *github.PullRequestEvent) error
+
+	// reviews routes challenge PRs to the reviewers of their categories
+	reviews *reviewQueue
+
+	// mu serializes checkouts and lint runs, which change the working directory
+	mu sync.Mutex
+}
+
+func runServe(args []string) {
+	addr := defaultServeAddr
+	workDir := defaultServeWorkDir
+	configFile := "lintrc.yaml"
+	for i := 0; i < len(args); i++ {
+		if value, ok := flagValue(args, &i, "--addr"); ok {
+			addr = value
+		} else if value, ok := flagValue(args, &i, "--work-dir"); ok {
+			workDir = value
+		} else if value, ok := flagValue(args, &i, "--config"); ok {
+			configFile = value
+		}
+	}
+
+	secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	token := os.Getenv("GITHUB_TOKEN")
+	if secret == "" {
+		log.Printf("Warning: GITHUB_WEBHOOK_SECRET is not set, /webhook is disabled")
+	} else if token == "" {
+		log.Fatalf("GITHUB_TOKEN environment variable is required to comment on pull requests")
+	}
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		log.Fatalf("Invalid --work-dir: %v", err)
+	}
+
+	server := newWebhookServer([]byte(secret), token, absWorkDir)
+	server.lintToken = os.Getenv("CLILINT_LINT_TOKEN")
+	if server.lintToken == "" && secret == "" {
+		log.Printf("Warning: neither CLILINT_LINT_TOKEN nor GITHUB_WEBHOOK_SECRET is set, /lint is disabled")
+	}
+	if _, err := os.Stat(configFile); err == nil {
+		if server.configFile, err = filepath.Abs(configFile); err != nil {
+			log.Fatalf("Invalid --config: %v", err)
+		}
+	} else if configFile != "lintrc.yaml" {
+		log.Fatalf("Error reading --config: %v", err)
+	}
+	log.Printf("Listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, server.routes()))
+}
+
+func newWebhookServer(secret []byte, token, workDir string) *webhookServer {
+	server := &webhookServer{secret: secret, token: token, workDir: workDir}
+	server.reviews = &reviewQueue{path: filepath.Join(workDir, "reviewers.json")}
+	server.lintPullRequest = server.lintAndComment
+	return server
+}
+
+func (s *webhookServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	// Without a secret the signature of deliveries cannot be verified
+	if len(s.secret) > 0 {
+		mux.HandleFunc("POST /webhook", s.handleWebhook)
+	}
+	// Submissions are only linted for callers holding a credential
+	if s.lintCredential() != "" {
+		mux.HandleFunc("POST /lint", s.handleLint)
+	}
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// handleWebhook verifies the delivery's signature and starts linting pull
+// requests that were opened or received new commits. The lint runs in the
+// background since GitHub expects a response within seconds.
+func (s *webhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := github.ValidatePayload(r, s.secret)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	switch e := event.(type) {
+	case *github.PingEvent:
+		fmt.Fprintln(w, "pong")
+	case *github.PullRequestEvent:
+		switch e.GetAction() {
+		case "opened", "synchronize", "reopened":
+			go func() {
+				if err := s.lintPullRequest(e); err != nil {
+					log.Printf("Warning: linting %s#%d failed: %v", e.GetRepo().GetFullName(), e.GetNumber(), err)
+				}
+			}()
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintln(w, "accepted")
+		case "closed":
+			// A closed PR no longer counts towards its reviewers' load
+			s.completeReviews(w, e.GetRepo().GetFullName(), e.GetNumber(), "")
+		default:
+			fmt.Fprintln(w, "ignored")
+		}
+	case *github.PullRequestReviewEvent:
+		// Comments alone do not complete a review
+		state := strings.ToLower(e.GetReview().GetState())
+		if e.GetAction() != "submitted" || (state != "approved" && state != "changes_requested") {
+			fmt.Fprintln(w, "ignored")
+			return
+		}
+		s.completeReviews(w, e.GetRepo().GetFullName(), e.GetPullRequest().GetNumber(), e.GetReview().GetUser().GetLogin())
+	default:
+		fmt.Fprintln(w, "ignored")
+	}
+}
+
+// lintAndComment checks out the pull request head, lints its changed
+// directories, and posts the result as the usual PR comment
+func (s *webhookServer) lintAndComment(event *github.PullRequestEvent) error {
+	env := Env{
+		token:     s.token,
+		owner:     event.GetRepo().GetOwner().GetLogin(),
+		repo:      event.GetRepo().GetName(),
+		prNumber:  event.GetNumber(),
+		commentPR: true,
+	}
+
+	prFiles, err := listPRFiles(env)
+	if err != nil {
+		return err
+	}
+
+	// Changed directories are read from the checkout, which is only
+	// consistent while the lock is held. The PR is linted with the server's
+	// config: its own lintrc.yaml could disable rules or name server files.
+	s.mu.Lock()
+	dir := filepath.Join(s.workDir, env.owner, env.repo, fmt.Sprintf("pr-%d", env.prNumber))
+	ref := fmt.Sprintf("refs/pull/%d/head", env.prNumber)
+	err = checkoutRef(dir, event.GetRepo().GetCloneURL(), ref, s.token)
+	var changedDirs []string
+	var results []LintResult
+	var config *LintConfig
+	if err == nil {
+		changedDirs = changedDirectoriesIn(dir, prFiles)
+	}
+	if err == nil && len(changedDirs) > 0 {
+		err = s.withServerConfig(func() error {
+			var configErr error
+			if config, configErr = loadLintConfig(); configErr != nil {
+				log.Printf("Warning: failed to load config: %v", configErr)
+			}
+			// Attachments of fork PRs come from external contributors
+			var err error
+			results, err = lintDirectoriesIn(dir, changedDirs, sandboxMode || isForkEvent(event))
+			return err
+		})
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(changedDirs) == 0 {
+		return postNoChangesComment(env)
+	}
+
+	sortResults(results)
+	body := prCommentBody(results, hasLintErrors(results), env, config)
+	section, err := s.routeReviewers(env, event, results)
+	if err != nil {
+		log.Printf("Warning: routing %s#%d to reviewers failed: %v", event.GetRepo().GetFullName(), env.prNumber, err)
+	}
+	return createComment(env, body+section)
+}
+
+// routeReviewers assigns the categories of a PR's challenges to reviewers
+// per the reviewers of the --config lintrc.yaml, requests their reviews, and
+// returns the assignments for the PR comment
+func (s *webhookServer) routeReviewers(env Env, event *github.PullRequestEvent, results []LintResult) (string, error) {
+	if s.configFile == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(s.configFile)
+	if err != nil {
+		return "", err
+	}
+	config, err := parseLintConfig(data, filepath.Dir(s.configFile))
+	if err != nil {
+		return "", err
+	}
+	if len(config.Reviewers.Categories) == 0 {
+		return "", nil
+	}
+
+	repo := event.GetRepo().GetFullName()
+	assignments, added, err := s.reviews.assign(repo, env.prNumber, event.GetPullRequest().GetUser().GetLogin(), challengeCategories(results), config.Reviewers)
+	if err != nil {
+		return "", err
+	}
+	if err := requestReviewers(env, added); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return reviewersSection(assignments), nil
+}
+
+// completeReviews marks review assignments of a PR as done
+func (s *webhookServer) completeReviews(w http.ResponseWriter, repo string, pr int, reviewer string) {
+	completed, err := s.reviews.complete(repo, pr, reviewer)
+	if err != nil {
+		log.Printf("Warning: updating the review queue for %s#%d failed: %v", repo, pr, err)
+		http.Error(w, "review queue unavailable", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(w, "completed %d review(s)\n", completed)
+}
+
+// lintDirectoriesIn lints dirs relative to root with root's lintrc.yaml
+func lintDirectoriesIn(root string, dirs []string, sandbox bool) ([]LintResult, error) {
+	var results []LintResult
+	err := inDirectory(root, func() error {
+		for _, dir := range dirs {
+			if _, err := os.Stat(dir); os.IsNotExist(err) {
+				// The directory was deleted by the pull request
+				continue
+			}
+			dirResults, err := lintChallengesWith(dir, sandbox)
+			if err != nil {
+				return fmt.Errorf("error linting directory %s: %v", dir, err)
+			}
+			results = append(results, dirResults...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// withServerConfig runs fn with the --config lintrc.yaml, or the defaults
+// without one, as the config of every lint, never a lintrc.yaml of the
+// linted content. The caller holds s.mu.
+func (s *webhookServer) withServerConfig(fn func() error) error {
+	lintConfigFile, pinnedLintConfig = s.configFile, true
+	defer func() {
+		lintConfigFile, pinnedLintConfig = "", false
+	}()
+	return fn()
+}
+
+// inDirectory runs fn with root as the working directory
+func inDirectory(root string, fn func() error) error {
+	origDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	return fn()
+}
+
+// checkoutRef fetches a single ref of a repository into dir, reusing an
+// earlier checkout, and authenticates with token for private repositories
+func checkoutRef(dir, url, ref, token string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if output, err := exec.Command("git", "-C", dir, "init", "--quiet").CombinedOutput(); err != nil {
+			return fmt.Errorf("git init failed: %v\n%s", err, output)
+		}
+	}
+
+	auth := "AUTHORIZATION: basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:"+token))
+	fetch := exec.Command("git", "-C", dir, "-c", "http.extraHeader="+auth, "fetch", "--depth", "1", url, ref)
+	if output, err := fetch.CombinedOutput(); err != nil {
+		return fmt.Errorf("git fetch failed: %v\n%s", err, output)
+	}
+	if output, err := exec.Command("git", "-C", dir, "checkout", "--force", "--quiet", "FETCH_HEAD").CombinedOutput(); err != nil {
+		return fmt.Errorf("git checkout failed: %v\n%s", err, output)
+	}
+	// Files of the previous checkout that the new head does not have
+	if output, err := exec.Command("git", "-C", dir, "clean", "-ffdxq").CombinedOutput(); err != nil {
+		return fmt.Errorf("git clean failed: %v\n%s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+)
+
+const testWebhookSecret = "s3cret"
+
+func webhookRequest(t *testing.T, eventType, body, secret string) *http.Request {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", eventType)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestHandleWebhook(t *testing.T) {
+	pullRequest := `{"action":"%s","number":7,"repository":{"name":"ctf","full_name":"org/ctf","owner":{"login":"org"}}}`
+
+	tests := []struct {
+		name       string
+		eventType  string
+		body       string
+		secret     string
+		wantStatus int
+		wantLint   bool
+	}{
+		{name: "opened", eventType: "pull_request", body: strings.Replace(pullRequest, "%s", "opened", 1), secret: testWebhookSecret, wantStatus: http.StatusAccepted, wantLint: true},
+		{name: "synchronize", eventType: "pull_request", body: strings.Replace(pullRequest, "%s", "synchronize", 1), secret: testWebhookSecret, wantStatus: http.StatusAccepted, wantLint: true},
+		{name: "closed is not linted", eventType: "pull_request", body: strings.Replace(pullRequest, "%s", "closed", 1), secret: testWebhookSecret, wantStatus: http.StatusOK},
+		{name: "ping", eventType: "ping", body: `{"zen":"hi"}`, secret: testWebhookSecret, wantStatus: http.StatusOK},
+		{name: "other events are ignored", eventType: "push", body: `{}`, secret: testWebhookSecret, wantStatus: http.StatusOK},
+		{name: "bad signature", eventType: "pull_request", body: strings.Replace(pullRequest, "%s", "opened", 1), secret: "wrong", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linted := make(chan *github.PullRequestEvent, 1)
+			server := newWebhookServer([]byte(testWebhookSecret), "token", t.TempDir())
+			server.lintPullRequest = func(event *github.PullRequestEvent) error {
+				linted <- event
+				return nil
+			}
+
+			recorder := httptest.NewRecorder()
+			server.routes().ServeHTTP(recorder, webhookRequest(t, tt.eventType, tt.body, tt.secret))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			select {
+			case event := <-linted:
+				if !tt.wantLint {
+					t.Fatal("pull request linted unexpectedly")
+				}
+				if event.GetNumber() != 7 || event.GetRepo().GetOwner().GetLogin() != "org" {
+					t.Errorf("unexpected event: %+v", event)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantLint {
+					t.Fatal("pull request was not linted")
+				}
+			}
+		})
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	server := newWebhookServer([]byte(testWebhookSecret), "token", t.TempDir())
+	recorder := httptest.NewRecorder()
+	server.routes().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("status = %d", recorder.Code)
+	}
+}
+
+func TestCheckoutRefAndLint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tempDir := t.TempDir()
+	source := filepath.Join(tempDir, "source")
+	writeRepoChallenge(t, filepath.Join(source, "web", "chall"), "name: chall\n")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", source, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	dir := filepath.Join(tempDir, "work", "pr-1")
+	if err := checkoutRef(dir, source, "refs/heads/main", "token"); err != nil {
+		t.Fatalf("checkoutRef() failed: %v", err)
+	}
+
+	// A second delivery updates the checkout and drops deleted files
+	writeRepoChallenge(t, filepath.Join(source, "pwn", "chall"), "name: pwn\n")
+	git("rm", "-q", "-r", "web")
+	git("add", "-A")
+	git("commit", "-q", "-m", "second")
+	if err := checkoutRef(dir, source, "refs/heads/main", "token"); err != nil {
+		t.Fatalf("checkoutRef() update failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "web")); !os.IsNotExist(err) {
+		t.Errorf("expected web/ to be removed from the checkout: %v", err)
+	}
+
+	results, err := lintDirectoriesIn(dir, []string{"pwn/chall", "web/chall"}, false)
+	if err != nil {
+		t.Fatalf("lintDirectoriesIn() failed: %v", err)
+	}
+	if len(results) != 1 || results[0].File != filepath.Join("pwn", "chall", "challenge.yml") {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	// Changed directories are resolved in the checkout, not the working directory
+	prFiles := []*github.CommitFile{{Filename: github.String("pwn/chall/public/notes.txt"), Status: github.String("modified")}}
+	if got := changedDirectoriesIn(dir, prFiles); len(got) != 1 || got[0] != filepath.Join("pwn", "chall") {
+		t.Errorf("changedDirectoriesIn() = %v", got)
+	}
+	if got := changedDirectories(prFiles); len(got) != 0 {
+		t.Errorf("changedDirectories() outside the checkout = %v", got)
+	}
+}
+
+func TestWithServerConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lintrc.yaml"), []byte("docs_base_url: https://pr.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	serverConfig := filepath.Join(t.TempDir(), "lintrc.yaml")
+	if err := os.WriteFile(serverConfig, []byte("docs_base_url: https://server.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The checkout's lintrc.yaml never applies: the server's does, or the defaults
+	for configFile, want := range map[string]string{serverConfig: "https://server.example.com", "": ""} {
+		server := newWebhookServer(nil, "", t.TempDir())
+		server.configFile = configFile
+		var config *LintConfig
+		err := server.withServerConfig(func() error {
+			return inDirectory(root, func() error {
+				var err error
+				config, err = loadLintConfig()
+				return err
+			})
+		})
+		if err != nil {
+			t.Fatalf("loadLintConfig() failed: %v", err)
+		}
+		if config.DocsBaseURL != want {
+			t.Errorf("config %q: DocsBaseURL = %q, want %q", configFile, config.DocsBaseURL, want)
+		}
+	}
+	if lintConfigFile != "" || pinnedLintConfig {
+		t.Error("config lookup not restored")
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldSelector matches challenges by a top-level field, like the field and
+// value of a deprecation
+type fieldSelector struct {
+	field  string
+	value  string
+	negate bool
+}
+
+// parseSelector parses field==value or field!=value
+func parseSelector(expr string) (fieldSelector, error) {
+	for _, op := range []string{"!=", "=="} {
+		if field, value, ok := strings.Cut(expr, op); ok {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				break
+			}
+			return fieldSelector{field: field, value: strings.TrimSpace(value), negate: op == "!="}, nil
+		}
+	}
+	return fieldSelector{}, fmt.Errorf("invalid filter '%s' (want field==value or field!=value)", expr)
+}
+
+// matches reports whether the raw challenge selects the field value; a
+// sequence such as tags matches when any of its items does
+func (s fieldSelector) matches(raw map[string]interface{}) bool {
+	matched := false
+	switch value := raw[s.field].(type) {
+	case nil:
+		matched = s.value == "" || s.value == "null"
+	case []interface{}:
+		for _, item := range value {
+			if fmt.Sprint(item) == s.value {
+				matched = true
+				break
+			}
+		}
+	default:
+		matched = fmt.Sprint(value) == s.value
+	}
+	return matched != s.negate
+}
+
+// parseAssignment parses field=value, where value is a YAML scalar
+func parseAssignment(expr string) (Fix, error) {
+	field, value, ok := strings.Cut(expr, "=")
+	field = strings.TrimSpace(field)
+	if !ok || field == "" {
+		return Fix{}, fmt.Errorf("invalid assignment '%s' (want field=value)", expr)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &node); err != nil {
+		return Fix{}, fmt.Errorf("invalid value in '%s': %v", expr, err)
+	}
+	if len(node.Content) > 0 && node.Content[0].Kind != yaml.ScalarNode {
+		return Fix{}, fmt.Errorf("value of '%s' must be a scalar", field)
+	}
+	if value == "" {
+		value = `""`
+	}
+	return Fix{Field: field, Value: value}, nil
+}
+
+func runSet(args []string) {
+	dryRun := false
+	var selectors []fieldSelector
+	var assignments []Fix
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--filter" && i+1 < len(args):
+			i++
+			selector, err := parseSelector(args[i])
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			selectors = append(selectors, selector)
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.Contains(arg, "="):
+			assignment, err := parseAssignment(arg)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			assignments = append(assignments, assignment)
+		default:
+			targetDirs = append(targetDirs, arg)
+		}
+	}
+	if len(assignments) == 0 {
+		log.Fatalf("Usage: clilint set [--filter field==value]... [--dry-run] field=value... [directory...]")
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	changed, err := setFields(targetDirs, selectors, assignments, dryRun)
+	for _, path := range changed {
+		if dryRun {
+			fmt.Printf("📝 %s (dry run)\n", path)
+		} else {
+			fmt.Printf("📝 %s\n", path)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	fmt.Printf("%d challenge(s) changed\n", len(changed))
+}
+
+// setFields applies the assignments to every challenge matching all
+// selectors, and returns the files whose content changed
+func setFields(targetDirs []string, selectors []fieldSelector, assignments []Fix, dryRun bool) ([]string, error) {
+	config, err := loadLintConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			return changed, fmt.Errorf("error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			data, err := readYAMLFile(path, config.YAMLLimits)
+			if err != nil {
+				return changed, err
+			}
+			effective, err := resolveChallengeData(data, config)
+			if err != nil {
+				return changed, fmt.Errorf("%s: %v", path, err)
+			}
+			var raw map[string]interface{}
+			if err := yaml.Unmarshal(effective, &raw); err != nil {
+				return changed, fmt.Errorf("%s: invalid YAML format: %v", path, err)
+			}
+
+			selected := true
+			for _, selector := range selectors {
+				if !selector.matches(raw) {
+					selected = false
+					break
+				}
+			}
+			if !selected {
+				continue
+			}
+
+			updated := data
+			for _, assignment := range assignments {
+				updated, err = setTopLevelScalar(updated, assignment.Field, assignment.Value)
+				if err != nil {
+					return changed, fmt.Errorf("%s: failed to set '%s': %v", path, assignment.Field, err)
+				}
+			}
+			if string(updated) == string(data) {
+				continue
+			}
+			if !dryRun {
+				if err := os.WriteFile(path, updated, 0644); err != nil {
+					return changed, err
+				}
+			}
+			changed = append(changed, path)
+		}
+	}
+	return changed, nil
+}
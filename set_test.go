@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFieldSelector(t *testing.T) {
+	raw := map[string]interface{}{"category": "web", "value": 500, "tags": []interface{}{"easy", "wave-2"}, "image": nil}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"category==web", true},
+		{"category == pwn", false},
+		{"category!=pwn", true},
+		{"value==500", true},
+		{"tags==easy", true},
+		{"tags!=hard", true},
+		{"image==null", true},
+		{"author==", true},
+	}
+	for _, tt := range tests {
+		selector, err := parseSelector(tt.expr)
+		if err != nil {
+			t.Fatalf("parseSelector(%q): %v", tt.expr, err)
+		}
+		if got := selector.matches(raw); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"category=web", "==web"} {
+		if _, err := parseSelector(expr); err == nil {
+			t.Errorf("parseSelector(%q) should fail", expr)
+		}
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Fix
+		wantErr bool
+	}{
+		{expr: "state=hidden", want: Fix{Field: "state", Value: "hidden"}},
+		{expr: `version="0.1"`, want: Fix{Field: "version", Value: `"0.1"`}},
+		{expr: "author=", want: Fix{Field: "author", Value: `""`}},
+		{expr: "tags=[a, b]", wantErr: true},
+		{expr: "=hidden", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAssignment(tt.expr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAssignment(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseAssignment(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatal(err)
+	}
+
+	challenges := map[string]string{
+		filepath.Join("web", "login", "challenge.yml"): "name: login\ncategory: web # reviewed\nstate: visible # shown at start\ntags:\n  - easy\n",
+		filepath.Join("web", "admin", "challenge.yml"): "name: admin\ncategory: web\nstate: hidden\ntags: [hard]\n",
+		filepath.Join("pwn", "bof", "challenge.yml"):   "name: bof\ncategory: pwn\nstate: visible\ntags: [easy]\n",
+	}
+	for path, content := range challenges {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	selectors := []fieldSelector{{field: "category", value: "web"}, {field: "tags", value: "easy"}}
+	assignments := []Fix{{Field: "state", Value: "hidden"}, {Field: "author", Value: "alice"}}
+
+	changed, err := setFields([]string{"."}, selectors, assignments, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := filepath.Join("web", "login", "challenge.yml")
+	if !reflect.DeepEqual(changed, []string{login}) {
+		t.Fatalf("changed = %v, want [%s]", changed, login)
+	}
+	if data, _ := os.ReadFile(login); string(data) != challenges[login] {
+		t.Errorf("dry run should not write, got:\n%s", data)
+	}
+
+	if _, err := setFields([]string{"."}, selectors, assignments, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "name: login\ncategory: web # reviewed\nstate: hidden # shown at start\ntags:\n  - easy\nauthor: alice\n"
+	if data, _ := os.ReadFile(login); string(data) != want {
+		t.Errorf("unexpected rewrite:\n%s", data)
+	}
+	for path, content := range challenges {
+		if path == login {
+			continue
+		}
+		if data, _ := os.ReadFile(path); string(data) != content {
+			t.Errorf("%s should be untouched, got:\n%s", path, data)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// SignaturesRule requires a detached signature next to every attachment
+type SignaturesRule struct {
+	Enabled bool `yaml:"enabled"`
+	// PublicKeys are minisign public keys ("RWQ..."), OpenSSH Ed25519 keys
+	// ("ssh-ed25519 AAAA..."), or files holding one, relative to lintrc.yaml
+	PublicKeys []string `yaml:"public_keys"`
+	// Namespace of OpenSSH signatures, as passed to ssh-keygen -Y sign -n (default: file)
+	Namespace string `yaml:"namespace"`
+}
+
+// signatureKeys holds the parsed public keys of a SignaturesRule
+type signatureKeys struct {
+	// minisign maps key IDs to keys
+	minisign map[[8]byte]ed25519.PublicKey
+	ssh      []ed25519.PublicKey
+}
+
+// checkSignatures verifies the .minisig or .sig file next to each local
+// files entry against the configured public keys
+func checkSignatures(challengePath string, files []string, rule SignaturesRule, config *LintConfig) []string {
+	var errors []string
+	if !rule.Enabled {
+		return errors
+	}
+
+	keys, err := loadSignatureKeys(rule.PublicKeys, config)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Invalid public key in signatures.public_keys: %v", err))
+		return errors
+	}
+	namespace := rule.Namespace
+	if namespace == "" {
+		namespace = "file"
+	}
+
+	baseDir := filepath.Dir(challengePath)
+	for _, file := range files {
+		if isRemoteFile(file) || strings.HasSuffix(file, ".minisig") || strings.HasSuffix(file, ".sig") {
+			continue
+		}
+		fullPath := filepath.Join(baseDir, normalizeFilePath(file))
+		if _, err := os.Stat(fullPath); err != nil {
+			// Reported by checkFiles
+			continue
+		}
+
+		var verifyErr error
+		if signature, err := os.ReadFile(fullPath + ".minisig"); err == nil {
+			verifyErr = verifyMinisign(fullPath, signature, keys)
+		} else if signature, err := os.ReadFile(fullPath + ".sig"); err == nil {
+			verifyErr = verifySSHSignature(fullPath, signature, namespace, keys)
+		} else {
+			errors = append(errors, fmt.Sprintf("File '%s' has no detached signature (%s.minisig or %s.sig)", file, file, file))
+			continue
+		}
+		if verifyErr != nil {
+			errors = append(errors, fmt.Sprintf("Signature of '%s' does not verify: %v", file, verifyErr))
+		}
+	}
+
+	return errors
+}
+
+// loadSignatureKeys parses the configured public keys, reading entries that
+// are not keys themselves as files
+func loadSignatureKeys(entries []string, config *LintConfig) (signatureKeys, error) {
+	keys := signatureKeys{minisign: make(map[[8]byte]ed25519.PublicKey)}
+	for _, entry := range entries {
+		text := strings.TrimSpace(entry)
+		if !isPublicKey(text) {
+			data, err := os.ReadFile(config.resolvePath(entry))
+			if err != nil {
+				return keys, err
+			}
+			text = lastLine(string(data))
+		}
+
+		if strings.HasPrefix(text, "ssh-ed25519 ") {
+			key, err := parseSSHPublicKey(text)
+			if err != nil {
+				return keys, err
+			}
+			keys.ssh = append(keys.ssh, key)
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(text)
+		if err != nil || len(raw) != 42 || string(raw[:2]) != "Ed" {
+			return keys, fmt.Errorf("not a minisign or ssh-ed25519 public key: %s", entry)
+		}
+		var id [8]byte
+		copy(id[:], raw[2:10])
+		keys.minisign[id] = ed25519.PublicKey(raw[10:])
+	}
+	return keys, nil
+}
+
+// isPublicKey reports whether a public_keys entry is a key rather than a path
+func isPublicKey(entry string) bool {
+	if strings.HasPrefix(entry, "ssh-ed25519 ") {
+		return true
+	}
+	raw, err := base64.StdEncoding.DecodeString(entry)
+	return err == nil && len(raw) == 42
+}
+
+// lastLine returns the last non-empty line, skipping minisign's comment line
+func lastLine(data string) string {
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// verifyMinisign checks a minisign signature: the file signature, prehashed
+// with BLAKE2b-512 ("ED") or not (legacy "Ed"), and the global signature
+// over the trusted comment
+func verifyMinisign(path string, signature []byte, keys signatureKeys) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("malformed minisign signature")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 74 {
+		return fmt.Errorf("malformed minisign signature")
+	}
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSignature) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed minisign signature")
+	}
+
+	var id [8]byte
+	copy(id[:], raw[2:10])
+	key, ok := keys.minisign[id]
+	if !ok {
+		// minisign prints key IDs as little-endian hex
+		return fmt.Errorf("signed with unknown key %016X", binary.LittleEndian.Uint64(id[:]))
+	}
+
+	var message []byte
+	switch string(raw[:2]) {
+	case "ED":
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		digest, _ := blake2b.New512(nil)
+		_, err = io.Copy(digest, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		message = digest.Sum(nil)
+	case "Ed":
+		if message, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported minisign algorithm %q", raw[:2])
+	}
+
+	fileSignature := raw[10:]
+	if !ed25519.Verify(key, message, fileSignature) {
+		return fmt.Errorf("invalid signature")
+	}
+	trustedComment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	if !ed25519.Verify(key, append(append([]byte{}, fileSignature...), trustedComment...), globalSignature) {
+		return fmt.Errorf("invalid signature of the trusted comment")
+	}
+	return nil
+}
+
+// verifySSHSignature checks an armored OpenSSH signature (ssh-keygen -Y sign)
+// made with an Ed25519 key
+func verifySSHSignature(path string, signature []byte, namespace string, keys signatureKeys) error {
+	armored := strings.TrimSpace(string(signature))
+	if !strings.HasPrefix(armored, "-----BEGIN SSH SIGNATURE-----") || !strings.HasSuffix(armored, "-----END SSH SIGNATURE-----") {
+		return fmt.Errorf("malformed SSH signature")
+	}
+	armored = strings.TrimPrefix(armored, "-----BEGIN SSH SIGNATURE-----")
+	armored = strings.TrimSuffix(armored, "-----END SSH SIGNATURE-----")
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(armored), ""))
+	if err != nil || !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		return fmt.Errorf("malformed SSH signature")
+	}
+
+	reader := sshReader{data: blob[len("SSHSIG"):]}
+	version := reader.uint32()
+	publicKey := reader.string()
+	signedNamespace := reader.string()
+	reserved := reader.string()
+	hashAlgorithm := reader.string()
+	signatureBlob := reader.string()
+	if reader.err != nil || version != 1 {
+		return fmt.Errorf("malformed SSH signature")
+	}
+	if string(signedNamespace) != namespace {
+		return fmt.Errorf("signed for namespace %q, expected %q", signedNamespace, namespace)
+	}
+
+	key, err := sshEd25519Key(publicKey)
+	if err != nil {
+		return err
+	}
+	trusted := false
+	for _, candidate := range keys.ssh {
+		if candidate.Equal(key) {
+			trusted = true
+		}
+	}
+	if !trusted {
+		return fmt.Errorf("signed with an unknown key")
+	}
+
+	sigReader := sshReader{data: signatureBlob}
+	algorithm := sigReader.string()
+	rawSignature := sigReader.string()
+	if sigReader.err != nil || string(algorithm) != "ssh-ed25519" {
+		return fmt.Errorf("unsupported SSH signature algorithm %q", algorithm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var digest []byte
+	switch string(hashAlgorithm) {
+	case "sha512":
+		sum := sha512.Sum512(data)
+		digest = sum[:]
+	case "sha256":
+		sum := sha256.Sum256(data)
+		digest = sum[:]
+	default:
+		return fmt.Errorf("unsupported SSH signature hash %q", hashAlgorithm)
+	}
+
+	var message bytes.Buffer
+	message.WriteString("SSHSIG")
+	for _, field := range [][]byte{signedNamespace, reserved, hashAlgorithm, digest} {
+		writeSSHString(&message, field)
+	}
+	if !ed25519.Verify(key, message.Bytes(), rawSignature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// parseSSHPublicKey parses an authorized_keys style ssh-ed25519 line
+func parseSSHPublicKey(line string) (ed25519.PublicKey, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("malformed ssh-ed25519 key: %s", line)
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed ssh-ed25519 key: %s", line)
+	}
+	return sshEd25519Key(blob)
+}
+
+// sshEd25519Key decodes an SSH wire-format Ed25519 public key
+func sshEd25519Key(blob []byte) (ed25519.PublicKey, error) {
+	reader := sshReader{data: blob}
+	algorithm := reader.string()
+	key := reader.string()
+	if reader.err != nil || string(algorithm) != "ssh-ed25519" || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("only ssh-ed25519 keys are supported")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// sshReader decodes SSH wire-format fields, remembering the first error
+type sshReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sshReader) uint32() uint32 {
+	if r.err != nil || len(r.data) < 4 {
+		r.err = fmt.Errorf("truncated data")
+		return 0
+	}
+	value := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return value
+}
+
+func (r *sshReader) string() []byte {
+	length := r.uint32()
+	if r.err != nil || uint32(len(r.data)) < length {
+		r.err = fmt.Errorf("truncated data")
+		return nil
+	}
+	value := r.data[:length]
+	r.data = r.data[length:]
+	return value
+}
+
+func writeSSHString(buf *bytes.Buffer, value []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(value)))
+	buf.Write(value)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignKey returns a minisign public key line for key with the given ID
+func minisignKey(public ed25519.PublicKey, id []byte) string {
+	return base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), public...))
+}
+
+// minisignSignature signs data the way minisign -S does, prehashed ("ED") or legacy ("Ed")
+func minisignSignature(t *testing.T, private ed25519.PrivateKey, id []byte, data []byte, algorithm string) string {
+	t.Helper()
+	message := data
+	if algorithm == "ED" {
+		digest := blake2b.Sum512(data)
+		message = digest[:]
+	}
+	signature := ed25519.Sign(private, message)
+	trustedComment := "timestamp:1700000000\tfile:dump.zip"
+	global := ed25519.Sign(private, append(append([]byte{}, signature...), trustedComment...))
+	return "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), id...), signature...)) + "\n" +
+		"trusted comment: " + trustedComment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+}
+
+func TestCheckSignaturesMinisign(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	otherPublic, otherPrivate, _ := ed25519.GenerateKey(nil)
+	id := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	otherID := []byte{8, 7, 6, 5, 4, 3, 2, 1}
+
+	tempDir := t.TempDir()
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	files := map[string]string{
+		"prehashed.zip":         "attachment",
+		"legacy.zip":            "attachment",
+		"tampered.zip":          "tampered",
+		"unsigned.zip":          "attachment",
+		"other.zip":             "attachment",
+		"prehashed.zip.minisig": minisignSignature(t, private, id, []byte("attachment"), "ED"),
+		"legacy.zip.minisig":    minisignSignature(t, private, id, []byte("attachment"), "Ed"),
+		"tampered.zip.minisig":  minisignSignature(t, private, id, []byte("attachment"), "ED"),
+		"other.zip.minisig":     minisignSignature(t, otherPrivate, otherID, []byte("attachment"), "ED"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keyFile := filepath.Join(tempDir, "minisign.pub")
+	if err := os.WriteFile(keyFile, []byte("untrusted comment: minisign public key\n"+minisignKey(public, id)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		files      []string
+		keys       []string
+		wantErrors []string
+	}{
+		{name: "disabled rule", files: []string{"unsigned.zip"}},
+		{name: "prehashed and legacy signatures", files: []string{"prehashed.zip", "legacy.zip", "prehashed.zip.minisig"}, keys: []string{minisignKey(public, id)}},
+		{name: "key from file", files: []string{"prehashed.zip"}, keys: []string{"minisign.pub"}},
+		{name: "second key", files: []string{"other.zip"}, keys: []string{"minisign.pub", minisignKey(otherPublic, otherID)}},
+		{name: "tampered attachment", files: []string{"tampered.zip"}, keys: []string{"minisign.pub"}, wantErrors: []string{"Signature of 'tampered.zip' does not verify: invalid signature"}},
+		{name: "missing signature", files: []string{"unsigned.zip", "missing.zip"}, keys: []string{"minisign.pub"}, wantErrors: []string{"File 'unsigned.zip' has no detached signature (unsigned.zip.minisig or unsigned.zip.sig)"}},
+		{name: "unknown key", files: []string{"other.zip"}, keys: []string{"minisign.pub"}, wantErrors: []string{"Signature of 'other.zip' does not verify: signed with unknown key 0102030405060708"}},
+		{name: "invalid key", files: []string{"prehashed.zip"}, keys: []string{"missing.pub"}, wantErrors: []string{"Invalid public key in signatures.public_keys"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := SignaturesRule{Enabled: tt.keys != nil, PublicKeys: tt.keys}
+			config := &LintConfig{baseDir: tempDir}
+			errs := checkSignatures(challengePath, tt.files, rule, config)
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got: %v", len(tt.wantErrors), errs)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i], want) {
+					t.Errorf("Expected error containing '%s', got: %s", want, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSignaturesSSH(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+
+	tempDir := t.TempDir()
+	keyPath := filepath.Join(tempDir, "signing_key")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v\n%s", err, output)
+	}
+	attachment := filepath.Join(tempDir, "dump.zip")
+	if err := os.WriteFile(attachment, []byte("attachment"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := exec.Command("ssh-keygen", "-Y", "sign", "-f", keyPath, "-n", "file", attachment).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen -Y sign failed: %v\n%s", err, output)
+	}
+
+	challengePath := filepath.Join(tempDir, "challenge.yml")
+	config := &LintConfig{baseDir: tempDir}
+	rule := SignaturesRule{Enabled: true, PublicKeys: []string{"signing_key.pub"}}
+	if errs := checkSignatures(challengePath, []string{"dump.zip"}, rule, config); len(errs) != 0 {
+		t.Errorf("expected the ssh-keygen signature to verify, got %v", errs)
+	}
+
+	rule.Namespace = "ctf-attachments"
+	errs := checkSignatures(challengePath, []string{"dump.zip"}, rule, config)
+	if len(errs) != 1 || !strings.Contains(errs[0], `signed for namespace "file"`) {
+		t.Errorf("expected a namespace mismatch, got %v", errs)
+	}
+
+	if err := os.WriteFile(attachment, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rule.Namespace = ""
+	errs = checkSignatures(challengePath, []string{"dump.zip"}, rule, config)
+	if len(errs) != 1 || !strings.Contains(errs[0], "invalid signature") {
+		t.Errorf("expected the tampered attachment to fail, got %v", errs)
+	}
+}
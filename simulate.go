@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// difficultyOrder ranks the difficulty tags from easiest to hardest
+var difficultyOrder = []string{"beginner", "easy", "medium", "hard"}
+
+// defaultSolveRates are the assumed share of teams solving a challenge of
+// each difficulty
+var defaultSolveRates = map[string]float64{
+	"beginner": 0.9,
+	"easy":     0.6,
+	"medium":   0.25,
+	"hard":     0.08,
+}
+
+// defaultSimulationTeams is the team count simulated unless configured
+const defaultSimulationTeams = 100
+
+// SimulationRule configures clilint simulate
+type SimulationRule struct {
+	// Teams is the number of teams simulated unless --teams is given
+	Teams int `yaml:"teams"`
+	// SolveRates overrides the share of teams solving each difficulty, e.g. {hard: 0.05}
+	SolveRates map[string]float64 `yaml:"solve_rates"`
+}
+
+// SimulatedChallenge is the projected final value of one challenge
+type SimulatedChallenge struct {
+	File       string `json:"file"`
+	Name       string `json:"name"`
+	Difficulty string `json:"difficulty"`
+	Initial    int    `json:"initial"`
+	Solves     int    `json:"solves"`
+	Final      int    `json:"final"`
+}
+
+func runSimulate(args []string) {
+	jsonOutput := false
+	teams := 0
+	var targetDirs []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--json" {
+			jsonOutput = true
+		} else if args[i] == "--teams" && i+1 < len(args) {
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				log.Fatalf("Invalid --teams (want a positive number): %s", args[i])
+			}
+			teams = n
+		} else {
+			targetDirs = append(targetDirs, args[i])
+		}
+	}
+	if len(targetDirs) == 0 {
+		targetDirs = []string{"."}
+	}
+
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Fatalf("Error loading lintrc.yaml: %v", err)
+	}
+	if teams == 0 {
+		teams = config.Simulation.Teams
+		if teams == 0 {
+			teams = defaultSimulationTeams
+		}
+	}
+
+	var simulated []SimulatedChallenge
+	for _, dir := range targetDirs {
+		paths, err := findChallengeFiles(dir)
+		if err != nil {
+			log.Fatalf("Error walking directory %s: %v", dir, err)
+		}
+		for _, path := range paths {
+			challenge, err := readChallenge(path)
+			if err != nil {
+				log.Printf("Warning: skipping %s: %v", path, err)
+				continue
+			}
+			simulated = append(simulated, simulateChallenge(path, challenge, teams, config.Simulation))
+		}
+	}
+	sortSimulated(simulated)
+	warnings := checkSimulatedOrder(simulated)
+
+	if jsonOutput {
+		jsonData, err := json.Marshal(map[string]interface{}{"teams": teams, "challenges": simulated, "warnings": warnings})
+		if err != nil {
+			log.Fatalf("Failed to marshal JSON output: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return
+	}
+	writeSimulation(os.Stdout, teams, simulated, warnings)
+}
+
+// simulateChallenge projects the final value of a challenge when the share
+// of teams expected for its difficulty solves it
+func simulateChallenge(path string, challenge Challenge, teams int, rule SimulationRule) SimulatedChallenge {
+	result := SimulatedChallenge{File: path, Name: challenge.Name, Difficulty: challengeDifficulty(challenge.Tags)}
+
+	rate, ok := rule.SolveRates[result.Difficulty]
+	if !ok {
+		rate = defaultSolveRates[result.Difficulty]
+	}
+	result.Solves = int(math.Round(float64(teams) * rate))
+
+	result.Initial = challengeValue(challenge)
+	result.Final = result.Initial
+	if challenge.Type == "dynamic" {
+		initial, _ := challenge.Extra["initial"].(int)
+		decay, _ := challenge.Extra["decay"].(int)
+		minimum, _ := challenge.Extra["minimum"].(int)
+		function, _ := challenge.Extra["function"].(string)
+		if initial > 0 {
+			result.Initial = initial
+			result.Final = dynamicValue(initial, decay, minimum, function, result.Solves)
+		}
+	}
+	return result
+}
+
+// dynamicValue is CTFd's value of a dynamic challenge after solves solves:
+// a parabola from initial reaching minimum at decay solves, or a linear
+// decrease by decay per solve, never below minimum
+func dynamicValue(initial, decay, minimum int, function string, solves int) int {
+	if solves > 0 {
+		// The first solve does not decrease the value
+		solves--
+	}
+	var value float64
+	if function == "linear" {
+		value = float64(initial - decay*solves)
+	} else {
+		if decay == 0 {
+			return initial
+		}
+		value = float64(minimum-initial)/float64(decay*decay)*float64(solves*solves) + float64(initial)
+	}
+	final := int(math.Ceil(value))
+	if final < minimum {
+		final = minimum
+	}
+	return final
+}
+
+// challengeDifficulty returns the first difficulty tag, or ""
+func challengeDifficulty(tags []string) string {
+	for _, tag := range tags {
+		if difficultyRank(tag) >= 0 {
+			return tag
+		}
+	}
+	return ""
+}
+
+func difficultyRank(difficulty string) int {
+	for i, candidate := range difficultyOrder {
+		if candidate == difficulty {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortSimulated orders challenges by difficulty, then by final value
+func sortSimulated(simulated []SimulatedChallenge) {
+	sort.SliceStable(simulated, func(i, j int) bool {
+		a, b := simulated[i], simulated[j]
+		if ra, rb := difficultyRank(a.Difficulty), difficultyRank(b.Difficulty); ra != rb {
+			return ra < rb
+		}
+		if a.Final != b.Final {
+			return a.Final > b.Final
+		}
+		return a.File < b.File
+	})
+}
+
+// checkSimulatedOrder warns about each challenge that ends up worth less
+// than the most valuable challenge of an easier difficulty
+func checkSimulatedOrder(simulated []SimulatedChallenge) []string {
+	var warnings []string
+	for _, challenge := range simulated {
+		rank := difficultyRank(challenge.Difficulty)
+		if rank <= 0 {
+			continue
+		}
+		var richest *SimulatedChallenge
+		for i := range simulated {
+			other := &simulated[i]
+			otherRank := difficultyRank(other.Difficulty)
+			if otherRank < 0 || otherRank >= rank {
+				continue
+			}
+			if richest == nil || other.Final > richest.Final {
+				richest = other
+			}
+		}
+		if richest != nil && challenge.Final < richest.Final {
+			warnings = append(warnings, fmt.Sprintf("%s challenge '%s' ends at %d points, less than %s challenge '%s' at %d",
+				challenge.Difficulty, challenge.Name, challenge.Final, richest.Difficulty, richest.Name, richest.Final))
+		}
+	}
+	return warnings
+}
+
+// writeSimulation writes the projected values and ordering warnings
+func writeSimulation(w io.Writer, teams int, simulated []SimulatedChallenge, warnings []string) {
+	fmt.Fprintf(w, "📈 Projected values with %d teams:\n", teams)
+	for _, challenge := range simulated {
+		difficulty := challenge.Difficulty
+		if difficulty == "" {
+			difficulty = "-"
+		}
+		fmt.Fprintf(w, "  %-8s %s: %d → %d (%d solves)\n", difficulty, challenge.Name, challenge.Initial, challenge.Final, challenge.Solves)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "⚠️  %s\n", warning)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDynamicValue(t *testing.T) {
+	tests := []struct {
+		name                    string
+		initial, decay, minimum int
+		function                string
+		solves                  int
+		want                    int
+	}{
+		{name: "unsolved", initial: 500, decay: 100, minimum: 100, solves: 0, want: 500},
+		{name: "first solve", initial: 500, decay: 100, minimum: 100, solves: 1, want: 500},
+		{name: "logarithmic", initial: 500, decay: 100, minimum: 100, solves: 11, want: 496},
+		{name: "logarithmic halfway", initial: 500, decay: 100, minimum: 100, solves: 51, want: 400},
+		{name: "logarithmic at minimum", initial: 500, decay: 100, minimum: 100, solves: 300, want: 100},
+		{name: "linear", initial: 500, decay: 10, minimum: 100, function: "linear", solves: 21, want: 300},
+		{name: "linear at minimum", initial: 500, decay: 10, minimum: 100, function: "linear", solves: 100, want: 100},
+		{name: "no decay", initial: 500, minimum: 100, solves: 50, want: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dynamicValue(tt.initial, tt.decay, tt.minimum, tt.function, tt.solves); got != tt.want {
+				t.Errorf("dynamicValue() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	parse := func(data string) Challenge {
+		var challenge Challenge
+		if err := yaml.Unmarshal([]byte(data), &challenge); err != nil {
+			t.Fatal(err)
+		}
+		return challenge
+	}
+	rule := SimulationRule{SolveRates: map[string]float64{"hard": 0.1}}
+
+	simulated := []SimulatedChallenge{
+		simulateChallenge("hard/challenge.yml", parse("name: heap\ntags: [hard]\ntype: dynamic\nextra: {initial: 500, decay: 5, minimum: 50}\n"), 100, rule),
+		simulateChallenge("easy/challenge.yml", parse("name: login\ntags: [easy]\ntype: dynamic\nextra: {initial: 500, decay: 300, minimum: 100}\n"), 100, rule),
+		simulateChallenge("static/challenge.yml", parse("name: quiz\ntags: [medium]\nvalue: 200\n"), 100, rule),
+	}
+	sortSimulated(simulated)
+
+	want := []SimulatedChallenge{
+		{File: "easy/challenge.yml", Name: "login", Difficulty: "easy", Initial: 500, Solves: 60, Final: 485},
+		{File: "static/challenge.yml", Name: "quiz", Difficulty: "medium", Initial: 200, Solves: 25, Final: 200},
+		{File: "hard/challenge.yml", Name: "heap", Difficulty: "hard", Initial: 500, Solves: 10, Final: 50},
+	}
+	if !reflect.DeepEqual(simulated, want) {
+		t.Fatalf("simulated = %+v, want %+v", simulated, want)
+	}
+
+	wantWarnings := []string{
+		"medium challenge 'quiz' ends at 200 points, less than easy challenge 'login' at 485",
+		"hard challenge 'heap' ends at 50 points, less than easy challenge 'login' at 485",
+	}
+	warnings := checkSimulatedOrder(simulated)
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("checkSimulatedOrder() = %v, want %v", warnings, wantWarnings)
+	}
+
+	var out bytes.Buffer
+	writeSimulation(&out, 100, simulated, warnings)
+	for _, line := range []string{
+		"📈 Projected values with 100 teams:\n",
+		"  easy     login: 500 → 485 (60 solves)\n",
+		"⚠️  hard challenge 'heap' ends at 50 points",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output missing %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSQLitePath is the database --format=sqlite writes unless --output is given
+const defaultSQLitePath = "clilint.db"
+
+// sqliteSchema is the layout of the results database. Every run replaces the
+// tables, so the file always describes the last run.
+const sqliteSchema = `DROP TABLE IF EXISTS metadata;
+DROP TABLE IF EXISTS challenges;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS findings;
+CREATE TABLE metadata (key TEXT PRIMARY KEY, value TEXT);
+CREATE TABLE challenges (
+  file TEXT PRIMARY KEY,
+  name TEXT,
+  author TEXT,
+  category TEXT,
+  type TEXT,
+  value INTEGER,
+  state TEXT,
+  flags INTEGER,
+  files INTEGER,
+  hints INTEGER,
+  errors INTEGER,
+  warnings INTEGER
+);
+CREATE TABLE tags (file TEXT, tag TEXT);
+CREATE TABLE findings (
+  file TEXT,
+  rule_id TEXT,
+  severity TEXT,
+  message TEXT,
+  field TEXT,
+  line INTEGER,
+  "column" INTEGER,
+  fixable INTEGER,
+  docs TEXT
+);
+CREATE INDEX findings_file ON findings (file);
+CREATE INDEX tags_file ON tags (file);
+`
+
+// sqliteScript renders the SQL that loads results into the database
+func sqliteScript(results []LintResult) string {
+	var script strings.Builder
+	script.WriteString("BEGIN;\n")
+	script.WriteString(sqliteSchema)
+
+	metadata := [][2]string{
+		{"generated_at", now().UTC().Format(time.RFC3339)},
+		{"ref", currentRef()},
+	}
+	for _, entry := range metadata {
+		fmt.Fprintf(&script, "INSERT INTO metadata VALUES (%s, %s);\n", sqlString(entry[0]), sqlString(entry[1]))
+	}
+
+	for _, result := range results {
+		// Unparseable challenges are still listed, with empty fields
+		challenge := result.challenge
+		fmt.Fprintf(&script, "INSERT INTO challenges VALUES (%s, %s, %s, %s, %s, %d, %s, %d, %d, %d, %d, %d);\n",
+			sqlString(result.File), sqlString(challenge.Name), sqlString(challenge.Author), sqlString(challenge.Category),
+			sqlString(challenge.Type), challenge.Value, sqlString(challenge.State),
+			len(challenge.Flags), len(challenge.Files), len(challenge.Hints), len(result.Errors), len(result.Warnings))
+		for _, tag := range challenge.Tags {
+			fmt.Fprintf(&script, "INSERT INTO tags VALUES (%s, %s);\n", sqlString(result.File), sqlString(tag))
+		}
+		for _, findings := range [][]Finding{result.Errors, result.Warnings, result.Deprecations} {
+			for _, finding := range findings {
+				fmt.Fprintf(&script, "INSERT INTO findings VALUES (%s, %s, %s, %s, %s, %s, %s, %d, %s);\n",
+					sqlString(result.File), sqlString(finding.RuleID), sqlString(finding.Severity), sqlString(finding.Message),
+					sqlNullString(finding.Field), sqlNullInt(finding.Line), sqlNullInt(finding.Column),
+					sqlBool(finding.Fixable), sqlNullString(finding.Docs))
+			}
+		}
+	}
+
+	script.WriteString("COMMIT;\n")
+	return script.String()
+}
+
+// checkSQLite fails when the sqlite3 command line shell, which writes the
+// database, is not installed
+func checkSQLite() error {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return fmt.Errorf("--format=sqlite requires the sqlite3 command line shell (e.g. apt install sqlite3): %v", err)
+	}
+	return nil
+}
+
+// writeSQLite loads results into the SQLite database at path using the
+// sqlite3 command line shell
+func writeSQLite(path string, results []LintResult) error {
+	if err := checkSQLite(); err != nil {
+		return err
+	}
+	cmd := exec.Command("sqlite3", "-bail", path)
+	cmd.Stdin = strings.NewReader(sqliteScript(results))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sqlite3 failed: %v\n%s", err, output)
+	}
+	return nil
+}
+
+func sqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func sqlNullString(value string) string {
+	if value == "" {
+		return "NULL"
+	}
+	return sqlString(value)
+}
+
+func sqlNullInt(value int) string {
+	if value == 0 {
+		return "NULL"
+	}
+	return strconv.Itoa(value)
+}
+
+func sqlBool(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
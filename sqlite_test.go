@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSQLiteChallenge(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	content := `name: "Bob's web"
+author: "bob"
+category: "web"
+flags: ["flag{a}", "flag{b}"]
+tags: ["hard", "author: bob"]
+value: 300
+type: dynamic
+state: visible
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSQLiteScript(t *testing.T) {
+	origNow := now
+	now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	defer func() {
+		now = origNow
+	}()
+
+	result := lintChallengeFile(writeSQLiteChallenge(t))
+	result.Errors = []Finding{
+		{RuleID: "version", Severity: SeverityError, Message: "Field 'version' should be '0.1'", Field: "version", Fixable: true},
+	}
+	result.Warnings = []Finding{
+		{RuleID: "host", Severity: SeverityWarning, Message: "no host", Line: 3, Column: 1},
+	}
+	result.Deprecations = nil
+	// Rows come from the result, not from the file as it is now
+	if err := os.Remove(result.File); err != nil {
+		t.Fatal(err)
+	}
+
+	script := sqliteScript([]LintResult{result})
+	for _, want := range []string{
+		"BEGIN;",
+		"CREATE TABLE challenges (",
+		"INSERT INTO metadata VALUES ('generated_at', '2026-10-16T12:00:00Z');",
+		"'Bob''s web', 'bob', 'web', 'dynamic', 300, 'visible', 2, 0, 0, 1, 1);",
+		"', 'hard');",
+		"', 'author: bob');",
+		"'version', 'error', 'Field ''version'' should be ''0.1''', 'version', NULL, NULL, 1, NULL);",
+		"'host', 'warning', 'no host', NULL, 3, 1, 0, NULL);",
+		"COMMIT;",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected %q in script:\n%s", want, script)
+		}
+	}
+}
+
+func TestSQLiteScriptAnonymous(t *testing.T) {
+	origMode := anonymousMode
+	defer func() { anonymousMode = origMode }()
+	anonymousMode = true
+
+	script := sqliteScript([]LintResult{lintChallengeFile(writeSQLiteChallenge(t))})
+	if strings.Contains(strings.ToLower(script), "bob") {
+		t.Errorf("expected no author in script:\n%s", script)
+	}
+	for _, want := range []string{"'(author)''s web', '', 'web',", "', 'author: (author)');"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected %q in script:\n%s", want, script)
+		}
+	}
+}
+
+func TestSQLiteScriptUnreadableChallenge(t *testing.T) {
+	results := []LintResult{{
+		File:   filepath.Join(t.TempDir(), "missing", "challenge.yml"),
+		Errors: []Finding{{RuleID: "read", Severity: SeverityError, Message: "Failed to read file"}},
+	}}
+	script := sqliteScript(results)
+	if !strings.Contains(script, "'', '', '', '', 0, '', 0, 0, 0, 1, 0);") {
+		t.Errorf("expected an empty challenge row, got:\n%s", script)
+	}
+	if !strings.Contains(script, "'read', 'error', 'Failed to read file'") {
+		t.Errorf("expected the read finding, got:\n%s", script)
+	}
+}
+
+func TestWriteSQLite(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+
+	result := lintChallengeFile(writeSQLiteChallenge(t))
+	result.Errors = []Finding{{RuleID: "version", Severity: SeverityError, Message: "Field 'version' should be '0.1'"}}
+	result.Warnings, result.Deprecations = nil, nil
+	results := []LintResult{result}
+	dbPath := filepath.Join(t.TempDir(), "clilint.db")
+	// Writing twice replaces the previous run
+	for i := 0; i < 2; i++ {
+		if err := writeSQLite(dbPath, results); err != nil {
+			t.Fatalf("writeSQLite() error: %v", err)
+		}
+	}
+
+	output, err := exec.Command("sqlite3", dbPath, "SELECT c.author, count(*) FROM findings f JOIN challenges c USING (file) GROUP BY c.author;").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(output)) != "bob|1" {
+		t.Errorf("unexpected query result: %q", output)
+	}
+}
+
+func TestCheckSQLite(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if err := checkSQLite(); err == nil || !strings.Contains(err.Error(), "sqlite3 command line shell") {
+		t.Errorf("checkSQLite() = %v, want a missing sqlite3 error", err)
+	}
+}
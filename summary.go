@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+)
+
+// summaryPath is set by --summary-file or CLILINT_SUMMARY_FILE; the summary
+// is only written when it is non-empty
+var summaryPath string
+
+// RunSummary is the compact results file of a lint run, for later workflow
+// steps that should not parse the log
+type RunSummary struct {
+	// Result is success or failure, as the result action output
+	Result       string `json:"result"`
+	Challenges   int    `json:"challenges"`
+	Failed       int    `json:"failed"`
+	Errors       int    `json:"errors"`
+	Warnings     int    `json:"warnings"`
+	Deprecations int    `json:"deprecations"`
+	// ChangedDirs are the challenge directories a PR touches; empty outside --comment-pr
+	ChangedDirs []string `json:"changed_dirs"`
+	// FailedFiles are the challenge.yml files with errors
+	FailedFiles []string `json:"failed_files"`
+}
+
+// summarizeResults counts the findings of a run
+func summarizeResults(results []LintResult, changedDirs []string) RunSummary {
+	summary := RunSummary{
+		Result:      "success",
+		Challenges:  len(results),
+		ChangedDirs: append([]string{}, changedDirs...),
+		FailedFiles: []string{},
+	}
+	for _, result := range results {
+		summary.Errors += len(result.Errors)
+		summary.Warnings += len(result.Warnings)
+		summary.Deprecations += len(result.Deprecations)
+		if len(result.Errors) > 0 {
+			summary.Failed++
+			summary.FailedFiles = append(summary.FailedFiles, result.File)
+		}
+	}
+	if summary.Failed > 0 {
+		summary.Result = "failure"
+	}
+	sort.Strings(summary.ChangedDirs)
+	sort.Strings(summary.FailedFiles)
+	return summary
+}
+
+// recordSummary writes the summary file, if one was given. Like the metrics
+// file it is replaced atomically.
+func recordSummary(results []LintResult, changedDirs []string) {
+	if summaryPath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(summarizeResults(results, changedDirs), "", "  ")
+	if err != nil {
+		log.Printf("Warning: failed to write the summary: %v", err)
+		return
+	}
+	if err := writeFileAtomic(summaryPath, string(data)+"\n"); err != nil {
+		log.Printf("Warning: failed to write the summary: %v", err)
+	}
+}
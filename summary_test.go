@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecordSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	summaryPath = path
+	t.Cleanup(func() { summaryPath = "" })
+
+	results := []LintResult{
+		{File: "web/b/challenge.yml", Errors: []Finding{{Message: "a"}, {Message: "b"}}, Warnings: []Finding{{Message: "c"}}},
+		{File: "pwn/a/challenge.yml", Deprecations: []Finding{{Message: "d"}}},
+		{File: "misc/c/challenge.yml", Errors: []Finding{{Message: "e"}}},
+	}
+	recordSummary(results, []string{"web/b", "pwn/a"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read the summary: %v", err)
+	}
+	var got RunSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v\n%s", err, data)
+	}
+	want := RunSummary{
+		Result:       "failure",
+		Challenges:   3,
+		Failed:       2,
+		Errors:       3,
+		Warnings:     1,
+		Deprecations: 1,
+		ChangedDirs:  []string{"pwn/a", "web/b"},
+		FailedFiles:  []string{"misc/c/challenge.yml", "web/b/challenge.yml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarizeResultsEmpty(t *testing.T) {
+	data, err := json.Marshal(summarizeResults(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lists are empty rather than null so that steps can iterate them directly
+	want := `{"result":"success","challenges":0,"failed":0,"errors":0,"warnings":0,"deprecations":0,"changed_dirs":[],"failed_files":[]}`
+	if string(data) != want {
+		t.Errorf("summary = %s, want %s", data, want)
+	}
+}
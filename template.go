@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// topLevelKeys returns the keys of the root mapping of a YAML document in
+// order, including keys merged in with "<<"
+func topLevelKeys(data []byte) ([]string, error) {
+	if err := checkAliasExpansion(data); err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	return mappingKeys(doc.Content[0]), nil
+}
+
+// checkTemplate verifies that a challenge contains every top-level key of the
+// template challenge.yml in the template's order. Keys the template does not
+// have may appear anywhere.
+func checkTemplate(data []byte, config *LintConfig) []string {
+	var errors []string
+
+	if config.Template == "" {
+		return errors
+	}
+
+	templateData, err := os.ReadFile(config.resolvePath(config.Template))
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to read template '%s': %v", config.Template, err))
+		return errors
+	}
+	templateKeys, err := topLevelKeys(templateData)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to parse template '%s': %v", config.Template, err))
+		return errors
+	}
+	keys, err := topLevelKeys(data)
+	if err != nil {
+		// Reported as invalid YAML
+		return errors
+	}
+
+	present := make(map[string]bool)
+	for _, key := range keys {
+		present[key] = true
+	}
+	var missing []string
+	position := make(map[string]int)
+	for i, key := range templateKeys {
+		position[key] = i
+		if !present[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		errors = append(errors, fmt.Sprintf("Missing keys from the challenge template: %s", strings.Join(missing, ", ")))
+	}
+
+	// Keys shared with the template must keep its relative order
+	previous := ""
+	for _, key := range keys {
+		pos, ok := position[key]
+		if !ok {
+			continue
+		}
+		if previous != "" && pos < position[previous] {
+			errors = append(errors, fmt.Sprintf("Key '%s' should come before '%s' as in the challenge template", key, previous))
+			break
+		}
+		previous = key
+	}
+
+	return errors
+}
+
+// isTemplateFile reports whether path is the template configured in lintrc.yaml,
+// which is skipped when searching for challenges
+func isTemplateFile(path string, config *LintConfig) bool {
+	if config.Template == "" {
+		return false
+	}
+	templatePath, err := filepath.Abs(config.resolvePath(config.Template))
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	return err == nil && absPath == templatePath
+}
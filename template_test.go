@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	template := `name: "<CHALLENGE NAME>"
+author: "<AUTHOR>"
+category: "<CATEGORY>"
+description: |
+  <DESCRIPTION>
+flags:
+  - "<FLAG>"
+tags:
+  - "author: <AUTHOR>"
+state: visible
+version: "0.1"
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "template.yml"), []byte(template), 0644); err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	config := &LintConfig{Template: "template.yml", baseDir: tempDir}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "conforming with extra keys",
+			content: "name: a\nauthor: b\ncategory: web\nvalue: 100\ndescription: d\nflags: []\ntags: []\nstate: visible\nversion: \"0.1\"\n",
+		},
+		{
+			name:    "missing keys",
+			content: "name: a\ncategory: web\ndescription: d\nflags: []\nstate: visible\n",
+			want:    []string{"Missing keys from the challenge template: author, tags, version"},
+		},
+		{
+			name:    "out of order",
+			content: "name: a\nauthor: b\ncategory: web\nflags: []\ndescription: d\ntags: []\nstate: visible\nversion: \"0.1\"\n",
+			want:    []string{"Key 'description' should come before 'flags' as in the challenge template"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkTemplate([]byte(tt.content), config)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if errs := checkTemplate(nil, &LintConfig{Template: "missing.yml", baseDir: tempDir}); len(errs) != 1 || !strings.Contains(errs[0], "Failed to read template") {
+		t.Errorf("Expected missing template error, got: %v", errs)
+	}
+}
+
+func TestTemplateSkippedWhenWalking(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	for _, dir := range []string{"templates", filepath.Join("web", "chall")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "challenge.yml"), []byte("name: x\n"), 0644); err != nil {
+			t.Fatalf("Failed to create challenge.yml: %v", err)
+		}
+	}
+	if err := os.WriteFile("lintrc.yaml", []byte("template: templates/challenge.yml\n"), 0644); err != nil {
+		t.Fatalf("Failed to create lintrc.yaml: %v", err)
+	}
+
+	paths, err := findChallengeFiles(".")
+	if err != nil {
+		t.Fatalf("findChallengeFiles failed: %v", err)
+	}
+	want := []string{filepath.Join("web", "chall", "challenge.yml")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("findChallengeFiles() = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext is how many unchanged lines surround the changes of a hunk
+const diffContext = 3
+
+// diffLine is a line of a diff: ' ' kept, '-' removed, or '+' added. text
+// keeps its newline, so a missing newline at the end of a file is a change
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff renders the changes from old to new contents of path as a
+// unified diff, "" when they are equal
+func unifiedDiff(path string, old, new []byte) string {
+	lines := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	// Line numbers in the old and new file at each diff line
+	oldAt, newAt := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	oldAt[0], newAt[0] = 1, 1
+	for i, line := range lines {
+		oldAt[i+1], newAt[i+1] = oldAt[i], newAt[i]
+		if line.kind != '+' {
+			oldAt[i+1]++
+		}
+		if line.kind != '-' {
+			newAt[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(lines); i++ {
+		if lines[i].kind == ' ' {
+			continue
+		}
+		// A hunk runs until diffContext lines after a change that is not
+		// followed by another within twice the context
+		start, last := max(0, i-diffContext), i
+		for j := i + 1; j < len(lines) && j <= last+2*diffContext; j++ {
+			if lines[j].kind != ' ' {
+				last = j
+			}
+		}
+		end := min(len(lines), last+diffContext+1)
+
+		if out.Len() == 0 {
+			name := filepath.ToSlash(path)
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldAt[start], oldAt[end]-oldAt[start]), hunkRange(newAt[start], newAt[end]-newAt[start]))
+		for _, line := range lines[start:end] {
+			out.WriteByte(line.kind)
+			out.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end - 1
+	}
+	return out.String()
+}
+
+// hunkRange renders the start and length of a hunk's lines in one file
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		// An empty range names the line before it
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits text after each newline
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns a shortest edit from a to b, from the longest common
+// subsequence of the lines between their common prefix and suffix. Fixes
+// change a few lines, so that middle part stays small
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:]
+	// and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			lines = append(lines, diffLine{' ', midA[i]})
+			i++
+			j++
+		case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', midA[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', midB[j]})
+			j++
+		}
+	}
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name, old, new, want string
+	}{
+		{name: "equal", old: "a\nb\n", new: "a\nb\n", want: ""},
+		{
+			name: "separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\nTWELVE\n",
+			want: "--- a/c.yml\n+++ b/c.yml\n@@ -1,5 +1,5 @@\n 1\n-2\n+TWO\n 3\n 4\n 5\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+TWELVE\n",
+		},
+		{
+			name: "merged hunk",
+			old:  "1\n2\n3\n4\n5\n6\n7\n",
+			new:  "1\nTWO\n3\n4\n5\nSIX\n7\n",
+			want: "--- a/c.yml\n+++ b/c.yml\n@@ -1,7 +1,7 @@\n 1\n-2\n+TWO\n 3\n 4\n 5\n-6\n+SIX\n 7\n",
+		},
+		{
+			name: "appended line",
+			old:  "a\nb",
+			new:  "a\nb\nc\n",
+			want: "--- a/c.yml\n+++ b/c.yml\n@@ -1,2 +1,3 @@\n a\n-b\n\\ No newline at end of file\n+b\n+c\n",
+		},
+		{
+			name: "into an empty file",
+			old:  "",
+			new:  "a\n",
+			want: "--- a/c.yml\n+++ b/c.yml\n@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("c.yml", []byte(tt.old), []byte(tt.new)); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
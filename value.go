@@ -0,0 +1,47 @@
+package main
+
+import "fmt"
+
+// ValueRule keeps challenge values within bounds and on a grid, so that the
+// scoreboard's numbers stay tidy and comparable
+type ValueRule struct {
+	// Min and Max bound the value (or a dynamic challenge's initial value)
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+	// Step is what values, and the minimum of dynamic challenges, must be a
+	// multiple of, e.g. 50
+	Step int `yaml:"step"`
+}
+
+func checkValue(challenge Challenge, rule ValueRule) []string {
+	var errors []string
+
+	if rule.Min == 0 && rule.Max == 0 && rule.Step == 0 {
+		return errors
+	}
+	check := func(field string, value int, bounded bool) {
+		if bounded && rule.Min > 0 && value < rule.Min {
+			errors = append(errors, fmt.Sprintf("Field '%s' is %d, below the minimum of %d (value.min)", field, value, rule.Min))
+		}
+		if bounded && rule.Max > 0 && value > rule.Max {
+			errors = append(errors, fmt.Sprintf("Field '%s' is %d, above the maximum of %d (value.max)", field, value, rule.Max))
+		}
+		if rule.Step > 0 && value%rule.Step != 0 {
+			errors = append(errors, fmt.Sprintf("Field '%s' is %d, not a multiple of %d (value.step)", field, value, rule.Step))
+		}
+	}
+
+	if challenge.Value != 0 {
+		check("value", challenge.Value, true)
+	}
+	if challenge.Type == "dynamic" {
+		if initial, ok := challenge.Extra["initial"].(int); ok && initial != challenge.Value {
+			check("extra.initial", initial, true)
+		}
+		// The minimum is what solved challenges decay to: only on the grid
+		if minimum, ok := challenge.Extra["minimum"].(int); ok {
+			check("extra.minimum", minimum, false)
+		}
+	}
+	return errors
+}
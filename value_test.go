@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckValue(t *testing.T) {
+	rule := ValueRule{Min: 100, Max: 500, Step: 50}
+	tests := []struct {
+		name      string
+		challenge Challenge
+		rule      ValueRule
+		want      []string
+	}{
+		{
+			name:      "no rule",
+			challenge: Challenge{Value: 123},
+		},
+		{
+			name:      "on step and in bounds",
+			challenge: Challenge{Value: 250},
+			rule:      rule,
+		},
+		{
+			name:      "off step",
+			challenge: Challenge{Value: 275},
+			rule:      rule,
+			want:      []string{"Field 'value' is 275, not a multiple of 50 (value.step)"},
+		},
+		{
+			name:      "out of bounds",
+			challenge: Challenge{Value: 1000},
+			rule:      rule,
+			want:      []string{"Field 'value' is 1000, above the maximum of 500 (value.max)"},
+		},
+		{
+			name:      "dynamic",
+			challenge: Challenge{Type: "dynamic", Extra: map[string]interface{}{"initial": 80, "decay": 30, "minimum": 25}},
+			rule:      rule,
+			want: []string{
+				"Field 'extra.initial' is 80, below the minimum of 100 (value.min)",
+				"Field 'extra.initial' is 80, not a multiple of 50 (value.step)",
+				"Field 'extra.minimum' is 25, not a multiple of 50 (value.step)",
+			},
+		},
+		{
+			name:      "dynamic minimum below min",
+			challenge: Challenge{Type: "dynamic", Value: 500, Extra: map[string]interface{}{"initial": 500, "minimum": 50}},
+			rule:      rule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkValue(tt.challenge, tt.rule)
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// varsPath is set by --vars and takes precedence over vars_file in lintrc.yaml
+var varsPath string
+
+// placeholderPattern matches ${VAR} placeholders
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// loadVars returns the values placeholders are expanded with: the vars file
+// (--vars, else vars_file in lintrc.yaml), overridden by environment variables
+// of the same name. Without a vars file only the environment is used.
+func loadVars(config *LintConfig) (func(string) (string, bool), error) {
+	values := make(map[string]string)
+
+	path := varsPath
+	if path == "" && config.VarsFile != "" {
+		path = config.resolvePath(config.VarsFile)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vars file: %v", err)
+		}
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse vars file: %v", err)
+		}
+		for key, value := range raw {
+			if value != nil {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := values[name]
+		return value, ok
+	}, nil
+}
+
+// expandPlaceholders replaces ${VAR} and Go template ({{ .VAR }}) placeholders
+// in value. It returns the names of variables that have no value, in which
+// case those placeholders are left as written.
+func expandPlaceholders(value string, lookup func(string) (string, bool)) (string, []string, error) {
+	var missing []string
+	expanded := placeholderPattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if v, ok := lookup(name); ok {
+			return v
+		}
+		missing = append(missing, name)
+		return match
+	})
+
+	if !strings.Contains(expanded, "{{") {
+		return expanded, missing, nil
+	}
+	tmpl, err := template.New("value").Option("missingkey=error").Parse(expanded)
+	if err != nil {
+		return value, missing, err
+	}
+	data := make(map[string]string)
+	for _, name := range templateFields(tmpl) {
+		if v, ok := lookup(name); ok {
+			data[name] = v
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return expanded, missing, nil
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return value, missing, err
+	}
+	return out.String(), nil, nil
+}
+
+// templateFields lists the top-level fields referenced by a parsed template
+func templateFields(tmpl *template.Template) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.FieldNode:
+			if name := n.Ident[0]; !seen[name] {
+				seen[name] = true
+				fields = append(fields, name)
+			}
+		}
+	}
+	walk(tmpl.Tree.Root)
+	sort.Strings(fields)
+	return fields
+}
+
+// expandChallenge expands placeholders in host and connection_info in place
+// and reports the ones that cannot be resolved
+func expandChallenge(challenge *Challenge, config *LintConfig) []string {
+	var errors []string
+
+	lookup, err := loadVars(config)
+	if err != nil {
+		errors = append(errors, err.Error())
+		return errors
+	}
+
+	expand := func(field string, value string) string {
+		expanded, missing, err := expandPlaceholders(value, lookup)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Invalid template in '%s': %v", field, err))
+			return value
+		}
+		for _, name := range missing {
+			errors = append(errors, fmt.Sprintf("Unresolved placeholder '%s' in '%s'", name, field))
+		}
+		return expanded
+	}
+
+	if host, ok := challenge.Host.(string); ok {
+		challenge.Host = expand("host", host)
+	}
+	challenge.ConnectionInfo = expand("connection_info", challenge.ConnectionInfo)
+	return errors
+}
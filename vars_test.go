@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandPlaceholders(t *testing.T) {
+	vars := map[string]string{"HOST": "chall.example.com", "PORT": "1337"}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		want        string
+		wantMissing []string
+		wantErr     bool
+	}{
+		{name: "no placeholders", value: "nc example.com 1337", want: "nc example.com 1337"},
+		{name: "dollar braces", value: "nc ${HOST} ${PORT}", want: "nc chall.example.com 1337"},
+		{name: "go template", value: "http://{{ .HOST }}:{{ .PORT }}/", want: "http://chall.example.com:1337/"},
+		{name: "template text with dots", value: "{{ .HOST }}.example.com", want: "chall.example.com.example.com"},
+		{name: "unresolved dollar", value: "nc ${MISSING} 1", want: "nc ${MISSING} 1", wantMissing: []string{"MISSING"}},
+		{name: "unresolved template", value: "{{ .MISSING }}", want: "{{ .MISSING }}", wantMissing: []string{"MISSING"}},
+		{name: "invalid template", value: "{{ .HOST", wantErr: true},
+		{name: "plain dollar", value: "$HOST", want: "$HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing, err := expandPlaceholders(tt.value, lookup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandPlaceholders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expandPlaceholders() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestLoadVars(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "staging.yml"), []byte("HOST: staging.example.com\nPORT: 1337\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORT", "31337")
+
+	lookup, err := loadVars(&LintConfig{VarsFile: "staging.yml", baseDir: tempDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host, _ := lookup("HOST"); host != "staging.example.com" {
+		t.Errorf("HOST = %q, want value from the vars file", host)
+	}
+	if port, _ := lookup("PORT"); port != "31337" {
+		t.Errorf("PORT = %q, want the environment to override the vars file", port)
+	}
+	if _, ok := lookup("CLILINT_TEST_UNSET"); ok {
+		t.Error("lookup found an unset variable")
+	}
+
+	varsPath = filepath.Join(tempDir, "missing.yml")
+	defer func() {
+		varsPath = ""
+	}()
+	if _, err := loadVars(&LintConfig{VarsFile: "staging.yml", baseDir: tempDir}); err == nil {
+		t.Error("--vars should take precedence over vars_file")
+	}
+}
+
+func TestLintChallengeFilePlaceholders(t *testing.T) {
+	tempDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer func() {
+		_ = os.Chdir(origDir)
+	}()
+	_ = os.Chdir(tempDir)
+
+	config := "requirements:\n  condition: none\nrequire_host: true\nvars_file: vars.yml\n"
+	if err := os.WriteFile("lintrc.yaml", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("vars.yml", []byte("DOMAIN: prod.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	content := `name: "test"
+author: "author"
+category: "pwn"
+description: "desc"
+flags: ["flag{test}"]
+tags: ["easy"]
+value: 100
+type: dynamic
+image: null
+host: "https://${DOMAIN}"
+connection_info: "nc ${DOMAIN} ${CLILINT_TEST_PORT}"
+state: visible
+version: "0.1"
+`
+	if err := os.WriteFile("challenge.yml", []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := lintChallengeFile("challenge.yml")
+	messages := findingMessages(result.Errors)
+	if len(messages) != 1 || !strings.Contains(messages[0], "Unresolved placeholder 'CLILINT_TEST_PORT' in 'connection_info'") {
+		t.Errorf("expected one unresolved placeholder error, got %v", messages)
+	}
+
+	t.Setenv("CLILINT_TEST_PORT", "1337")
+	result = lintChallengeFile("challenge.yml")
+	if len(result.Errors) != 0 {
+		t.Errorf("expected no errors, got %v", findingMessages(result.Errors))
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// version is the release of the binary, set at build time with
+// -ldflags "-X main.version=v1.4.0"; otherwise the module version is used
+var version string
+
+// releaseOwner and releaseRepo are where clilint is released
+const (
+	releaseOwner = "diver-osint-ctf"
+	releaseRepo  = "clilint"
+)
+
+// toolVersion returns the release of the binary, with the VCS revision of
+// development builds
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	current := info.Main.Version
+	if current == "" || current == "(devel)" {
+		current = "devel"
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				current += "+" + setting.Value
+			case "vcs.modified":
+				if setting.Value == "true" {
+					current += "-dirty"
+				}
+			}
+		}
+	}
+	return current
+}
+
+// semver is a parsed MAJOR.MINOR.PATCH version; missing parts are zero
+type semver struct {
+	parts      [3]int
+	prerelease string
+}
+
+// parseVersion parses "v1.4.0", "1.4", or "1.4.0-rc.1"; build metadata is ignored
+func parseVersion(value string) (semver, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "v")
+	value, _, _ = strings.Cut(value, "+")
+	var parsed semver
+	value, parsed.prerelease, _ = strings.Cut(value, "-")
+	fields := strings.Split(value, ".")
+	if len(fields) > 3 {
+		return semver{}, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		parsed.parts[i] = n
+	}
+	return parsed, true
+}
+
+// compare returns -1, 0, or 1; a prerelease precedes its release
+func (v semver) compare(other semver) int {
+	for i := range v.parts {
+		if v.parts[i] != other.parts[i] {
+			if v.parts[i] < other.parts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.prerelease == other.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case other.prerelease == "":
+		return -1
+	case v.prerelease < other.prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// pseudoVersion matches the prerelease of the pseudo-versions Go stamps on
+// builds of untagged commits, such as v0.0.0-20261016031930-241fd404ed58
+var pseudoVersion = regexp.MustCompile(`(^|\.)[0-9]{14}-[0-9a-f]{12}$`)
+
+// releaseVersion parses the version of a released binary; development
+// builds, pseudo-versions, and builds with local changes are not releases
+func releaseVersion(value string) (semver, bool) {
+	if strings.HasSuffix(value, "+dirty") {
+		return semver{}, false
+	}
+	parsed, ok := parseVersion(value)
+	if !ok || pseudoVersion.MatchString(parsed.prerelease) {
+		return semver{}, false
+	}
+	return parsed, true
+}
+
+// satisfiesVersion reports whether current meets a constraint of
+// comma-separated comparisons such as ">= 1.4.0, < 2"; a bare version
+// must match exactly
+func satisfiesVersion(constraint, current string) (bool, error) {
+	currentVersion, ok := releaseVersion(current)
+	if !ok {
+		return false, fmt.Errorf("version %s is not a release", current)
+	}
+	for _, term := range strings.Split(constraint, ",") {
+		term = strings.TrimSpace(term)
+		op := "="
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(term, candidate) {
+				op = candidate
+				term = strings.TrimSpace(term[len(candidate):])
+				break
+			}
+		}
+		want, ok := parseVersion(term)
+		if !ok {
+			return false, fmt.Errorf("invalid required_version %q", constraint)
+		}
+		cmp := currentVersion.compare(want)
+		met := map[string]bool{
+			">=": cmp >= 0, "<=": cmp <= 0, "!=": cmp != 0,
+			">": cmp > 0, "<": cmp < 0, "=": cmp == 0,
+		}[op]
+		if !met {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// unknownConfigKeys lists the settings of a lintrc.yaml that LintConfig does
+// not have, such as those of rules added in a later release, as dotted paths
+func unknownConfigKeys(data []byte) ([]string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	return unknownKeys(root.Content[0], reflect.TypeOf(LintConfig{}), ""), nil
+}
+
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var unknown []string
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type)
+		yamlFields(t, fields)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			fieldType, ok := fields[key]
+			if !ok {
+				unknown = append(unknown, prefix+key)
+				continue
+			}
+			unknown = append(unknown, unknownKeys(node.Content[i+1], fieldType, prefix+key+".")...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			unknown = append(unknown, unknownKeys(node.Content[i+1], t.Elem(), prefix+node.Content[i].Value+".")...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			unknown = append(unknown, unknownKeys(item, t.Elem(), prefix)...)
+		}
+	}
+	return unknown
+}
+
+// unknownConfigRules lists the rule IDs that checklist and readiness items
+// require to pass but this version does not have
+func unknownConfigRules(config *LintConfig) []string {
+	items := append([]ChecklistItem{}, config.Checklist...)
+	for _, item := range config.Readiness.Items {
+		items = append(items, item.ChecklistItem)
+	}
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, item := range items {
+		for _, ruleID := range item.Passes {
+			if _, ok := lintRules[ruleID]; !ok && !seen[ruleID] {
+				seen[ruleID] = true
+				unknown = append(unknown, ruleID)
+			}
+		}
+	}
+	return unknown
+}
+
+// yamlFields collects the yaml keys of a struct, including inlined structs
+func yamlFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("yaml")
+		name, options, _ := strings.Cut(tag, ",")
+		switch {
+		case options == "inline":
+			yamlFields(field.Type, fields)
+		case name == "-" || !field.IsExported():
+		case name == "":
+			fields[strings.ToLower(field.Name)] = field.Type
+		default:
+			fields[name] = field.Type
+		}
+	}
+}
+
+// runVersion prints the version; with --check it also compares it with the
+// latest release and lintrc.yaml, and exits 1 if the config needs a newer
+// binary than this one
+func runVersion(args []string) {
+	check := false
+	for _, arg := range args {
+		if arg != "--check" {
+			log.Fatalf("Usage: clilint version [--check]")
+		}
+		check = true
+	}
+	current := toolVersion()
+	fmt.Printf("clilint %s\n", current)
+	if !check {
+		return
+	}
+	if !checkToolVersion(os.Stdout, current, latestRelease) {
+		exit(1)
+	}
+}
+
+// latestRelease returns the tag of the latest clilint release
+func latestRelease() (string, error) {
+	client, ctx := getGitHubClient(os.Getenv("GITHUB_TOKEN"))
+	release, _, err := client.Repositories.GetLatestRelease(ctx, releaseOwner, releaseRepo)
+	if err != nil {
+		return "", err
+	}
+	return release.GetTagName(), nil
+}
+
+// checkToolVersion reports on the running version and returns false if
+// lintrc.yaml requires another release or uses settings it does not know.
+// A newer release alone is only a notice, as is failing to look it up.
+func checkToolVersion(w io.Writer, current string, latest func() (string, error)) bool {
+	ok := true
+	currentVersion, isRelease := releaseVersion(current)
+
+	if tag, err := latest(); err != nil {
+		fmt.Fprintf(w, "⚠️  Could not look up the latest release: %v\n", err)
+	} else if latestVersion, valid := parseVersion(tag); valid && isRelease {
+		if currentVersion.compare(latestVersion) < 0 {
+			fmt.Fprintf(w, "⚠️  %s is outdated: the latest release is %s\n", current, tag)
+		} else {
+			fmt.Fprintf(w, "✅ %s is the latest release\n", current)
+		}
+	} else if valid {
+		fmt.Fprintf(w, "ℹ️  Development build; the latest release is %s\n", tag)
+	}
+
+	configPath, data, err := readLintConfig()
+	if err != nil {
+		fmt.Fprintf(w, "❌ %v\n", err)
+		return false
+	}
+	if data == nil {
+		return ok
+	}
+	baseDir := "."
+	if configPath != "inline" {
+		baseDir = filepath.Dir(configPath)
+	}
+	config, err := parseLintConfig(data, baseDir)
+	if err != nil {
+		fmt.Fprintf(w, "❌ %s: %v\n", configPath, err)
+		return false
+	}
+
+	if constraint := config.RequiredVersion; constraint != "" {
+		if !isRelease {
+			fmt.Fprintf(w, "⚠️  %s: required_version %q is not checked for development builds\n", configPath, constraint)
+		} else if met, err := satisfiesVersion(constraint, current); err != nil {
+			fmt.Fprintf(w, "❌ %s: %v\n", configPath, err)
+			ok = false
+		} else if !met {
+			fmt.Fprintf(w, "❌ %s requires clilint %s, but this is %s: update the version the workflow pins\n", configPath, constraint, current)
+			ok = false
+		} else {
+			fmt.Fprintf(w, "✅ %s satisfies required_version %q\n", current, constraint)
+		}
+	}
+
+	// Keys renamed by a migration are known under their new name
+	if upgraded, err := upgradeConfig(data); err == nil {
+		data = upgraded
+	}
+	unknown, err := unknownConfigKeys(data)
+	if err != nil {
+		fmt.Fprintf(w, "❌ %s: %v\n", configPath, err)
+		return false
+	}
+	if len(unknown) > 0 {
+		fmt.Fprintf(w, "❌ %s uses settings this version does not know, so their rules would not run: %s\n", configPath, strings.Join(unknown, ", "))
+		fmt.Fprintf(w, "   Update clilint, or fix the setting names if they are misspelled\n")
+		ok = false
+	}
+	if rules := unknownConfigRules(config); len(rules) > 0 {
+		fmt.Fprintf(w, "❌ %s references rules this version does not have: %s\n", configPath, strings.Join(rules, ", "))
+		ok = false
+	}
+	return ok
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSatisfiesVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		current    string
+		want       bool
+	}{
+		{">= 1.4.0", "v1.4.0", true},
+		{">= 1.4.0", "v1.3.9", false},
+		{">= 1.4", "v1.10.0", true},
+		{">= 1.4.0, < 2", "v2.0.0", false},
+		{">= 1.4.0, < 2", "v1.9.3", true},
+		{">= 1.4.0", "v1.4.0-rc.1", false},
+		{"1.4.2", "v1.4.2", true},
+		{"=1.4.2", "v1.4.3", false},
+		{"!= 1.4.2", "v1.4.3", true},
+		{"> 1.4", "v1.4.0", false},
+		{"<= 1.4", "v1.4.0", true},
+	}
+	for _, tt := range tests {
+		got, err := satisfiesVersion(tt.constraint, tt.current)
+		if err != nil {
+			t.Errorf("satisfiesVersion(%q, %q) error: %v", tt.constraint, tt.current, err)
+		} else if got != tt.want {
+			t.Errorf("satisfiesVersion(%q, %q) = %v, want %v", tt.constraint, tt.current, got, tt.want)
+		}
+	}
+
+	if _, err := satisfiesVersion(">= one", "v1.0.0"); err == nil {
+		t.Error("expected an invalid constraint to be an error")
+	}
+	for _, current := range []string{"devel+abc", "v0.0.0-20261016031930-241fd404ed58", "v1.4.0+dirty"} {
+		if _, err := satisfiesVersion(">= 1.0", current); err == nil {
+			t.Errorf("expected %s not to be treated as a release", current)
+		}
+	}
+}
+
+func TestUnknownConfigKeys(t *testing.T) {
+	data := []byte(`required_version: ">= 1.4"
+max_file_size: 1024
+healthcheck:
+  required: true
+  retries: 3
+profiles:
+  web:
+    require_host: true
+    max_attempts: 5
+readiness:
+  items:
+    - item: Writeup
+      exists: writeup.md
+      weight: 2
+      owner: alice
+mystery_rule:
+  enabled: true
+`)
+	got, err := unknownConfigKeys(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"healthcheck.retries", "profiles.web.max_attempts", "readiness.items.owner", "mystery_rule"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unknownConfigKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckToolVersion(t *testing.T) {
+	origConfig := inlineConfig
+	t.Cleanup(func() { inlineConfig = origConfig })
+	latest := func() (string, error) { return "v1.6.0", nil }
+
+	tests := []struct {
+		name    string
+		config  string
+		current string
+		latest  func() (string, error)
+		ok      bool
+		want    string
+	}{
+		{name: "up to date", config: "required_version: \">= 1.4\"\n", current: "v1.6.0", latest: latest, ok: true, want: "is the latest release"},
+		{name: "outdated but allowed", config: "required_version: \">= 1.4\"\n", current: "v1.5.0", latest: latest, ok: true, want: "the latest release is v1.6.0"},
+		{name: "outdated and required", config: "required_version: \">= 1.6\"\n", current: "v1.5.0", latest: latest, ok: false, want: "requires clilint >= 1.6"},
+		{name: "unknown setting", config: "image_size:\n  max_size: 1\nsbom:\n  required: true\n", current: "v1.6.0", latest: latest, ok: false, want: "does not know, so their rules would not run: sbom"},
+		{name: "unknown rule", config: "checklist:\n  - item: SBOM\n    passes: [state, sbom]\n", current: "v1.6.0", latest: latest, ok: false, want: "rules this version does not have: sbom"},
+		{name: "development build", config: "required_version: \">= 9\"\n", current: "devel+abc", latest: latest, ok: true, want: "not checked for development builds"},
+		{name: "offline", config: "max_file_size: 1\n", current: "v1.0.0", latest: func() (string, error) { return "", errors.New("no network") }, ok: true, want: "Could not look up the latest release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inlineConfig = tt.config
+			var buf bytes.Buffer
+			if ok := checkToolVersion(&buf, tt.current, tt.latest); ok != tt.ok {
+				t.Errorf("checkToolVersion() = %v, want %v\n%s", ok, tt.ok, buf.String())
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output should contain %q:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestExampleConfigKeysKnown(t *testing.T) {
+	data, err := os.ReadFile("lintrc.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown, err := unknownConfigKeys(data); err != nil || len(unknown) > 0 {
+		t.Errorf("lintrc.yaml has unknown settings %v (%v)", unknown, err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prunedDirs are never searched for challenges: VCS metadata and build
+// trees can hold thousands of files and never contain a challenge.yml
+var prunedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"dist":         true,
+
+	// Repositories cloned by the multi command
+	defaultRepoCacheDir: true,
+}
+
+// walkOptions controls the search for challenge.yml files
+type walkOptions struct {
+	// Ignore lists gitignore-style patterns relative to the walk root
+	Ignore []string
+	// MaxDepth limits how many directories below the root are searched, 0 means unlimited
+	MaxDepth int
+	// FollowSymlinks descends into symlinked directories
+	FollowSymlinks bool
+}
+
+// walkFlags holds the walk options given on the command line
+var walkFlags walkOptions
+
+// findChallengeFiles returns the paths of all challenge.yml files under rootDir
+func findChallengeFiles(rootDir string) ([]string, error) {
+	options := walkFlags
+	config, err := loadLintConfig()
+	if err != nil {
+		return walkChallengeFiles(rootDir, options)
+	}
+	options.Ignore = config.Ignore
+
+	paths, err := walkChallengeFiles(rootDir, options)
+	challenges := paths[:0]
+	for _, path := range paths {
+		if !isTemplateFile(path, config) {
+			challenges = append(challenges, path)
+		}
+	}
+	return challenges, err
+}
+
+// walkChallengeFiles searches rootDir for challenge.yml files, skipping pruned
+// directories, paths matching the ignore patterns, and directories deeper than
+// MaxDepth. Symlinked directories are followed once each when enabled; links
+// back into a directory already being walked are skipped.
+func walkChallengeFiles(rootDir string, options walkOptions) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+
+	// walk searches dir, reporting paths as if dir were located at linkPath
+	var walk func(dir, linkPath string) error
+	walk = func(dir, linkPath string) error {
+		return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, relErr := filepath.Rel(dir, path)
+			if relErr != nil || rel == "." {
+				return nil
+			}
+			diskPath := path
+			path = filepath.Join(linkPath, rel)
+			rel, _ = filepath.Rel(rootDir, path)
+			rel = filepath.ToSlash(rel)
+
+			if d.Type()&fs.ModeSymlink != 0 {
+				if !options.FollowSymlinks {
+					if d.Name() == "challenge.yml" && !isIgnored(rel, false, options.Ignore) {
+						paths = append(paths, path)
+					}
+					return nil
+				}
+				target, err := filepath.EvalSymlinks(diskPath)
+				if err != nil {
+					log.Printf("Warning: skipping broken symlink %s", path)
+					return nil
+				}
+				info, err := os.Stat(target)
+				if err != nil || !info.IsDir() {
+					if d.Name() == "challenge.yml" && !isIgnored(rel, false, options.Ignore) {
+						paths = append(paths, path)
+					}
+					return nil
+				}
+				if prunedDirs[d.Name()] || isIgnored(rel, true, options.Ignore) || exceedsDepth(rel, options.MaxDepth) {
+					return nil
+				}
+				linkParent, _ := filepath.EvalSymlinks(filepath.Dir(diskPath))
+				if linkParent == target || strings.HasPrefix(linkParent, target+string(filepath.Separator)) {
+					log.Printf("Warning: skipping symlink loop %s -> %s", path, target)
+					return nil
+				}
+				if seen[target] {
+					return nil
+				}
+				seen[target] = true
+				return walk(target, path)
+			}
+
+			if d.IsDir() {
+				if prunedDirs[d.Name()] || isIgnored(rel, true, options.Ignore) || exceedsDepth(rel, options.MaxDepth) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if d.Name() == "challenge.yml" && !isIgnored(rel, false, options.Ignore) {
+				paths = append(paths, path)
+			}
+
+			return nil
+		})
+	}
+
+	root, err := filepath.EvalSymlinks(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	seen[root] = true
+	err = walk(rootDir, rootDir)
+
+	return dedupeRealPaths(paths), err
+}
+
+// dedupeRealPaths drops paths that resolve to a file already listed, which
+// happens when a followed symlink points into the walked tree
+func dedupeRealPaths(paths []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, path := range paths {
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			real = path
+		}
+		if seen[real] {
+			continue
+		}
+		seen[real] = true
+		unique = append(unique, path)
+	}
+	return unique
+}
+
+// exceedsDepth reports whether a directory at relPath is deeper than maxDepth
+func exceedsDepth(relPath string, maxDepth int) bool {
+	return maxDepth > 0 && strings.Count(relPath, "/")+1 > maxDepth
+}
+
+// isIgnored reports whether relPath matches any of the gitignore-style
+// patterns. Patterns ending in '/' only match directories; backslashes are
+// treated as separators so Windows-style patterns behave the same.
+func isIgnored(relPath string, isDir bool, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = slashPath(pattern)
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		if matchGitPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSymlink reports whether path is a symbolic link, without following it
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWalkChallengeFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, dir := range []string{
+		"web/chall",
+		"web/chall/node_modules/pkg",
+		"pwn/chall/dist",
+		"pwn/chall/.git",
+		"misc/wip/chall",
+		"crypto/deep/nested/chall",
+	} {
+		if err := os.MkdirAll(filepath.Join(tempDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, dir, "challenge.yml"), []byte("name: chall\n"), 0644); err != nil {
+			t.Fatalf("Failed to create challenge.yml: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		ignore []string
+		depth  int
+		want   []string
+	}{
+		{
+			name: "prunes build and VCS trees",
+			want: []string{"crypto/deep/nested/chall", "misc/wip/chall", "web/chall"},
+		},
+		{
+			name:   "ignore patterns",
+			ignore: []string{"wip/", "crypto/**"},
+			want:   []string{"web/chall"},
+		},
+		{
+			name:  "max depth",
+			depth: 3,
+			want:  []string{"misc/wip/chall", "web/chall"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := walkChallengeFiles(tempDir, walkOptions{Ignore: tt.ignore, MaxDepth: tt.depth})
+			if err != nil {
+				t.Fatalf("walkChallengeFiles failed: %v", err)
+			}
+			var got []string
+			for _, path := range paths {
+				rel, _ := filepath.Rel(tempDir, filepath.Dir(path))
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("walkChallengeFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkChallengeFilesSymlinks(t *testing.T) {
+	tempDir := t.TempDir()
+	shared := filepath.Join(tempDir, "shared", "chall")
+	if err := os.MkdirAll(shared, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(shared, "challenge.yml"), []byte("name: chall\n"), 0644); err != nil {
+		t.Fatalf("Failed to create challenge.yml: %v", err)
+	}
+	root := filepath.Join(tempDir, "event")
+	if err := os.MkdirAll(filepath.Join(root, "web"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	// A linked challenge, a loop back to the root and a dangling link
+	for link, target := range map[string]string{
+		filepath.Join(root, "web", "chall"): shared,
+		filepath.Join(root, "web", "loop"):  root,
+		filepath.Join(root, "web", "gone"):  filepath.Join(tempDir, "missing"),
+	} {
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("Symlinks not supported: %v", err)
+		}
+	}
+
+	paths, err := walkChallengeFiles(root, walkOptions{})
+	if err != nil {
+		t.Fatalf("walkChallengeFiles failed: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("Expected symlinked directories not to be followed, got %v", paths)
+	}
+
+	paths, err = walkChallengeFiles(root, walkOptions{FollowSymlinks: true})
+	if err != nil {
+		t.Fatalf("walkChallengeFiles failed: %v", err)
+	}
+	want := []string{filepath.Join(root, "web", "chall", "challenge.yml")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("walkChallengeFiles() = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// syncEnabled is set by --sync; results are only pushed to the wiki when set
+var syncEnabled bool
+
+// notionAPIURL is the Notion API endpoint, replaced in tests
+var notionAPIURL = "https://api.notion.com"
+
+// notionVersion is the Notion API version the requests are written against
+const notionVersion = "2022-06-28"
+
+// notionTextLimit is the length limit of a Notion rich text value
+const notionTextLimit = 2000
+
+// SyncRule configures where --sync keeps the status of each challenge
+type SyncRule struct {
+	Notion     NotionSync     `yaml:"notion"`
+	Confluence ConfluenceSync `yaml:"confluence"`
+}
+
+// NotionSync upserts one row per challenge into a Notion database with the
+// properties Name (title), File, Findings (text), Status (select), and
+// Errors, Warnings, Readiness (number). The token is read from NOTION_TOKEN.
+type NotionSync struct {
+	DatabaseID string `yaml:"database_id"`
+}
+
+// ConfluenceSync upserts one page per challenge into a Confluence space.
+// Credentials are read from CONFLUENCE_USER and CONFLUENCE_TOKEN (basic
+// auth), or CONFLUENCE_TOKEN alone as a personal access token.
+type ConfluenceSync struct {
+	BaseURL     string `yaml:"base_url"` // e.g. https://example.atlassian.net/wiki
+	Space       string `yaml:"space"`
+	ParentID    string `yaml:"parent_id"`    // optional parent page of new pages
+	TitlePrefix string `yaml:"title_prefix"` // prepended to challenge names
+}
+
+// challengeStatus is the synced state of one challenge
+type challengeStatus struct {
+	File      string
+	Name      string
+	Status    string
+	Errors    int
+	Warnings  int
+	Readiness int
+	Findings  []string
+}
+
+// syncWiki pushes the status of each challenge to the configured wikis.
+// Failures are reported as warnings so that an unreachable wiki does not
+// fail the lint run. Only runs on the default branch are synced, so that the
+// wiki never shows unmerged changes.
+func syncWiki(results []LintResult) {
+	if !syncEnabled {
+		return
+	}
+	if !onDefaultBranch() {
+		log.Printf("Warning: --sync is ignored outside the default branch")
+		return
+	}
+	config, err := loadLintConfig()
+	if err != nil {
+		log.Printf("Warning: failed to sync wiki: %v", err)
+		return
+	}
+	config = withoutReadinessProbes(config)
+
+	statuses := make([]challengeStatus, 0, len(results))
+	for _, result := range results {
+		statuses = append(statuses, statusOf(result, config))
+	}
+
+	rule := config.Sync
+	if rule.Notion.DatabaseID == "" && rule.Confluence.Space == "" {
+		log.Printf("Warning: --sync is set but lintrc.yaml configures neither sync.notion nor sync.confluence")
+		return
+	}
+	if rule.Notion.DatabaseID != "" {
+		if err := syncNotion(httpClient, rule.Notion, os.Getenv("NOTION_TOKEN"), statuses); err != nil {
+			log.Printf("Warning: failed to sync Notion: %v", err)
+		}
+	}
+	if rule.Confluence.Space != "" {
+		if err := syncConfluence(httpClient, rule.Confluence, statuses); err != nil {
+			log.Printf("Warning: failed to sync Confluence: %v", err)
+		}
+	}
+}
+
+// onDefaultBranch reports whether a GitHub Actions run is for a push to the
+// repository's default branch, read from the event payload. Runs outside
+// GitHub Actions are the organizers' own and always count.
+func onDefaultBranch() bool {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return true
+	}
+	data, err := os.ReadFile(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return false
+	}
+	var event struct {
+		Repository struct {
+			DefaultBranch string `json:"default_branch"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil || event.Repository.DefaultBranch == "" {
+		return false
+	}
+	return os.Getenv("GITHUB_REF") == "refs/heads/"+event.Repository.DefaultBranch
+}
+
+// withoutReadinessProbes returns a copy of the config without the readiness
+// items that run a command or request a URL: syncing never runs code from
+// the checkout or makes requests other than to the wiki
+func withoutReadinessProbes(config *LintConfig) *LintConfig {
+	copied := *config
+	copied.Readiness.Items = nil
+	for _, item := range config.Readiness.Items {
+		if item.Command == "" && item.URL == "" {
+			copied.Readiness.Items = append(copied.Readiness.Items, item)
+		}
+	}
+	return &copied
+}
+
+// statusOf summarizes a lint result and its readiness score
+func statusOf(result LintResult, config *LintConfig) challengeStatus {
+	status := challengeStatus{
+		File:      result.File,
+		Name:      result.Name,
+		Status:    "Passing",
+		Errors:    len(result.Errors),
+		Warnings:  len(result.Warnings),
+		Readiness: scoreReadiness(result, config).Score,
+	}
+	if status.Name == "" {
+		status.Name = result.File
+	}
+	if status.Errors > 0 {
+		status.Status = "Failing"
+	}
+	for _, finding := range result.Errors {
+		status.Findings = append(status.Findings, fmt.Sprintf("❌ [%s] %s", finding.RuleID, finding.Message))
+	}
+	for _, finding := range result.Warnings {
+		status.Findings = append(status.Findings, fmt.Sprintf("⚠️ [%s] %s", finding.RuleID, finding.Message))
+	}
+	return status
+}
+
+// syncNotion updates the database row whose File matches each challenge,
+// or creates it
+func syncNotion(client *http.Client, rule NotionSync, token string, statuses []challengeStatus) error {
+	if token == "" {
+		return fmt.Errorf("NOTION_TOKEN is not set")
+	}
+	request := func(method, path string, body interface{}, out interface{}) error {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(method, notionAPIURL+path, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Notion-Version", notionVersion)
+		req.Header.Set("Content-Type", "application/json")
+		return doJSON(client, req, out)
+	}
+
+	for _, status := range statuses {
+		var query struct {
+			Results []struct {
+				ID string `json:"id"`
+			} `json:"results"`
+		}
+		filter := map[string]interface{}{
+			"filter": map[string]interface{}{"property": "File", "rich_text": map[string]string{"equals": status.File}},
+		}
+		if err := request(http.MethodPost, "/v1/databases/"+url.PathEscape(rule.DatabaseID)+"/query", filter, &query); err != nil {
+			return err
+		}
+
+		properties := notionProperties(status)
+		if len(query.Results) > 0 {
+			err := request(http.MethodPatch, "/v1/pages/"+url.PathEscape(query.Results[0].ID), map[string]interface{}{"properties": properties}, nil)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		page := map[string]interface{}{
+			"parent":     map[string]string{"database_id": rule.DatabaseID},
+			"properties": properties,
+		}
+		if err := request(http.MethodPost, "/v1/pages", page, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func notionProperties(status challengeStatus) map[string]interface{} {
+	text := func(content string) []map[string]interface{} {
+		if len([]rune(content)) > notionTextLimit {
+			content = string([]rune(content)[:notionTextLimit-1]) + "…"
+		}
+		return []map[string]interface{}{{"text": map[string]string{"content": content}}}
+	}
+	return map[string]interface{}{
+		"Name":      map[string]interface{}{"title": text(status.Name)},
+		"File":      map[string]interface{}{"rich_text": text(status.File)},
+		"Status":    map[string]interface{}{"select": map[string]string{"name": status.Status}},
+		"Errors":    map[string]interface{}{"number": status.Errors},
+		"Warnings":  map[string]interface{}{"number": status.Warnings},
+		"Readiness": map[string]interface{}{"number": status.Readiness},
+		"Findings":  map[string]interface{}{"rich_text": text(strings.Join(status.Findings, "\n"))},
+	}
+}
+
+// syncConfluence updates the page titled after each challenge, or creates it
+func syncConfluence(client *http.Client, rule ConfluenceSync, statuses []challengeStatus) error {
+	if rule.BaseURL == "" {
+		return fmt.Errorf("sync.confluence.base_url is not set")
+	}
+	user, token := os.Getenv("CONFLUENCE_USER"), os.Getenv("CONFLUENCE_TOKEN")
+	if token == "" {
+		return fmt.Errorf("CONFLUENCE_TOKEN is not set")
+	}
+	base := strings.TrimSuffix(rule.BaseURL, "/") + "/rest/api/content"
+	request := func(method, endpoint string, body interface{}, out interface{}) error {
+		var reader io.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, endpoint, reader)
+		if err != nil {
+			return err
+		}
+		if user != "" {
+			req.SetBasicAuth(user, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return doJSON(client, req, out)
+	}
+
+	for _, status := range statuses {
+		title := rule.TitlePrefix + status.Name
+		var existing struct {
+			Results []struct {
+				ID      string `json:"id"`
+				Version struct {
+					Number int `json:"number"`
+				} `json:"version"`
+			} `json:"results"`
+		}
+		query := url.Values{"spaceKey": {rule.Space}, "title": {title}, "expand": {"version"}}
+		if err := request(http.MethodGet, base+"?"+query.Encode(), nil, &existing); err != nil {
+			return err
+		}
+
+		page := map[string]interface{}{
+			"type":  "page",
+			"title": title,
+			"space": map[string]string{"key": rule.Space},
+			"body": map[string]interface{}{
+				"storage": map[string]string{"value": confluenceBody(status), "representation": "storage"},
+			},
+		}
+		if len(existing.Results) > 0 {
+			current := existing.Results[0]
+			page["id"] = current.ID
+			page["version"] = map[string]int{"number": current.Version.Number + 1}
+			if err := request(http.MethodPut, base+"/"+url.PathEscape(current.ID), page, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if rule.ParentID != "" {
+			page["ancestors"] = []map[string]string{{"id": rule.ParentID}}
+		}
+		if err := request(http.MethodPost, base, page, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confluenceBody renders a challenge status in Confluence storage format
+func confluenceBody(status challengeStatus) string {
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("<p><strong>File:</strong> <code>%s</code></p>", html.EscapeString(status.File)))
+	body.WriteString(fmt.Sprintf("<p><strong>Status:</strong> %s (%d error(s), %d warning(s))</p>", status.Status, status.Errors, status.Warnings))
+	body.WriteString(fmt.Sprintf("<p><strong>Readiness:</strong> %d</p>", status.Readiness))
+	if len(status.Findings) > 0 {
+		body.WriteString("<ul>")
+		for _, finding := range status.Findings {
+			body.WriteString("<li>" + html.EscapeString(finding) + "</li>")
+		}
+		body.WriteString("</ul>")
+	}
+	return body.String()
+}
+
+// doJSON sends a request and decodes a JSON response into out, if not nil
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: HTTP %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStatusOf(t *testing.T) {
+	result := LintResult{
+		File:     "web/login/challenge.yml",
+		Name:     "login",
+		Errors:   []Finding{{RuleID: "flags", Message: "Flag format is invalid"}},
+		Warnings: []Finding{{RuleID: "tags", Message: "Tag 'Web' should be lowercase"}},
+	}
+	status := statusOf(result, &LintConfig{})
+	if status.Status != "Failing" || status.Errors != 1 || status.Warnings != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	wantFindings := []string{"❌ [flags] Flag format is invalid", "⚠️ [tags] Tag 'Web' should be lowercase"}
+	if !reflect.DeepEqual(status.Findings, wantFindings) {
+		t.Errorf("findings = %q, want %q", status.Findings, wantFindings)
+	}
+
+	if status := statusOf(LintResult{File: "misc/quiz/challenge.yml"}, &LintConfig{}); status.Status != "Passing" || status.Name != "misc/quiz/challenge.yml" {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestOnDefaultBranch(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(eventPath, []byte(`{"repository": {"default_branch": "main"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+
+	tests := []struct {
+		actions string
+		ref     string
+		want    bool
+	}{
+		{actions: "", ref: "", want: true},
+		{actions: "true", ref: "refs/heads/main", want: true},
+		{actions: "true", ref: "refs/heads/feature", want: false},
+		{actions: "true", ref: "refs/pull/7/merge", want: false},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITHUB_ACTIONS", tt.actions)
+		t.Setenv("GITHUB_REF", tt.ref)
+		if got := onDefaultBranch(); got != tt.want {
+			t.Errorf("GITHUB_ACTIONS=%q GITHUB_REF=%q: onDefaultBranch() = %v, want %v", tt.actions, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestWithoutReadinessProbes(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	config := &LintConfig{Readiness: ReadinessRule{Items: []ReadinessItem{
+		{ChecklistItem: ChecklistItem{Item: "solver"}, Command: "touch " + marker},
+		{ChecklistItem: ChecklistItem{Item: "instance"}, URL: "http://127.0.0.1:1/"},
+		{ChecklistItem: ChecklistItem{Item: "writeup", Exists: "writeup.md"}},
+	}}}
+
+	probeless := withoutReadinessProbes(config)
+	if len(probeless.Readiness.Items) != 1 || probeless.Readiness.Items[0].Item != "writeup" {
+		t.Errorf("items = %+v", probeless.Readiness.Items)
+	}
+	if len(config.Readiness.Items) != 3 {
+		t.Error("the original config was modified")
+	}
+	statusOf(LintResult{File: filepath.Join(t.TempDir(), "challenge.yml")}, probeless)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("a readiness command ran during sync")
+	}
+}
+
+func TestSyncNotion(t *testing.T) {
+	var requests []string
+	var updated, created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer notion-token" || r.Header.Get("Notion-Version") != notionVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.URL.Path == "/v1/databases/db-1/query":
+			filter := body["filter"].(map[string]interface{})["rich_text"].(map[string]interface{})
+			if filter["equals"] == "web/login/challenge.yml" {
+				io.WriteString(w, `{"results":[{"id":"page-1"}]}`)
+				return
+			}
+			io.WriteString(w, `{"results":[]}`)
+		case r.Method == http.MethodPatch:
+			updated = body
+			io.WriteString(w, "{}")
+		default:
+			created = body
+			io.WriteString(w, "{}")
+		}
+	}))
+	defer server.Close()
+
+	origURL := notionAPIURL
+	defer func() { notionAPIURL = origURL }()
+	notionAPIURL = server.URL
+
+	statuses := []challengeStatus{
+		{File: "web/login/challenge.yml", Name: "login", Status: "Failing", Errors: 1, Readiness: 80, Findings: []string{"❌ [flags] Flag format is invalid"}},
+		{File: "misc/quiz/challenge.yml", Name: "quiz", Status: "Passing", Readiness: 100},
+	}
+	if err := syncNotion(http.DefaultClient, NotionSync{DatabaseID: "db-1"}, "notion-token", statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{
+		"POST /v1/databases/db-1/query",
+		"PATCH /v1/pages/page-1",
+		"POST /v1/databases/db-1/query",
+		"POST /v1/pages",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %v, want %v", requests, wantRequests)
+	}
+	properties := updated["properties"].(map[string]interface{})
+	if properties["Status"].(map[string]interface{})["select"].(map[string]interface{})["name"] != "Failing" ||
+		properties["Readiness"].(map[string]interface{})["number"] != float64(80) {
+		t.Errorf("unexpected properties %v", properties)
+	}
+	if created["parent"].(map[string]interface{})["database_id"] != "db-1" {
+		t.Errorf("unexpected created page %v", created)
+	}
+
+	if err := syncNotion(http.DefaultClient, NotionSync{DatabaseID: "db-1"}, "", statuses); err == nil {
+		t.Error("expected an error without NOTION_TOKEN")
+	}
+}
+
+func TestNotionPropertiesTruncatesFindings(t *testing.T) {
+	status := challengeStatus{Findings: []string{strings.Repeat("x", notionTextLimit+10)}}
+	findings := notionProperties(status)["Findings"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	content := findings[0]["text"].(map[string]string)["content"]
+	if n := len([]rune(content)); n != notionTextLimit {
+		t.Errorf("findings are %d characters, want %d", n, notionTextLimit)
+	}
+}
+
+func TestSyncConfluence(t *testing.T) {
+	var requests []string
+	var pages []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "bot@example.com" || token != "confluence-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("spaceKey") == "CTF" && r.URL.Query().Get("title") == "[chall] login" {
+				io.WriteString(w, `{"results":[{"id":"42","version":{"number":3}}]}`)
+				return
+			}
+			io.WriteString(w, `{"results":[]}`)
+			return
+		}
+		var page map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&page)
+		pages = append(pages, page)
+		io.WriteString(w, "{}")
+	}))
+	defer server.Close()
+
+	t.Setenv("CONFLUENCE_USER", "bot@example.com")
+	t.Setenv("CONFLUENCE_TOKEN", "confluence-token")
+	rule := ConfluenceSync{BaseURL: server.URL + "/wiki/", Space: "CTF", ParentID: "7", TitlePrefix: "[chall] "}
+	statuses := []challengeStatus{
+		{File: "web/login/challenge.yml", Name: "login", Status: "Failing", Errors: 1, Findings: []string{"❌ [flags] <flag> is invalid"}},
+		{File: "misc/quiz/challenge.yml", Name: "quiz", Status: "Passing"},
+	}
+	if err := syncConfluence(http.DefaultClient, rule, statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{
+		"GET /wiki/rest/api/content",
+		"PUT /wiki/rest/api/content/42",
+		"GET /wiki/rest/api/content",
+		"POST /wiki/rest/api/content",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Fatalf("requests = %v, want %v", requests, wantRequests)
+	}
+	if version := pages[0]["version"].(map[string]interface{})["number"]; version != float64(4) {
+		t.Errorf("updated version = %v, want 4", version)
+	}
+	body := pages[0]["body"].(map[string]interface{})["storage"].(map[string]interface{})["value"].(string)
+	if !strings.Contains(body, "<li>❌ [flags] &lt;flag&gt; is invalid</li>") {
+		t.Errorf("findings are not escaped in %s", body)
+	}
+	if pages[1]["title"] != "[chall] quiz" || pages[1]["ancestors"] == nil {
+		t.Errorf("unexpected created page %v", pages[1])
+	}
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// topLevelField is a key/value pair of the root mapping of a YAML document
+type topLevelField struct {
+	Key   *yaml.Node
+	Value *yaml.Node
+	// EndLine is the last line (1-based) belonging to the value
+	EndLine int
+}
+
+// findTopLevelField locates a key of the root mapping, returning nil if absent
+func findTopLevelField(data []byte, key string) (*topLevelField, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != key {
+			continue
+		}
+		field := &topLevelField{Key: root.Content[i], Value: root.Content[i+1]}
+
+		// The last field ends with the document, not with the file
+		endLine := documentEnd(lines, root.Line)
+		if i+2 < len(root.Content) {
+			endLine = root.Content[i+2].Line - 1
+		}
+		// Trailing blank and comment-only lines belong to the next key
+		for endLine > field.Value.Line && isBlankOrComment(lines[endLine-1]) {
+			endLine--
+		}
+		if endLine < field.Key.Line {
+			endLine = field.Key.Line
+		}
+		field.EndLine = endLine
+		return field, nil
+	}
+	return nil, nil
+}
+
+// documentEnd returns the last line (1-based) of the document whose root
+// starts at line start: the line before the next "---" or "..." marker, or
+// the last line of the file
+func documentEnd(lines []string, start int) int {
+	for i := start; i < len(lines); i++ {
+		if isDocumentMarker(lines[i]) {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+func isDocumentMarker(line string) bool {
+	line = strings.TrimRight(line, "\r")
+	for _, marker := range []string{"---", "..."} {
+		if rest, ok := strings.CutPrefix(line, marker); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// isSingleLine reports whether the field's key and value fit on one line
+func (f *topLevelField) isSingleLine() bool {
+	return f.Value.Kind == yaml.ScalarNode && f.EndLine == f.Key.Line &&
+		f.Value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0
+}
+
+// setTopLevelScalar sets a root-level key to a scalar YAML value while
+// leaving every other byte of the document untouched: comments, blank lines,
+// quoting, line endings, and the other documents of the file. Missing keys
+// are appended to the end of the first document. An anchor on the old value
+// is kept, so that aliases of it stay valid.
+func setTopLevelScalar(data []byte, key, value string) ([]byte, error) {
+	updated, err := spliceTopLevelScalar(data, key, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEdit(data, updated, key, value); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func spliceTopLevelScalar(data []byte, key, value string) ([]byte, error) {
+	field, err := findTopLevelField(data, key)
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(data)
+	eol := "\n"
+	if strings.Contains(text, "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(text, "\n")
+	if field == nil {
+		entry := key + ": " + value + strings.TrimSuffix(eol, "\n")
+		if end, ok := firstDocumentEnd(data, lines); ok {
+			updated := append([]string{}, lines[:end]...)
+			updated = append(updated, entry)
+			updated = append(updated, lines[end:]...)
+			return []byte(strings.Join(updated, "\n")), nil
+		}
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += eol
+		}
+		return []byte(text + entry + "\n"), nil
+	}
+
+	if field.Value.Anchor != "" {
+		value = "&" + field.Value.Anchor + " " + value
+	}
+	keyLine, cr := cutCR(lines[field.Key.Line-1])
+
+	if field.Value.Line == field.Key.Line && field.EndLine == field.Key.Line &&
+		field.Value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		start := runeOffset(keyLine, field.Value.Column-1)
+		end := scalarEnd(keyLine, start, field.Value.Style)
+		if field.Value.Kind != yaml.ScalarNode && field.Value.Kind != yaml.AliasNode {
+			end = flowEnd(keyLine, start)
+		}
+		if start > 0 && keyLine[start-1] == ':' {
+			// Empty value right after the colon
+			value = " " + value
+		}
+		lines[field.Key.Line-1] = keyLine[:start] + value + keyLine[end:] + cr
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+
+	// Block or multi-line value: rewrite the key line, keeping its comment,
+	// and drop the value lines
+	colon := strings.Index(keyLine[runeOffset(keyLine, field.Key.Column-1):], ":")
+	if colon < 0 {
+		return nil, fmt.Errorf("cannot locate ':' after key '%s'", key)
+	}
+	prefix := keyLine[:runeOffset(keyLine, field.Key.Column-1)+colon+1]
+	if comment := keyLineComment(keyLine[len(prefix):]); comment != "" {
+		value += " " + comment
+	}
+	replaced := append([]string{}, lines[:field.Key.Line-1]...)
+	replaced = append(replaced, prefix+" "+value+cr)
+	replaced = append(replaced, lines[field.EndLine:]...)
+	return []byte(strings.Join(replaced, "\n")), nil
+}
+
+// firstDocumentEnd returns the index in lines of the marker ending the
+// first document of a multi-document file
+func firstDocumentEnd(data []byte, lines []string) (int, bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0, false
+	}
+	end := documentEnd(lines, doc.Content[0].Line)
+	return end, end < len(lines)
+}
+
+// cutCR splits the carriage return of a CRLF line off the line
+func cutCR(line string) (string, string) {
+	if trimmed, ok := strings.CutSuffix(line, "\r"); ok {
+		return trimmed, "\r"
+	}
+	return line, ""
+}
+
+// keyLineComment returns the comment after the colon of a key whose value
+// starts on the next line, skipping anchor and tag properties, or ""
+func keyLineComment(rest string) string {
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		switch {
+		case strings.HasPrefix(rest, "#"):
+			return strings.TrimRight(rest, " \t")
+		case strings.HasPrefix(rest, "&") || strings.HasPrefix(rest, "!"):
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				return ""
+			}
+			rest = rest[end:]
+		default:
+			// Nothing, or the first line of a multi-line scalar
+			return ""
+		}
+	}
+}
+
+// checkEdit makes sure an edit of a root-level key changed nothing but that
+// field: the lines outside it must be byte-for-byte the same, and with a
+// scalar value, the key must now hold the value. An edit that fails the
+// check is not written, so that no comment is ever lost.
+func checkEdit(old, updated []byte, key, value string) error {
+	oldRest, err := linesOutsideField(old, key)
+	if err != nil {
+		return err
+	}
+	newRest, err := linesOutsideField(updated, key)
+	if err != nil {
+		return fmt.Errorf("editing '%s' would break the YAML: %v", key, err)
+	}
+	if oldRest != newRest {
+		return fmt.Errorf("editing '%s' would change other lines of the file; edit it by hand", key)
+	}
+	if value == "" {
+		return nil
+	}
+
+	field, err := findTopLevelField(updated, key)
+	if err != nil || field == nil {
+		return fmt.Errorf("editing '%s' would lose the key", key)
+	}
+	var got, want interface{}
+	if err := field.Value.Decode(&got); err != nil {
+		return fmt.Errorf("editing '%s' would break the YAML: %v", key, err)
+	}
+	if err := yaml.Unmarshal([]byte(value), &want); err != nil {
+		return fmt.Errorf("invalid value for '%s': %v", key, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		return fmt.Errorf("editing '%s' would not set it to %s; edit it by hand", key, value)
+	}
+	return nil
+}
+
+// linesOutsideField returns the text of a document without the lines of a
+// root-level key, and without its final newline
+func linesOutsideField(data []byte, key string) (string, error) {
+	field, err := findTopLevelField(data, key)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if field == nil {
+		return text, nil
+	}
+	lines := strings.Split(text, "\n")
+	end := min(field.EndLine, len(lines))
+	rest := append([]string{}, lines[:field.Key.Line-1]...)
+	rest = append(rest, lines[end:]...)
+	// Without a field at the end, the line before it ends the text
+	return strings.TrimSuffix(strings.Join(rest, "\n"), "\r"), nil
+}
+
+// appendToTopLevelSequence adds an item to a root-level sequence, keeping the
+// layout of block sequences. Missing, empty and flow sequences are rewritten
+// as a flow sequence.
+func appendToTopLevelSequence(data []byte, key, item string) ([]byte, error) {
+	field, err := findTopLevelField(data, key)
+	if err != nil {
+		return nil, err
+	}
+	quoted := strconv.Quote(item)
+
+	if field != nil && field.Value.Kind == yaml.SequenceNode && field.Value.Style&yaml.FlowStyle == 0 && len(field.Value.Content) > 0 {
+		lines := strings.Split(string(data), "\n")
+		first := lines[field.Value.Content[0].Line-1]
+		dash := strings.Index(first, "-")
+		if dash < 0 {
+			return nil, fmt.Errorf("cannot locate '-' of the first item of '%s'", key)
+		}
+		_, cr := cutCR(lines[field.EndLine-1])
+		entry := first[:dash] + "- " + quoted + cr
+		updated := append([]string{}, lines[:field.EndLine]...)
+		updated = append(updated, entry)
+		updated = append(updated, lines[field.EndLine:]...)
+		result := []byte(strings.Join(updated, "\n"))
+		if err := checkEdit(data, result, key, ""); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Aliased sequences are copied so that the anchor's other users keep it
+	var items []string
+	if value := resolveAlias(fieldValue(field)); value != nil && value.Kind == yaml.SequenceNode {
+		for _, node := range value.Content {
+			items = append(items, strconv.Quote(resolveAlias(node).Value))
+		}
+	}
+	items = append(items, quoted)
+	return setTopLevelScalar(data, key, "["+strings.Join(items, ", ")+"]")
+}
+
+func fieldValue(field *topLevelField) *yaml.Node {
+	if field == nil {
+		return nil
+	}
+	return field.Value
+}
+
+// fieldLineAfter returns the line number and the rewritten text of the key
+// line after setting the value, if the change is confined to that single line
+func fieldLineAfter(data []byte, key, value string) (int, string, bool) {
+	field, err := findTopLevelField(data, key)
+	if err != nil || field == nil || !field.isSingleLine() || field.Value.Line != field.Key.Line {
+		return 0, "", false
+	}
+	updated, err := setTopLevelScalar(data, key, value)
+	if err != nil {
+		return 0, "", false
+	}
+	return field.Key.Line, strings.Split(string(updated), "\n")[field.Key.Line-1], true
+}
+
+// runeOffset converts a 0-based character column to a byte offset in line
+func runeOffset(line string, column int) int {
+	count := 0
+	for offset := range line {
+		if count == column {
+			return offset
+		}
+		count++
+	}
+	return len(line)
+}
+
+// flowEnd returns the byte offset just past the flow collection starting at start
+func flowEnd(line string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(line)
+}
+
+// scalarEnd returns the byte offset just past the scalar starting at start,
+// excluding any trailing whitespace and comment
+func scalarEnd(line string, start int, style yaml.Style) int {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				return i + 1
+			}
+		}
+		return len(line)
+	case style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+		return len(line)
+	}
+
+	end := len(line)
+	if comment := strings.Index(line[start:], " #"); comment >= 0 {
+		end = start + comment
+	}
+	return start + len(strings.TrimRight(line[start:end], " \t"))
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetTopLevelScalar(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		value string
+		want  string
+	}{
+		{
+			name:  "plain scalar with comment",
+			input: "name: \"chall\" # 名前\nstate: hidden   # not yet\nversion: \"0.1\"\n",
+			key:   "state",
+			value: "visible",
+			want:  "name: \"chall\" # 名前\nstate: visible   # not yet\nversion: \"0.1\"\n",
+		},
+		{
+			name:  "double quoted scalar",
+			input: "version: \"0.2\"\nstate: visible\n",
+			key:   "version",
+			value: `"0.1"`,
+			want:  "version: \"0.1\"\nstate: visible\n",
+		},
+		{
+			name:  "single quoted scalar with escaped quote",
+			input: "state: 'it''s hidden' # comment\n",
+			key:   "state",
+			value: "visible",
+			want:  "state: visible # comment\n",
+		},
+		{
+			name:  "block mapping replaced",
+			input: "image:\n  name: chall\n  build: .\n\n# connection\nhost: null\n",
+			key:   "image",
+			value: "null",
+			want:  "image: null\n\n# connection\nhost: null\n",
+		},
+		{
+			name:  "flow mapping replaced",
+			input: "image: {name: chall} # container\nhost: null\n",
+			key:   "image",
+			value: "null",
+			want:  "image: null # container\nhost: null\n",
+		},
+		{
+			name:  "empty value",
+			input: "state:\nversion: \"0.1\"\n",
+			key:   "state",
+			value: "visible",
+			want:  "state: visible\nversion: \"0.1\"\n",
+		},
+		{
+			name:  "missing key appended",
+			input: "name: chall\n# trailing comment",
+			key:   "state",
+			value: "visible",
+			want:  "name: chall\n# trailing comment\nstate: visible\n",
+		},
+		{
+			name:  "CRLF line endings",
+			input: "name: chall\r\nstate: hidden # wip\r\nimage:\r\n  name: chall\r\n",
+			key:   "state",
+			value: "visible",
+			want:  "name: chall\r\nstate: visible # wip\r\nimage:\r\n  name: chall\r\n",
+		},
+		{
+			name:  "CRLF key appended",
+			input: "name: chall\r\n",
+			key:   "state",
+			value: "visible",
+			want:  "name: chall\r\nstate: visible\r\n",
+		},
+		{
+			name:  "missing key appended to the first document",
+			input: "name: chall\n# shared\n---\nstate: hidden\n",
+			key:   "version",
+			value: `"0.1"`,
+			want:  "name: chall\n# shared\nversion: \"0.1\"\n---\nstate: hidden\n",
+		},
+		{
+			name:  "block value before the next document",
+			input: "name: chall\nimage:\n  name: chall\n---\nvalue: 100\n",
+			key:   "image",
+			value: "null",
+			want:  "name: chall\nimage: null\n---\nvalue: 100\n",
+		},
+		{
+			name:  "comment on the key line of a block value",
+			input: "image: &img # built by CI\n  name: chall\nhost: null\n",
+			key:   "image",
+			value: "null",
+			want:  "image: &img null # built by CI\nhost: null\n",
+		},
+		{
+			name:  "anchored scalar keeps its anchor",
+			input: "state: &state hidden\nnote: *state\n",
+			key:   "state",
+			value: "visible",
+			want:  "state: &state visible\nnote: *state\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := setTopLevelScalar([]byte(tt.input), tt.key, tt.value)
+			if err != nil {
+				t.Fatalf("setTopLevelScalar failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setTopLevelScalar() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldLineAfter(t *testing.T) {
+	data := []byte("name: chall\nstate: hidden # wip\nimage:\n  name: chall\n")
+
+	line, text, ok := fieldLineAfter(data, "state", "visible")
+	if !ok || line != 2 || text != "state: visible # wip" {
+		t.Errorf("fieldLineAfter(state) = %d, %q, %v", line, text, ok)
+	}
+
+	if _, _, ok := fieldLineAfter(data, "image", "null"); ok {
+		t.Error("Expected multi-line field not to produce a single-line suggestion")
+	}
+	if _, _, ok := fieldLineAfter(data, "version", `"0.1"`); ok {
+		t.Error("Expected missing field not to produce a single-line suggestion")
+	}
+}
+
+func TestAppendToTopLevelSequence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "block sequence keeps indentation",
+			input: "tags:\n    - \"author: alice\" # me\n    - web\n\nstate: visible\n",
+			want:  "tags:\n    - \"author: alice\" # me\n    - web\n    - \"easy\"\n\nstate: visible\n",
+		},
+		{
+			name:  "flow sequence",
+			input: "tags: [web] # tags\nstate: visible\n",
+			want:  "tags: [\"web\", \"easy\"] # tags\nstate: visible\n",
+		},
+		{
+			name:  "empty value",
+			input: "tags:\nstate: visible\n",
+			want:  "tags: [\"easy\"]\nstate: visible\n",
+		},
+		{
+			name:  "missing key",
+			input: "name: chall\n",
+			want:  "name: chall\ntags: [\"easy\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appendToTopLevelSequence([]byte(tt.input), "tags", "easy")
+			if err != nil {
+				t.Fatalf("appendToTopLevelSequence failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("appendToTopLevelSequence() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// roundTripChallenge exercises what authors put in challenge.yml: comments
+// documenting flag provenance, blank lines, every quoting style, block
+// scalars, anchors, and flow collections
+const roundTripChallenge = `# Challenge: phantom capture
+# flag provenance: generated by solver/gen.py (seed 1337)
+
+name: 'Phantom Capture'   # display name
+author: "alice, bob"
+category: osint
+description: |
+  Find where the photo was taken.
+
+  # not a comment: part of the description
+value: 300
+type: dynamic
+extra: &scoring
+  initial: 500 # before decay
+  decay: 50
+  minimum: 100
+flags:
+  # rotated 2026-10-01 after the leak
+  - flag{phantom_capture}
+  - {type: regex, content: 'flag\{p[h4]antom\}', data: case_insensitive}
+tags: [osint, medium] # difficulty
+files:
+  - dist/photo.jpg
+
+# hosted by the infra team
+host: null
+state: hidden # flipped at release
+version: "0.2"
+`
+
+func TestEditsPreserveUntouchedLines(t *testing.T) {
+	for _, eol := range []string{"\n", "\r\n"} {
+		input := strings.ReplaceAll(roundTripChallenge, "\n", eol)
+		for _, key := range []string{"name", "author", "category", "description", "value", "type", "extra", "flags", "tags", "files", "host", "state", "version", "healthcheck"} {
+			updated, err := setTopLevelScalar([]byte(input), key, "x")
+			if err != nil {
+				t.Fatalf("setting %s (%q): %v", key, eol, err)
+			}
+			oldRest, _ := linesOutsideField([]byte(input), key)
+			newRest, _ := linesOutsideField(updated, key)
+			if oldRest != newRest {
+				t.Errorf("setting %s (%q) changed other lines:\n%s", key, eol, updated)
+			}
+			if eol == "\r\n" && strings.Count(string(updated), "\n") != strings.Count(string(updated), "\r\n") {
+				t.Errorf("setting %s mixed line endings:\n%q", key, updated)
+			}
+		}
+
+		updated, err := appendToTopLevelSequence([]byte(input), "files", "dist/notes.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strings.ReplaceAll(roundTripChallenge, "  - dist/photo.jpg\n", "  - dist/photo.jpg\n  - \"dist/notes.txt\"\n")
+		if string(updated) != strings.ReplaceAll(want, "\n", eol) {
+			t.Errorf("appending to files (%q) =\n%q", eol, updated)
+		}
+	}
+
+	// The fixes --fix makes, applied in a row, leave everything else alone
+	fixed, err := fixedData([]byte(roundTripChallenge), []Fix{{Field: "state", Value: "visible"}, {Field: "version", Value: `"0.1"`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(strings.Replace(roundTripChallenge, "state: hidden #", "state: visible #", 1), `version: "0.2"`, `version: "0.1"`, 1)
+	if string(fixed) != want {
+		t.Errorf("fixedData() =\n%s\nwant\n%s", fixed, want)
+	}
+}
+
+func TestCheckEdit(t *testing.T) {
+	old := []byte("# provenance\nname: chall\nstate: hidden\n")
+	if err := checkEdit(old, []byte("# provenance\nname: chall\nstate: visible\n"), "state", "visible"); err != nil {
+		t.Errorf("valid edit rejected: %v", err)
+	}
+	if err := checkEdit(old, []byte("name: chall\nstate: visible\n"), "state", "visible"); err == nil {
+		t.Error("expected an error for a lost comment")
+	}
+	if err := checkEdit(old, []byte("# provenance\nname: chall\nstate: hidden\n"), "state", "visible"); err == nil {
+		t.Error("expected an error for a value that was not set")
+	}
+	if err := checkEdit(old, []byte("# provenance\nname: chall\nstate: [\n"), "state", "visible"); err == nil {
+		t.Error("expected an error for broken YAML")
+	}
+}
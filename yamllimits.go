@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults of yaml_limits. A challenge.yml is a few kilobytes with a handful
+// of levels; the limits only stop files such as a log dump committed under the
+// wrong name from being read whole and exhausting the CI runner.
+const (
+	defaultMaxYAMLSize  = 1024 * 1024 // 1MB in bytes
+	defaultMaxYAMLDepth = 64
+)
+
+// YAMLLimitsRule bounds the challenge.yml files and includes that are parsed
+type YAMLLimitsRule struct {
+	// MaxSize is the largest file read, in bytes (default 1 MB)
+	MaxSize int64 `yaml:"max_size"`
+	// MaxDepth is the deepest nesting of mappings and sequences (default 64)
+	MaxDepth int `yaml:"max_depth"`
+}
+
+func (r YAMLLimitsRule) maxSize() int64 {
+	if r.MaxSize > 0 {
+		return r.MaxSize
+	}
+	return defaultMaxYAMLSize
+}
+
+func (r YAMLLimitsRule) maxDepth() int {
+	if r.MaxDepth > 0 {
+		return r.MaxDepth
+	}
+	return defaultMaxYAMLDepth
+}
+
+// yamlLimitError is a file over yaml_limits, reported as its own finding
+// rather than as a read or parse failure
+type yamlLimitError struct {
+	message string
+}
+
+func (e *yamlLimitError) Error() string {
+	return e.message
+}
+
+// readYAMLFile reads a YAML file of at most rule.MaxSize bytes. Larger files
+// are rejected after reading one byte past the limit, however large they are.
+func readYAMLFile(path string, rule YAMLLimitsRule) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	limit := rule.maxSize()
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > limit {
+		return nil, yamlTooLarge(info.Size(), limit)
+	}
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, yamlTooLarge(-1, limit)
+	}
+	if err := checkYAMLDepth(data, rule.maxDepth()); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func yamlTooLarge(size, limit int64) error {
+	maxMB := float64(limit) / (1024 * 1024)
+	if size < 0 {
+		return &yamlLimitError{fmt.Sprintf("YAML file is too large: more than %.2f MB (yaml_limits.max_size); was a log or dump committed under this name?", maxMB)}
+	}
+	sizeMB := float64(size) / (1024 * 1024)
+	return &yamlLimitError{fmt.Sprintf("YAML file is too large: %.2f MB (maximum allowed: %.2f MB, yaml_limits.max_size); was a log or dump committed under this name?", sizeMB, maxMB)}
+}
+
+// checkYAMLDepth rejects documents nested deeper than maxDepth. The tree is
+// walked without recursion, and aliases are not followed: their expansion
+// is bounded by checkAliasExpansion.
+func checkYAMLDepth(data []byte, maxDepth int) error {
+	docs, err := yamlDocuments(data)
+	if err != nil {
+		// Reported by the regular parse
+		return nil
+	}
+	type entry struct {
+		node  *yaml.Node
+		depth int
+	}
+	for _, doc := range docs {
+		stack := []entry{{doc, 0}}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			depth := current.depth
+			if current.node.Kind == yaml.MappingNode || current.node.Kind == yaml.SequenceNode {
+				depth++
+				if depth > maxDepth {
+					return &yamlLimitError{fmt.Sprintf("YAML is nested more than %d levels deep (line %d); refusing to parse the document", maxDepth, current.node.Line)}
+				}
+			}
+			for _, child := range current.node.Content {
+				stack = append(stack, entry{child, depth})
+			}
+		}
+	}
+	return nil
+}
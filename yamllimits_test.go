@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadYAMLFile(t *testing.T) {
+	tempDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	deep := strings.Repeat("[", 70) + strings.Repeat("]", 70) + "\n"
+	nested := "a:\n  b:\n    c:\n      - d: 1\n"
+
+	tests := []struct {
+		name    string
+		path    string
+		rule    YAMLLimitsRule
+		wantErr string
+	}{
+		{name: "small", path: write("small.yml", "name: test\n")},
+		{name: "over max_size", path: write("big.yml", strings.Repeat("#", 2048)), rule: YAMLLimitsRule{MaxSize: 1024}, wantErr: "too large: 0.00 MB"},
+		{name: "deep flow sequence", path: write("deep.yml", deep), wantErr: "more than 64 levels"},
+		{name: "within max_depth", path: write("nested.yml", nested), rule: YAMLLimitsRule{MaxDepth: 5}},
+		{name: "over max_depth", path: write("nested2.yml", nested), rule: YAMLLimitsRule{MaxDepth: 4}, wantErr: "more than 4 levels deep (line 4)"},
+		{name: "invalid YAML is left to the parser", path: write("invalid.yml", "name: [unclosed\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readYAMLFile(tt.path, tt.rule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("readYAMLFile() error: %v", err)
+				}
+				return
+			}
+			var limitErr *yamlLimitError
+			if !errors.As(err, &limitErr) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readYAMLFile() error = %v, want a limit error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLintChallengeFileEnormous(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "challenge.yml")
+	// A sparse 200 MB file: rejected without reading it into memory
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Truncate(200 << 20); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	result := lintChallengeFile(path)
+	if len(result.Errors) != 1 || result.Errors[0].RuleID != "yaml-limits" {
+		t.Fatalf("expected a single yaml-limits error, got %v", result.Errors)
+	}
+	if !strings.Contains(result.Errors[0].Message, "200.00 MB (maximum allowed: 1.00 MB") {
+		t.Errorf("unexpected message: %s", result.Errors[0].Message)
+	}
+	if _, err := readChallenge(path); err == nil {
+		t.Error("readChallenge() accepted an enormous file")
+	}
+}